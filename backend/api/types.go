@@ -144,6 +144,7 @@ const (
 	ResourceKindPlugin                   = "plugin"
 	ResourceKindEndpoint                 = "endpoint"
 	ResourceKindNetworkPolicy            = "networkpolicy"
+	ResourceKindPodDisruptionBudget      = "poddisruptionbudget"
 )
 
 // Scalable method return whether ResourceKind is scalable.
@@ -181,6 +182,7 @@ const (
 	ClientTypeRbacClient          = "rbacclient"
 	ClientTypeAPIExtensionsClient = "apiextensionsclient"
 	ClientTypeNetworkingClient    = "networkingclient"
+	ClientTypePolicyClient        = "policyclient"
 	ClientTypePluginsClient       = "plugin"
 )
 
@@ -207,7 +209,7 @@ var KindToAPIMapping = map[string]APIMapping{
 	ResourceKindIngress:                  {"ingresses", ClientTypeExtensionClient, true},
 	ResourceKindJob:                      {"jobs", ClientTypeBatchClient, true},
 	ResourceKindCronJob:                  {"cronjobs", ClientTypeBetaBatchClient, true},
-	ResourceKindLimitRange:               {"limitrange", ClientTypeDefault, true},
+	ResourceKindLimitRange:               {"limitranges", ClientTypeDefault, true},
 	ResourceKindNamespace:                {"namespaces", ClientTypeDefault, false},
 	ResourceKindNode:                     {"nodes", ClientTypeDefault, false},
 	ResourceKindPersistentVolumeClaim:    {"persistentvolumeclaims", ClientTypeDefault, true},
@@ -224,7 +226,11 @@ var KindToAPIMapping = map[string]APIMapping{
 	ResourceKindStorageClass:             {"storageclasses", ClientTypeStorageClient, false},
 	ResourceKindEndpoint:                 {"endpoints", ClientTypeDefault, true},
 	ResourceKindNetworkPolicy:            {"networkpolicies", ClientTypeNetworkingClient, true},
+	ResourceKindPodDisruptionBudget:      {"poddisruptionbudgets", ClientTypePolicyClient, true},
 	ResourceKindClusterRole:              {"clusterroles", ClientTypeRbacClient, false},
+	ResourceKindClusterRoleBinding:       {"clusterrolebindings", ClientTypeRbacClient, false},
+	ResourceKindRole:                     {"roles", ClientTypeRbacClient, true},
+	ResourceKindRoleBinding:              {"rolebindings", ClientTypeRbacClient, true},
 	ResourceKindPlugin:                   {"plugins", ClientTypePluginsClient, true},
 }
 
@@ -126,6 +126,12 @@ func (b *holderBuilder) SetAPILogLevel(apiLogLevel string) *holderBuilder {
 	return b
 }
 
+// SetClientCAFile 'client-ca-file' argument of Dashboard binary.
+func (b *holderBuilder) SetClientCAFile(clientCAFile string) *holderBuilder {
+	b.holder.clientCAFile = clientCAFile
+	return b
+}
+
 // SetAuthenticationMode 'authentication-mode' argument of Dashboard binary.
 func (b *holderBuilder) SetAuthenticationMode(authMode []string) *holderBuilder {
 	b.holder.authenticationMode = authMode
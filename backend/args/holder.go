@@ -37,6 +37,7 @@ type holder struct {
 	defaultCertDir       string
 	certFile             string
 	keyFile              string
+	clientCAFile         string
 	apiServerHost        string
 	metricsProvider      string
 	heapsterHost         string
@@ -151,6 +152,11 @@ func (h *holder) GetAPILogLevel() string {
 	return h.apiLogLevel
 }
 
+// GetClientCAFile 'client-ca-file' argument of Dashboard binary.
+func (h *holder) GetClientCAFile() string {
+	return h.clientCAFile
+}
+
 // GetAuthenticationMode 'authentication-mode' argument of Dashboard binary.
 func (h *holder) GetAuthenticationMode() []string {
 	return h.authenticationMode
@@ -25,7 +25,7 @@ func ToAuthenticationModes(modes []string) AuthenticationModes {
 	result := AuthenticationModes{}
 	modesMap := map[string]bool{}
 
-	for _, mode := range []AuthenticationMode{Token, Basic} {
+	for _, mode := range []AuthenticationMode{Token, Basic, ClientCertificate} {
 		modesMap[mode.String()] = true
 	}
 
@@ -27,6 +27,7 @@ func TestToAuthenticationModes(t *testing.T) {
 		{[]string{}, AuthenticationModes{}},
 		{[]string{"token"}, AuthenticationModes{Token: true}},
 		{[]string{"token", "basic", "test"}, AuthenticationModes{Token: true, Basic: true}},
+		{[]string{"clientcertificate"}, AuthenticationModes{ClientCertificate: true}},
 	}
 
 	for _, c := range cases {
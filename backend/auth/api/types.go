@@ -75,6 +75,9 @@ func (a AuthenticationMode) String() string {
 const (
 	Token AuthenticationMode = "token"
 	Basic AuthenticationMode = "basic"
+	// ClientCertificate authenticates requests with a client certificate verified by the TLS listener. Certificate
+	// CN and O are mapped to Kubernetes user and groups and requests are executed through impersonation.
+	ClientCertificate AuthenticationMode = "clientcertificate"
 )
 
 // AuthManager is used for user authentication management.
@@ -82,12 +82,16 @@ func (fcm *fakeClientManager) CSRFKey() string {
 	return ""
 }
 
+func (fcm *fakeClientManager) User(c *gin.Context) (string, error) {
+	return "", nil
+}
+
 func (fcm *fakeClientManager) HasAccess(authInfo api.AuthInfo) error {
 	return fcm.HasAccessError
 }
 
 func (fcm *fakeClientManager) VerberClient(c *gin.Context, config *rest.Config) (clientapi.ResourceVerber, error) {
-	return client.NewResourceVerber(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil), nil
+	return client.NewResourceVerber(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil), nil
 }
 
 func (fcm *fakeClientManager) CanI(c *gin.Context, ssar *v1.SelfSubjectAccessReview) bool {
@@ -0,0 +1,340 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bulk
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	clientapi "github.com/ycyxuehan/dashboard-gin/backend/client/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/pod"
+	"github.com/ycyxuehan/dashboard-gin/backend/scaling"
+)
+
+// ActionName is the name of an action that can be applied to many resources at once.
+type ActionName string
+
+// List of all supported bulk actions.
+const (
+	ActionDelete   ActionName = "delete"
+	ActionScale    ActionName = "scale"
+	ActionRestart  ActionName = "restart"
+	ActionLabel    ActionName = "label"
+	ActionAnnotate ActionName = "annotate"
+	ActionSuspend  ActionName = "suspend"
+	ActionResume   ActionName = "resume"
+)
+
+// ResultStatus is the outcome of a bulk action for a single resource.
+type ResultStatus string
+
+// List of all result statuses.
+const (
+	ResultStatusSucceeded ResultStatus = "Succeeded"
+	ResultStatusFailed    ResultStatus = "Failed"
+)
+
+const (
+	// defaultConcurrency is used when the spec does not limit the number of parallel requests.
+	defaultConcurrency = 5
+
+	// maxConcurrency is the highest number of parallel requests.
+	maxConcurrency = 20
+
+	// maxResources is the highest number of resources a single bulk action can change.
+	maxResources = 500
+
+	// restartedAtAnnotation is set on the pod template to roll out new pods, the same way kubectl does it.
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+)
+
+// restartableKinds are the kinds that can be restarted by changing their pod template.
+var restartableKinds = map[string]bool{
+	api.ResourceKindDeployment:  true,
+	api.ResourceKindDaemonSet:   true,
+	api.ResourceKindStatefulSet: true,
+}
+
+// scalableKinds are the kinds supported by the scaling package.
+var scalableKinds = map[string]bool{
+	api.ResourceKindDeployment:            true,
+	api.ResourceKindReplicaSet:            true,
+	api.ResourceKindReplicationController: true,
+	api.ResourceKindStatefulSet:           true,
+}
+
+// scaleResource is replaced in tests, as scaling needs a real API server.
+var scaleResource = scaling.ScaleResource
+
+// ResourceReference identifies a single resource. Namespace is empty for cluster scoped kinds.
+type ResourceReference struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// ActionSpec describes a bulk action. Resources are either listed explicitly, or selected by kind and label
+// selector, optionally limited to a namespace.
+type ActionSpec struct {
+	Action ActionName `json:"action"`
+
+	Resources []ResourceReference `json:"resources,omitempty"`
+
+	Kind          string `json:"kind,omitempty"`
+	Namespace     string `json:"namespace,omitempty"`
+	LabelSelector string `json:"labelSelector,omitempty"`
+
+	// Replicas is the desired number of replicas of the scale action.
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Values are the labels or annotations to set. Null values remove the label or annotation.
+	Values map[string]*string `json:"values,omitempty"`
+
+	// DryRun only checks that the action can be applied, without changing any resource.
+	DryRun bool `json:"dryRun"`
+
+	// Concurrency is the number of resources changed in parallel. Defaults to 5.
+	Concurrency int `json:"concurrency"`
+}
+
+// ResourceResult is the outcome of the action for a single resource.
+type ResourceResult struct {
+	ResourceReference `json:",inline"`
+	Status            ResultStatus `json:"status"`
+	Message           string       `json:"message,omitempty"`
+}
+
+// ActionResult is the per resource report of a bulk action.
+type ActionResult struct {
+	Action    ActionName       `json:"action"`
+	DryRun    bool             `json:"dryRun"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []ResourceResult `json:"results"`
+}
+
+// Execute applies the action to all resources of the spec. Failures of single resources do not stop the other
+// resources from being changed, they are reported in the result instead.
+func Execute(verber clientapi.ResourceVerber, client kubernetes.Interface, cfg *rest.Config,
+	spec *ActionSpec) (*ActionResult, error) {
+	concurrency, err := validateSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	references, err := getReferences(verber, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(references) > maxResources {
+		return nil, errors.NewInvalid(fmt.Sprintf("Bulk actions are limited to %d resources, %d selected",
+			maxResources, len(references)))
+	}
+
+	log.Printf("Applying bulk action %s to %d resources, dry run: %t", spec.Action, len(references), spec.DryRun)
+
+	result := &ActionResult{
+		Action:  spec.Action,
+		DryRun:  spec.DryRun,
+		Results: make([]ResourceResult, len(references)),
+	}
+
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, reference := range references {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, reference ResourceReference) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			result.Results[i] = ResourceResult{ResourceReference: reference, Status: ResultStatusSucceeded}
+			if err := apply(verber, client, cfg, spec, reference); err != nil {
+				result.Results[i].Status = ResultStatusFailed
+				result.Results[i].Message = err.Error()
+			}
+		}(i, reference)
+	}
+	wg.Wait()
+
+	for _, item := range result.Results {
+		if item.Status == ResultStatusSucceeded {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+	}
+
+	return result, nil
+}
+
+func validateSpec(spec *ActionSpec) (int, error) {
+	switch spec.Action {
+	case ActionDelete, ActionRestart, ActionSuspend, ActionResume:
+	case ActionScale:
+		if spec.Replicas == nil || *spec.Replicas < 0 {
+			return 0, errors.NewInvalid("Scale action requires a non-negative number of replicas")
+		}
+	case ActionLabel, ActionAnnotate:
+		if len(spec.Values) == 0 {
+			return 0, errors.NewInvalid(fmt.Sprintf("Action %s requires values", spec.Action))
+		}
+	default:
+		return 0, errors.NewInvalid(fmt.Sprintf("Unsupported bulk action: %s", spec.Action))
+	}
+
+	selected := len(spec.Kind) > 0 || len(spec.LabelSelector) > 0
+	if len(spec.Resources) > 0 && selected {
+		return 0, errors.NewInvalid("Either resources or kind and label selector can be set, not both")
+	}
+	if len(spec.Resources) == 0 && (len(spec.Kind) == 0 || len(strings.TrimSpace(spec.LabelSelector)) == 0) {
+		return 0, errors.NewInvalid("Resources or kind and label selector are required")
+	}
+
+	if spec.Concurrency < 0 || spec.Concurrency > maxConcurrency {
+		return 0, errors.NewInvalid(fmt.Sprintf("Concurrency must be between 0 and %d", maxConcurrency))
+	} else if spec.Concurrency == 0 {
+		return defaultConcurrency, nil
+	}
+	return spec.Concurrency, nil
+}
+
+// getReferences returns the explicitly listed resources or lists the resources selected by kind and label
+// selector.
+func getReferences(verber clientapi.ResourceVerber, spec *ActionSpec) ([]ResourceReference, error) {
+	if len(spec.Resources) > 0 {
+		return spec.Resources, nil
+	}
+
+	if _, err := labels.Parse(spec.LabelSelector); err != nil {
+		return nil, errors.NewInvalid(err.Error())
+	}
+
+	object, err := verber.List(spec.Kind, spec.Namespace, spec.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	unknown, ok := object.(*runtime.Unknown)
+	if !ok {
+		return nil, errors.NewUnexpectedObject(object)
+	}
+
+	list := &metaV1.PartialObjectMetadataList{}
+	if err := json.Unmarshal(unknown.Raw, list); err != nil {
+		return nil, err
+	}
+
+	references := make([]ResourceReference, 0)
+	for _, item := range list.Items {
+		references = append(references, ResourceReference{Kind: spec.Kind, Namespace: item.Namespace,
+			Name: item.Name})
+	}
+	return references, nil
+}
+
+func apply(verber clientapi.ResourceVerber, client kubernetes.Interface, cfg *rest.Config, spec *ActionSpec,
+	reference ResourceReference) error {
+	namespaceSet := len(reference.Namespace) > 0
+
+	switch spec.Action {
+	case ActionDelete:
+		if spec.DryRun {
+			_, err := verber.Get(reference.Kind, namespaceSet, reference.Namespace, reference.Name)
+			return err
+		}
+		return verber.Delete(reference.Kind, namespaceSet, reference.Namespace, reference.Name)
+	case ActionScale:
+		if !scalableKinds[reference.Kind] {
+			return errors.NewInvalid(fmt.Sprintf("Resource kind %s cannot be scaled", reference.Kind))
+		}
+		if spec.DryRun {
+			_, err := verber.Get(reference.Kind, namespaceSet, reference.Namespace, reference.Name)
+			return err
+		}
+		// Scaling changes the config, so every request gets its own copy.
+		_, err := scaleResource(rest.CopyConfig(cfg), reference.Kind, reference.Namespace, reference.Name,
+			strconv.Itoa(int(*spec.Replicas)))
+		return err
+	case ActionRestart:
+		if reference.Kind == api.ResourceKindPod {
+			if spec.DryRun {
+				_, err := verber.Get(reference.Kind, namespaceSet, reference.Namespace, reference.Name)
+				return err
+			}
+			_, err := pod.RestartPod(client, reference.Namespace, reference.Name)
+			return err
+		}
+	}
+
+	patch, err := getPatch(spec, reference)
+	if err != nil {
+		return err
+	}
+
+	options := metaV1.PatchOptions{}
+	if spec.DryRun {
+		options.DryRun = []string{metaV1.DryRunAll}
+	}
+
+	_, err = verber.Patch(reference.Kind, namespaceSet, reference.Namespace, reference.Name, types.MergePatchType,
+		patch, options)
+	return err
+}
+
+// getPatch returns the JSON merge patch of actions that change a single field of the resource.
+func getPatch(spec *ActionSpec, reference ResourceReference) ([]byte, error) {
+	var patch map[string]interface{}
+
+	switch spec.Action {
+	case ActionLabel:
+		patch = map[string]interface{}{"metadata": map[string]interface{}{"labels": spec.Values}}
+	case ActionAnnotate:
+		patch = map[string]interface{}{"metadata": map[string]interface{}{"annotations": spec.Values}}
+	case ActionRestart:
+		if !restartableKinds[reference.Kind] {
+			return nil, errors.NewInvalid(fmt.Sprintf("Resource kind %s cannot be restarted", reference.Kind))
+		}
+		patch = map[string]interface{}{"spec": map[string]interface{}{"template": map[string]interface{}{
+			"metadata": map[string]interface{}{"annotations": map[string]string{
+				restartedAtAnnotation: time.Now().Format(time.RFC3339),
+			}},
+		}}}
+	case ActionSuspend, ActionResume:
+		if reference.Kind != api.ResourceKindCronJob {
+			return nil, errors.NewInvalid(fmt.Sprintf("Resource kind %s cannot be suspended", reference.Kind))
+		}
+		patch = map[string]interface{}{"spec": map[string]interface{}{"suspend": spec.Action == ActionSuspend}}
+	default:
+		return nil, errors.NewInvalid(fmt.Sprintf("Unsupported bulk action: %s", spec.Action))
+	}
+
+	return json.Marshal(patch)
+}
@@ -0,0 +1,291 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bulk
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/scaling"
+)
+
+type fakeVerber struct {
+	sync.Mutex
+	calls    []string
+	patches  map[string]string
+	dryRuns  int
+	list     string
+	notFound map[string]bool
+}
+
+func newFakeVerber() *fakeVerber {
+	return &fakeVerber{patches: make(map[string]string), notFound: make(map[string]bool)}
+}
+
+func (verber *fakeVerber) record(verb, kind, namespace, name string) error {
+	verber.Lock()
+	defer verber.Unlock()
+	verber.calls = append(verber.calls, fmt.Sprintf("%s %s %s/%s", verb, kind, namespace, name))
+	if verber.notFound[name] {
+		return errors.NewNotFound(fmt.Sprintf("%s %s not found", kind, name))
+	}
+	return nil
+}
+
+func (verber *fakeVerber) Put(kind string, namespaceSet bool, namespace string, name string,
+	object *runtime.Unknown) error {
+	return verber.record("put", kind, namespace, name)
+}
+
+func (verber *fakeVerber) Get(kind string, namespaceSet bool, namespace string, name string) (runtime.Object,
+	error) {
+	return &runtime.Unknown{}, verber.record("get", kind, namespace, name)
+}
+
+func (verber *fakeVerber) List(kind string, namespace string, labelSelector string) (runtime.Object, error) {
+	verber.record("list", kind, namespace, labelSelector)
+	return &runtime.Unknown{Raw: []byte(verber.list)}, nil
+}
+
+func (verber *fakeVerber) Delete(kind string, namespaceSet bool, namespace string, name string) error {
+	return verber.record("delete", kind, namespace, name)
+}
+
+func (verber *fakeVerber) Create(kind string, namespaceSet bool, namespace string,
+	object *runtime.Unknown) (runtime.Object, error) {
+	return object, verber.record("create", kind, namespace, "")
+}
+
+func (verber *fakeVerber) Patch(kind string, namespaceSet bool, namespace string, name string,
+	patchType types.PatchType, data []byte, options metaV1.PatchOptions) (runtime.Object, error) {
+	verber.Lock()
+	verber.patches[namespace+"/"+name] = string(data)
+	if len(options.DryRun) > 0 {
+		verber.dryRuns++
+	}
+	verber.Unlock()
+	return &runtime.Unknown{}, verber.record("patch", kind, namespace, name)
+}
+
+func (verber *fakeVerber) sortedCalls() []string {
+	calls := append([]string{}, verber.calls...)
+	sort.Strings(calls)
+	return calls
+}
+
+func int32Pointer(value int32) *int32 {
+	return &value
+}
+
+func stringPointer(value string) *string {
+	return &value
+}
+
+func TestExecuteShouldValidateSpec(t *testing.T) {
+	resources := []ResourceReference{{Kind: "deployment", Namespace: "default", Name: "app"}}
+	cases := []*ActionSpec{
+		{Action: "unknown", Resources: resources},
+		{Action: ActionScale, Resources: resources},
+		{Action: ActionScale, Resources: resources, Replicas: int32Pointer(-1)},
+		{Action: ActionLabel, Resources: resources},
+		{Action: ActionDelete},
+		{Action: ActionDelete, Kind: "deployment"},
+		{Action: ActionDelete, Kind: "deployment", LabelSelector: "app=test", Resources: resources},
+		{Action: ActionDelete, Kind: "deployment", LabelSelector: "app in (test"},
+		{Action: ActionDelete, Resources: resources, Concurrency: maxConcurrency + 1},
+		{Action: ActionDelete, Resources: make([]ResourceReference, maxResources+1)},
+	}
+
+	for _, c := range cases {
+		verber := newFakeVerber()
+		_, err := Execute(verber, fake.NewSimpleClientset(), &rest.Config{}, c)
+		if err == nil {
+			t.Errorf("Execute(%#v) == nil, expected error", c)
+		}
+		if len(verber.calls) > 0 {
+			t.Errorf("Execute(%#v) should not change resources, got calls %v", c, verber.calls)
+		}
+	}
+}
+
+func TestExecuteShouldReportResultsPerResource(t *testing.T) {
+	verber := newFakeVerber()
+	verber.notFound["missing"] = true
+	spec := &ActionSpec{
+		Action: ActionDelete,
+		Resources: []ResourceReference{
+			{Kind: "deployment", Namespace: "default", Name: "app"},
+			{Kind: "deployment", Namespace: "default", Name: "missing"},
+			{Kind: "namespace", Name: "test"},
+		},
+		Concurrency: 2,
+	}
+
+	actual, err := Execute(verber, fake.NewSimpleClientset(), &rest.Config{}, spec)
+	if err != nil {
+		t.Fatalf("Execute() returned error: %s", err)
+	}
+
+	expected := &ActionResult{
+		Action:    ActionDelete,
+		Succeeded: 2,
+		Failed:    1,
+		Results: []ResourceResult{
+			{ResourceReference: spec.Resources[0], Status: ResultStatusSucceeded},
+			{ResourceReference: spec.Resources[1], Status: ResultStatusFailed,
+				Message: "deployment missing not found"},
+			{ResourceReference: spec.Resources[2], Status: ResultStatusSucceeded},
+		},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Execute() == %#v, expected %#v", actual, expected)
+	}
+
+	expectedCalls := []string{"delete deployment default/app", "delete deployment default/missing",
+		"delete namespace /test"}
+	if calls := verber.sortedCalls(); !reflect.DeepEqual(calls, expectedCalls) {
+		t.Errorf("Execute() made calls %v, expected %v", calls, expectedCalls)
+	}
+}
+
+func TestExecuteShouldSelectResourcesByLabel(t *testing.T) {
+	verber := newFakeVerber()
+	verber.list = `{"items":[{"metadata":{"name":"a","namespace":"default"}},` +
+		`{"metadata":{"name":"b","namespace":"default"}}]}`
+	spec := &ActionSpec{
+		Action:        ActionLabel,
+		Kind:          "deployment",
+		Namespace:     "default",
+		LabelSelector: "app=test",
+		Values:        map[string]*string{"team": stringPointer("web"), "old": nil},
+	}
+
+	actual, err := Execute(verber, fake.NewSimpleClientset(), &rest.Config{}, spec)
+	if err != nil {
+		t.Fatalf("Execute() returned error: %s", err)
+	}
+
+	if actual.Succeeded != 2 || actual.Failed != 0 {
+		t.Errorf("Execute() == %#v, expected 2 succeeded resources", actual)
+	}
+
+	expectedPatch := `{"metadata":{"labels":{"old":null,"team":"web"}}}`
+	expectedPatches := map[string]string{"default/a": expectedPatch, "default/b": expectedPatch}
+	if !reflect.DeepEqual(verber.patches, expectedPatches) {
+		t.Errorf("Execute() sent patches %v, expected %v", verber.patches, expectedPatches)
+	}
+
+	if verber.calls[0] != "list deployment default/app=test" {
+		t.Errorf("Execute() should list resources first, got calls %v", verber.calls)
+	}
+}
+
+func TestExecuteShouldNotChangeResourcesInDryRun(t *testing.T) {
+	resources := []ResourceReference{
+		{Kind: "deployment", Namespace: "default", Name: "app"},
+		{Kind: "cronjob", Namespace: "default", Name: "job"},
+	}
+	cases := []struct {
+		spec          *ActionSpec
+		expectedCalls []string
+		dryRuns       int
+		failed        int
+	}{
+		{
+			&ActionSpec{Action: ActionDelete, Resources: resources, DryRun: true},
+			[]string{"get cronjob default/job", "get deployment default/app"}, 0, 0,
+		},
+		{
+			&ActionSpec{Action: ActionScale, Resources: resources, Replicas: int32Pointer(3), DryRun: true},
+			[]string{"get deployment default/app"}, 0, 1,
+		},
+		{
+			&ActionSpec{Action: ActionRestart, Resources: resources, DryRun: true},
+			[]string{"patch deployment default/app"}, 1, 1,
+		},
+		{
+			&ActionSpec{Action: ActionSuspend, Resources: resources, DryRun: true},
+			[]string{"patch cronjob default/job"}, 1, 1,
+		},
+	}
+
+	for _, c := range cases {
+		verber := newFakeVerber()
+		actual, err := Execute(verber, fake.NewSimpleClientset(), &rest.Config{}, c.spec)
+		if err != nil {
+			t.Fatalf("Execute(%#v) returned error: %s", c.spec, err)
+		}
+
+		if !actual.DryRun || actual.Failed != c.failed {
+			t.Errorf("Execute(%#v) == %#v, expected dry run with %d failures", c.spec, actual, c.failed)
+		}
+
+		if calls := verber.sortedCalls(); !reflect.DeepEqual(calls, c.expectedCalls) {
+			t.Errorf("Execute(%#v) made calls %v, expected %v", c.spec, calls, c.expectedCalls)
+		}
+
+		if verber.dryRuns != c.dryRuns {
+			t.Errorf("Execute(%#v) sent %d dry run patches, expected %d", c.spec, verber.dryRuns, c.dryRuns)
+		}
+	}
+}
+
+func TestExecuteShouldScaleResources(t *testing.T) {
+	defer func(original func(*rest.Config, string, string, string, string) (*scaling.ReplicaCounts, error)) {
+		scaleResource = original
+	}(scaleResource)
+
+	var mutex sync.Mutex
+	scaled := make([]string, 0)
+	scaleResource = func(cfg *rest.Config, kind, namespace, name, count string) (*scaling.ReplicaCounts, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		scaled = append(scaled, fmt.Sprintf("%s %s/%s %s", kind, namespace, name, count))
+		return &scaling.ReplicaCounts{}, nil
+	}
+
+	spec := &ActionSpec{
+		Action:   ActionScale,
+		Replicas: int32Pointer(0),
+		Resources: []ResourceReference{
+			{Kind: "deployment", Namespace: "default", Name: "app"},
+			{Kind: "statefulset", Namespace: "default", Name: "db"},
+		},
+	}
+	actual, err := Execute(newFakeVerber(), fake.NewSimpleClientset(), &rest.Config{}, spec)
+	if err != nil {
+		t.Fatalf("Execute() returned error: %s", err)
+	}
+
+	if actual.Succeeded != 2 {
+		t.Errorf("Execute() == %#v, expected 2 succeeded resources", actual)
+	}
+
+	sort.Strings(scaled)
+	expected := []string{"deployment default/app 0", "statefulset default/db 0"}
+	if !reflect.DeepEqual(scaled, expected) {
+		t.Errorf("Execute() scaled %v, expected %v", scaled, expected)
+	}
+}
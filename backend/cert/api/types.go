@@ -14,13 +14,20 @@
 
 package api
 
-import "crypto/tls"
+import (
+	"crypto/tls"
+	"time"
+)
 
 const (
 	// DashboardCertName is the certificate file names that will be generated by Dashboard
 	DashboardCertName = "dashboard.crt"
 	// DashboardKeyName is the key file names that will be generated by Dashboard
 	DashboardKeyName = "dashboard.key"
+	// ReloadInterval is the interval in which the serving certificate is reloaded from its source
+	ReloadInterval = 30 * time.Second
+	// RotateBefore is the time before expiry from which auto-generated certificates are replaced
+	RotateBefore = 30 * 24 * time.Hour
 )
 
 // Manager is responsible for generating and storing self-signed certificates that can be used by Dashboard
@@ -30,6 +37,18 @@ type Manager interface {
 	GetCertificates() (tls.Certificate, error)
 }
 
+// Holder keeps the serving certificate and replaces it whenever its source changes, so that new certificates are
+// used without restarting Dashboard.
+type Holder interface {
+	// GetCertificate returns the current certificate. It can be used as tls.Config.GetCertificate.
+	GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error)
+	// Update replaces the current certificate if the given one differs from it.
+	Update(tls.Certificate)
+	// Start reloads the certificate from its source every interval in a separate goroutine. Should not block
+	// thread that calls it.
+	Start(interval time.Duration)
+}
+
 // Creator is responsible for preparing and generating certificates.
 type Creator interface {
 	// GenerateKey generates certificate key
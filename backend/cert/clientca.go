@@ -0,0 +1,42 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+)
+
+// LoadClientCAs reads PEM encoded CA bundle from given file. Returned pool is used by the TLS listener to verify
+// client certificates.
+func LoadClientCAs(path string) (*x509.CertPool, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("client CA bundle is required by client certificate authentication, " +
+			"use --client-ca-file argument to set it")
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no valid certificates found in client CA bundle %s", path)
+	}
+
+	return pool, nil
+}
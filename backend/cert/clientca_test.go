@@ -0,0 +1,61 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadClientCAs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "client-ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	bundle, _ := newKeyPair(t, time.Now().Add(time.Hour))
+	valid := filepath.Join(dir, "ca.crt")
+	invalid := filepath.Join(dir, "invalid.crt")
+	if err := ioutil.WriteFile(valid, bundle, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(invalid, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		path        string
+		expectedErr bool
+	}{
+		{valid, false},
+		{invalid, true},
+		{filepath.Join(dir, "missing.crt"), true},
+		{"", true},
+	}
+
+	for _, c := range cases {
+		pool, err := LoadClientCAs(c.path)
+		if (err != nil) != c.expectedErr {
+			t.Fatalf("LoadClientCAs(%q): expected error %t, but got %v", c.path, c.expectedErr, err)
+		}
+		if !c.expectedErr && len(pool.Subjects()) != 1 {
+			t.Fatalf("LoadClientCAs(%q): expected 1 certificate, but got %d", c.path, len(pool.Subjects()))
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"bytes"
+	"crypto/tls"
+	"log"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+
+	certapi "github.com/ycyxuehan/dashboard-gin/backend/cert/api"
+	syncApi "github.com/ycyxuehan/dashboard-gin/backend/sync/api"
+)
+
+// Holder is used to implement cert/api/types.Holder interface. See Holder for more information.
+type Holder struct {
+	source      func() (tls.Certificate, error)
+	certificate *tls.Certificate
+	mux         sync.RWMutex
+
+	// Certificate last returned by the source. The current certificate is only replaced if the source returns a
+	// different one, so that it does not switch back and forth with certificates set by Update.
+	loaded *tls.Certificate
+}
+
+// GetCertificate implements Holder interface. See Holder for more information.
+func (h *Holder) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+	return h.certificate, nil
+}
+
+// Update implements Holder interface. See Holder for more information.
+func (h *Holder) Update(certificate tls.Certificate) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	if h.certificate != nil && equalCertificates(h.certificate, &certificate) {
+		return
+	}
+
+	if h.certificate != nil {
+		log.Println("Serving certificate has changed. Using the new certificate.")
+	}
+	h.certificate = &certificate
+}
+
+// Start implements Holder interface. See Holder for more information. Errors of the source are logged and the
+// current certificate is kept, so that a partially written certificate does not stop Dashboard from serving.
+func (h *Holder) Start(interval time.Duration) {
+	go func() {
+		for range time.Tick(interval) {
+			h.reload()
+		}
+	}()
+}
+
+func (h *Holder) reload() {
+	certificate, err := h.source()
+	if err != nil {
+		log.Printf("Could not reload serving certificate: %s", err.Error())
+		return
+	}
+
+	if equalCertificates(h.loaded, &certificate) {
+		return
+	}
+	h.loaded = &certificate
+	h.Update(certificate)
+}
+
+// WatchSecret updates the holder whenever the certificate and key under given keys of the secret synchronized by
+// given synchronizer change.
+func WatchSecret(holder certapi.Holder, synchronizer syncApi.Synchronizer, certKey, keyKey string) {
+	var certPEM, keyPEM []byte
+	synchronizer.RegisterActionHandler(func(obj runtime.Object) {
+		secret, ok := obj.(*v1.Secret)
+		if !ok || len(secret.Data[certKey]) == 0 || len(secret.Data[keyKey]) == 0 {
+			return
+		}
+
+		// Synchronizers report the secret on every poll, only changes are of interest.
+		if bytes.Equal(certPEM, secret.Data[certKey]) && bytes.Equal(keyPEM, secret.Data[keyKey]) {
+			return
+		}
+		certPEM, keyPEM = secret.Data[certKey], secret.Data[keyKey]
+
+		certificate, err := tls.X509KeyPair(secret.Data[certKey], secret.Data[keyKey])
+		if err != nil {
+			log.Printf("Ignoring invalid serving certificate in secret %s: %s", secret.Name, err.Error())
+			return
+		}
+		holder.Update(certificate)
+	}, watch.Added, watch.Modified)
+}
+
+func equalCertificates(a, b *tls.Certificate) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if len(a.Certificate) != len(b.Certificate) {
+		return false
+	}
+	for i := range a.Certificate {
+		if !bytes.Equal(a.Certificate[i], b.Certificate[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewCertHolder creates Holder object with the certificate returned by the source.
+func NewCertHolder(source func() (tls.Certificate, error)) (certapi.Holder, error) {
+	certificate, err := source()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Holder{source: source, certificate: &certificate, loaded: &certificate}, nil
+}
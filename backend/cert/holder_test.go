@@ -0,0 +1,160 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+
+	syncApi "github.com/ycyxuehan/dashboard-gin/backend/sync/api"
+)
+
+// newKeyPair returns a PEM encoded self-signed certificate and its key.
+func newKeyPair(t *testing.T, notAfter time.Time) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), NotBefore: time.Now(), NotAfter: notAfter}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func newCertificate(t *testing.T, notAfter time.Time) tls.Certificate {
+	certPEM, keyPEM := newKeyPair(t, notAfter)
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certificate
+}
+
+func current(t *testing.T, holder *Holder) *tls.Certificate {
+	certificate, err := holder.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certificate
+}
+
+func TestHolderReload(t *testing.T) {
+	first := newCertificate(t, time.Now().Add(time.Hour))
+	second := newCertificate(t, time.Now().Add(time.Hour))
+	fromSecret := newCertificate(t, time.Now().Add(time.Hour))
+
+	var sourceCertificate = first
+	var sourceError error
+	h, err := NewCertHolder(func() (tls.Certificate, error) { return sourceCertificate, sourceError })
+	if err != nil {
+		t.Fatalf("NewCertHolder() unexpected error: %v", err)
+	}
+	holder := h.(*Holder)
+
+	if !equalCertificates(current(t, holder), &first) {
+		t.Error("expected initial certificate from the source")
+	}
+
+	// A certificate set by update is kept as long as the source does not change.
+	holder.Update(fromSecret)
+	holder.reload()
+	if !equalCertificates(current(t, holder), &fromSecret) {
+		t.Error("expected certificate set by Update to be kept")
+	}
+
+	sourceCertificate = second
+	holder.reload()
+	if !equalCertificates(current(t, holder), &second) {
+		t.Error("expected changed source certificate to be used")
+	}
+
+	sourceError = errors.New("partially written")
+	holder.reload()
+	if !equalCertificates(current(t, holder), &second) {
+		t.Error("expected certificate to be kept if the source fails")
+	}
+}
+
+// fakeSynchronizer only records action handlers.
+type fakeSynchronizer struct {
+	handlers map[watch.EventType][]syncApi.ActionHandlerFunction
+}
+
+func (f *fakeSynchronizer) Name() string                    { return "fake" }
+func (f *fakeSynchronizer) Start()                          {}
+func (f *fakeSynchronizer) Error() chan error               { return nil }
+func (f *fakeSynchronizer) Create(runtime.Object) error     { return nil }
+func (f *fakeSynchronizer) Get() runtime.Object             { return nil }
+func (f *fakeSynchronizer) Update(runtime.Object) error     { return nil }
+func (f *fakeSynchronizer) Delete() error                   { return nil }
+func (f *fakeSynchronizer) Refresh()                        {}
+func (f *fakeSynchronizer) SetPoller(poller syncApi.Poller) {}
+func (f *fakeSynchronizer) RegisterActionHandler(handler syncApi.ActionHandlerFunction, events ...watch.EventType) {
+	for _, event := range events {
+		f.handlers[event] = append(f.handlers[event], handler)
+	}
+}
+
+func TestWatchSecret(t *testing.T) {
+	initial := newCertificate(t, time.Now().Add(time.Hour))
+	h, err := NewCertHolder(func() (tls.Certificate, error) { return initial, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	holder := h.(*Holder)
+
+	synchronizer := &fakeSynchronizer{handlers: make(map[watch.EventType][]syncApi.ActionHandlerFunction)}
+	WatchSecret(holder, synchronizer, "tls.crt", "tls.key")
+
+	certPEM, keyPEM := newKeyPair(t, time.Now().Add(time.Hour))
+	cases := []struct {
+		data    map[string][]byte
+		changed bool
+	}{
+		{map[string][]byte{}, false},
+		{map[string][]byte{"tls.crt": certPEM, "tls.key": []byte("invalid")}, false},
+		{map[string][]byte{"tls.crt": certPEM, "tls.key": keyPEM}, true},
+	}
+
+	for _, c := range cases {
+		for _, handler := range synchronizer.handlers[watch.Modified] {
+			handler(&v1.Secret{Data: c.data})
+		}
+		if changed := !equalCertificates(current(t, holder), &initial); changed != c.changed {
+			t.Errorf("expected certificate change to be %v for secret data %v", c.changed, c.data)
+		}
+	}
+}
@@ -16,8 +16,11 @@ package cert
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"log"
 	"os"
+	"sync"
+	"time"
 
 	certapi "github.com/ycyxuehan/dashboard-gin/backend/cert/api"
 )
@@ -26,18 +29,28 @@ import (
 type Manager struct {
 	creator certapi.Creator
 	certDir string
+
+	// Last generated certificate. It is returned until it expires within certapi.RotateBefore.
+	generated *tls.Certificate
+	mux       sync.Mutex
 }
 
-// GetCertificates implements Manager interface. See Manager for more information.
+// GetCertificates implements Manager interface. See Manager for more information. Generated certificates are kept
+// and replaced by new ones before they expire, so it can be called repeatedly.
 func (m *Manager) GetCertificates() (tls.Certificate, error) {
 	if m.keyFileExists() && m.certFileExists() {
-		log.Println("Certificates already exist. Returning.")
 		return tls.LoadX509KeyPair(
 			m.path(m.creator.GetCertFileName()),
 			m.path(m.creator.GetKeyFileName()),
 		)
 	}
 
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.generated != nil && !expiresWithin(m.generated, certapi.RotateBefore) {
+		return *m.generated, nil
+	}
+
 	key := m.creator.GenerateKey()
 	cert := m.creator.GenerateCertificate(key)
 	log.Println("Successfully created certificates")
@@ -45,7 +58,26 @@ func (m *Manager) GetCertificates() (tls.Certificate, error) {
 	if err != nil {
 		return tls.Certificate{}, err
 	}
-	return tls.X509KeyPair(certPEM, keyPEM)
+
+	generated, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	m.generated = &generated
+	return generated, nil
+}
+
+// expiresWithin returns true if the leaf certificate expires within the given duration or cannot be parsed.
+func expiresWithin(certificate *tls.Certificate, duration time.Duration) bool {
+	if len(certificate.Certificate) == 0 {
+		return true
+	}
+
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		return true
+	}
+	return time.Now().Add(duration).After(leaf.NotAfter)
 }
 
 func (m *Manager) keyFileExists() bool {
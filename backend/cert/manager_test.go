@@ -0,0 +1,57 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"crypto/elliptic"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/cert/ecdsa"
+)
+
+func TestManagerRotatesGeneratedCertificates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	manager := NewCertManager(ecdsa.NewECDSACreator("", "", elliptic.P256()), dir).(*Manager)
+
+	first, err := manager.GetCertificates()
+	if err != nil {
+		t.Fatalf("GetCertificates() unexpected error: %v", err)
+	}
+	second, err := manager.GetCertificates()
+	if err != nil {
+		t.Fatalf("GetCertificates() unexpected error: %v", err)
+	}
+	if !equalCertificates(&first, &second) {
+		t.Error("expected generated certificate to be kept until it expires")
+	}
+
+	expiring := newCertificate(t, time.Now().Add(24*time.Hour))
+	manager.generated = &expiring
+	rotated, err := manager.GetCertificates()
+	if err != nil {
+		t.Fatalf("GetCertificates() unexpected error: %v", err)
+	}
+	if equalCertificates(&rotated, &expiring) || expiresWithin(&rotated, time.Hour) {
+		t.Error("expected a new certificate to be generated for an expiring one")
+	}
+}
@@ -17,7 +17,9 @@ package api
 import (
 	v1 "k8s.io/api/authorization/v1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -50,6 +52,7 @@ type ClientManager interface {
 	ClientCmdConfig(c *gin.Context) (clientcmd.ClientConfig, error)
 	CSRFKey() string
 	HasAccess(authInfo api.AuthInfo) error
+	User(c *gin.Context) (string, error)
 	VerberClient(c *gin.Context, config *rest.Config) (ResourceVerber, error)
 	SetTokenManager(manager authApi.TokenManager)
 }
@@ -59,7 +62,11 @@ type ResourceVerber interface {
 	Put(kind string, namespaceSet bool, namespace string, name string,
 		object *runtime.Unknown) error
 	Get(kind string, namespaceSet bool, namespace string, name string) (runtime.Object, error)
+	List(kind string, namespace string, labelSelector string) (runtime.Object, error)
 	Delete(kind string, namespaceSet bool, namespace string, name string) error
+	Create(kind string, namespaceSet bool, namespace string, object *runtime.Unknown) (runtime.Object, error)
+	Patch(kind string, namespaceSet bool, namespace string, name string, patchType types.PatchType, data []byte,
+		options metaV1.PatchOptions) (runtime.Object, error)
 }
 
 // CanIResponse is used to as response to check whether or not user is allowed to access given endpoint.
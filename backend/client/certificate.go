@@ -0,0 +1,90 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"net/http"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/args"
+	authApi "github.com/ycyxuehan/dashboard-gin/backend/auth/api"
+)
+
+const systemIdentityPrefix = "system:"
+
+// Returns user and groups of the client certificate verified by the TLS listener. Common name is used as user name
+// and organizations as groups. Certificates are only taken into account when client certificate authentication
+// mode is enabled. Certificates with reserved system users or groups are rejected, as dashboard would otherwise
+// impersonate them, e.g. system:masters, with its own permissions.
+func clientCertificateUser(req *http.Request) (string, []string, bool) {
+	if !authApi.ToAuthenticationModes(args.Holder.GetAuthenticationMode()).IsEnabled(authApi.ClientCertificate) {
+		return "", nil, false
+	}
+
+	if req == nil || req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+		return "", nil, false
+	}
+
+	subject := req.TLS.VerifiedChains[0][0].Subject
+	if len(subject.CommonName) == 0 || isSystemIdentity(subject.CommonName) {
+		return "", nil, false
+	}
+
+	for _, group := range subject.Organization {
+		if isSystemIdentity(group) {
+			return "", nil, false
+		}
+	}
+
+	return subject.CommonName, subject.Organization, true
+}
+
+// Users and groups prefixed with "system:" are reserved for Kubernetes components.
+func isSystemIdentity(name string) bool {
+	return strings.HasPrefix(name, systemIdentityPrefix)
+}
+
+// IsClientCertificateRequest returns true if given request is authenticated only by the verified client certificate,
+// i.e. it does not carry a bearer token or a JWE token. Browsers attach client certificates to every request, so such
+// requests have to be protected against CSRF.
+func IsClientCertificateRequest(req *http.Request) bool {
+	if req == nil || strings.HasPrefix(req.Header.Get("Authorization"), "Bearer ") ||
+		len(req.Header.Get(JWETokenHeader)) > 0 {
+		return false
+	}
+
+	_, _, ok := clientCertificateUser(req)
+	return ok
+}
+
+// Returns auth info that uses credentials of given config to impersonate given user and groups. Dashboard needs
+// permission to impersonate users and groups for requests made this way to succeed.
+func impersonatingAuthInfo(cfg *rest.Config, user string, groups []string) *api.AuthInfo {
+	return &api.AuthInfo{
+		Token:                 cfg.BearerToken,
+		TokenFile:             cfg.BearerTokenFile,
+		ClientCertificate:     cfg.TLSClientConfig.CertFile,
+		ClientCertificateData: cfg.TLSClientConfig.CertData,
+		ClientKey:             cfg.TLSClientConfig.KeyFile,
+		ClientKeyData:         cfg.TLSClientConfig.KeyData,
+		Username:              cfg.Username,
+		Password:              cfg.Password,
+		Impersonate:           user,
+		ImpersonateGroups:     groups,
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"log"
 	"strings"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
 	v1 "k8s.io/api/authorization/v1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -243,6 +244,41 @@ func (cm *clientManager) HasAccess(authInfo api.AuthInfo) error {
 	return err
 }
 
+// User returns the name of the user that makes given request. Impersonated users, users of basic authentication and
+// of client certificates are known from the request. Bearer tokens are resolved with a token review, so dashboard
+// needs the permission to create token reviews. Empty name is returned for requests that use privileges of the
+// dashboard itself.
+func (cm *clientManager) User(c *gin.Context) (string, error) {
+	if !cm.isSecureModeEnabled(c) {
+		return "", nil
+	}
+
+	authInfo, err := cm.extractAuthInfo(c)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case len(authInfo.Impersonate) > 0:
+		return authInfo.Impersonate, nil
+	case len(authInfo.Username) > 0:
+		return authInfo.Username, nil
+	case len(authInfo.Token) > 0:
+		review, err := cm.insecureClient.AuthenticationV1().TokenReviews().Create(context.TODO(),
+			&authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: authInfo.Token}},
+			metaV1.CreateOptions{})
+		if err != nil {
+			return "", err
+		}
+		if !review.Status.Authenticated {
+			return "", errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+		}
+		return review.Status.User.Username, nil
+	}
+
+	return "", errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+}
+
 // VerberClient returns new verber client based on authentication information extracted from request
 func (cm *clientManager) VerberClient(c *gin.Context, config *rest.Config) (clientapi.ResourceVerber, error) {
 	k8sClient, err := cm.Client(c)
@@ -275,6 +311,7 @@ func (cm *clientManager) VerberClient(c *gin.Context, config *rest.Config) (clie
 		k8sClient.StorageV1().RESTClient(),
 		k8sClient.RbacV1().RESTClient(),
 		k8sClient.NetworkingV1().RESTClient(),
+		k8sClient.PolicyV1beta1().RESTClient(),
 		apiextensionsRestClient,
 		pluginsclient.DashboardV1alpha1().RESTClient(),
 		config), nil
@@ -372,6 +409,11 @@ func (cm *clientManager) extractAuthInfo(c *gin.Context) (*api.AuthInfo, error)
 		return cm.tokenManager.Decrypt(jweToken)
 	}
 
+	// Verified client certificate is used only if user did not log in any other way
+	if user, groups, ok := clientCertificateUser(c.Request); ok {
+		return impersonatingAuthInfo(cm.insecureConfig, user, groups), nil
+	}
+
 	return nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
 }
 
@@ -380,7 +422,9 @@ func (cm *clientManager) containsAuthInfo(c *gin.Context) bool {
 	authHeader :=c.GetHeader("Authorization")
 	jweToken :=c.GetHeader(JWETokenHeader)
 
-	return len(authHeader) > 0 || len(jweToken) > 0
+	_, _, certificatePresent := clientCertificateUser(c.Request)
+
+	return len(authHeader) > 0 || len(jweToken) > 0 || certificatePresent
 }
 
 func (cm *clientManager) extractTokenFromHeader(authHeader string) string {
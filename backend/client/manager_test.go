@@ -16,13 +16,20 @@ package client
 
 import (
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"net/http"
+	"reflect"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ycyxuehan/dashboard-gin/backend/args"
 	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
 )
 
 func TestNewClientManager(t *testing.T) {
@@ -591,3 +598,135 @@ func TestImpersonationExtrasClient(t *testing.T) {
 		}
 	}
 }
+
+func TestClientCertificateConfig(t *testing.T) {
+	args.GetHolderBuilder().SetEnableSkipLogin(false)
+	defer args.GetHolderBuilder().SetAuthenticationMode(nil)
+
+	verified := func(subject pkix.Name) *tls.ConnectionState {
+		return &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: subject}}}}
+	}
+
+	cases := []struct {
+		modes          []string
+		request        *gin.Context
+		expectedUser   string
+		expectedGroups []string
+		expectedErr    bool
+	}{
+		{
+			[]string{"token", "clientcertificate"},
+			&gin.Context{Request: &http.Request{
+				Header: http.Header{},
+				TLS:    verified(pkix.Name{CommonName: "admin", Organization: []string{"ops", "audit"}}),
+			}},
+			"admin", []string{"ops", "audit"}, false,
+		},
+		{
+			[]string{"token", "clientcertificate"},
+			&gin.Context{Request: &http.Request{
+				Header: http.Header{"Authorization": {"Bearer test-token"}},
+				TLS:    verified(pkix.Name{CommonName: "admin"}),
+			}},
+			"", nil, false,
+		},
+		{
+			[]string{"token", "clientcertificate"},
+			&gin.Context{Request: &http.Request{
+				Header: http.Header{},
+				TLS:    verified(pkix.Name{Organization: []string{"ops"}}),
+			}},
+			"", nil, true,
+		},
+		{
+			[]string{"token"},
+			&gin.Context{Request: &http.Request{
+				Header: http.Header{},
+				TLS:    verified(pkix.Name{CommonName: "admin"}),
+			}},
+			"", nil, true,
+		},
+		{
+			[]string{"token", "clientcertificate"},
+			&gin.Context{Request: &http.Request{
+				Header: http.Header{},
+				TLS:    verified(pkix.Name{CommonName: "admin", Organization: []string{"ops", "system:masters"}}),
+			}},
+			"", nil, true,
+		},
+		{
+			[]string{"token", "clientcertificate"},
+			&gin.Context{Request: &http.Request{
+				Header: http.Header{},
+				TLS:    verified(pkix.Name{CommonName: "system:admin"}),
+			}},
+			"", nil, true,
+		},
+	}
+
+	for _, c := range cases {
+		args.GetHolderBuilder().SetAuthenticationMode(c.modes)
+		manager := NewClientManager("", "https://localhost:8080")
+		cfg, err := manager.Config(c.request)
+
+		if (err != nil) != c.expectedErr {
+			t.Fatalf("Config(%v): Expected error %t but got %v", c.request, c.expectedErr, err)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if cfg.Impersonate.UserName != c.expectedUser {
+			t.Fatalf("Config(%v): Expected impersonated user %q but got %q",
+				c.request, c.expectedUser, cfg.Impersonate.UserName)
+		}
+
+		if !reflect.DeepEqual(cfg.Impersonate.Groups, c.expectedGroups) {
+			t.Fatalf("Config(%v): Expected impersonated groups %v but got %v",
+				c.request, c.expectedGroups, cfg.Impersonate.Groups)
+		}
+	}
+}
+
+func TestUser(t *testing.T) {
+	args.GetHolderBuilder().SetEnableSkipLogin(false)
+	args.GetHolderBuilder().SetAuthenticationMode([]string{"token", "clientcertificate"})
+	defer args.GetHolderBuilder().SetAuthenticationMode(nil)
+
+	manager := NewClientManager("", "https://localhost:8080").(*clientManager)
+	insecureClient := fake.NewSimpleClientset()
+	insecureClient.PrependReactor("create", "tokenreviews",
+		func(action clienttesting.Action) (bool, runtime.Object, error) {
+			review := action.(clienttesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+			review.Status.Authenticated = review.Spec.Token == "valid-token"
+			review.Status.User.Username = "token-user"
+			return true, review, nil
+		})
+	manager.insecureClient = insecureClient
+
+	cases := []struct {
+		header      http.Header
+		state       *tls.ConnectionState
+		expected    string
+		expectedErr bool
+	}{
+		{http.Header{"Authorization": {"Bearer valid-token"}}, &tls.ConnectionState{}, "token-user", false},
+		{http.Header{"Authorization": {"Bearer other-token"}}, &tls.ConnectionState{}, "", true},
+		{http.Header{"Authorization": {"Bearer valid-token"}, "Impersonate-User": {"bob"}},
+			&tls.ConnectionState{}, "bob", false},
+		{http.Header{}, &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{
+			{Subject: pkix.Name{CommonName: "admin"}}}}}, "admin", false},
+		{http.Header{}, &tls.ConnectionState{}, "", true},
+	}
+
+	for _, c := range cases {
+		user, err := manager.User(&gin.Context{Request: &http.Request{Header: c.header, TLS: c.state}})
+		if (err != nil) != c.expectedErr {
+			t.Fatalf("User(%v): Expected error %t but got %v", c.header, c.expectedErr, err)
+		}
+		if user != c.expected {
+			t.Fatalf("User(%v): Expected user %q but got %q", c.header, c.expected, user)
+		}
+	}
+}
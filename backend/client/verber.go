@@ -17,11 +17,13 @@ package client
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	restclient "k8s.io/client-go/rest"
 
 	"github.com/ycyxuehan/dashboard-gin/backend/api"
@@ -30,6 +32,9 @@ import (
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/customresourcedefinition"
 )
 
+// DefaultFieldManager is the field manager recorded by server-side apply when the caller did not name one.
+const DefaultFieldManager = "dashboard"
+
 // resourceVerber is a struct responsible for doing common verb operations on resources, like
 // DELETE, PUT, UPDATE.
 type resourceVerber struct {
@@ -42,6 +47,7 @@ type resourceVerber struct {
 	storageClient       RESTClient
 	rbacClient          RESTClient
 	networkingClient    RESTClient
+	policyClient        RESTClient
 	apiExtensionsClient RESTClient
 	pluginsClient       RESTClient
 	config              *restclient.Config
@@ -72,6 +78,8 @@ func (verber *resourceVerber) getRESTClientByType(clientType api.ClientType) RES
 		return verber.rbacClient
 	case api.ClientTypeNetworkingClient:
 		return verber.networkingClient
+	case api.ClientTypePolicyClient:
+		return verber.policyClient
 	case api.ClientTypeAPIExtensionsClient:
 		return verber.apiExtensionsClient
 	case api.ClientTypePluginsClient:
@@ -82,6 +90,24 @@ func (verber *resourceVerber) getRESTClientByType(clientType api.ClientType) RES
 }
 
 func (verber *resourceVerber) getResourceSpecFromKind(kind string, namespaceSet bool) (client RESTClient, resourceSpec api.APIMapping, err error) {
+	client, resourceSpec, err = verber.resolveResourceSpec(kind)
+	if err != nil {
+		return
+	}
+
+	if namespaceSet != resourceSpec.Namespaced {
+		if namespaceSet {
+			err = errors.NewInvalid(fmt.Sprintf("Set namespace for not-namespaced resource kind: %s", kind))
+			return
+		}
+		err = errors.NewInvalid(fmt.Sprintf("Set no namespace for namespaced resource kind: %s", kind))
+	}
+	return
+}
+
+// resolveResourceSpec returns the client and API mapping of the given kind. Kinds that are not built in are looked
+// up as custom resource definitions.
+func (verber *resourceVerber) resolveResourceSpec(kind string) (client RESTClient, resourceSpec api.APIMapping, err error) {
 	resourceSpec, ok := api.KindToAPIMapping[kind]
 	if !ok {
 		var crdInfo crdInfo
@@ -103,15 +129,6 @@ func (verber *resourceVerber) getResourceSpecFromKind(kind string, namespaceSet
 		}
 	}
 
-	if namespaceSet != resourceSpec.Namespaced {
-		if namespaceSet {
-			err = errors.NewInvalid(fmt.Sprintf("Set namespace for not-namespaced resource kind: %s", kind))
-			return
-		}
-		err = errors.NewInvalid(fmt.Sprintf("Set no namespace for namespaced resource kind: %s", kind))
-		return
-	}
-
 	if client == nil {
 		client = verber.getRESTClientByType(resourceSpec.ClientType)
 	}
@@ -164,12 +181,14 @@ type RESTClient interface {
 	Delete() *restclient.Request
 	Put() *restclient.Request
 	Get() *restclient.Request
+	Post() *restclient.Request
+	Patch(pt types.PatchType) *restclient.Request
 }
 
 // NewResourceVerber creates a new resource verber that uses the given client for performing operations.
-func NewResourceVerber(client, extensionsClient, appsClient, batchClient, betaBatchClient, autoscalingClient, storageClient, rbacClient, networkingClient, apiExtensionsClient, pluginsClient RESTClient, config *restclient.Config) clientapi.ResourceVerber {
+func NewResourceVerber(client, extensionsClient, appsClient, batchClient, betaBatchClient, autoscalingClient, storageClient, rbacClient, networkingClient, policyClient, apiExtensionsClient, pluginsClient RESTClient, config *restclient.Config) clientapi.ResourceVerber {
 	return &resourceVerber{client, extensionsClient, appsClient,
-		batchClient, betaBatchClient, autoscalingClient, storageClient, rbacClient, networkingClient, apiExtensionsClient, pluginsClient, config}
+		batchClient, betaBatchClient, autoscalingClient, storageClient, rbacClient, networkingClient, policyClient, apiExtensionsClient, pluginsClient, config}
 }
 
 // Delete deletes the resource of the given kind in the given namespace with the given name.
@@ -233,3 +252,109 @@ func (verber *resourceVerber) Get(kind string, namespaceSet bool, namespace stri
 	err = req.Do(context.TODO()).Into(result)
 	return result, err
 }
+
+// Create creates a new resource of the given kind in the given namespace. The name is taken from the object.
+func (verber *resourceVerber) Create(kind string, namespaceSet bool, namespace string,
+	object *runtime.Unknown) (runtime.Object, error) {
+
+	client, resourceSpec, err := verber.getResourceSpecFromKind(kind, namespaceSet)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &runtime.Unknown{}
+	req := client.Post().
+		Resource(resourceSpec.Resource).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Accept", "application/json").
+		Body([]byte(object.Raw))
+
+	if resourceSpec.Namespaced {
+		req.Namespace(namespace)
+	}
+
+	err = req.Do(context.TODO()).Into(result)
+	return result, err
+}
+
+// Patch applies the patch of the given type to the resource of the given kind in the given namespace with the
+// given name. Supported are JSON merge patches, strategic merge patches and server-side apply. Strategic merge
+// patches are not supported by custom resources.
+func (verber *resourceVerber) Patch(kind string, namespaceSet bool, namespace string, name string,
+	patchType types.PatchType, data []byte, options v1.PatchOptions) (runtime.Object, error) {
+
+	switch patchType {
+	case types.MergePatchType, types.ApplyPatchType:
+	case types.StrategicMergePatchType:
+		if _, ok := api.KindToAPIMapping[kind]; !ok {
+			return nil, errors.NewInvalid(fmt.Sprintf("Strategic merge patch is not supported for resource kind: %s",
+				kind))
+		}
+	default:
+		return nil, errors.NewInvalid(fmt.Sprintf("Unsupported patch type: %s", patchType))
+	}
+
+	if patchType == types.ApplyPatchType {
+		if len(options.FieldManager) == 0 {
+			options.FieldManager = DefaultFieldManager
+		}
+	} else if options.Force != nil {
+		return nil, errors.NewInvalid("Force can only be set for server-side apply")
+	}
+
+	client, resourceSpec, err := verber.getResourceSpecFromKind(kind, namespaceSet)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &runtime.Unknown{}
+	req := client.Patch(patchType).
+		Resource(resourceSpec.Resource).
+		Name(name).
+		SetHeader("Accept", "application/json").
+		Body(data)
+
+	if resourceSpec.Namespaced {
+		req.Namespace(namespace)
+	}
+
+	// Parameters are set directly, as custom resource groups are not registered in the parameter codec scheme.
+	if len(options.FieldManager) > 0 {
+		req.Param("fieldManager", options.FieldManager)
+	}
+	if options.Force != nil {
+		req.Param("force", strconv.FormatBool(*options.Force))
+	}
+	for _, dryRun := range options.DryRun {
+		req.Param("dryRun", dryRun)
+	}
+
+	err = req.Do(context.TODO()).Into(result)
+	return result, err
+}
+
+// List lists resources of the given kind matching the label selector. Namespaced kinds are listed in all
+// namespaces if no namespace is given.
+func (verber *resourceVerber) List(kind string, namespace string, labelSelector string) (runtime.Object, error) {
+	client, resourceSpec, err := verber.resolveResourceSpec(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resourceSpec.Namespaced && len(namespace) > 0 {
+		return nil, errors.NewInvalid(fmt.Sprintf("Set namespace for not-namespaced resource kind: %s", kind))
+	}
+
+	result := &runtime.Unknown{}
+	req := client.Get().Resource(resourceSpec.Resource).SetHeader("Accept", "application/json")
+
+	if len(namespace) > 0 {
+		req.Namespace(namespace)
+	}
+	if len(labelSelector) > 0 {
+		req.Param("labelSelector", labelSelector)
+	}
+
+	err = req.Do(context.TODO()).Into(result)
+	return result, err
+}
@@ -24,6 +24,7 @@ import (
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/rest/fake"
@@ -71,6 +72,13 @@ func (c *FakeRESTClient) Get() *restclient.Request {
 	return restclient.NewRequestWithClient(&url.URL{Path: "/api/v1/"}, "", restclient.ClientContentConfig{}, fake.CreateHTTPClient(NewFakeClientFunc(c))).Verb("GET")
 }
 
+func (c *FakeRESTClient) Post() *restclient.Request {
+	return restclient.NewRequestWithClient(&url.URL{Path: "/api/v1/"}, "", restclient.ClientContentConfig{}, fake.CreateHTTPClient(NewFakeClientFunc(c))).Verb("POST")
+}
+
+func (c *FakeRESTClient) Patch(pt types.PatchType) *restclient.Request {
+	return restclient.NewRequestWithClient(&url.URL{Path: "/api/v1/"}, "", restclient.ClientContentConfig{}, fake.CreateHTTPClient(NewFakeClientFunc(c))).Verb("PATCH").SetHeader("Content-Type", string(pt))
+}
 
 // Removes all quote signs that might have been added to the message.
 // Might depend on dependencies version how they are constructed.
@@ -228,3 +236,114 @@ func TestDeleteShouldRespectNotNamespacednessOfResourceKind(t *testing.T) {
 		t.Fatalf("Expected error on verber delete but got %#v", err)
 	}
 }
+
+func TestCreateShouldPropagateErrorsAndChooseClient(t *testing.T) {
+	verber := resourceVerber{
+		client:     &FakeRESTClient{err: errors.NewInvalid("err")},
+		appsClient: &FakeRESTClient{err: errors.NewInvalid("err from apps")},
+	}
+	object := &runtime.Unknown{Raw: []byte(`{"metadata":{"name":"baz"}}`)}
+
+	_, err := verber.Create("deployment", true, "bar", object)
+
+	if !reflect.DeepEqual(normalize(err.Error()), "Post /api/v1/namespaces/bar/deployments: err from apps") {
+		t.Fatalf("Expected error on verber create but got %#v", err.Error())
+	}
+
+	_, err = verber.Create("namespace", false, "", object)
+
+	if !reflect.DeepEqual(normalize(err.Error()), "Post /api/v1/namespaces: err") {
+		t.Fatalf("Expected error on verber create but got %#v", err.Error())
+	}
+}
+
+func TestCreateShouldThrowErrorOnUnknownResourceKind(t *testing.T) {
+	verber := resourceVerber{
+		client:              &FakeRESTClient{},
+		apiExtensionsClient: &FakeRESTClient{err: errors.NewNotFound("err")},
+	}
+
+	_, err := verber.Create("foo", true, "bar", nil)
+
+	if !reflect.DeepEqual(normalize(err.Error()), "Get /api/v1/customresourcedefinitions/foo: err") {
+		t.Fatalf("Expected error on verber create but got %#v", err.Error())
+	}
+}
+
+func TestPatchShouldPropagateErrorsAndChooseClient(t *testing.T) {
+	verber := resourceVerber{
+		client:     &FakeRESTClient{err: errors.NewInvalid("err")},
+		appsClient: &FakeRESTClient{err: errors.NewInvalid("err from apps")},
+	}
+	data := []byte(`{"metadata":{"labels":{"foo":"bar"}}}`)
+
+	_, err := verber.Patch("statefulset", true, "bar", "baz", types.MergePatchType, data, metaV1.PatchOptions{})
+
+	if !reflect.DeepEqual(normalize(err.Error()), "Patch /api/v1/namespaces/bar/statefulsets/baz: err from apps") {
+		t.Fatalf("Expected error on verber patch but got %#v", err.Error())
+	}
+
+	_, err = verber.Patch("service", true, "bar", "baz", types.ApplyPatchType, data, metaV1.PatchOptions{})
+
+	if !reflect.DeepEqual(normalize(err.Error()),
+		"Patch /api/v1/namespaces/bar/services/baz?fieldManager=dashboard: err") {
+		t.Fatalf("Expected error on verber patch but got %#v", err.Error())
+	}
+}
+
+func TestPatchShouldValidatePatchType(t *testing.T) {
+	force := true
+	cases := []struct {
+		kind      string
+		patchType types.PatchType
+		options   metaV1.PatchOptions
+		expected  error
+	}{
+		{
+			"foo", types.StrategicMergePatchType, metaV1.PatchOptions{},
+			errors.NewInvalid("Strategic merge patch is not supported for resource kind: foo"),
+		},
+		{
+			"service", types.JSONPatchType, metaV1.PatchOptions{},
+			errors.NewInvalid("Unsupported patch type: application/json-patch+json"),
+		},
+		{
+			"service", types.MergePatchType, metaV1.PatchOptions{Force: &force},
+			errors.NewInvalid("Force can only be set for server-side apply"),
+		},
+	}
+
+	verber := resourceVerber{client: &FakeRESTClient{}}
+	for _, c := range cases {
+		_, err := verber.Patch(c.kind, true, "bar", "baz", c.patchType, []byte("{}"), c.options)
+		if !reflect.DeepEqual(err, c.expected) {
+			t.Errorf("Patch(%s, %s) == %#v, expected %#v", c.kind, c.patchType, err, c.expected)
+		}
+	}
+}
+
+func TestListShouldPropagateErrorsAndChooseClient(t *testing.T) {
+	verber := resourceVerber{
+		client:     &FakeRESTClient{err: errors.NewInvalid("err")},
+		appsClient: &FakeRESTClient{err: errors.NewInvalid("err from apps")},
+	}
+
+	_, err := verber.List("deployment", "bar", "app=web")
+
+	if !reflect.DeepEqual(normalize(err.Error()),
+		"Get /api/v1/namespaces/bar/deployments?labelSelector=app%3Dweb: err from apps") {
+		t.Fatalf("Expected error on verber list but got %#v", err.Error())
+	}
+
+	_, err = verber.List("deployment", "", "")
+
+	if !reflect.DeepEqual(normalize(err.Error()), "Get /api/v1/deployments: err from apps") {
+		t.Fatalf("Expected error on verber list but got %#v", err.Error())
+	}
+
+	_, err = verber.List("namespace", "bar", "")
+
+	if !reflect.DeepEqual(err, errors.NewInvalid("Set namespace for not-namespaced resource kind: namespace")) {
+		t.Fatalf("Expected error on verber list but got %#v", err)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -34,6 +35,7 @@ import (
 	authApi "github.com/ycyxuehan/dashboard-gin/backend/auth/api"
 	"github.com/ycyxuehan/dashboard-gin/backend/auth/jwe"
 	"github.com/ycyxuehan/dashboard-gin/backend/cert"
+	certapi "github.com/ycyxuehan/dashboard-gin/backend/cert/api"
 	"github.com/ycyxuehan/dashboard-gin/backend/cert/ecdsa"
 	"github.com/ycyxuehan/dashboard-gin/backend/client"
 	clientapi "github.com/ycyxuehan/dashboard-gin/backend/client/api"
@@ -53,6 +55,7 @@ var (
 	argDefaultCertDir      = pflag.String("default-cert-dir", "/certs", "Directory path containing '--tls-cert-file' and '--tls-key-file' files. Used also when auto-generating certificates flag is set.")
 	argCertFile            = pflag.String("tls-cert-file", "", "File containing the default x509 Certificate for HTTPS.")
 	argKeyFile             = pflag.String("tls-key-file", "", "File containing the default x509 private key matching --tls-cert-file.")
+	argClientCAFile        = pflag.String("client-ca-file", "", "File containing PEM encoded CA bundle used to verify client certificates. Required by 'clientcertificate' authentication mode.")
 	argApiserverHost       = pflag.String("apiserver-host", "", "The address of the Kubernetes Apiserver "+
 		"to connect to in the format of protocol://address:port, e.g., "+
 		"http://localhost:8080. If not specified, the assumption is that the binary runs inside a "+
@@ -68,7 +71,7 @@ var (
 		"Kubernetes cluster and service proxy will be used.")
 	argKubeConfigFile     = pflag.String("kubeconfig", "", "Path to kubeconfig file with authorization and master location information.")
 	argTokenTTL           = pflag.Int("token-ttl", int(authApi.DefaultTokenTTL), "Expiration time (in seconds) of JWE tokens generated by dashboard. '0' never expires")
-	argAuthenticationMode = pflag.StringSlice("authentication-mode", []string{authApi.Token.String()}, "Enables authentication options that will be reflected on login screen. Supported values: token, basic. "+
+	argAuthenticationMode = pflag.StringSlice("authentication-mode", []string{authApi.Token.String()}, "Enables authentication options that will be reflected on login screen. Supported values: token, basic, clientcertificate. "+
 		"Note that basic option should only be used if apiserver has '--authorization-mode=ABAC' and '--basic-auth-file' flags set.")
 	argMetricClientCheckPeriod   = pflag.Int("metric-client-check-period", 30, "Time in seconds that defines how often configured metric client health check should be run.")
 	argAutoGenerateCertificates  = pflag.Bool("auto-generate-certificates", false, "When set to true, Dashboard will automatically generate certificates used to serve HTTPS. (default false)")
@@ -151,24 +154,37 @@ func main() {
 		handleFatalInitError(err)
 	}
 
-	var servingCerts []tls.Certificate
+	var certHolder certapi.Holder
+	var certFileName, keyFileName string
 	if args.Holder.GetAutoGenerateCertificates() {
 		log.Println("Auto-generating certificates")
 		certCreator := ecdsa.NewECDSACreator(args.Holder.GetKeyFile(), args.Holder.GetCertFile(), elliptic.P256())
 		certManager := cert.NewCertManager(certCreator, args.Holder.GetDefaultCertDir())
-		servingCert, err := certManager.GetCertificates()
+		certHolder, err = cert.NewCertHolder(certManager.GetCertificates)
 		if err != nil {
 			handleFatalInitServingCertError(err)
 		}
-		servingCerts = []tls.Certificate{servingCert}
+		certFileName, keyFileName = certCreator.GetCertFileName(), certCreator.GetKeyFileName()
 	} else if args.Holder.GetCertFile() != "" && args.Holder.GetKeyFile() != "" {
 		certFilePath := args.Holder.GetDefaultCertDir() + string(os.PathSeparator) + args.Holder.GetCertFile()
 		keyFilePath := args.Holder.GetDefaultCertDir() + string(os.PathSeparator) + args.Holder.GetKeyFile()
-		servingCert, err := tls.LoadX509KeyPair(certFilePath, keyFilePath)
+		certHolder, err = cert.NewCertHolder(func() (tls.Certificate, error) {
+			return tls.LoadX509KeyPair(certFilePath, keyFilePath)
+		})
 		if err != nil {
 			handleFatalInitServingCertError(err)
 		}
-		servingCerts = []tls.Certificate{servingCert}
+		certFileName, keyFileName = filepath.Base(certFilePath), filepath.Base(keyFilePath)
+	}
+
+	if certHolder != nil {
+		// Reload certificates from files, rotate generated ones and follow changes of the certificate secret, so
+		// that renewed certificates are used without restarting and dropping active connections.
+		certHolder.Start(certapi.ReloadInterval)
+		certSynchronizer := sync.NewSynchronizerManager(clientManager.InsecureClient()).
+			Secret(args.Holder.GetNamespace(), authApi.CertificateHolderSecretName)
+		cert.WatchSecret(certHolder, certSynchronizer, certFileName, keyFileName)
+		sync.Overwatch.RegisterSynchronizer(certSynchronizer, sync.AlwaysRestart)
 	}
 
 	// Run a HTTP server that serves static public files from './public' and handles API calls.
@@ -179,19 +195,35 @@ func main() {
 	http.Handle("/metrics", promhttp.Handler())
 
 	// Listen for http or https
-	if servingCerts != nil {
+	clientCertificateAuth := authApi.ToAuthenticationModes(args.Holder.GetAuthenticationMode()).
+		IsEnabled(authApi.ClientCertificate)
+	if certHolder != nil {
 		log.Printf("Serving securely on HTTPS port: %d", args.Holder.GetPort())
 		secureAddr := fmt.Sprintf("%s:%d", args.Holder.GetBindAddress(), args.Holder.GetPort())
+		tlsConfig := &tls.Config{
+			GetCertificate: certHolder.GetCertificate,
+			MinVersion:     tls.VersionTLS12,
+		}
+		if clientCertificateAuth {
+			// Client certificates are optional, so that other authentication modes keep working on the same port.
+			clientCAs, err := cert.LoadClientCAs(args.Holder.GetClientCAFile())
+			if err != nil {
+				handleFatalInitServingCertError(err)
+			}
+			tlsConfig.ClientCAs = clientCAs
+			tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+			log.Printf("Client certificate authentication enabled, using CA bundle: %s", args.Holder.GetClientCAFile())
+		}
 		server := &http.Server{
-			Addr:    secureAddr,
-			Handler: http.DefaultServeMux,
-			TLSConfig: &tls.Config{
-				Certificates: servingCerts,
-				MinVersion:   tls.VersionTLS12,
-			},
+			Addr:      secureAddr,
+			Handler:   http.DefaultServeMux,
+			TLSConfig: tlsConfig,
 		}
 		go func() { log.Fatal(server.ListenAndServeTLS("", "")) }()
 	} else {
+		if clientCertificateAuth {
+			log.Print("Client certificate authentication requires HTTPS and will not be available")
+		}
 		log.Printf("Serving insecurely on HTTP port: %d", args.Holder.GetInsecurePort())
 		addr := fmt.Sprintf("%s:%d", args.Holder.GetInsecureBindAddress(), args.Holder.GetInsecurePort())
 		go func() { log.Fatal(http.ListenAndServe(addr, nil)) }()
@@ -241,6 +273,7 @@ func initArgHolder() {
 	builder.SetDefaultCertDir(*argDefaultCertDir)
 	builder.SetCertFile(*argCertFile)
 	builder.SetKeyFile(*argKeyFile)
+	builder.SetClientCAFile(*argClientCAFile)
 	builder.SetApiServerHost(*argApiserverHost)
 	builder.SetMetricsProvider(*argMetricsProvider)
 	builder.SetHeapsterHost(*argHeapsterHost)
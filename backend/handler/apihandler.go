@@ -15,13 +15,21 @@
 package handler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
 	"log"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/networkpolicy"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/permission"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/poddisruptionbudget"
 	"github.com/ycyxuehan/dashboard-gin/backend/utils/httphelper"
 
 	"github.com/ycyxuehan/dashboard-gin/backend/handler/parser"
@@ -30,19 +38,30 @@ import (
 	"github.com/ycyxuehan/dashboard-gin/backend/plugin"
 
 	"golang.org/x/net/xsrftoken"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/tools/remotecommand"
 
 	"github.com/ycyxuehan/dashboard-gin/backend/api"
 	"github.com/ycyxuehan/dashboard-gin/backend/auth"
 	authApi "github.com/ycyxuehan/dashboard-gin/backend/auth/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/bulk"
+	"github.com/ycyxuehan/dashboard-gin/backend/client"
 	clientapi "github.com/ycyxuehan/dashboard-gin/backend/client/api"
 	"github.com/ycyxuehan/dashboard-gin/backend/errors"
 	"github.com/ycyxuehan/dashboard-gin/backend/integration"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/capacity"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/certificate"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/cluster"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/clusterrole"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/clusterrolebinding"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/config"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/configmap"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/connectivity"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/container"
 	// "github.com/ycyxuehan/dashboard-gin/backend/resource/controller"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/cronjob"
@@ -50,6 +69,8 @@ import (
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/daemonset"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/deployment"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/diagnosis"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/discovery"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/event"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/horizontalpodautoscaler"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/ingress"
@@ -64,11 +85,13 @@ import (
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/replicationcontroller"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/role"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/rolebinding"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/search"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/secret"
 	resourceService "github.com/ycyxuehan/dashboard-gin/backend/resource/service"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/serviceaccount"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/statefulset"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/storageclass"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/workload"
 	"github.com/ycyxuehan/dashboard-gin/backend/scaling"
 	"github.com/ycyxuehan/dashboard-gin/backend/settings"
 	settingsApi "github.com/ycyxuehan/dashboard-gin/backend/settings/api"
@@ -107,9 +130,8 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 
 	// apiV1Ws := new(restful.WebService)
 
-	InstallFilters(e, cManager)
-
 	r := e.Group("/api/v1")
+	InstallFilters(r, cManager)
 
 	integrationHandler := integration.NewIntegrationHandler(iManager)
 	integrationHandler.Install(r)
@@ -137,6 +159,26 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 	appdeploymentValidateGroup.POST("/imagereference", apiHandler.handleImageReferenceValidity)
 	appdeploymentValidateGroup.POST("/protocol", apiHandler.handleProtocolValidity)
 	r.GET("/appdeploymentfromfile", apiHandler.handleDeployFromFile)
+
+	workloadGroup := r.Group("/workload")
+	workloadGroup.GET("/", apiHandler.handleGetWorkloads)
+	workloadGroup.GET("/:namespace", apiHandler.handleGetWorkloads)
+
+	discoveryGroup := r.Group("/discovery")
+	discoveryGroup.GET("/", apiHandler.handleGetDiscovery)
+	discoveryGroup.GET("/:namespace", apiHandler.handleGetDiscovery)
+
+	configGroup := r.Group("/config")
+	configGroup.GET("/", apiHandler.handleGetConfig)
+	configGroup.GET("/:namespace", apiHandler.handleGetConfig)
+
+	r.GET("/cluster", apiHandler.handleGetCluster)
+	r.GET("/capacity", apiHandler.handleGetCapacity)
+	r.POST("/capacity/fit", apiHandler.handleGetCapacityFit)
+
+	r.POST("/bulk", apiHandler.handleBulkAction)
+
+	r.GET("/diagnosis/:kind/:namespace/:name", apiHandler.handleGetDiagnosis)
 	
 	replicationcontrollerGroup := r.Group("/replicationcontroller")
 	replicationcontrollerGroup.GET("/", apiHandler.handleGetReplicationControllerList)
@@ -163,6 +205,9 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 	podGroup.GET("/:namespace/:pod/event", apiHandler.handleGetPodEvents)
 	podGroup.GET("/:namespace/:pod/shell/:container", apiHandler.handleExecShell)
 	podGroup.GET("/:namespace/:pod/persistentvolumeclaim", apiHandler.handleGetPodPersistentVolumeClaims)
+	podGroup.DELETE("/:namespace/:pod", apiHandler.handleDeletePod)
+	podGroup.POST("/:namespace/:pod/evict", apiHandler.handleEvictPod)
+	podGroup.POST("/:namespace/:pod/restart", apiHandler.handleRestartPod)
 
 	deploymentGroup := r.Group("/deployment")
 	deploymentGroup.GET("/", apiHandler.handleGetDeployments)
@@ -192,12 +237,18 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 	horizontalpodautoscalerGroup.GET("/:namespace", apiHandler.handleGetHorizontalPodAutoscalerList)
 	horizontalpodautoscalerGroup.GET("/:namespace/:horizontalpodautoscaler", apiHandler.handleGetHorizontalPodAutoscalerDetail)
 	horizontalpodautoscalerGroup.GET("/:namespace/:horizontalpodautoscaler/:kind", apiHandler.handleGetHorizontalPodAutoscalerListForResource)
+	horizontalpodautoscalerGroup.POST("/:namespace", apiHandler.handleCreateHorizontalPodAutoscaler)
+	horizontalpodautoscalerGroup.PUT("/:namespace/:horizontalpodautoscaler", apiHandler.handleUpdateHorizontalPodAutoscaler)
+	horizontalpodautoscalerGroup.DELETE("/:namespace/:horizontalpodautoscaler", apiHandler.handleDeleteHorizontalPodAutoscaler)
 	jobGroup := r.Group("/job")
 	jobGroup.GET("/", apiHandler.handleGetJobList)
 	jobGroup.GET("/:namespace", apiHandler.handleGetJobList)
 	jobGroup.GET("/:namespace/:name", apiHandler.handleGetJobDetail)
 	jobGroup.GET("/:namespace/:name/pod", apiHandler.handleGetJobPods)
 	jobGroup.GET("/:namespace/:name/event", apiHandler.handleGetJobEvents)
+	jobGroup.GET("/:namespace/:name/run", apiHandler.handleGetJobRun)
+	jobGroup.DELETE("/:namespace/finished", apiHandler.handleDeleteFinishedJobs)
+	jobGroup.POST("/:namespace/:name/rerun", apiHandler.handleRerunJob)
 
 	cronjobGroup := r.Group("/cronjob")
 	cronjobGroup.GET("/", apiHandler.handleGetCronJobList)
@@ -205,7 +256,11 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 	cronjobGroup.GET("/:namespace/:name", apiHandler.handleGetCronJobDetail)
 	cronjobGroup.GET("/:namespace/:name/job", apiHandler.handleGetCronJobJobs)
 	cronjobGroup.GET("/:namespace/:name/event", apiHandler.handleGetCronJobEvents)
-	cronjobGroup.GET("/:namespace/:name/trigger", apiHandler.handleTriggerCronJob)
+	cronjobGroup.GET("/:namespace/:name/history", apiHandler.handleGetCronJobHistory)
+	cronjobGroup.POST("/:namespace/:name/trigger", apiHandler.handleTriggerCronJob)
+	cronjobGroup.POST("/:namespace/:name/suspend", apiHandler.handleSuspendCronJob)
+	cronjobGroup.POST("/:namespace/:name/resume", apiHandler.handleResumeCronJob)
+	cronjobGroup.PUT("/:namespace/:name/schedule", apiHandler.handleUpdateCronJobSchedule)
 	
 	namespaceGroup := r.Group("/namespace")
 	namespaceGroup.POST("/", apiHandler.handleCreateNamespace)
@@ -217,12 +272,23 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 	secretGroup.POST("/", apiHandler.handleCreateImagePullSecret)
 	secretGroup.GET("/", apiHandler.handleGetSecretList)
 	secretGroup.GET("/:namespace", apiHandler.handleGetSecretList)
+	secretGroup.POST("/:type", apiHandler.handleCreateSecret)
 	secretGroup.GET("/:namespace/:name", apiHandler.handleGetSecretDetail)
+	secretGroup.GET("/:namespace/:name/key/:key", apiHandler.handleRevealSecretKey)
+	secretGroup.PUT("/:namespace/:name/key/:key", apiHandler.handleUpdateSecretKey)
+	secretGroup.DELETE("/:namespace/:name/key/:key", apiHandler.handleDeleteSecretKey)
+
+	certificateGroup := r.Group("/certificate")
+	certificateGroup.GET("/", apiHandler.handleGetCertificateList)
+	certificateGroup.GET("/:namespace", apiHandler.handleGetCertificateList)
 
 	configmapGroup := r.Group("/configmap")
 	configmapGroup.GET("/", apiHandler.handleGetConfigMapList)
 	configmapGroup.GET("/:namespace", apiHandler.handleGetConfigMapList)
 	configmapGroup.GET("/:namespace/:configmap", apiHandler.handleGetConfigMapDetail)
+	configmapGroup.POST("/:namespace/:configmap/key/:key", apiHandler.handleCreateConfigMapKey)
+	configmapGroup.PUT("/:namespace/:configmap/key/:key", apiHandler.handleUpdateConfigMapKey)
+	configmapGroup.DELETE("/:namespace/:configmap/key/:key", apiHandler.handleDeleteConfigMapKey)
 
 	serviceGroup := r.Group("/service")
 	serviceGroup.GET("/", apiHandler.handleGetServiceList)
@@ -247,6 +313,18 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 	networkpolicyGroup.GET("/", apiHandler.handleGetNetworkPolicyList)
 	networkpolicyGroup.GET("/:namespace", apiHandler.handleGetNetworkPolicyList)
 	networkpolicyGroup.GET("/:namespace/:networkpolicy", apiHandler.handleGetNetworkPolicyDetail)
+	networkpolicyGroup.POST("/simulation", apiHandler.handleSimulateNetworkPolicies)
+	r.GET("/networkisolation/:namespace", apiHandler.handleGetNetworkIsolation)
+
+	connectivityGroup := r.Group("/connectivity")
+	connectivityGroup.GET("/:namespace", apiHandler.handleGetConnectivityGraph)
+	connectivityGroup.GET("/:namespace/dot", apiHandler.handleGetConnectivityGraphDOT)
+
+	podDisruptionBudgetGroup := r.Group("/poddisruptionbudget")
+	podDisruptionBudgetGroup.GET("/", apiHandler.handleGetPodDisruptionBudgetList)
+	podDisruptionBudgetGroup.GET("/:namespace", apiHandler.handleGetPodDisruptionBudgetList)
+	podDisruptionBudgetGroup.GET("/:namespace/:poddisruptionbudget", apiHandler.handleGetPodDisruptionBudgetDetail)
+	podDisruptionBudgetGroup.GET("/:namespace/:poddisruptionbudget/pod", apiHandler.handleGetPodDisruptionBudgetPods)
 
 	statefulsetGroup := r.Group("/statefulset")
 	statefulsetGroup.GET("/", apiHandler.handleGetStatefulSetList)
@@ -261,31 +339,64 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 	nodeGroup.GET("/:namespace/:name", apiHandler.handleGetNodeDetail)
 	nodeGroup.GET("/:namespace/:name/pod", apiHandler.handleGetNodePods)
 	nodeGroup.GET("/:namespace/:name/event", apiHandler.handleGetNodeEvents)
+	nodeGroup.PUT("/:namespace/:name/cordon", apiHandler.handleCordonNode)
+	nodeGroup.PUT("/:namespace/:name/uncordon", apiHandler.handleUncordonNode)
+	nodeGroup.POST("/:namespace/:name/drain", apiHandler.handleDrainNode)
+	nodeGroup.GET("/:namespace/:name/drain", apiHandler.handleGetNodeDrain)
+	nodeGroup.PUT("/:namespace/:name/metadata", apiHandler.handleUpdateNodeMetadata)
+	nodeGroup.PUT("/", apiHandler.handleUpdateNodesMetadata)
 
 	rawGroup := r.Group("/_raw/:kind")
 	rawGroup.DELETE("/namespace/:namespace/name/:name", apiHandler.handleDeleteResource)
 	rawGroup.GET("/namespace/:namespace/name/:name", apiHandler.handleGetResource)
 	rawGroup.PUT("/namespace/:namespace/name/:name", apiHandler.handlePutResource)
+	rawGroup.PATCH("/namespace/:namespace/name/:name", apiHandler.handlePatchResource)
 
 	rawGroup.DELETE("/name/:name", apiHandler.handleDeleteResource)
 	rawGroup.GET("/name/:name", apiHandler.handleGetResource)
 	rawGroup.PUT("/name/:name", apiHandler.handlePutResource)
+	rawGroup.PATCH("/name/:name", apiHandler.handlePatchResource)
+
+	rawGroup.POST("/namespace/:namespace", apiHandler.handleCreateResource)
+	rawGroup.POST("/", apiHandler.handleCreateResource)
 
 	clusterroleGroup := r.Group("/clusterrole")
 	clusterroleGroup.GET("/", apiHandler.handleGetClusterRoleList)
 	clusterroleGroup.GET("/:name", apiHandler.handleGetClusterRoleDetail)
+	clusterroleGroup.POST("/", apiHandler.handleCreateClusterRole)
+	clusterroleGroup.PUT("/:name", apiHandler.handleUpdateClusterRole)
+	clusterroleGroup.DELETE("/:name", apiHandler.handleDeleteClusterRole)
 
 	clusterrolebindingGroup := r.Group("/clusterrolebinding")
 	clusterrolebindingGroup.GET("/", apiHandler.handleGetClusterRoleBindingList)
 	clusterrolebindingGroup.GET("/:name", apiHandler.handleGetClusterRoleBindingDetail)
+	clusterrolebindingGroup.POST("/", apiHandler.handleCreateClusterRoleBinding)
+	clusterrolebindingGroup.PUT("/:name", apiHandler.handleUpdateClusterRoleBinding)
+	clusterrolebindingGroup.PATCH("/:name/subjects", apiHandler.handlePatchClusterRoleBindingSubjects)
+	clusterrolebindingGroup.DELETE("/:name", apiHandler.handleDeleteClusterRoleBinding)
 
 	roleGroup := r.Group("/role/:namespace")
 	roleGroup.GET("/", apiHandler.handleGetRoleList)
 	roleGroup.GET("/:name", apiHandler.handleGetRoleDetail)
+	roleGroup.POST("/", apiHandler.handleCreateRole)
+	roleGroup.PUT("/:name", apiHandler.handleUpdateRole)
+	roleGroup.DELETE("/:name", apiHandler.handleDeleteRole)
 
 	rolebindingGroup := r.Group("/rolebinding/:namespace")
 	rolebindingGroup.GET("/", apiHandler.handleGetRoleBindingList)
 	rolebindingGroup.GET("/:name", apiHandler.handleGetRoleBindingDetail)
+	rolebindingGroup.POST("/", apiHandler.handleCreateRoleBinding)
+	rolebindingGroup.PUT("/:name", apiHandler.handleUpdateRoleBinding)
+	rolebindingGroup.PATCH("/:name/subjects", apiHandler.handlePatchRoleBindingSubjects)
+	rolebindingGroup.DELETE("/:name", apiHandler.handleDeleteRoleBinding)
+
+	rbacGroup := r.Group("/rbac")
+	rbacGroup.GET("/permissions", apiHandler.handleGetEffectivePermissions)
+	rbacGroup.GET("/whocan", apiHandler.handleGetWhoCan)
+	rbacGroup.GET("/template", apiHandler.handleGetRBACTemplates)
+	rbacGroup.POST("/template/:template", apiHandler.handleApplyRBACTemplate)
+
+	r.GET("/cani/summary", apiHandler.handleGetCanISummary)
 
 	persistentvolumeGroup := r.Group("/persistentvolume")
 	persistentvolumeGroup.GET("/", apiHandler.handleGetPersistentVolumeList)
@@ -310,6 +421,8 @@ func CreateHTTPAPIHandler(iManager integration.IntegrationManager, cManager clie
 	storageclassGroup.GET("/:storageclass",apiHandler.handleGetStorageClass)
 	storageclassGroup.GET("/:storageclass/persistentvolume",apiHandler.handleGetStorageClassPersistentVolumes)
 	
+	r.GET("/search", apiHandler.handleSearch)
+
 	logGroup := r.Group("/log")
 	logGroup.GET("/pod/:namespace/:pod", apiHandler.handleLogs)
 	logGroup.GET("/pod/:namespace/:pod/:container", apiHandler.handleLogs)
@@ -451,360 +564,436 @@ func (apiHandler *APIHandler) handleGetRoleBindingDetail(c *gin.Context) {
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetCsrfToken(c *gin.Context) {
-	action := c.Param("action")
-	token := xsrftoken.Generate(apiHandler.cManager.CSRFKey(), "none", action)
-	httphelper.RestfullResponse(c,http.StatusOK, api.CsrfToken{Token: token})
-}
-
-func (apiHandler *APIHandler) handleGetStatefulSetList(c *gin.Context) {
+func (apiHandler *APIHandler) handleCreateClusterRole(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := parseNamespacePathParameter(c)
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := statefulset.GetStatefulSetList(k8sClient, namespace, dataSelect,
-		apiHandler.iManager.Metric().Client())
+	spec := new(clusterrole.ClusterRoleSpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := clusterrole.CreateClusterRole(k8sClient, spec)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusCreated, result)
 }
 
-func (apiHandler *APIHandler) handleGetStatefulSetDetail(c *gin.Context) {
+func (apiHandler *APIHandler) handleUpdateClusterRole(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("statefulset")
-	result, err := statefulset.GetStatefulSetDetail(k8sClient, apiHandler.iManager.Metric().Client(), namespace, name)
+	spec := new(clusterrole.ClusterRoleSpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
 
+	result, err := clusterrole.UpdateClusterRole(k8sClient, c.Param("name"), spec)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetStatefulSetPods(c *gin.Context) {
+func (apiHandler *APIHandler) handleDeleteClusterRole(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("statefulset")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := statefulset.GetStatefulSetPods(k8sClient, apiHandler.iManager.Metric().Client(), dataSelect, name, namespace)
+	err = clusterrole.DeleteClusterRole(k8sClient, c.Param("name"))
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, nil)
 }
 
-func (apiHandler *APIHandler) handleGetStatefulSetEvents(c *gin.Context) {
+func (apiHandler *APIHandler) handleCreateClusterRoleBinding(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("statefulset")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := event.GetResourceEvents(k8sClient, dataSelect, namespace, name)
+	spec := new(clusterrolebinding.ClusterRoleBindingSpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := clusterrolebinding.CreateClusterRoleBinding(k8sClient, spec)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusCreated, result)
 }
 
-func (apiHandler *APIHandler) handleGetServiceList(c *gin.Context) {
+func (apiHandler *APIHandler) handleUpdateClusterRoleBinding(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := parseNamespacePathParameter(c)
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := resourceService.GetServiceList(k8sClient, namespace, dataSelect)
+	spec := new(clusterrolebinding.ClusterRoleBindingSpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := clusterrolebinding.UpdateClusterRoleBinding(k8sClient, c.Param("name"), spec)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetServiceDetail(c *gin.Context) {
+func (apiHandler *APIHandler) handlePatchClusterRoleBindingSubjects(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("service")
-	result, err := resourceService.GetServiceDetail(k8sClient, namespace, name)
+	spec := new(permission.SubjectPatch)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := clusterrolebinding.PatchClusterRoleBindingSubjects(k8sClient, c.Param("name"), spec)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetServiceEvent(c *gin.Context) {
+func (apiHandler *APIHandler) handleDeleteClusterRoleBinding(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("service")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := resourceService.GetServiceEvents(k8sClient, dataSelect, namespace, name)
+	err = clusterrolebinding.DeleteClusterRoleBinding(k8sClient, c.Param("name"))
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, nil)
 }
 
-func (apiHandler *APIHandler) handleGetServiceAccountList(c *gin.Context) {
+func (apiHandler *APIHandler) handleCreateRole(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := parseNamespacePathParameter(c)
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := serviceaccount.GetServiceAccountList(k8sClient, namespace, dataSelect)
+	spec := new(role.RoleSpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := role.CreateRole(k8sClient, c.Param("namespace"), spec)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusCreated, result)
 }
 
-func (apiHandler *APIHandler) handleGetServiceAccountDetail(c *gin.Context) {
+func (apiHandler *APIHandler) handleUpdateRole(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("serviceaccount")
-	result, err := serviceaccount.GetServiceAccountDetail(k8sClient, namespace, name)
+	spec := new(role.RoleSpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := role.UpdateRole(k8sClient, c.Param("namespace"), c.Param("name"), spec)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetServiceAccountImagePullSecrets(c *gin.Context) {
+func (apiHandler *APIHandler) handleDeleteRole(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("serviceaccount")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := serviceaccount.GetServiceAccountImagePullSecrets(k8sClient, namespace, name, dataSelect)
+	err = role.DeleteRole(k8sClient, c.Param("namespace"), c.Param("name"))
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, nil)
 }
 
-func (apiHandler *APIHandler) handleGetServiceAccountSecrets(c *gin.Context) {
+func (apiHandler *APIHandler) handleCreateRoleBinding(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("serviceaccount")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := serviceaccount.GetServiceAccountSecrets(k8sClient, namespace, name, dataSelect)
+	spec := new(rolebinding.RoleBindingSpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := rolebinding.CreateRoleBinding(k8sClient, c.Param("namespace"), spec)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusCreated, result)
 }
 
-func (apiHandler *APIHandler) handleGetIngressDetail(c *gin.Context) {
+func (apiHandler *APIHandler) handleUpdateRoleBinding(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("name")
-	result, err := ingress.GetIngressDetail(k8sClient, namespace, name)
+	spec := new(rolebinding.RoleBindingSpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := rolebinding.UpdateRoleBinding(k8sClient, c.Param("namespace"), c.Param("name"), spec)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetIngressList(c *gin.Context) {
+func (apiHandler *APIHandler) handlePatchRoleBindingSubjects(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	namespace := parseNamespacePathParameter(c)
-	result, err := ingress.GetIngressList(k8sClient, namespace, dataSelect)
+	spec := new(permission.SubjectPatch)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := rolebinding.PatchRoleBindingSubjects(k8sClient, c.Param("namespace"), c.Param("name"),
+		spec)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetServicePods(c *gin.Context) {
+func (apiHandler *APIHandler) handleDeleteRoleBinding(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("service")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := resourceService.GetServicePods(k8sClient, apiHandler.iManager.Metric().Client(), namespace, name, dataSelect)
+	err = rolebinding.DeleteRoleBinding(k8sClient, c.Param("namespace"), c.Param("name"))
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, nil)
 }
 
-func (apiHandler *APIHandler) handleGetNetworkPolicyList(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetRBACTemplates(c *gin.Context) {
+	httphelper.RestfullResponse(c, http.StatusOK, permission.GetTemplates())
+}
+
+func (apiHandler *APIHandler) handleApplyRBACTemplate(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := parseNamespacePathParameter(c)
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := networkpolicy.GetNetworkPolicyList(k8sClient, namespace, dataSelect)
+	dryRun, err := parseDryRunQueryParameter(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
-}
 
-func (apiHandler *APIHandler) handleGetNetworkPolicyDetail(c *gin.Context) {
-	k8sClient, err := apiHandler.cManager.Client(c)
-	if err != nil {
+	spec := new(permission.TemplateSpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("networkpolicy")
-	result, err := networkpolicy.GetNetworkPolicyDetail(k8sClient, namespace, name)
+	result, err := permission.ApplyTemplate(k8sClient, c.Param("template"), spec, dryRun)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusCreated, result)
 }
 
-func (apiHandler *APIHandler) handleGetNodeList(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetEffectivePermissions(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := node.GetNodeList(k8sClient, dataSelect, apiHandler.iManager.Metric().Client())
+	subject := permission.Subject{
+		Kind:      c.Query("kind"),
+		Name:      c.Query("name"),
+		Namespace: c.Query("namespace"),
+	}
+	var groups []string
+	if len(c.Query("groups")) > 0 {
+		groups = strings.Split(c.Query("groups"), ",")
+	}
+
+	result, err := permission.GetEffectivePermissions(k8sClient, subject, groups, c.Query("bindingNamespace"))
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetNodeDetail(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetWhoCan(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	name := c.Param("name")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := node.GetNodeDetail(k8sClient, apiHandler.iManager.Metric().Client(), name, dataSelect)
+	confirm, err := parseBoolQueryParameter(c, "confirm")
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+
+	var reviewer permission.AccessReviewer
+	if confirm {
+		reviewer = func(review *authorizationv1.SelfSubjectAccessReview) bool {
+			return apiHandler.cManager.CanI(c, review)
+		}
+	}
+
+	attributes := permission.ResourceAttributes{
+		Verb:           c.Query("verb"),
+		APIGroup:       c.Query("apiGroup"),
+		Resource:       c.Query("resource"),
+		Subresource:    c.Query("subresource"),
+		ResourceName:   c.Query("name"),
+		Namespace:      c.Query("namespace"),
+		NonResourceURL: c.Query("nonResourceURL"),
+	}
+	result, err := permission.GetWhoCan(k8sClient, attributes, reviewer)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetNodeEvents(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetCanISummary(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	name := c.Param("name")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := event.GetNodeEvents(k8sClient, dataSelect, name)
+	result, err := permission.GetCapabilitySummary(k8sClient, getCredentialsHash(c), c.Query("namespace"))
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetNodePods(c *gin.Context) {
+// getCredentialsHash returns a hash of the credential and impersonation headers and of the verified client certificate
+// of the request, so that results can be cached per user without keeping tokens in memory. Returns an empty string if
+// the request has no credentials.
+func getCredentialsHash(c *gin.Context) string {
+	headers := []string{"Authorization", client.JWETokenHeader}
+	for header := range c.Request.Header {
+		if strings.HasPrefix(header, "Impersonate-") {
+			headers = append(headers, header)
+		}
+	}
+	sort.Strings(headers[2:])
+
+	credentials := false
+	hash := sha256.New()
+	for _, header := range headers {
+		for _, value := range c.Request.Header.Values(header) {
+			credentials = true
+			hash.Write([]byte(header + ":" + value + "\n"))
+		}
+	}
+
+	if state := c.Request.TLS; state != nil && len(state.VerifiedChains) > 0 && len(state.VerifiedChains[0]) > 0 {
+		credentials = true
+		hash.Write([]byte("Certificate:"))
+		hash.Write(state.VerifiedChains[0][0].Raw)
+	}
+
+	if !credentials {
+		return ""
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (apiHandler *APIHandler) handleGetCsrfToken(c *gin.Context) {
+	action := c.Param("action")
+	token := xsrftoken.Generate(apiHandler.cManager.CSRFKey(), "none", action)
+	httphelper.RestfullResponse(c,http.StatusOK, api.CsrfToken{Token: token})
+}
+
+func (apiHandler *APIHandler) handleGetStatefulSetList(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	name := c.Param("name")
+	namespace := parseNamespacePathParameter(c)
 	dataSelect := parser.ParseDataSelectPathParameter(c)
 	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := node.GetNodePods(k8sClient, apiHandler.iManager.Metric().Client(), dataSelect, name)
+	result, err := statefulset.GetStatefulSetList(k8sClient, namespace, dataSelect,
+		apiHandler.iManager.Metric().Client())
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -812,154 +1001,1560 @@ func (apiHandler *APIHandler) handleGetNodePods(c *gin.Context) {
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleDeploy(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetStatefulSetDetail(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	appDeploymentSpec := new(deployment.AppDeploymentSpec)
-	if err := httphelper.ReadRequestBody(c,appDeploymentSpec); err != nil {
-		errors.HandleInternalError(c, err)
-		return
-	}
-	if err := deployment.DeployApp(appDeploymentSpec, k8sClient); err != nil {
+	namespace := c.Param("namespace")
+	name := c.Param("statefulset")
+	result, err := statefulset.GetStatefulSetDetail(k8sClient, apiHandler.iManager.Metric().Client(), namespace, name)
+
+	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusCreated, appDeploymentSpec)
+	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleScaleResource(c *gin.Context) {
-	cfg, err := apiHandler.cManager.Config(c)
+func (apiHandler *APIHandler) handleGetStatefulSetPods(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Query("namespace")
-	kind := c.Param("kind")
-	name := c.Param("name")
-	count := c.Param("scaleBy")
-	replicaCountSpec, err := scaling.ScaleResource(cfg, kind, namespace, name, count)
+	namespace := c.Param("namespace")
+	name := c.Param("statefulset")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := statefulset.GetStatefulSetPods(k8sClient, apiHandler.iManager.Metric().Client(), dataSelect, name, namespace)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, replicaCountSpec)
+	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetReplicaCount(c *gin.Context) {
-	cfg, err := apiHandler.cManager.Config(c)
+func (apiHandler *APIHandler) handleGetStatefulSetEvents(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
 	namespace := c.Param("namespace")
-	kind := c.Param("kind")
-	name := c.Param("name")
-	replicaCounts, err := scaling.GetReplicaCounts(cfg, kind, namespace, name)
+	name := c.Param("statefulset")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	result, err := event.GetResourceEvents(k8sClient, dataSelect, namespace, name)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, replicaCounts)
+	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleDeployFromFile(c *gin.Context) {
-	cfg, err := apiHandler.cManager.Config(c)
+func (apiHandler *APIHandler) handleGetServiceList(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	deploymentSpec := new(deployment.AppDeploymentFromFileSpec)
+	namespace := parseNamespacePathParameter(c)
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	result, err := resourceService.GetServiceList(k8sClient, namespace, dataSelect)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetServiceDetail(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("service")
+	result, err := resourceService.GetServiceDetail(k8sClient, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetServiceEvent(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("service")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := resourceService.GetServiceEvents(k8sClient, dataSelect, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetServiceAccountList(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(c)
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	result, err := serviceaccount.GetServiceAccountList(k8sClient, namespace, dataSelect)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetServiceAccountDetail(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("serviceaccount")
+	result, err := serviceaccount.GetServiceAccountDetail(k8sClient, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetServiceAccountImagePullSecrets(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("serviceaccount")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	result, err := serviceaccount.GetServiceAccountImagePullSecrets(k8sClient, namespace, name, dataSelect)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetServiceAccountSecrets(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("serviceaccount")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	result, err := serviceaccount.GetServiceAccountSecrets(k8sClient, namespace, name, dataSelect)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetIngressDetail(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	result, err := ingress.GetIngressDetail(k8sClient, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetIngressList(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	namespace := parseNamespacePathParameter(c)
+	result, err := ingress.GetIngressList(k8sClient, namespace, dataSelect)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetServicePods(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("service")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := resourceService.GetServicePods(k8sClient, apiHandler.iManager.Metric().Client(), namespace, name, dataSelect)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetNetworkPolicyList(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(c)
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	result, err := networkpolicy.GetNetworkPolicyList(k8sClient, namespace, dataSelect)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetNetworkPolicyDetail(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("networkpolicy")
+	result, err := networkpolicy.GetNetworkPolicyDetail(k8sClient, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+// handleSimulateNetworkPolicies checks if network policies allow the traffic described in the request body.
+func (apiHandler *APIHandler) handleSimulateNetworkPolicies(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	spec := new(networkpolicy.SimulationSpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := networkpolicy.SimulateTraffic(k8sClient, spec)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
+}
+
+// handleGetNetworkIsolation returns which pods of the namespace are isolated by network policies.
+func (apiHandler *APIHandler) handleGetNetworkIsolation(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := networkpolicy.GetIsolationStatus(k8sClient, c.Param("namespace"))
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
+}
+
+// handleGetConnectivityGraph returns graph of workloads, services and ingresses of the namespace.
+func (apiHandler *APIHandler) handleGetConnectivityGraph(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := connectivity.GetConnectivityGraph(k8sClient, c.Param("namespace"))
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
+}
+
+// handleGetConnectivityGraphDOT returns graph of the namespace in Graphviz DOT language.
+func (apiHandler *APIHandler) handleGetConnectivityGraphDOT(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := connectivity.GetConnectivityGraph(k8sClient, c.Param("namespace"))
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(result.ToDOT()))
+}
+
+func (apiHandler *APIHandler) handleGetPodDisruptionBudgetList(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(c)
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	result, err := poddisruptionbudget.GetPodDisruptionBudgetList(k8sClient, namespace, dataSelect)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetPodDisruptionBudgetDetail(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("poddisruptionbudget")
+	result, err := poddisruptionbudget.GetPodDisruptionBudgetDetail(k8sClient, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetPodDisruptionBudgetPods(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("poddisruptionbudget")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := poddisruptionbudget.GetPodDisruptionBudgetPods(k8sClient, apiHandler.iManager.Metric().Client(),
+		dataSelect, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetNodeList(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := node.GetNodeList(k8sClient, dataSelect, apiHandler.iManager.Metric().Client())
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetNodeDetail(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	name := c.Param("name")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := node.GetNodeDetail(k8sClient, apiHandler.iManager.Metric().Client(), name, dataSelect)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetNodeEvents(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	name := c.Param("name")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := event.GetNodeEvents(k8sClient, dataSelect, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetNodePods(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	name := c.Param("name")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := node.GetNodePods(k8sClient, apiHandler.iManager.Metric().Client(), dataSelect, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleCordonNode(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	if _, err := node.CordonNode(k8sClient, c.Param("name")); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, nil)
+}
+
+func (apiHandler *APIHandler) handleUncordonNode(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	if _, err := node.UncordonNode(k8sClient, c.Param("name")); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, nil)
+}
+
+func (apiHandler *APIHandler) handleDrainNode(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	spec := new(node.DrainSpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := node.DrainNode(k8sClient, c.Param("name"), spec)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusAccepted, result)
+}
+
+func (apiHandler *APIHandler) handleGetNodeDrain(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := node.GetDrainOperation(k8sClient, c.Param("name"))
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleUpdateNodeMetadata(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	dryRun, err := parseDryRunQueryParameter(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	spec := new(node.NodeMetadataSpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := node.UpdateNodeMetadata(k8sClient, c.Param("name"), spec, dryRun)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
+}
+
+// handleUpdateNodesMetadata updates labels and taints of all nodes matching the labelSelector query parameter.
+func (apiHandler *APIHandler) handleUpdateNodesMetadata(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	dryRun, err := parseDryRunQueryParameter(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	spec := new(node.NodeMetadataSpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := node.UpdateNodesMetadata(k8sClient, c.Query("labelSelector"), spec, dryRun)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
+}
+
+// handleDeletePod deletes a pod with the grace period given by the gracePeriodSeconds query parameter. With
+// force=true the pod is deleted immediately.
+func (apiHandler *APIHandler) handleDeletePod(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	gracePeriodSeconds, err := parseGracePeriodQueryParameter(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	force := false
+	if value := c.Query("force"); len(value) > 0 {
+		if force, err = strconv.ParseBool(value); err != nil {
+			errors.HandleInternalError(c, errors.NewBadRequest(fmt.Sprintf("Invalid force parameter: %s", value)))
+			return
+		}
+	}
+
+	namespace, name := c.Param("namespace"), c.Param("pod")
+	if force && gracePeriodSeconds != nil {
+		err = errors.NewInvalid("Grace period cannot be set for force deletion")
+	} else if force {
+		err = pod.ForceDeletePod(k8sClient, namespace, name)
+	} else {
+		err = pod.DeletePod(k8sClient, namespace, name, gracePeriodSeconds)
+	}
+
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, nil)
+}
+
+func (apiHandler *APIHandler) handleEvictPod(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	gracePeriodSeconds, err := parseGracePeriodQueryParameter(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	if err := pod.EvictPod(k8sClient, c.Param("namespace"), c.Param("pod"), gracePeriodSeconds); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, nil)
+}
+
+func (apiHandler *APIHandler) handleRestartPod(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := pod.RestartPod(k8sClient, c.Param("namespace"), c.Param("pod"))
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleDeploy(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	appDeploymentSpec := new(deployment.AppDeploymentSpec)
+	if err := httphelper.ReadRequestBody(c,appDeploymentSpec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	if err := deployment.DeployApp(appDeploymentSpec, k8sClient); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusCreated, appDeploymentSpec)
+}
+
+func (apiHandler *APIHandler) handleScaleResource(c *gin.Context) {
+	cfg, err := apiHandler.cManager.Config(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Query("namespace")
+	kind := c.Param("kind")
+	name := c.Param("name")
+	count := c.Param("scaleBy")
+	replicaCountSpec, err := scaling.ScaleResource(cfg, kind, namespace, name, count)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, replicaCountSpec)
+}
+
+func (apiHandler *APIHandler) handleGetReplicaCount(c *gin.Context) {
+	cfg, err := apiHandler.cManager.Config(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	kind := c.Param("kind")
+	name := c.Param("name")
+	replicaCounts, err := scaling.GetReplicaCounts(cfg, kind, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, replicaCounts)
+}
+
+func (apiHandler *APIHandler) handleDeployFromFile(c *gin.Context) {
+	cfg, err := apiHandler.cManager.Config(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	deploymentSpec := new(deployment.AppDeploymentFromFileSpec)
 	if err := httphelper.ReadRequestBody(c,deploymentSpec); err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	isDeployed, err := deployment.DeployAppFromFile(cfg, deploymentSpec)
-	if !isDeployed {
+	isDeployed, err := deployment.DeployAppFromFile(cfg, deploymentSpec)
+	if !isDeployed {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	errorMessage := ""
+	if err != nil {
+		errorMessage = err.Error()
+	}
+
+	httphelper.RestfullResponse(c,http.StatusCreated, deployment.AppDeploymentFromFileResponse{
+		Name:    deploymentSpec.Name,
+		Content: deploymentSpec.Content,
+		Error:   errorMessage,
+	})
+}
+
+func (apiHandler *APIHandler) handleNameValidity(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	spec := new(validation.AppNameValiditySpec)
+	if err := httphelper.ReadRequestBody(c,spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	validity, err := validation.ValidateAppName(spec, k8sClient)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	httphelper.RestfullResponse(c,http.StatusOK, validity)
+}
+
+func (apiHandler *APIHandler) handleImageReferenceValidity(c *gin.Context) {
+	spec := new(validation.ImageReferenceValiditySpec)
+	if err := httphelper.ReadRequestBody(c,spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	validity, err := validation.ValidateImageReference(spec)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, validity)
+}
+
+func (apiHandler *APIHandler) handleProtocolValidity(c *gin.Context) {
+	spec := new(validation.ProtocolValiditySpec)
+	if err := httphelper.ReadRequestBody(c,spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, validation.ValidateProtocol(spec))
+}
+
+func (apiHandler *APIHandler) handleGetAvailableProtocols(c *gin.Context) {
+	httphelper.RestfullResponse(c,http.StatusOK, deployment.GetAvailableProtocols())
+}
+
+func (apiHandler *APIHandler) handleGetReplicationControllerList(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(c)
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := replicationcontroller.GetReplicationControllerList(k8sClient, namespace, dataSelect, apiHandler.iManager.Metric().Client())
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetReplicaSets(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(c)
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := replicaset.GetReplicaSetList(k8sClient, namespace, dataSelect, apiHandler.iManager.Metric().Client())
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetReplicaSetDetail(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	replicaSet := c.Param("replicaSet")
+	result, err := replicaset.GetReplicaSetDetail(k8sClient, apiHandler.iManager.Metric().Client(), namespace, replicaSet)
+
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetReplicaSetPods(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	replicaSet := c.Param("replicaSet")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := replicaset.GetReplicaSetPods(k8sClient, apiHandler.iManager.Metric().Client(), dataSelect, replicaSet, namespace)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetReplicaSetServices(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	replicaSet := c.Param("replicaSet")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := replicaset.GetReplicaSetServices(k8sClient, dataSelect, namespace, replicaSet)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetReplicaSetEvents(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("replicaSet")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := event.GetResourceEvents(k8sClient, dataSelect, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+
+}
+
+func (apiHandler *APIHandler) handleGetPodEvents(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	log.Println("Getting events related to a pod in namespace")
+	namespace := c.Param("namespace")
+	name := c.Param("pod")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := pod.GetEventsForPod(k8sClient, dataSelect, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+// Handles execute shell API call
+func (apiHandler *APIHandler) handleExecShell(c *gin.Context) {
+	sessionID, err := genTerminalSessionId()
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	cfg, err := apiHandler.cManager.Config(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	terminalSessions.Set(sessionID, TerminalSession{
+		id:       sessionID,
+		bound:    make(chan error),
+		sizeChan: make(chan remotecommand.TerminalSize),
+	})
+	go WaitForTerminal(k8sClient, cfg, c, sessionID)
+	httphelper.RestfullResponse(c,http.StatusOK, TerminalResponse{ID: sessionID})
+}
+
+func (apiHandler *APIHandler) handleGetDeployments(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(c)
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := deployment.GetDeploymentList(k8sClient, namespace, dataSelect, apiHandler.iManager.Metric().Client())
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetDeploymentDetail(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("deployment")
+	result, err := deployment.GetDeploymentDetail(k8sClient, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetDeploymentEvents(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("deployment")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	result, err := event.GetResourceEvents(k8sClient, dataSelect, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetDeploymentOldReplicaSets(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("deployment")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := deployment.GetDeploymentOldReplicaSets(k8sClient, dataSelect, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetDeploymentNewReplicaSet(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("deployment")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := deployment.GetDeploymentNewReplicaSet(k8sClient, dataSelect, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetPods(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(c)
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics // download standard metrics - cpu, and memory - by default
+	result, err := pod.GetPodList(k8sClient, apiHandler.iManager.Metric().Client(), namespace, dataSelect)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetPodDetail(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("pod")
+	result, err := pod.GetPodDetail(k8sClient, apiHandler.iManager.Metric().Client(), namespace, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetReplicationControllerDetail(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("replicationController")
+	result, err := replicationcontroller.GetReplicationControllerDetail(k8sClient, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleUpdateReplicasCount(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("replicationController")
+	spec := new(replicationcontroller.ReplicationControllerSpec)
+	if err := httphelper.ReadRequestBody(c,spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	if err := replicationcontroller.UpdateReplicasCount(k8sClient, namespace, name, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	httphelper.RestfullResponse(c,http.StatusAccepted, nil)
+}
+
+func (apiHandler *APIHandler) handleGetResource(c *gin.Context) {
+	config, err := apiHandler.cManager.Config(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	verber, err := apiHandler.cManager.VerberClient(c, config)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	kind := c.Param("kind")
+	if kind == api.ResourceKindSecret {
+		// Secret values are only returned by the reveal endpoint, that checks the reveal permission and records an
+		// event for every revealed value.
+		errors.HandleInternalError(c, errors.NewGenericResponse(http.StatusForbidden,
+			"Secrets can not be read raw, values have to be revealed one key at a time"))
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	ok := namespace == ""
+	result, err := verber.Get(kind, ok, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handlePutResource(
+	c *gin.Context) {
+	config, err := apiHandler.cManager.Config(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	verber, err := apiHandler.cManager.VerberClient(c, config)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	kind := c.Param("kind")
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	ok := namespace == ""
+	putSpec := &runtime.Unknown{}
+	if err := httphelper.ReadRequestBody(c,putSpec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	if err := verber.Put(kind, ok, namespace, name, putSpec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	httphelper.RestfullResponse(c,http.StatusCreated, nil)
+}
+
+func (apiHandler *APIHandler) handleCreateResource(c *gin.Context) {
+	config, err := apiHandler.cManager.Config(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	verber, err := apiHandler.cManager.VerberClient(c, config)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	kind := c.Param("kind")
+	namespace := c.Param("namespace")
+	object := &runtime.Unknown{}
+	if err := httphelper.ReadRequestBody(c, object); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := verber.Create(kind, namespace != "", namespace, object)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	httphelper.RestfullResponse(c, http.StatusCreated, result)
+}
+
+// handlePatchResource patches a single resource. The patch type is taken from the Content-Type header:
+// application/merge-patch+json (default), application/strategic-merge-patch+json or application/apply-patch+yaml
+// for server-side apply. Server-side apply also accepts the fieldManager and force query parameters.
+func (apiHandler *APIHandler) handlePatchResource(c *gin.Context) {
+	config, err := apiHandler.cManager.Config(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	verber, err := apiHandler.cManager.VerberClient(c, config)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	patchType, err := parsePatchType(c.ContentType())
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	options := metaV1.PatchOptions{FieldManager: c.Query("fieldManager")}
+	if force := c.Query("force"); force != "" {
+		forced, err := strconv.ParseBool(force)
+		if err != nil {
+			errors.HandleInternalError(c, errors.NewBadRequest(fmt.Sprintf("Invalid force parameter: %s", force)))
+			return
+		}
+		options.Force = &forced
+	}
+
+	data, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	kind := c.Param("kind")
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	result, err := verber.Patch(kind, namespace != "", namespace, name, patchType, data, options)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	// Patched secrets are not returned, as their values can only be revealed one key at a time.
+	if kind == api.ResourceKindSecret {
+		result = nil
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleDeleteResource(
+	c *gin.Context) {
+	config, err := apiHandler.cManager.Config(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	verber, err := apiHandler.cManager.VerberClient(c, config)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	kind := c.Param("kind")
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	ok := namespace == ""
+	if err := verber.Delete(kind, ok, namespace, name); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	// Try to unpin resource if it was pinned.
+	pinnedResource := &settingsApi.PinnedResource{
+		Name:      name,
+		Kind:      kind,
+		Namespace: namespace,
+	}
+
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	if err = apiHandler.sManager.DeletePinnedResource(k8sClient, pinnedResource); err != nil {
+		if !errors.IsNotFoundError(err) {
+			log.Printf("error while unpinning resource: %s", err.Error())
+		}
+	}
+
+	httphelper.RestfullResponse(c,http.StatusOK, nil)
+}
+
+func (apiHandler *APIHandler) handleGetReplicationControllerPods(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	rc := c.Param("replicationController")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := replicationcontroller.GetReplicationControllerPods(k8sClient, apiHandler.iManager.Metric().Client(), dataSelect, rc, namespace)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleCreateNamespace(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespaceSpec := new(ns.NamespaceSpec)
+	if err := httphelper.ReadRequestBody(c,namespaceSpec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	if err := ns.CreateNamespace(namespaceSpec, k8sClient); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusCreated, namespaceSpec)
+}
+
+func (apiHandler *APIHandler) handleGetNamespaces(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	result, err := ns.GetNamespaceList(k8sClient, dataSelect)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetNamespaceDetail(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	name := c.Param("name")
+	result, err := ns.GetNamespaceDetail(k8sClient, name)
+	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
 
-	errorMessage := ""
+func (apiHandler *APIHandler) handleGetNamespaceEvents(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
-		errorMessage = err.Error()
+		errors.HandleInternalError(c, err)
+		return
 	}
 
-	httphelper.RestfullResponse(c,http.StatusCreated, deployment.AppDeploymentFromFileResponse{
-		Name:    deploymentSpec.Name,
-		Content: deploymentSpec.Content,
-		Error:   errorMessage,
-	})
+	name := c.Param("name")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	result, err := event.GetNamespaceEvents(k8sClient, dataSelect, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleNameValidity(c *gin.Context) {
+func (apiHandler *APIHandler) handleCreateImagePullSecret(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	spec := new(validation.AppNameValiditySpec)
+	spec := new(secret.ImagePullSecretSpec)
 	if err := httphelper.ReadRequestBody(c,spec); err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
+	result, err := secret.CreateSecret(k8sClient, spec)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusCreated, result)
+}
 
-	validity, err := validation.ValidateAppName(spec, k8sClient)
+func (apiHandler *APIHandler) handleCreateSecret(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	httphelper.RestfullResponse(c,http.StatusOK, validity)
+	var spec secret.SecretSpec
+	switch c.Param("type") {
+	case "opaque":
+		spec = new(secret.OpaqueSecretSpec)
+	case "tls":
+		spec = new(secret.TLSSecretSpec)
+	case "basic-auth":
+		spec = new(secret.BasicAuthSecretSpec)
+	case "ssh-auth":
+		spec = new(secret.SSHAuthSecretSpec)
+	case "dockerconfigjson":
+		spec = new(secret.DockerConfigJSONSecretSpec)
+	default:
+		errors.HandleInternalError(c, errors.NewBadRequest("Unsupported secret type: "+c.Param("type")))
+		return
+	}
+
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	result, err := secret.CreateSecret(k8sClient, spec)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusCreated, result)
 }
 
-func (apiHandler *APIHandler) handleImageReferenceValidity(c *gin.Context) {
-	spec := new(validation.ImageReferenceValiditySpec)
-	if err := httphelper.ReadRequestBody(c,spec); err != nil {
+func (apiHandler *APIHandler) handleRevealSecretKey(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	validity, err := validation.ValidateImageReference(spec)
+	user, err := apiHandler.cManager.User(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, validity)
+
+	result, err := secret.RevealSecretKey(k8sClient, user, c.Param("namespace"), c.Param("name"), c.Param("key"))
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleProtocolValidity(c *gin.Context) {
-	spec := new(validation.ProtocolValiditySpec)
-	if err := httphelper.ReadRequestBody(c,spec); err != nil {
+func (apiHandler *APIHandler) handleUpdateSecretKey(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, validation.ValidateProtocol(spec))
+
+	roll, err := parseBoolQueryParameter(c, "roll")
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	spec := new(secret.SecretKeySpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	result, err := secret.UpdateSecretKey(k8sClient, c.Param("namespace"), c.Param("name"), c.Param("key"), spec,
+		roll)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetAvailableProtocols(c *gin.Context) {
-	httphelper.RestfullResponse(c,http.StatusOK, deployment.GetAvailableProtocols())
+func (apiHandler *APIHandler) handleDeleteSecretKey(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	roll, err := parseBoolQueryParameter(c, "roll")
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := secret.DeleteSecretKey(k8sClient, c.Param("namespace"), c.Param("name"), c.Param("key"), roll)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetReplicationControllerList(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetSecretDetail(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	result, err := secret.GetSecretDetail(k8sClient, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetSecretList(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := parseNamespacePathParameter(c)
 	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := replicationcontroller.GetReplicationControllerList(k8sClient, namespace, dataSelect, apiHandler.iManager.Metric().Client())
+	namespace := parseNamespacePathParameter(c)
+	result, err := secret.GetSecretList(k8sClient, namespace, dataSelect)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -967,7 +2562,7 @@ func (apiHandler *APIHandler) handleGetReplicationControllerList(c *gin.Context)
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetReplicaSets(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetConfigMapList(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
@@ -976,8 +2571,7 @@ func (apiHandler *APIHandler) handleGetReplicaSets(c *gin.Context) {
 
 	namespace := parseNamespacePathParameter(c)
 	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := replicaset.GetReplicaSetList(k8sClient, namespace, dataSelect, apiHandler.iManager.Metric().Client())
+	result, err := configmap.GetConfigMapList(k8sClient, namespace, dataSelect)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -985,26 +2579,30 @@ func (apiHandler *APIHandler) handleGetReplicaSets(c *gin.Context) {
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetReplicaSetDetail(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetCertificateList(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	replicaSet := c.Param("replicaSet")
-	result, err := replicaset.GetReplicaSetDetail(k8sClient, apiHandler.iManager.Metric().Client(), namespace, replicaSet)
-
+	threshold, err := parseIntQueryParameter(c, "threshold")
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	namespace := parseNamespacePathParameter(c)
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	result, err := certificate.GetCertificateList(k8sClient, namespace, dataSelect, int(threshold))
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetReplicaSetPods(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetConfigMapDetail(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
@@ -1012,108 +2610,123 @@ func (apiHandler *APIHandler) handleGetReplicaSetPods(c *gin.Context) {
 	}
 
 	namespace := c.Param("namespace")
-	replicaSet := c.Param("replicaSet")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := replicaset.GetReplicaSetPods(k8sClient, apiHandler.iManager.Metric().Client(), dataSelect, replicaSet, namespace)
+	name := c.Param("configmap")
+	result, err := configmap.GetConfigMapDetail(k8sClient, namespace, name)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetReplicaSetServices(c *gin.Context) {
+func (apiHandler *APIHandler) handleCreateConfigMapKey(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	replicaSet := c.Param("replicaSet")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := replicaset.GetReplicaSetServices(k8sClient, dataSelect, namespace, replicaSet)
+	roll, err := parseBoolQueryParameter(c, "roll")
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	spec := new(configmap.ConfigMapKeySpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	result, err := configmap.CreateConfigMapKey(k8sClient, c.Param("namespace"), c.Param("configmap"), c.Param("key"), spec,
+		roll)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusCreated, result)
 }
 
-func (apiHandler *APIHandler) handleGetReplicaSetEvents(c *gin.Context) {
+func (apiHandler *APIHandler) handleUpdateConfigMapKey(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("replicaSet")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := event.GetResourceEvents(k8sClient, dataSelect, namespace, name)
+	roll, err := parseBoolQueryParameter(c, "roll")
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
 
+	spec := new(configmap.ConfigMapKeySpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	result, err := configmap.UpdateConfigMapKey(k8sClient, c.Param("namespace"), c.Param("configmap"), c.Param("key"), spec,
+		roll)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetPodEvents(c *gin.Context) {
+func (apiHandler *APIHandler) handleDeleteConfigMapKey(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	log.Println("Getting events related to a pod in namespace")
-	namespace := c.Param("namespace")
-	name := c.Param("pod")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := pod.GetEventsForPod(k8sClient, dataSelect, namespace, name)
+	roll, err := parseBoolQueryParameter(c, "roll")
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
-}
 
-// Handles execute shell API call
-func (apiHandler *APIHandler) handleExecShell(c *gin.Context) {
-	sessionID, err := genTerminalSessionId()
+	result, err := configmap.DeleteConfigMapKey(k8sClient, c.Param("namespace"), c.Param("configmap"), c.Param("key"), roll)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
+}
 
+func (apiHandler *APIHandler) handleGetPersistentVolumeList(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	cfg, err := apiHandler.cManager.Config(c)
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	result, err := persistentvolume.GetPersistentVolumeList(k8sClient, dataSelect)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
 
-	terminalSessions.Set(sessionID, TerminalSession{
-		id:       sessionID,
-		bound:    make(chan error),
-		sizeChan: make(chan remotecommand.TerminalSize),
-	})
-	go WaitForTerminal(k8sClient, cfg, c, sessionID)
-	httphelper.RestfullResponse(c,http.StatusOK, TerminalResponse{ID: sessionID})
+func (apiHandler *APIHandler) handleGetPersistentVolumeDetail(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	name := c.Param("persistentvolume")
+	result, err := persistentvolume.GetPersistentVolumeDetail(k8sClient, name)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetDeployments(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetPersistentVolumeClaimList(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
@@ -1122,8 +2735,7 @@ func (apiHandler *APIHandler) handleGetDeployments(c *gin.Context) {
 
 	namespace := parseNamespacePathParameter(c)
 	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := deployment.GetDeploymentList(k8sClient, namespace, dataSelect, apiHandler.iManager.Metric().Client())
+	result, err := persistentvolumeclaim.GetPersistentVolumeClaimList(k8sClient, namespace, dataSelect)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -1131,7 +2743,7 @@ func (apiHandler *APIHandler) handleGetDeployments(c *gin.Context) {
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetDeploymentDetail(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetPersistentVolumeClaimDetail(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
@@ -1139,17 +2751,16 @@ func (apiHandler *APIHandler) handleGetDeploymentDetail(c *gin.Context) {
 	}
 
 	namespace := c.Param("namespace")
-	name := c.Param("deployment")
-	result, err := deployment.GetDeploymentDetail(k8sClient, namespace, name)
+	name := c.Param("name")
+	result, err := persistentvolumeclaim.GetPersistentVolumeClaimDetail(k8sClient, namespace, name)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetDeploymentEvents(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetPodContainers(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
@@ -1157,9 +2768,8 @@ func (apiHandler *APIHandler) handleGetDeploymentEvents(c *gin.Context) {
 	}
 
 	namespace := c.Param("namespace")
-	name := c.Param("deployment")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := event.GetResourceEvents(k8sClient, dataSelect, namespace, name)
+	name := c.Param("pod")
+	result, err := container.GetPodContainers(k8sClient, namespace, name)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -1167,7 +2777,7 @@ func (apiHandler *APIHandler) handleGetDeploymentEvents(c *gin.Context) {
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetDeploymentOldReplicaSets(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetReplicationControllerEvents(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
@@ -1175,10 +2785,9 @@ func (apiHandler *APIHandler) handleGetDeploymentOldReplicaSets(c *gin.Context)
 	}
 
 	namespace := c.Param("namespace")
-	name := c.Param("deployment")
+	name := c.Param("replicationController")
 	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := deployment.GetDeploymentOldReplicaSets(k8sClient, dataSelect, namespace, name)
+	result, err := event.GetResourceEvents(k8sClient, dataSelect, namespace, name)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -1186,7 +2795,7 @@ func (apiHandler *APIHandler) handleGetDeploymentOldReplicaSets(c *gin.Context)
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetDeploymentNewReplicaSet(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetReplicationControllerServices(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
@@ -1194,10 +2803,9 @@ func (apiHandler *APIHandler) handleGetDeploymentNewReplicaSet(c *gin.Context) {
 	}
 
 	namespace := c.Param("namespace")
-	name := c.Param("deployment")
+	name := c.Param("replicationController")
 	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := deployment.GetDeploymentNewReplicaSet(k8sClient, dataSelect, namespace, name)
+	result, err := replicationcontroller.GetReplicationControllerServices(k8sClient, dataSelect, namespace, name)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -1205,7 +2813,7 @@ func (apiHandler *APIHandler) handleGetDeploymentNewReplicaSet(c *gin.Context) {
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetPods(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetDaemonSetList(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
@@ -1214,8 +2822,8 @@ func (apiHandler *APIHandler) handleGetPods(c *gin.Context) {
 
 	namespace := parseNamespacePathParameter(c)
 	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics // download standard metrics - cpu, and memory - by default
-	result, err := pod.GetPodList(k8sClient, apiHandler.iManager.Metric().Client(), namespace, dataSelect)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := daemonset.GetDaemonSetList(k8sClient, namespace, dataSelect, apiHandler.iManager.Metric().Client())
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -1223,7 +2831,8 @@ func (apiHandler *APIHandler) handleGetPods(c *gin.Context) {
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetPodDetail(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetDaemonSetDetail(
+	c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
@@ -1231,8 +2840,8 @@ func (apiHandler *APIHandler) handleGetPodDetail(c *gin.Context) {
 	}
 
 	namespace := c.Param("namespace")
-	name := c.Param("pod")
-	result, err := pod.GetPodDetail(k8sClient, apiHandler.iManager.Metric().Client(), namespace, name)
+	name := c.Param("daemonSet")
+	result, err := daemonset.GetDaemonSetDetail(k8sClient, apiHandler.iManager.Metric().Client(), namespace, name)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -1240,7 +2849,7 @@ func (apiHandler *APIHandler) handleGetPodDetail(c *gin.Context) {
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetReplicationControllerDetail(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetDaemonSetPods(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
@@ -1248,8 +2857,10 @@ func (apiHandler *APIHandler) handleGetReplicationControllerDetail(c *gin.Contex
 	}
 
 	namespace := c.Param("namespace")
-	name := c.Param("replicationController")
-	result, err := replicationcontroller.GetReplicationControllerDetail(k8sClient, namespace, name)
+	name := c.Param("daemonSet")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := daemonset.GetDaemonSetPods(k8sClient, apiHandler.iManager.Metric().Client(), dataSelect, name, namespace)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -1257,7 +2868,7 @@ func (apiHandler *APIHandler) handleGetReplicationControllerDetail(c *gin.Contex
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleUpdateReplicasCount(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetDaemonSetServices(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
@@ -1265,203 +2876,170 @@ func (apiHandler *APIHandler) handleUpdateReplicasCount(c *gin.Context) {
 	}
 
 	namespace := c.Param("namespace")
-	name := c.Param("replicationController")
-	spec := new(replicationcontroller.ReplicationControllerSpec)
-	if err := httphelper.ReadRequestBody(c,spec); err != nil {
-		errors.HandleInternalError(c, err)
-		return
-	}
-
-	if err := replicationcontroller.UpdateReplicasCount(k8sClient, namespace, name, spec); err != nil {
-		errors.HandleInternalError(c, err)
-		return
-	}
-
-	httphelper.RestfullResponse(c,http.StatusAccepted, nil)
-}
-
-func (apiHandler *APIHandler) handleGetResource(c *gin.Context) {
-	config, err := apiHandler.cManager.Config(c)
+	daemonSet := c.Param("daemonSet")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	result, err := daemonset.GetDaemonSetServices(k8sClient, dataSelect, namespace, daemonSet)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
 
-	verber, err := apiHandler.cManager.VerberClient(c, config)
+func (apiHandler *APIHandler) handleGetDaemonSetEvents(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	kind := c.Param("kind")
 	namespace := c.Param("namespace")
-	name := c.Param("name")
-	ok := namespace == ""
-	result, err := verber.Get(kind, ok, namespace, name)
+	name := c.Param("daemonSet")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	result, err := event.GetResourceEvents(k8sClient, dataSelect, namespace, name)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handlePutResource(
-	c *gin.Context) {
-	config, err := apiHandler.cManager.Config(c)
+func (apiHandler *APIHandler) handleGetHorizontalPodAutoscalerList(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	verber, err := apiHandler.cManager.VerberClient(c, config)
+	namespace := parseNamespacePathParameter(c)
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	result, err := horizontalpodautoscaler.GetHorizontalPodAutoscalerList(k8sClient, namespace, dataSelect)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
 
-	kind := c.Param("kind")
-	namespace := c.Param("namespace")
-	name := c.Param("name")
-	ok := namespace == ""
-	putSpec := &runtime.Unknown{}
-	if err := httphelper.ReadRequestBody(c,putSpec); err != nil {
+func (apiHandler *APIHandler) handleGetHorizontalPodAutoscalerListForResource(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-
-	if err := verber.Put(kind, ok, namespace, name, putSpec); err != nil {
+///:namespace/:horizontalpodautoscaler/:kind
+	namespace := c.Param("namespace")
+	name := c.Param("horizontalpodautoscaler")
+	kind := c.Param("kind")
+	result, err := horizontalpodautoscaler.GetHorizontalPodAutoscalerListForResource(k8sClient, namespace, kind, name)
+	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-
-	httphelper.RestfullResponse(c,http.StatusCreated, nil)
+	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleDeleteResource(
-	c *gin.Context) {
-	config, err := apiHandler.cManager.Config(c)
+func (apiHandler *APIHandler) handleGetHorizontalPodAutoscalerDetail(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	verber, err := apiHandler.cManager.VerberClient(c, config)
+	namespace := c.Param("namespace")
+	name := c.Param("horizontalpodautoscaler")
+	result, err := horizontalpodautoscaler.GetHorizontalPodAutoscalerDetail(k8sClient, namespace, name)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
 
-	kind := c.Param("kind")
-	namespace := c.Param("namespace")
-	name := c.Param("name")
-	ok := namespace == ""
-	if err := verber.Delete(kind, ok, namespace, name); err != nil {
+// handleCreateHorizontalPodAutoscaler creates an autoscaler in the namespace given in the path.
+func (apiHandler *APIHandler) handleCreateHorizontalPodAutoscaler(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
+	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	// Try to unpin resource if it was pinned.
-	pinnedResource := &settingsApi.PinnedResource{
-		Name:      name,
-		Kind:      kind,
-		Namespace: namespace,
-	}
-
-	k8sClient, err := apiHandler.cManager.Client(c)
+	config, err := apiHandler.cManager.Config(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	if err = apiHandler.sManager.DeletePinnedResource(k8sClient, pinnedResource); err != nil {
-		if !errors.IsNotFoundError(err) {
-			log.Printf("error while unpinning resource: %s", err.Error())
-		}
-	}
-
-	httphelper.RestfullResponse(c,http.StatusOK, nil)
-}
 
-func (apiHandler *APIHandler) handleGetReplicationControllerPods(c *gin.Context) {
-	k8sClient, err := apiHandler.cManager.Client(c)
-	if err != nil {
+	spec := new(horizontalpodautoscaler.HorizontalPodAutoscalerSpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
+	spec.Namespace = c.Param("namespace")
 
-	namespace := c.Param("namespace")
-	rc := c.Param("replicationController")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := replicationcontroller.GetReplicationControllerPods(k8sClient, apiHandler.iManager.Metric().Client(), dataSelect, rc, namespace)
+	result, err := horizontalpodautoscaler.CreateHorizontalPodAutoscaler(k8sClient, config, spec)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusCreated, result)
 }
 
-func (apiHandler *APIHandler) handleCreateNamespace(c *gin.Context) {
+func (apiHandler *APIHandler) handleUpdateHorizontalPodAutoscaler(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespaceSpec := new(ns.NamespaceSpec)
-	if err := httphelper.ReadRequestBody(c,namespaceSpec); err != nil {
-		errors.HandleInternalError(c, err)
-		return
-	}
-	if err := ns.CreateNamespace(namespaceSpec, k8sClient); err != nil {
+	config, err := apiHandler.cManager.Config(c)
+	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusCreated, namespaceSpec)
-}
 
-func (apiHandler *APIHandler) handleGetNamespaces(c *gin.Context) {
-	k8sClient, err := apiHandler.cManager.Client(c)
-	if err != nil {
+	spec := new(horizontalpodautoscaler.HorizontalPodAutoscalerSpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := ns.GetNamespaceList(k8sClient, dataSelect)
+	result, err := horizontalpodautoscaler.UpdateHorizontalPodAutoscaler(k8sClient, config, c.Param("namespace"),
+		c.Param("horizontalpodautoscaler"), spec)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetNamespaceDetail(c *gin.Context) {
+func (apiHandler *APIHandler) handleDeleteHorizontalPodAutoscaler(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	name := c.Param("name")
-	result, err := ns.GetNamespaceDetail(k8sClient, name)
+	err = horizontalpodautoscaler.DeleteHorizontalPodAutoscaler(k8sClient, c.Param("namespace"),
+		c.Param("horizontalpodautoscaler"))
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, nil)
 }
 
-func (apiHandler *APIHandler) handleGetNamespaceEvents(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetJobList(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	name := c.Param("name")
+	namespace := parseNamespacePathParameter(c)
 	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := event.GetNamespaceEvents(k8sClient, dataSelect, name)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := job.GetJobList(k8sClient, namespace, dataSelect, apiHandler.iManager.Metric().Client())
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -1469,27 +3047,24 @@ func (apiHandler *APIHandler) handleGetNamespaceEvents(c *gin.Context) {
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleCreateImagePullSecret(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetJobDetail(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	spec := new(secret.ImagePullSecretSpec)
-	if err := httphelper.ReadRequestBody(c,spec); err != nil {
-		errors.HandleInternalError(c, err)
-		return
-	}
-	result, err := secret.CreateSecret(k8sClient, spec)
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	result, err := job.GetJobDetail(k8sClient, namespace, name)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusCreated, result)
+	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetSecretDetail(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetJobPods(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
@@ -1498,7 +3073,9 @@ func (apiHandler *APIHandler) handleGetSecretDetail(c *gin.Context) {
 
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	result, err := secret.GetSecretDetail(k8sClient, namespace, name)
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := job.GetJobPods(k8sClient, apiHandler.iManager.Metric().Client(), dataSelect, namespace, name)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -1506,16 +3083,17 @@ func (apiHandler *APIHandler) handleGetSecretDetail(c *gin.Context) {
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetSecretList(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetJobEvents(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
+	namespace := c.Param("namespace")
+	name := c.Param("name")
 	dataSelect := parser.ParseDataSelectPathParameter(c)
-	namespace := parseNamespacePathParameter(c)
-	result, err := secret.GetSecretList(k8sClient, namespace, dataSelect)
+	result, err := job.GetJobEvents(k8sClient, dataSelect, namespace, name)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -1523,7 +3101,7 @@ func (apiHandler *APIHandler) handleGetSecretList(c *gin.Context) {
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetConfigMapList(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetCronJobList(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
@@ -1532,7 +3110,8 @@ func (apiHandler *APIHandler) handleGetConfigMapList(c *gin.Context) {
 
 	namespace := parseNamespacePathParameter(c)
 	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := configmap.GetConfigMapList(k8sClient, namespace, dataSelect)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := cronjob.GetCronJobList(k8sClient, namespace, dataSelect, apiHandler.iManager.Metric().Client())
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -1540,7 +3119,7 @@ func (apiHandler *APIHandler) handleGetConfigMapList(c *gin.Context) {
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetConfigMapDetail(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetCronJobDetail(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
@@ -1548,8 +3127,8 @@ func (apiHandler *APIHandler) handleGetConfigMapDetail(c *gin.Context) {
 	}
 
 	namespace := c.Param("namespace")
-	name := c.Param("configmap")
-	result, err := configmap.GetConfigMapDetail(k8sClient, namespace, name)
+	name := c.Param("name")
+	result, err := cronjob.GetCronJobDetail(k8sClient, namespace, name)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -1557,15 +3136,22 @@ func (apiHandler *APIHandler) handleGetConfigMapDetail(c *gin.Context) {
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetPersistentVolumeList(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetCronJobJobs(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	active := true
+	if c.Query("active") == "false" {
+		active = false
+	}
+
 	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := persistentvolume.GetPersistentVolumeList(k8sClient, dataSelect)
+	result, err := cronjob.GetCronJobJobs(k8sClient, apiHandler.iManager.Metric().Client(), dataSelect, namespace, name, active)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -1573,15 +3159,17 @@ func (apiHandler *APIHandler) handleGetPersistentVolumeList(c *gin.Context) {
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetPersistentVolumeDetail(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetCronJobEvents(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	name := c.Param("persistentvolume")
-	result, err := persistentvolume.GetPersistentVolumeDetail(k8sClient, name)
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	result, err := cronjob.GetCronJobEvents(k8sClient, dataSelect, namespace, name)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -1589,194 +3177,181 @@ func (apiHandler *APIHandler) handleGetPersistentVolumeDetail(c *gin.Context) {
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetPersistentVolumeClaimList(c *gin.Context) {
+func (apiHandler *APIHandler) handleTriggerCronJob(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := parseNamespacePathParameter(c)
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := persistentvolumeclaim.GetPersistentVolumeClaimList(k8sClient, namespace, dataSelect)
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	err = cronjob.TriggerCronJob(k8sClient, namespace, name)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c,http.StatusOK, nil)
 }
 
-func (apiHandler *APIHandler) handleGetPersistentVolumeClaimDetail(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetCronJobHistory(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("name")
-	result, err := persistentvolumeclaim.GetPersistentVolumeClaimDetail(k8sClient, namespace, name)
+	result, err := cronjob.GetCronJobHistory(k8sClient, c.Param("namespace"), c.Param("name"))
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetPodContainers(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetJobRun(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("pod")
-	result, err := container.GetPodContainers(k8sClient, namespace, name)
+	result, err := job.GetJobRun(k8sClient, c.Param("namespace"), c.Param("name"))
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetReplicationControllerEvents(c *gin.Context) {
+func (apiHandler *APIHandler) handleSuspendCronJob(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("replicationController")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := event.GetResourceEvents(k8sClient, dataSelect, namespace, name)
+	result, err := cronjob.SuspendCronJob(k8sClient, c.Param("namespace"), c.Param("name"))
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetReplicationControllerServices(c *gin.Context) {
+func (apiHandler *APIHandler) handleResumeCronJob(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("replicationController")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := replicationcontroller.GetReplicationControllerServices(k8sClient, dataSelect, namespace, name)
+	result, err := cronjob.ResumeCronJob(k8sClient, c.Param("namespace"), c.Param("name"))
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetDaemonSetList(c *gin.Context) {
+// handleUpdateCronJobSchedule sets a new schedule and returns the next run times. The number of run times is set
+// by the count query parameter.
+func (apiHandler *APIHandler) handleUpdateCronJobSchedule(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := parseNamespacePathParameter(c)
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := daemonset.GetDaemonSetList(k8sClient, namespace, dataSelect, apiHandler.iManager.Metric().Client())
+	dryRun, err := parseDryRunQueryParameter(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
-}
 
-func (apiHandler *APIHandler) handleGetDaemonSetDetail(
-	c *gin.Context) {
-	k8sClient, err := apiHandler.cManager.Client(c)
+	count, err := parseIntQueryParameter(c, "count")
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("daemonSet")
-	result, err := daemonset.GetDaemonSetDetail(k8sClient, apiHandler.iManager.Metric().Client(), namespace, name)
+	spec := new(cronjob.ScheduleSpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	result, err := cronjob.UpdateCronJobSchedule(k8sClient, c.Param("namespace"), c.Param("name"), spec,
+		int(count), dryRun)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetDaemonSetPods(c *gin.Context) {
+// handleDeleteFinishedJobs deletes the finished jobs of the namespaces. Only jobs that finished more than
+// olderThanSeconds ago are deleted, the parameter is required. The _all namespace selects jobs of all namespaces.
+func (apiHandler *APIHandler) handleDeleteFinishedJobs(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("daemonSet")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := daemonset.GetDaemonSetPods(k8sClient, apiHandler.iManager.Metric().Client(), dataSelect, name, namespace)
+	dryRun, err := parseDryRunQueryParameter(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
-}
 
-func (apiHandler *APIHandler) handleGetDaemonSetServices(c *gin.Context) {
-	k8sClient, err := apiHandler.cManager.Client(c)
+	if len(c.Query("olderThanSeconds")) == 0 {
+		errors.HandleInternalError(c, errors.NewBadRequest("olderThanSeconds parameter is required"))
+		return
+	}
+
+	olderThan, err := parseIntQueryParameter(c, "olderThanSeconds")
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	daemonSet := c.Param("daemonSet")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := daemonset.GetDaemonSetServices(k8sClient, dataSelect, namespace, daemonSet)
+	namespace := parseNamespacePathParameter(c)
+	if c.Param("namespace") == common.AllNamespacesPlaceholder {
+		namespace = common.NewNamespaceQuery(nil)
+	}
+	result, err := job.DeleteFinishedJobs(k8sClient, namespace, time.Duration(olderThan)*time.Second, dryRun)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetDaemonSetEvents(c *gin.Context) {
+func (apiHandler *APIHandler) handleRerunJob(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("daemonSet")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := event.GetResourceEvents(k8sClient, dataSelect, namespace, name)
+	result, err := job.RerunJob(k8sClient, c.Param("namespace"), c.Param("name"))
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusCreated, result)
 }
 
-func (apiHandler *APIHandler) handleGetHorizontalPodAutoscalerList(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetStorageClassList(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := parseNamespacePathParameter(c)
 	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := horizontalpodautoscaler.GetHorizontalPodAutoscalerList(k8sClient, namespace, dataSelect)
+	result, err := storageclass.GetStorageClassList(k8sClient, dataSelect)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -1784,17 +3359,15 @@ func (apiHandler *APIHandler) handleGetHorizontalPodAutoscalerList(c *gin.Contex
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetHorizontalPodAutoscalerListForResource(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetStorageClass(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-///:namespace/:horizontalpodautoscaler/:kind
-	namespace := c.Param("namespace")
-	name := c.Param("horizontalpodautoscaler")
-	kind := c.Param("kind")
-	result, err := horizontalpodautoscaler.GetHorizontalPodAutoscalerListForResource(k8sClient, namespace, kind, name)
+
+	name := c.Param("storageclass")
+	result, err := storageclass.GetStorageClass(k8sClient, name)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -1802,16 +3375,17 @@ func (apiHandler *APIHandler) handleGetHorizontalPodAutoscalerListForResource(c
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetHorizontalPodAutoscalerDetail(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetStorageClassPersistentVolumes(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("horizontalpodautoscaler")
-	result, err := horizontalpodautoscaler.GetHorizontalPodAutoscalerDetail(k8sClient, namespace, name)
+	name := c.Param("storageclass")
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	result, err := persistentvolume.GetStorageClassPersistentVolumes(k8sClient,
+		name, dataSelect)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -1819,17 +3393,18 @@ func (apiHandler *APIHandler) handleGetHorizontalPodAutoscalerDetail(c *gin.Cont
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetJobList(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetPodPersistentVolumeClaims(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := parseNamespacePathParameter(c)
+	name := c.Param("pod")
+	namespace := c.Param("namespace")
 	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := job.GetJobList(k8sClient, namespace, dataSelect, apiHandler.iManager.Metric().Client())
+	result, err := persistentvolumeclaim.GetPodPersistentVolumeClaims(k8sClient,
+		namespace, name, dataSelect)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -1837,289 +3412,278 @@ func (apiHandler *APIHandler) handleGetJobList(c *gin.Context) {
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetJobDetail(c *gin.Context) {
-	k8sClient, err := apiHandler.cManager.Client(c)
+func (apiHandler *APIHandler) handleGetCustomResourceDefinitionList(c *gin.Context) {
+	apiextensionsclient, err := apiHandler.cManager.APIExtensionsClient(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("name")
-	result, err := job.GetJobDetail(k8sClient, namespace, name)
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	result, err := customresourcedefinition.GetCustomResourceDefinitionList(apiextensionsclient, dataSelect)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
+
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetJobPods(c *gin.Context) {
-	k8sClient, err := apiHandler.cManager.Client(c)
+func (apiHandler *APIHandler) handleGetCustomResourceDefinitionDetail(c *gin.Context) {
+	config, err := apiHandler.cManager.Config(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("name")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := job.GetJobPods(k8sClient, apiHandler.iManager.Metric().Client(), dataSelect, namespace, name)
+	apiextensionsclient, err := apiHandler.cManager.APIExtensionsClient(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
-}
 
-func (apiHandler *APIHandler) handleGetJobEvents(c *gin.Context) {
-	k8sClient, err := apiHandler.cManager.Client(c)
+	name := c.Param("crd")
+	result, err := customresourcedefinition.GetCustomResourceDefinitionDetail(apiextensionsclient, config, name)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("name")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := job.GetJobEvents(k8sClient, dataSelect, namespace, name)
+	httphelper.RestfullResponse(c,http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetCustomResourceObjectList(c *gin.Context) {
+	config, err := apiHandler.cManager.Config(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
-}
 
-func (apiHandler *APIHandler) handleGetCronJobList(c *gin.Context) {
-	k8sClient, err := apiHandler.cManager.Client(c)
+	apiextensionsclient, err := apiHandler.cManager.APIExtensionsClient(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
+	crdName := c.Param("crd")
 	namespace := parseNamespacePathParameter(c)
 	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := cronjob.GetCronJobList(k8sClient, namespace, dataSelect, apiHandler.iManager.Metric().Client())
+	result, err := customresourcedefinition.GetCustomResourceObjectList(apiextensionsclient, config, namespace, dataSelect, crdName)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
+
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetCronJobDetail(c *gin.Context) {
-	k8sClient, err := apiHandler.cManager.Client(c)
+func (apiHandler *APIHandler) handleGetCustomResourceObjectDetail(c *gin.Context) {
+	config, err := apiHandler.cManager.Config(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("name")
-	result, err := cronjob.GetCronJobDetail(k8sClient, namespace, name)
+	apiextensionsclient, err := apiHandler.cManager.APIExtensionsClient(c)
+	if err != nil {
+		errors.HandleInternalError(c, err)
+		return
+	}
+
+	name := c.Param("object")
+	crdName := c.Param("crd")
+	namespace := parseNamespacePathParameter(c)
+	result, err := customresourcedefinition.GetCustomResourceObjectDetail(apiextensionsclient, namespace, config, crdName, name)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
+
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetCronJobJobs(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetCustomResourceObjectEvents(c *gin.Context) {
+	log.Println("Getting events related to a custom resource object in namespace")
+
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
+	name := c.Param("object")
 	namespace := c.Param("namespace")
-	name := c.Param("name")
-	active := true
-	if c.Query("active") == "false" {
-		active = false
-	}
-
 	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := cronjob.GetCronJobJobs(k8sClient, apiHandler.iManager.Metric().Client(), dataSelect, namespace, name, active)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := customresourcedefinition.GetEventsForCustomResourceObject(k8sClient, dataSelect, namespace, name)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
+
 	httphelper.RestfullResponse(c,http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetCronJobEvents(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetWorkloads(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("name")
+	namespace := parseNamespacePathParameter(c)
 	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := cronjob.GetCronJobEvents(k8sClient, dataSelect, namespace, name)
+	dataSelect.MetricQuery = dataselect.NoMetrics
+	result, err := workload.GetWorkloads(k8sClient, apiHandler.iManager.Metric().Client(), namespace, dataSelect)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleTriggerCronJob(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetDiscovery(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	namespace := c.Param("namespace")
-	name := c.Param("name")
-	err = cronjob.TriggerCronJob(k8sClient, namespace, name)
+	namespace := parseNamespacePathParameter(c)
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	result, err := discovery.GetDiscovery(k8sClient, namespace, dataSelect)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, nil)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetStorageClassList(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetConfig(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
+	namespace := parseNamespacePathParameter(c)
 	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := storageclass.GetStorageClassList(k8sClient, dataSelect)
+	result, err := config.GetConfig(k8sClient, namespace, dataSelect)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetStorageClass(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetCluster(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	name := c.Param("storageclass")
-	result, err := storageclass.GetStorageClass(k8sClient, name)
+	dataSelect := parser.ParseDataSelectPathParameter(c)
+	dataSelect.MetricQuery = dataselect.NoMetrics
+	result, err := cluster.GetCluster(k8sClient, apiHandler.iManager.Metric().Client(), dataSelect)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetStorageClassPersistentVolumes(c *gin.Context) {
+// handleGetCapacity returns the cluster capacity report. Nodes are grouped into pools by the poolLabel query
+// parameter.
+func (apiHandler *APIHandler) handleGetCapacity(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	name := c.Param("storageclass")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := persistentvolume.GetStorageClassPersistentVolumes(k8sClient,
-		name, dataSelect)
+	result, err := capacity.GetCapacityReport(k8sClient, apiHandler.iManager.Metric().Client(), c.Query("poolLabel"))
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetPodPersistentVolumeClaims(c *gin.Context) {
+func (apiHandler *APIHandler) handleGetCapacityFit(c *gin.Context) {
 	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	name := c.Param("pod")
-	namespace := c.Param("namespace")
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := persistentvolumeclaim.GetPodPersistentVolumeClaims(k8sClient,
-		namespace, name, dataSelect)
-	if err != nil {
+	spec := new(capacity.FitSpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-	httphelper.RestfullResponse(c,http.StatusOK, result)
-}
 
-func (apiHandler *APIHandler) handleGetCustomResourceDefinitionList(c *gin.Context) {
-	apiextensionsclient, err := apiHandler.cManager.APIExtensionsClient(c)
+	result, err := capacity.GetFit(k8sClient, spec)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
+}
 
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := customresourcedefinition.GetCustomResourceDefinitionList(apiextensionsclient, dataSelect)
+// handleGetDiagnosis returns the probable root causes of a pod or controller not being ready.
+func (apiHandler *APIHandler) handleGetDiagnosis(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	httphelper.RestfullResponse(c,http.StatusOK, result)
-}
-
-func (apiHandler *APIHandler) handleGetCustomResourceDefinitionDetail(c *gin.Context) {
-	config, err := apiHandler.cManager.Config(c)
+	result, err := diagnosis.GetDiagnosis(k8sClient, c.Param("kind"), c.Param("namespace"), c.Param("name"))
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
+	httphelper.RestfullResponse(c, http.StatusOK, result)
+}
 
-	apiextensionsclient, err := apiHandler.cManager.APIExtensionsClient(c)
+// handleBulkAction applies a single action to a list of resources or to all resources matching a label selector.
+// Failures are reported per resource.
+func (apiHandler *APIHandler) handleBulkAction(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	name := c.Param("crd")
-	result, err := customresourcedefinition.GetCustomResourceDefinitionDetail(apiextensionsclient, config, name)
+	config, err := apiHandler.cManager.Config(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	httphelper.RestfullResponse(c,http.StatusOK, result)
-}
-
-func (apiHandler *APIHandler) handleGetCustomResourceObjectList(c *gin.Context) {
-	config, err := apiHandler.cManager.Config(c)
+	verber, err := apiHandler.cManager.VerberClient(c, config)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	apiextensionsclient, err := apiHandler.cManager.APIExtensionsClient(c)
-	if err != nil {
+	spec := new(bulk.ActionSpec)
+	if err := httphelper.ReadRequestBody(c, spec); err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	crdName := c.Param("crd")
-	namespace := parseNamespacePathParameter(c)
-	dataSelect := parser.ParseDataSelectPathParameter(c)
-	result, err := customresourcedefinition.GetCustomResourceObjectList(apiextensionsclient, config, namespace, dataSelect, crdName)
+	result, err := bulk.Execute(verber, k8sClient, config, spec)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetCustomResourceObjectDetail(c *gin.Context) {
-	config, err := apiHandler.cManager.Config(c)
+func (apiHandler *APIHandler) handleSearch(c *gin.Context) {
+	k8sClient, err := apiHandler.cManager.Client(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
@@ -2131,38 +3695,26 @@ func (apiHandler *APIHandler) handleGetCustomResourceObjectDetail(c *gin.Context
 		return
 	}
 
-	name := c.Param("object")
-	crdName := c.Param("crd")
-	namespace := parseNamespacePathParameter(c)
-	result, err := customresourcedefinition.GetCustomResourceObjectDetail(apiextensionsclient, namespace, config, crdName, name)
+	config, err := apiHandler.cManager.Config(c)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	httphelper.RestfullResponse(c,http.StatusOK, result)
-}
-
-func (apiHandler *APIHandler) handleGetCustomResourceObjectEvents(c *gin.Context) {
-	log.Println("Getting events related to a custom resource object in namespace")
-
-	k8sClient, err := apiHandler.cManager.Client(c)
+	dynamicClient, err := dynamic.NewForConfig(config)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
 
-	name := c.Param("object")
-	namespace := c.Param("namespace")
+	namespace := parseNamespaceQueryParameter(c)
 	dataSelect := parser.ParseDataSelectPathParameter(c)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := customresourcedefinition.GetEventsForCustomResourceObject(k8sClient, dataSelect, namespace, name)
+	result, err := search.Search(k8sClient, apiextensionsclient, dynamicClient, namespace, c.Query("q"), dataSelect)
 	if err != nil {
 		errors.HandleInternalError(c, err)
 		return
 	}
-
-	httphelper.RestfullResponse(c,http.StatusOK, result)
+	httphelper.RestfullResponse(c, http.StatusOK, result)
 }
 
 func (apiHandler *APIHandler) handleLogSource(c *gin.Context) {
@@ -2279,3 +3831,75 @@ func parseNamespaceQueryParameter(c *gin.Context) *common.NamespaceQuery {
 	}
 	return common.NewNamespaceQuery(nonEmptyNamespaces)
 }
+
+// parsePatchType maps the Content-Type of a patch request to the patch type. Requests without a patch specific
+// content type are treated as JSON merge patches.
+func parsePatchType(contentType string) (types.PatchType, error) {
+	switch contentType {
+	case "", "application/json", string(types.MergePatchType):
+		return types.MergePatchType, nil
+	case string(types.StrategicMergePatchType):
+		return types.StrategicMergePatchType, nil
+	case string(types.ApplyPatchType):
+		return types.ApplyPatchType, nil
+	default:
+		return "", errors.NewBadRequest(fmt.Sprintf("Unsupported patch content type: %s", contentType))
+	}
+}
+
+// parseDryRunQueryParameter parses the optional dryRun query parameter. Requests are not dry runs by default.
+func parseDryRunQueryParameter(c *gin.Context) (bool, error) {
+	dryRun := c.Query("dryRun")
+	if len(dryRun) == 0 {
+		return false, nil
+	}
+
+	result, err := strconv.ParseBool(dryRun)
+	if err != nil {
+		return false, errors.NewBadRequest(fmt.Sprintf("Invalid dryRun parameter: %s", dryRun))
+	}
+	return result, nil
+}
+
+// parseGracePeriodQueryParameter parses the optional gracePeriodSeconds query parameter. No value means that the
+// default grace period of the resource is used.
+func parseGracePeriodQueryParameter(c *gin.Context) (*int64, error) {
+	value := c.Query("gracePeriodSeconds")
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	result, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, errors.NewBadRequest(fmt.Sprintf("Invalid gracePeriodSeconds parameter: %s", value))
+	}
+	return &result, nil
+}
+
+// parseBoolQueryParameter parses an optional boolean query parameter. False is returned if it is not set.
+func parseBoolQueryParameter(c *gin.Context, name string) (bool, error) {
+	value := c.Query(name)
+	if len(value) == 0 {
+		return false, nil
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.NewBadRequest(fmt.Sprintf("Invalid %s parameter: %s", name, value))
+	}
+	return result, nil
+}
+
+// parseIntQueryParameter parses an optional integer query parameter. Zero is returned if it is not set.
+func parseIntQueryParameter(c *gin.Context, name string) (int64, error) {
+	value := c.Query(name)
+	if len(value) == 0 {
+		return 0, nil
+	}
+
+	result, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, errors.NewBadRequest(fmt.Sprintf("Invalid %s parameter: %s", name, value))
+	}
+	return result, nil
+}
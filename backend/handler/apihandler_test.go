@@ -15,9 +15,13 @@
 package handler
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"bytes"
@@ -33,6 +37,7 @@ import (
 	"github.com/ycyxuehan/dashboard-gin/backend/settings"
 	"github.com/ycyxuehan/dashboard-gin/backend/sync"
 	"github.com/ycyxuehan/dashboard-gin/backend/systembanner"
+	"golang.org/x/net/xsrftoken"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
@@ -56,34 +61,108 @@ func TestCreateHTTPAPIHandler(t *testing.T) {
 }
 
 func TestShouldDoCsrfValidation(t *testing.T) {
-	c1, _ := gin.CreateTestContext(httptest.NewRecorder())
-	c1.Request = &http.Request{
-		Method: "PUT",
-	}
+	args.GetHolderBuilder().SetAuthenticationMode([]string{"token", "clientcertificate"})
+	defer args.GetHolderBuilder().SetAuthenticationMode(nil)
 
-	c2, _ := gin.CreateTestContext(httptest.NewRecorder())
-	c2.Request = &http.Request{
-		Method: "POST",
+	newContext := func(method string, header http.Header, state *tls.ConnectionState) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = &http.Request{Method: method, Header: header, URL: &url.URL{Path: "/api/v1/pod"}, TLS: state}
+		return c
 	}
+	tokenHeader := http.Header{}
+	tokenHeader.Set("Authorization", "Bearer token")
+	jweHeader := http.Header{}
+	jweHeader.Set(client.JWETokenHeader, "token")
 
 	cases := []struct {
 		request  *gin.Context
 		expected bool
 	}{
-		{
-			c1,
-			false,
-		},
-		{
-			c2,
-			true,
-		},
+		{newContext("PUT", http.Header{}, nil), false},
+		{newContext("POST", http.Header{}, nil), true},
+		{newContext("GET", http.Header{}, verifiedClientCertificate("admin")), false},
+		{newContext("PUT", http.Header{}, verifiedClientCertificate("admin")), true},
+		{newContext("PATCH", http.Header{}, verifiedClientCertificate("admin")), true},
+		{newContext("DELETE", http.Header{}, verifiedClientCertificate("admin")), true},
+		{newContext("DELETE", tokenHeader, verifiedClientCertificate("admin")), false},
+		{newContext("DELETE", jweHeader, verifiedClientCertificate("admin")), false},
 	}
 	for _, c := range cases {
 		actual := shouldDoCsrfValidation(c.request)
 		if actual != c.expected {
-			t.Errorf("shouldDoCsrfValidation(%#v) returns %#v, expected %#v", c.request, actual, c.expected)
+			t.Errorf("shouldDoCsrfValidation(%s %v) returns %#v, expected %#v", c.request.Request.Method,
+				c.request.Request.Header, actual, c.expected)
+		}
+	}
+}
+
+func TestValidateXSRFFilterWithClientCertificate(t *testing.T) {
+	args.GetHolderBuilder().SetAuthenticationMode([]string{"token", "clientcertificate"})
+	defer args.GetHolderBuilder().SetAuthenticationMode(nil)
+
+	const csrfKey = "test-key"
+	cases := []struct {
+		method, token, authorization string
+		expected                     int
+	}{
+		{http.MethodDelete, "", "", http.StatusUnauthorized},
+		{http.MethodPost, "", "", http.StatusUnauthorized},
+		{http.MethodPut, "invalid", "", http.StatusUnauthorized},
+		{http.MethodDelete, xsrftoken.Generate(csrfKey, "none", "pod"), "", http.StatusOK},
+		{http.MethodGet, "", "", http.StatusOK},
+		{http.MethodPost, "", "Bearer token", http.StatusOK},
+		{http.MethodDelete, "", "Bearer token", http.StatusOK},
+	}
+
+	for _, c := range cases {
+		e := gin.New()
+		e.Use(validateXSRFFilter(csrfKey))
+		e.Handle(c.method, "/api/v1/pod/:namespace/:name", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		req := httptest.NewRequest(c.method, "/api/v1/pod/default/test", nil)
+		req.TLS = verifiedClientCertificate("admin")
+		if len(c.token) > 0 {
+			req.Header.Set("X-CSRF-TOKEN", c.token)
 		}
+		if len(c.authorization) > 0 {
+			req.Header.Set("Authorization", c.authorization)
+		}
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, req)
+
+		if recorder.Code != c.expected {
+			t.Errorf("%s with token %q and authorization %q returned status %d, expected %d", c.method, c.token,
+				c.authorization, recorder.Code, c.expected)
+		}
+	}
+}
+
+func TestGetCredentialsHashWithClientCertificate(t *testing.T) {
+	hash := func(header http.Header, state *tls.ConnectionState) string {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = &http.Request{Header: header, TLS: state}
+		return getCredentialsHash(c)
+	}
+
+	if actual := hash(http.Header{}, nil); actual != "" {
+		t.Errorf("getCredentialsHash() without credentials returns %q, expected empty hash", actual)
+	}
+
+	admin := hash(http.Header{}, verifiedClientCertificate("admin"))
+	viewer := hash(http.Header{}, verifiedClientCertificate("viewer"))
+	if admin == "" || viewer == "" || admin == viewer {
+		t.Errorf("getCredentialsHash() returns %q and %q for different certificates, expected distinct hashes",
+			admin, viewer)
+	}
+
+	if actual := hash(http.Header{}, verifiedClientCertificate("admin")); actual != admin {
+		t.Errorf("getCredentialsHash() returns %q for the same certificate, expected %q", actual, admin)
+	}
+}
+
+func verifiedClientCertificate(user string) *tls.ConnectionState {
+	return &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: user}, Raw: []byte("raw-" + user)}}},
 	}
 }
 
@@ -174,3 +253,30 @@ func TestFormatRequestLog(t *testing.T) {
 		}
 	}
 }
+
+func TestParseNamespacePathParameter(t *testing.T) {
+	cases := []struct {
+		namespace string
+		expected  string
+		matches   []string
+	}{
+		{"default", "default", []string{"default"}},
+		{"a, b", "", []string{"a", "b"}},
+	}
+
+	for _, c := range cases {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Params = gin.Params{{Key: "namespace", Value: c.namespace}}
+		query := parseNamespacePathParameter(ctx)
+
+		if query.ToRequestParam() != c.expected {
+			t.Errorf("parseNamespacePathParameter(%s) requests namespace %q, expected %q", c.namespace,
+				query.ToRequestParam(), c.expected)
+		}
+		for _, namespace := range c.matches {
+			if !query.Matches(namespace) {
+				t.Errorf("parseNamespacePathParameter(%s) does not match namespace %s", c.namespace, namespace)
+			}
+		}
+	}
+}
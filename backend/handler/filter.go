@@ -29,6 +29,7 @@ import (
 
 	"github.com/ycyxuehan/dashboard-gin/backend/args"
 	authApi "github.com/ycyxuehan/dashboard-gin/backend/auth/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/client"
 	clientapi "github.com/ycyxuehan/dashboard-gin/backend/client/api"
 	"github.com/ycyxuehan/dashboard-gin/backend/errors"
 	"github.com/ycyxuehan/dashboard-gin/backend/utils/httphelper"
@@ -41,10 +42,10 @@ const (
 )
 
 // InstallFilters installs defined filter for given web service
-func InstallFilters(ws *gin.Engine, manager clientapi.ClientManager) {
+func InstallFilters(ws gin.IRoutes, manager clientapi.ClientManager) {
 	// ws.Filter(requestAndResponseLogger)
 	// ws.Filter(metricsFilter)
-	// ws.Filter(validateXSRFFilter(manager.CSRFKey()))
+	ws.Use(validateXSRFFilter(manager.CSRFKey()))
 	// ws.Filter(restrictedResourcesFilter)
 }
 
@@ -140,6 +141,12 @@ func metricsFilter(c *gin.Context/*chain *restful.FilterChain*/) {
 
 func validateXSRFFilter(csrfKey string) gin.HandlerFunc {
 	return func(c *gin.Context/*, chain *restful.FilterChain*/) {
+		// Only requests authenticated by the client certificate are validated for now, as
+		// the frontend does not send CSRF tokens along with all other requests yet.
+		if !client.IsClientCertificateRequest(c.Request) {
+			return
+		}
+
 		resource := mapUrlToResource(c.Request.URL.Path)
 
 		if resource == nil || (shouldDoCsrfValidation(c) &&
@@ -149,6 +156,7 @@ func validateXSRFFilter(csrfKey string) gin.HandlerFunc {
 			log.Print(err)
 			c.Header("Content-Type", "text/plain")
 			httphelper.RestfullResponse(c, http.StatusUnauthorized, err.Error()+"\n")
+			c.Abort()
 			return
 		}
 
@@ -157,10 +165,17 @@ func validateXSRFFilter(csrfKey string) gin.HandlerFunc {
 }
 
 // Post requests should set correct X-CSRF-TOKEN header, all other requests
-// should either not edit anything or be already safe to CSRF attacks (PUT
-// and DELETE)
+// should either not edit anything or be already safe to CSRF attacks (PUT,
+// PATCH and DELETE). Requests authenticated only by the client certificate
+// are not, as browsers send the certificate along with any request.
 func shouldDoCsrfValidation(c *gin.Context) bool {
-	if c.Request.Method != http.MethodPost {
+	switch c.Request.Method {
+	case http.MethodPost:
+	case http.MethodPut, http.MethodPatch, http.MethodDelete:
+		if !client.IsClientCertificateRequest(c.Request) {
+			return false
+		}
+	default:
 		return false
 	}
 
@@ -0,0 +1,298 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
+)
+
+// Filter expressions have following grammar:
+//
+//   expression := or
+//   or         := and (("or" | "||") and)*
+//   and        := unary (("and" | "&&")? unary)*
+//   unary      := ("not" | "!") unary | "(" expression ")" | term
+//   term       := "label:" selector | property operator value | value
+//
+// Supported operators are listed in dataselect.FilterOperators. Values containing whitespace or parentheses
+// have to be quoted, i.e. label:"env in (prod,staging)". A bare value is matched against the name of the
+// resource. Examples:
+//
+//   status!=Running
+//   not status=Running and name^=nginx
+//   (label:app=web or label:app=api) creationTimestamp>2020-01-01
+//   restarts>=3 || name~=^kube-.*-[0-9]+$
+
+type filterTokenType int
+
+const (
+	filterTokenTerm filterTokenType = iota
+	filterTokenAnd
+	filterTokenOr
+	filterTokenNot
+	filterTokenLeftParen
+	filterTokenRightParen
+)
+
+type filterToken struct {
+	tokenType filterTokenType
+	value     string
+}
+
+// ParseFilterExpression parses raw filter expression. It returns nil expression if raw expression is empty.
+func ParseFilterExpression(raw string) (dataselect.FilterExpression, error) {
+	tokens, err := tokenizeFilterExpression(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	p := &filterExpressionParser{tokens: tokens}
+	expression, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.position < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in filter expression", p.tokens[p.position].value)
+	}
+
+	return expression, nil
+}
+
+func tokenizeFilterExpression(raw string) ([]filterToken, error) {
+	tokens := make([]filterToken, 0)
+	runes := []rune(raw)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, filterToken{tokenType: filterTokenLeftParen, value: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, filterToken{tokenType: filterTokenRightParen, value: ")"})
+			i++
+		case r == '!' && (i+1 >= len(runes) || runes[i+1] != '='):
+			tokens = append(tokens, filterToken{tokenType: filterTokenNot, value: "!"})
+			i++
+		default:
+			word, quoted, next, err := readFilterWord(runes, i)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, newFilterWordToken(word, quoted))
+			i = next
+		}
+	}
+
+	return tokens, nil
+}
+
+// readFilterWord reads a single word starting at given position. Quoted parts of the word are unquoted and may
+// contain whitespace, parentheses and escaped quotes.
+func readFilterWord(runes []rune, start int) (word string, quoted bool, next int, err error) {
+	var builder strings.Builder
+	inQuotes := false
+
+	i := start
+	for ; i < len(runes); i++ {
+		r := runes[i]
+		if inQuotes {
+			switch {
+			case r == '\\' && i+1 < len(runes):
+				i++
+				builder.WriteRune(runes[i])
+			case r == '"':
+				inQuotes = false
+			default:
+				builder.WriteRune(r)
+			}
+			continue
+		}
+
+		if unicode.IsSpace(r) || r == '(' || r == ')' {
+			break
+		}
+
+		if r == '"' {
+			inQuotes = true
+			quoted = true
+			continue
+		}
+
+		builder.WriteRune(r)
+	}
+
+	if inQuotes {
+		return "", false, i, fmt.Errorf("unterminated quote in filter expression")
+	}
+
+	return builder.String(), quoted, i, nil
+}
+
+func newFilterWordToken(word string, quoted bool) filterToken {
+	if !quoted {
+		switch strings.ToLower(word) {
+		case "and", "&&":
+			return filterToken{tokenType: filterTokenAnd, value: word}
+		case "or", "||":
+			return filterToken{tokenType: filterTokenOr, value: word}
+		case "not":
+			return filterToken{tokenType: filterTokenNot, value: word}
+		}
+	}
+
+	return filterToken{tokenType: filterTokenTerm, value: word}
+}
+
+type filterExpressionParser struct {
+	tokens   []filterToken
+	position int
+}
+
+func (p *filterExpressionParser) peek() *filterToken {
+	if p.position >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.position]
+}
+
+func (p *filterExpressionParser) parseOr() (dataselect.FilterExpression, error) {
+	expression, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	expressions := []dataselect.FilterExpression{expression}
+	for token := p.peek(); token != nil && token.tokenType == filterTokenOr; token = p.peek() {
+		p.position++
+		expression, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		expressions = append(expressions, expression)
+	}
+
+	if len(expressions) == 1 {
+		return expressions[0], nil
+	}
+	return &dataselect.OrFilterExpression{Expressions: expressions}, nil
+}
+
+func (p *filterExpressionParser) parseAnd() (dataselect.FilterExpression, error) {
+	expression, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	expressions := []dataselect.FilterExpression{expression}
+	for token := p.peek(); token != nil; token = p.peek() {
+		if token.tokenType == filterTokenAnd {
+			p.position++
+		} else if token.tokenType != filterTokenTerm && token.tokenType != filterTokenNot &&
+			token.tokenType != filterTokenLeftParen {
+			break
+		}
+
+		expression, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		expressions = append(expressions, expression)
+	}
+
+	if len(expressions) == 1 {
+		return expressions[0], nil
+	}
+	return &dataselect.AndFilterExpression{Expressions: expressions}, nil
+}
+
+func (p *filterExpressionParser) parseUnary() (dataselect.FilterExpression, error) {
+	token := p.peek()
+	if token == nil {
+		return nil, fmt.Errorf("unexpected end of filter expression")
+	}
+	p.position++
+
+	switch token.tokenType {
+	case filterTokenNot:
+		expression, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &dataselect.NotFilterExpression{Expression: expression}, nil
+	case filterTokenLeftParen:
+		expression, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing == nil || closing.tokenType != filterTokenRightParen {
+			return nil, fmt.Errorf("missing closing parenthesis in filter expression")
+		}
+		p.position++
+		return expression, nil
+	case filterTokenTerm:
+		return parseFilterTerm(token.value)
+	default:
+		return nil, fmt.Errorf("unexpected %q in filter expression", token.value)
+	}
+}
+
+// parseFilterTerm parses a single term, i.e. name^=nginx or label:app=web.
+func parseFilterTerm(term string) (dataselect.FilterExpression, error) {
+	propertyEnd := strings.IndexFunc(term, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' && r != '-'
+	})
+	if propertyEnd == -1 {
+		// Bare value without any operator.
+		return dataselect.NewFilterTerm(dataselect.NameProperty, dataselect.FilterOperatorContains, term)
+	}
+
+	if propertyEnd == 0 {
+		return nil, fmt.Errorf("missing property name in filter term %q", term)
+	}
+
+	property := term[:propertyEnd]
+	rest := term[propertyEnd:]
+	for _, operator := range dataselect.FilterOperators {
+		if !strings.HasPrefix(rest, string(operator)) {
+			continue
+		}
+
+		value := rest[len(operator):]
+		if property == dataselect.LabelProperty && operator == dataselect.FilterOperatorContains {
+			term, err := dataselect.NewLabelFilterTerm(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid label selector %q: %s", value, err)
+			}
+			return term, nil
+		}
+
+		return dataselect.NewFilterTerm(dataselect.PropertyName(property), operator, value)
+	}
+
+	return nil, fmt.Errorf("unsupported operator in filter term %q", term)
+}
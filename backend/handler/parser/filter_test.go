@@ -0,0 +1,136 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
+)
+
+type testCell struct {
+	name   string
+	status string
+	labels map[string]string
+}
+
+func (self testCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(self.name)
+	case dataselect.StatusProperty:
+		return dataselect.StdComparableString(self.status)
+	case dataselect.LabelProperty:
+		return dataselect.StdComparableLabels(self.labels)
+	default:
+		return nil
+	}
+}
+
+var testCells = []testCell{
+	{"nginx-1", "Running", map[string]string{"app": "web", "env": "prod"}},
+	{"nginx-2", "Pending", map[string]string{"app": "web", "env": "staging"}},
+	{"redis", "Failed", map[string]string{"app": "db", "env": "prod"}},
+	{"my app", "Running", nil},
+}
+
+func TestParseFilterExpression(t *testing.T) {
+	cases := []struct {
+		expression string
+		expected   []string
+	}{
+		{"status!=Running", []string{"nginx-2", "redis"}},
+		{"not status=Running", []string{"nginx-2", "redis"}},
+		{"!status=Running", []string{"nginx-2", "redis"}},
+		{"name^=nginx status=Running", []string{"nginx-1"}},
+		{"name^=nginx and status=Running", []string{"nginx-1"}},
+		{"name^=nginx && status=Running", []string{"nginx-1"}},
+		{"status=Failed or status=Pending", []string{"nginx-2", "redis"}},
+		{"status=Failed || label:env=staging", []string{"nginx-2", "redis"}},
+		{"label:app=web,env=prod", []string{"nginx-1"}},
+		{`label:"env in (prod, staging)" not (name~=^nginx-[0-9]$)`, []string{"redis"}},
+		{"label:app=web or label:app=db and status=Running", []string{"nginx-1", "nginx-2"}},
+		{"(label:app=web or label:app=db) and status=Running", []string{"nginx-1"}},
+		{`name="my app"`, []string{"my app"}},
+		{"redis", []string{"redis"}},
+		{"NOT name:nginx AND NOT name:redis", []string{"my app"}},
+	}
+
+	for _, c := range cases {
+		expression, err := ParseFilterExpression(c.expression)
+		if err != nil {
+			t.Errorf("ParseFilterExpression(%q) returned error: %s", c.expression, err)
+			continue
+		}
+
+		actual := []string{}
+		for _, cell := range testCells {
+			if expression.Matches(cell) {
+				actual = append(actual, cell.name)
+			}
+		}
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("ParseFilterExpression(%q) matches %v, expected %v", c.expression, actual, c.expected)
+		}
+	}
+}
+
+func TestParseFilterExpressionEmpty(t *testing.T) {
+	expression, err := ParseFilterExpression("   ")
+	if expression != nil || err != nil {
+		t.Errorf("ParseFilterExpression() should return nil expression for empty input, got %v, %v",
+			expression, err)
+	}
+}
+
+func TestParseFilterExpressionInvalid(t *testing.T) {
+	cases := []string{
+		"status=Running and",
+		"(status=Running",
+		"status=Running)",
+		"=Running",
+		`name="unterminated`,
+		"name~=[a-",
+		"label:app=web=",
+		"or status=Running",
+	}
+
+	for _, c := range cases {
+		if _, err := ParseFilterExpression(c); err == nil {
+			t.Errorf("ParseFilterExpression(%q) should return error", c)
+		}
+	}
+}
+
+func TestParseDataSelectPathParameterFilter(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/api/v1/pod?filterBy=name,nginx&filter=status!%3DRunning", nil)
+
+	dataSelect := ParseDataSelectPathParameter(c)
+	if len(dataSelect.FilterQuery.FilterByList) != 1 || dataSelect.FilterQuery.Expression == nil {
+		t.Fatalf("ParseDataSelectPathParameter() should parse both filterBy and filter, got %+v",
+			dataSelect.FilterQuery)
+	}
+
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/api/v1/pod?filter=status%3DRunning%20and", nil)
+	if ParseDataSelectPathParameter(c).FilterQuery != dataselect.NoFilter {
+		t.Error("ParseDataSelectPathParameter() should ignore invalid filter expression")
+	}
+}
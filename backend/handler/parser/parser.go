@@ -15,6 +15,7 @@
 package parser
 
 import (
+	"log"
 	"strconv"
 	"strings"
 
@@ -38,8 +39,16 @@ func parsePaginationPathParameter(c *gin.Context) *dataselect.PaginationQuery {
 	return dataselect.NewPaginationQuery(int(itemsPerPage), int(page-1))
 }
 
+// Parses filterBy and filter query parameters of the request and returns a FilterQuery object. Invalid filter
+// expression is logged and ignored, the same way as invalid sort and pagination options are.
 func parseFilterPathParameter(c *gin.Context) *dataselect.FilterQuery {
-	return dataselect.NewFilterQuery(strings.Split(c.Query("filterBy"), ","))
+	filterQuery := dataselect.NewFilterQuery(strings.Split(c.Query("filterBy"), ","))
+	expression, err := ParseFilterExpression(c.Query("filter"))
+	if err != nil {
+		log.Printf("Ignoring invalid filter expression: %s", err.Error())
+		return filterQuery
+	}
+	return dataselect.NewFilterExpressionQuery(filterQuery, expression)
 }
 
 // Parses query parameters of the request and returns a SortQuery object
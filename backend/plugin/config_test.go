@@ -120,6 +120,10 @@ func (cm *fakeClientManager) CSRFKey() string {
 	panic("implement me")
 }
 
+func (cm *fakeClientManager) User(c *gin.Context) (string, error) {
+	panic("implement me")
+}
+
 func (cm *fakeClientManager) HasAccess(authInfo api.AuthInfo) error {
 	panic("implement me")
 }
@@ -53,6 +53,8 @@ func (p PluginCell) GetProperty(name dataselect.PropertyName) dataselect.Compara
 		return dataselect.StdComparableTime(p.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(p.ObjectMeta.Namespace)
+	case dataselect.LabelProperty:
+		return dataselect.StdComparableLabels(p.ObjectMeta.Labels)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
@@ -0,0 +1,271 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capacity
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	metricapi "github.com/ycyxuehan/dashboard-gin/backend/integration/metric/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/node"
+)
+
+// DefaultPoolLabel is the node label used to group nodes into pools if no other label is given.
+const DefaultPoolLabel = "node.kubernetes.io/instance-type"
+
+// ResourceSummary sums up allocatable, requested, limited and used resources of a set of nodes or pods. CPU is
+// counted in milicores and memory in bytes.
+type ResourceSummary struct {
+	CPUAllocatable int64 `json:"cpuAllocatable"`
+	CPURequests    int64 `json:"cpuRequests"`
+	CPULimits      int64 `json:"cpuLimits"`
+	CPUUsage       int64 `json:"cpuUsage"`
+
+	MemoryAllocatable int64 `json:"memoryAllocatable"`
+	MemoryRequests    int64 `json:"memoryRequests"`
+	MemoryLimits      int64 `json:"memoryLimits"`
+	MemoryUsage       int64 `json:"memoryUsage"`
+
+	PodAllocatable int64 `json:"podAllocatable"`
+	Pods           int64 `json:"pods"`
+}
+
+// NodePoolCapacity is the capacity of all nodes sharing the same value of the pool label. Nodes without the label
+// are grouped into a pool with an empty name.
+type NodePoolCapacity struct {
+	Name            string   `json:"name"`
+	Nodes           []string `json:"nodes"`
+	ResourceSummary `json:",inline"`
+}
+
+// NamespaceCapacity is the resource consumption of all pods in a namespace. Namespaces have no allocatable
+// resources of their own.
+type NamespaceCapacity struct {
+	Name            string `json:"name"`
+	ResourceSummary `json:",inline"`
+}
+
+// CapacityReport is the cluster-wide capacity planning report.
+type CapacityReport struct {
+	// PoolLabel is the node label used to group nodes into pools.
+	PoolLabel string `json:"poolLabel"`
+
+	Cluster ResourceSummary `json:"cluster"`
+
+	NodePools []NodePoolCapacity `json:"nodePools"`
+
+	Namespaces []NamespaceCapacity `json:"namespaces"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// podResources are the requests, limits and usage of a single pod.
+type podResources struct {
+	pod    v1.Pod
+	amount ResourceSummary
+}
+
+// GetCapacityReport returns allocatable, requested, limited and used resources per node pool, per namespace and
+// for the whole cluster. Usage is only filled in if metrics are available.
+func GetCapacityReport(client client.Interface, metricClient metricapi.MetricClient,
+	poolLabel string) (*CapacityReport, error) {
+	if len(poolLabel) == 0 {
+		poolLabel = DefaultPoolLabel
+	}
+	log.Printf("Getting cluster capacity report grouped by %s", poolLabel)
+
+	nodes, pods, nonCriticalErrors, err := getNodesAndPods(client)
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := getPodResources(pods, metricClient)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CapacityReport{
+		PoolLabel:  poolLabel,
+		NodePools:  make([]NodePoolCapacity, 0),
+		Namespaces: make([]NamespaceCapacity, 0),
+		Errors:     nonCriticalErrors,
+	}
+
+	nodeUsage := getNodeUsage(nodes, metricClient)
+	podsByNode := make(map[string][]podResources)
+	for _, item := range resources {
+		podsByNode[item.pod.Spec.NodeName] = append(podsByNode[item.pod.Spec.NodeName], item)
+	}
+
+	pools := make(map[string]*NodePoolCapacity)
+	for i, item := range nodes {
+		summary := getNodeSummary(item, podsByNode[item.Name], nodeUsage[i])
+
+		name := item.Labels[poolLabel]
+		pool, ok := pools[name]
+		if !ok {
+			pool = &NodePoolCapacity{Name: name, Nodes: make([]string, 0)}
+			pools[name] = pool
+		}
+		pool.Nodes = append(pool.Nodes, item.Name)
+		pool.add(summary)
+		report.Cluster.add(summary)
+	}
+
+	namespaces := make(map[string]*NamespaceCapacity)
+	for _, item := range resources {
+		namespace, ok := namespaces[item.pod.Namespace]
+		if !ok {
+			namespace = &NamespaceCapacity{Name: item.pod.Namespace}
+			namespaces[item.pod.Namespace] = namespace
+		}
+		namespace.add(item.amount)
+	}
+
+	for _, pool := range pools {
+		report.NodePools = append(report.NodePools, *pool)
+	}
+	sort.Slice(report.NodePools, func(i, j int) bool { return report.NodePools[i].Name < report.NodePools[j].Name })
+
+	for _, namespace := range namespaces {
+		report.Namespaces = append(report.Namespaces, *namespace)
+	}
+	sort.Slice(report.Namespaces, func(i, j int) bool { return report.Namespaces[i].Name < report.Namespaces[j].Name })
+
+	return report, nil
+}
+
+// getNodesAndPods lists all nodes and all pods that are not terminated yet.
+func getNodesAndPods(client client.Interface) ([]v1.Node, []v1.Pod, []error, error) {
+	nodes, err := client.CoreV1().Nodes().List(context.TODO(), api.ListEverything)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	fieldSelector, err := fields.ParseSelector("status.phase!=" + string(v1.PodSucceeded) +
+		",status.phase!=" + string(v1.PodFailed))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	pods, err := client.CoreV1().Pods(v1.NamespaceAll).List(context.TODO(), metaV1.ListOptions{
+		FieldSelector: fieldSelector.String(),
+	})
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, nil, nil, criticalError
+	}
+
+	items := make([]v1.Pod, 0)
+	if pods != nil {
+		for _, pod := range pods.Items {
+			if pod.Status.Phase != v1.PodSucceeded && pod.Status.Phase != v1.PodFailed {
+				items = append(items, pod)
+			}
+		}
+	}
+
+	return nodes.Items, items, nonCriticalErrors, nil
+}
+
+// getPodResources returns requests, limits and usage of every pod. Pods that are not scheduled yet are included,
+// as they count towards their namespace.
+func getPodResources(pods []v1.Pod, metricClient metricapi.MetricClient) ([]podResources, error) {
+	selectors := make([]metricapi.ResourceSelector, len(pods))
+	for i, pod := range pods {
+		selectors[i] = metricapi.ResourceSelector{
+			Namespace:    pod.Namespace,
+			ResourceType: api.ResourceKindPod,
+			ResourceName: pod.Name,
+			UID:          pod.UID,
+		}
+	}
+	usage := downloadUsage(selectors, metricClient)
+
+	result := make([]podResources, len(pods))
+	for i, pod := range pods {
+		reqs, limits, err := node.PodRequestsAndLimits(&pod)
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = podResources{
+			pod: pod,
+			amount: ResourceSummary{
+				CPURequests:    reqs.Cpu().MilliValue(),
+				CPULimits:      limits.Cpu().MilliValue(),
+				CPUUsage:       usage[i].cpu,
+				MemoryRequests: reqs.Memory().Value(),
+				MemoryLimits:   limits.Memory().Value(),
+				MemoryUsage:    usage[i].memory,
+				Pods:           1,
+			},
+		}
+	}
+
+	return result, nil
+}
+
+func getNodeUsage(nodes []v1.Node, metricClient metricapi.MetricClient) []resourceUsage {
+	selectors := make([]metricapi.ResourceSelector, len(nodes))
+	for i, item := range nodes {
+		selectors[i] = metricapi.ResourceSelector{
+			ResourceType: api.ResourceKindNode,
+			ResourceName: item.Name,
+			UID:          item.UID,
+		}
+	}
+	return downloadUsage(selectors, metricClient)
+}
+
+// getNodeSummary sums up the pods of the node. Node usage is taken from node metrics, as it also contains system
+// processes that are not part of any pod.
+func getNodeSummary(item v1.Node, pods []podResources, usage resourceUsage) ResourceSummary {
+	summary := ResourceSummary{
+		CPUAllocatable:    item.Status.Allocatable.Cpu().MilliValue(),
+		MemoryAllocatable: item.Status.Allocatable.Memory().Value(),
+		PodAllocatable:    item.Status.Allocatable.Pods().Value(),
+	}
+
+	for _, pod := range pods {
+		summary.add(pod.amount)
+	}
+	summary.CPUUsage = usage.cpu
+	summary.MemoryUsage = usage.memory
+
+	return summary
+}
+
+func (summary *ResourceSummary) add(other ResourceSummary) {
+	summary.CPUAllocatable += other.CPUAllocatable
+	summary.CPURequests += other.CPURequests
+	summary.CPULimits += other.CPULimits
+	summary.CPUUsage += other.CPUUsage
+	summary.MemoryAllocatable += other.MemoryAllocatable
+	summary.MemoryRequests += other.MemoryRequests
+	summary.MemoryLimits += other.MemoryLimits
+	summary.MemoryUsage += other.MemoryUsage
+	summary.PodAllocatable += other.PodAllocatable
+	summary.Pods += other.Pods
+}
@@ -0,0 +1,193 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capacity
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	integrationapi "github.com/ycyxuehan/dashboard-gin/backend/integration/api"
+	metricapi "github.com/ycyxuehan/dashboard-gin/backend/integration/metric/api"
+)
+
+// fakeMetricClient returns the given usage of every resource by its name.
+type fakeMetricClient struct {
+	usage map[string]resourceUsage
+}
+
+func (fakeMetricClient) ID() integrationapi.IntegrationID {
+	return "fake"
+}
+
+func (fakeMetricClient) HealthCheck() error {
+	return nil
+}
+
+func (self fakeMetricClient) DownloadMetric(selectors []metricapi.ResourceSelector, metricName string,
+	cachedResources *metricapi.CachedResources) metricapi.MetricPromises {
+	promises := metricapi.NewMetricPromises(len(selectors))
+	metrics := make([]metricapi.Metric, len(selectors))
+	for i, selector := range selectors {
+		value := self.usage[selector.ResourceName].cpu
+		if metricName == metricapi.MemoryUsage {
+			value = self.usage[selector.ResourceName].memory
+		}
+		metrics[i] = metricapi.Metric{DataPoints: metricapi.DataPoints{{X: 1, Y: 0}, {X: 2, Y: value}}}
+	}
+	promises.PutMetrics(metrics, nil)
+	return promises
+}
+
+func (self fakeMetricClient) DownloadMetrics(selectors []metricapi.ResourceSelector, metricNames []string,
+	cachedResources *metricapi.CachedResources) metricapi.MetricPromises {
+	result := metricapi.MetricPromises{}
+	for _, metricName := range metricNames {
+		result = append(result, self.DownloadMetric(selectors, metricName, cachedResources)...)
+	}
+	return result
+}
+
+func (fakeMetricClient) AggregateMetrics(metrics metricapi.MetricPromises, metricName string,
+	aggregations metricapi.AggregationModes) metricapi.MetricPromises {
+	return metrics
+}
+
+func getCapacityTestNode(name, pool, cpu, memory string) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Labels: map[string]string{DefaultPoolLabel: pool}},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse(cpu),
+				v1.ResourceMemory: resource.MustParse(memory),
+				v1.ResourcePods:   resource.MustParse("10"),
+			},
+			Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+		},
+	}
+}
+
+func getCapacityTestPod(name, namespace, nodeName, cpu, memory string) *v1.Pod {
+	resources := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse(cpu),
+		v1.ResourceMemory: resource.MustParse(memory),
+	}
+	return &v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: v1.PodSpec{
+			NodeName: nodeName,
+			Containers: []v1.Container{{
+				Resources: v1.ResourceRequirements{Requests: resources, Limits: resources},
+			}},
+		},
+		Status: v1.PodStatus{Phase: v1.PodRunning},
+	}
+}
+
+func TestGetCapacityReport(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		getCapacityTestNode("node-1", "large", "4", "8Gi"),
+		getCapacityTestNode("node-2", "large", "4", "8Gi"),
+		getCapacityTestNode("node-3", "small", "2", "4Gi"),
+		getCapacityTestPod("web", "default", "node-1", "1", "1Gi"),
+		getCapacityTestPod("db", "storage", "node-2", "2", "2Gi"),
+		getCapacityTestPod("cache", "default", "node-3", "500m", "512Mi"),
+	)
+	metricClient := fakeMetricClient{usage: map[string]resourceUsage{
+		"node-1": {cpu: 800, memory: 1024},
+		"node-3": {cpu: 100, memory: 512},
+		"web":    {cpu: 500, memory: 256},
+		"cache":  {cpu: 50, memory: 128},
+	}}
+
+	actual, err := GetCapacityReport(client, metricClient, "")
+	if err != nil {
+		t.Fatalf("GetCapacityReport() returned error: %s", err)
+	}
+
+	gi := int64(1024 * 1024 * 1024)
+	expected := &CapacityReport{
+		PoolLabel: DefaultPoolLabel,
+		Cluster: ResourceSummary{
+			CPUAllocatable: 10000, CPURequests: 3500, CPULimits: 3500, CPUUsage: 900,
+			MemoryAllocatable: 20 * gi, MemoryRequests: 3*gi + gi/2, MemoryLimits: 3*gi + gi/2, MemoryUsage: 1536,
+			PodAllocatable: 30, Pods: 3,
+		},
+		NodePools: []NodePoolCapacity{
+			{
+				Name:  "large",
+				Nodes: []string{"node-1", "node-2"},
+				ResourceSummary: ResourceSummary{
+					CPUAllocatable: 8000, CPURequests: 3000, CPULimits: 3000, CPUUsage: 800,
+					MemoryAllocatable: 16 * gi, MemoryRequests: 3 * gi, MemoryLimits: 3 * gi, MemoryUsage: 1024,
+					PodAllocatable: 20, Pods: 2,
+				},
+			},
+			{
+				Name:  "small",
+				Nodes: []string{"node-3"},
+				ResourceSummary: ResourceSummary{
+					CPUAllocatable: 2000, CPURequests: 500, CPULimits: 500, CPUUsage: 100,
+					MemoryAllocatable: 4 * gi, MemoryRequests: gi / 2, MemoryLimits: gi / 2, MemoryUsage: 512,
+					PodAllocatable: 10, Pods: 1,
+				},
+			},
+		},
+		Namespaces: []NamespaceCapacity{
+			{
+				Name: "default",
+				ResourceSummary: ResourceSummary{
+					CPURequests: 1500, CPULimits: 1500, CPUUsage: 550,
+					MemoryRequests: gi + gi/2, MemoryLimits: gi + gi/2, MemoryUsage: 384, Pods: 2,
+				},
+			},
+			{
+				Name: "storage",
+				ResourceSummary: ResourceSummary{
+					CPURequests: 2000, CPULimits: 2000, MemoryRequests: 2 * gi, MemoryLimits: 2 * gi, Pods: 1,
+				},
+			},
+		},
+		Errors: []error{},
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetCapacityReport() == %#v, expected %#v", actual, expected)
+	}
+}
+
+func TestGetCapacityReportWithoutMetrics(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "node-1", Labels: map[string]string{"pool": "a"}}},
+		&v1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "node-2"}},
+	)
+
+	actual, err := GetCapacityReport(client, nil, "pool")
+	if err != nil {
+		t.Fatalf("GetCapacityReport() returned error: %s", err)
+	}
+
+	pools := make([]string, 0)
+	for _, pool := range actual.NodePools {
+		pools = append(pools, pool.Name)
+	}
+	if expected := []string{"", "a"}; !reflect.DeepEqual(pools, expected) {
+		t.Errorf("GetCapacityReport() returned pools %v, expected %v", pools, expected)
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capacity
+
+import (
+	"fmt"
+	"log"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/node"
+)
+
+const (
+	// defaultMaxReplicas is used when the fit spec does not limit the number of simulated replicas.
+	defaultMaxReplicas = 1000
+
+	// maxReplicasLimit is the highest number of replicas that can be simulated.
+	maxReplicasLimit = 10000
+)
+
+// FitSpec describes the pod template of which as many replicas as possible are placed on the current nodes.
+type FitSpec struct {
+	Template v1.PodTemplateSpec `json:"template"`
+
+	// MaxReplicas stops the simulation after the given number of replicas. Defaults to 1000.
+	MaxReplicas int `json:"maxReplicas"`
+}
+
+// NodeFit is the number of replicas placed on a single node.
+type NodeFit struct {
+	Name     string `json:"name"`
+	Replicas int    `json:"replicas"`
+
+	// Reason explains why no replica fits on the node.
+	Reason string `json:"reason,omitempty"`
+}
+
+// FitResult is the result of the bin-packing simulation.
+type FitResult struct {
+	// Replicas is the number of additional replicas that fit on the current nodes.
+	Replicas int `json:"replicas"`
+
+	// Limited is true if the simulation stopped at the maximum number of replicas.
+	Limited bool `json:"limited"`
+
+	// CPURequests is number of milicores requested by a single replica.
+	CPURequests int64 `json:"cpuRequests"`
+
+	// MemoryRequests is number of bytes requested by a single replica.
+	MemoryRequests int64 `json:"memoryRequests"`
+
+	Nodes []NodeFit `json:"nodes"`
+}
+
+// nodeCapacity is the free capacity of a node during the simulation.
+type nodeCapacity struct {
+	fit                   *NodeFit
+	cpu, memory, pods     int64
+	cpuTotal, memoryTotal int64
+}
+
+// GetFit simulates how many more replicas of the pod template fit on the current nodes. Replicas are placed one
+// by one on the least allocated node, like the default scheduler spreads pods. CPU, memory, pod count, node
+// selector and NoSchedule and NoExecute taints are taken into account, other scheduling constraints are not.
+func GetFit(client client.Interface, spec *FitSpec) (*FitResult, error) {
+	log.Print("Simulating pod template fit on cluster nodes")
+
+	maxReplicas := spec.MaxReplicas
+	if maxReplicas < 0 || maxReplicas > maxReplicasLimit {
+		return nil, errors.NewInvalid(fmt.Sprintf("Max replicas must be between 0 and %d", maxReplicasLimit))
+	} else if maxReplicas == 0 {
+		maxReplicas = defaultMaxReplicas
+	}
+
+	nodes, pods, _, err := getNodesAndPods(client)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &v1.Pod{ObjectMeta: spec.Template.ObjectMeta, Spec: spec.Template.Spec}
+	reqs, _, err := node.PodRequestsAndLimits(template)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &FitResult{
+		CPURequests:    reqs.Cpu().MilliValue(),
+		MemoryRequests: reqs.Memory().Value(),
+		Nodes:          make([]NodeFit, len(nodes)),
+	}
+
+	candidates, err := getCandidates(nodes, pods, template, result)
+	if err != nil {
+		return nil, err
+	}
+
+	for result.Replicas < maxReplicas {
+		best := getLeastAllocated(candidates, result.CPURequests, result.MemoryRequests)
+		if best == nil {
+			break
+		}
+
+		best.cpu -= result.CPURequests
+		best.memory -= result.MemoryRequests
+		best.pods--
+		best.fit.Replicas++
+		result.Replicas++
+	}
+	result.Limited = result.Replicas == maxReplicas
+
+	for _, candidate := range candidates {
+		if candidate.fit.Replicas == 0 {
+			candidate.fit.Reason = getInsufficientReason(candidate, result.CPURequests, result.MemoryRequests)
+		}
+	}
+
+	return result, nil
+}
+
+// getCandidates returns the free capacity of all nodes the template can be scheduled on. Nodes it cannot be
+// scheduled on get a reason in the result.
+func getCandidates(nodes []v1.Node, pods []v1.Pod, template *v1.Pod, result *FitResult) ([]*nodeCapacity, error) {
+	podsByNode := make(map[string][]v1.Pod)
+	for _, pod := range pods {
+		podsByNode[pod.Spec.NodeName] = append(podsByNode[pod.Spec.NodeName], pod)
+	}
+
+	candidates := make([]*nodeCapacity, 0)
+	for i, item := range nodes {
+		result.Nodes[i] = NodeFit{Name: item.Name}
+		if reason := getUnschedulableReason(item, template); len(reason) > 0 {
+			result.Nodes[i].Reason = reason
+			continue
+		}
+
+		candidate := &nodeCapacity{
+			fit:         &result.Nodes[i],
+			cpu:         item.Status.Allocatable.Cpu().MilliValue(),
+			memory:      item.Status.Allocatable.Memory().Value(),
+			pods:        item.Status.Allocatable.Pods().Value(),
+			cpuTotal:    item.Status.Allocatable.Cpu().MilliValue(),
+			memoryTotal: item.Status.Allocatable.Memory().Value(),
+		}
+
+		for _, pod := range podsByNode[item.Name] {
+			reqs, _, err := node.PodRequestsAndLimits(&pod)
+			if err != nil {
+				return nil, err
+			}
+			candidate.cpu -= reqs.Cpu().MilliValue()
+			candidate.memory -= reqs.Memory().Value()
+			candidate.pods--
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
+// getUnschedulableReason returns why the template cannot be scheduled on the node regardless of free resources,
+// or an empty string if it can.
+func getUnschedulableReason(item v1.Node, template *v1.Pod) string {
+	if item.Spec.Unschedulable {
+		return "Node is cordoned"
+	}
+
+	ready := false
+	for _, condition := range item.Status.Conditions {
+		if condition.Type == v1.NodeReady && condition.Status == v1.ConditionTrue {
+			ready = true
+		}
+	}
+	if !ready {
+		return "Node is not ready"
+	}
+
+	if !labels.SelectorFromSet(template.Spec.NodeSelector).Matches(labels.Set(item.Labels)) {
+		return "Node selector does not match"
+	}
+
+	for i := range item.Spec.Taints {
+		taint := &item.Spec.Taints[i]
+		if taint.Effect == v1.TaintEffectPreferNoSchedule || isTolerated(template.Spec.Tolerations, taint) {
+			continue
+		}
+		return fmt.Sprintf("Taint %s is not tolerated", taint.ToString())
+	}
+
+	return ""
+}
+
+func isTolerated(tolerations []v1.Toleration, taint *v1.Taint) bool {
+	for _, toleration := range tolerations {
+		if toleration.ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// getLeastAllocated returns the node with the highest free share of CPU and memory that still fits the requests.
+func getLeastAllocated(candidates []*nodeCapacity, cpu, memory int64) *nodeCapacity {
+	var best *nodeCapacity
+	bestScore := -1.0
+
+	for _, candidate := range candidates {
+		if candidate.pods < 1 || candidate.cpu < cpu || candidate.memory < memory {
+			continue
+		}
+
+		if score := candidate.getFreeShare(); score > bestScore {
+			best = candidate
+			bestScore = score
+		}
+	}
+
+	return best
+}
+
+// getFreeShare returns the average fraction of free CPU and memory of the node.
+func (candidate *nodeCapacity) getFreeShare() float64 {
+	var cpuShare, memoryShare float64
+	if candidate.cpuTotal > 0 {
+		cpuShare = float64(candidate.cpu) / float64(candidate.cpuTotal)
+	}
+	if candidate.memoryTotal > 0 {
+		memoryShare = float64(candidate.memory) / float64(candidate.memoryTotal)
+	}
+	return (cpuShare + memoryShare) / 2
+}
+
+func getInsufficientReason(candidate *nodeCapacity, cpu, memory int64) string {
+	switch {
+	case candidate.pods < 1:
+		return "Too many pods"
+	case candidate.cpu < cpu:
+		return "Insufficient cpu"
+	case candidate.memory < memory:
+		return "Insufficient memory"
+	default:
+		return ""
+	}
+}
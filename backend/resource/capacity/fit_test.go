@@ -0,0 +1,106 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capacity
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func getFitSpec(cpu, memory string, maxReplicas int) *FitSpec {
+	return &FitSpec{
+		Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: []v1.Container{{
+			Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse(cpu),
+				v1.ResourceMemory: resource.MustParse(memory),
+			}},
+		}}}},
+		MaxReplicas: maxReplicas,
+	}
+}
+
+func TestGetFit(t *testing.T) {
+	cordoned := getCapacityTestNode("node-4", "large", "4", "8Gi")
+	cordoned.Spec.Unschedulable = true
+	tainted := getCapacityTestNode("node-5", "large", "4", "8Gi")
+	tainted.Spec.Taints = []v1.Taint{{Key: "dedicated", Value: "db", Effect: v1.TaintEffectNoSchedule}}
+
+	client := fake.NewSimpleClientset(
+		getCapacityTestNode("node-1", "large", "4", "8Gi"),
+		getCapacityTestNode("node-2", "large", "4", "8Gi"),
+		getCapacityTestNode("node-3", "small", "1", "4Gi"),
+		cordoned,
+		tainted,
+		getCapacityTestPod("web", "default", "node-1", "3", "1Gi"),
+		getCapacityTestPod("cache", "default", "node-3", "500m", "512Mi"),
+	)
+
+	cases := []struct {
+		info     string
+		spec     *FitSpec
+		expected *FitResult
+	}{
+		{
+			"replicas are spread over nodes with free capacity",
+			getFitSpec("1", "1Gi", 0),
+			&FitResult{
+				Replicas: 5, CPURequests: 1000, MemoryRequests: 1024 * 1024 * 1024,
+				Nodes: []NodeFit{
+					{Name: "node-1", Replicas: 1},
+					{Name: "node-2", Replicas: 4},
+					{Name: "node-3", Reason: "Insufficient cpu"},
+					{Name: "node-4", Reason: "Node is cordoned"},
+					{Name: "node-5", Reason: "Taint dedicated=db:NoSchedule is not tolerated"},
+				},
+			},
+		},
+		{
+			"simulation stops at max replicas",
+			getFitSpec("100m", "128Mi", 3),
+			&FitResult{
+				Replicas: 3, Limited: true, CPURequests: 100, MemoryRequests: 128 * 1024 * 1024,
+				Nodes: []NodeFit{
+					{Name: "node-1"},
+					{Name: "node-2", Replicas: 3},
+					{Name: "node-3"},
+					{Name: "node-4", Reason: "Node is cordoned"},
+					{Name: "node-5", Reason: "Taint dedicated=db:NoSchedule is not tolerated"},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := GetFit(client, c.spec)
+		if err != nil {
+			t.Errorf("GetFit(): %s. Unexpected error: %s", c.info, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetFit(): %s. Got %#v, expected %#v", c.info, actual, c.expected)
+		}
+	}
+}
+
+func TestGetFitInvalidMaxReplicas(t *testing.T) {
+	if _, err := GetFit(fake.NewSimpleClientset(), getFitSpec("1", "1Gi", -1)); err == nil {
+		t.Errorf("GetFit() expected error for negative max replicas")
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capacity
+
+import (
+	"log"
+
+	metricapi "github.com/ycyxuehan/dashboard-gin/backend/integration/metric/api"
+)
+
+// resourceUsage is the latest CPU usage in milicores and memory usage in bytes of a resource.
+type resourceUsage struct {
+	cpu    int64
+	memory int64
+}
+
+// downloadUsage returns the latest CPU and memory usage of every selected resource, in the order of the selectors.
+// Usage is left empty for resources without metrics, so that the report still works without a metric client.
+func downloadUsage(selectors []metricapi.ResourceSelector, metricClient metricapi.MetricClient) []resourceUsage {
+	result := make([]resourceUsage, len(selectors))
+	if metricClient == nil || len(selectors) == 0 {
+		return result
+	}
+
+	promises := metricClient.DownloadMetrics(selectors,
+		[]string{metricapi.CpuUsage, metricapi.MemoryUsage}, metricapi.NoResourceCache)
+	for i, promise := range promises {
+		if i >= 2*len(selectors) {
+			break
+		}
+
+		metric, err := promise.GetMetric()
+		if err != nil {
+			log.Printf("Skipping usage of %s: %s", selectors[i%len(selectors)].ResourceName, err)
+			continue
+		}
+
+		value := getLatestValue(metric)
+		if i < len(selectors) {
+			result[i].cpu = value
+		} else {
+			result[i-len(selectors)].memory = value
+		}
+	}
+
+	return result
+}
+
+func getLatestValue(metric *metricapi.Metric) int64 {
+	if metric == nil || len(metric.DataPoints) == 0 {
+		return 0
+	}
+	return metric.DataPoints[len(metric.DataPoints)-1].Y
+}
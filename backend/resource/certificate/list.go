@@ -0,0 +1,273 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/secret"
+)
+
+// DefaultExpiryThreshold is the number of days before the end of validity from which a certificate is reported as
+// expiring soon, if no other threshold is given.
+const DefaultExpiryThreshold = 30
+
+// CertificateStatus is the validity of a certificate at the time of the scan.
+type CertificateStatus string
+
+// List of certificate statuses.
+const (
+	CertificateStatusValid        CertificateStatus = "valid"
+	CertificateStatusExpiringSoon CertificateStatus = "expiringSoon"
+	CertificateStatusExpired      CertificateStatus = "expired"
+)
+
+// IngressReference is an ingress that serves a certificate.
+type IngressReference struct {
+	Namespace string   `json:"namespace"`
+	Name      string   `json:"name"`
+	Hosts     []string `json:"hosts"`
+}
+
+// Certificate is a single certificate found in a secret. Chains result in one entry per certificate.
+type Certificate struct {
+	// Metadata of the secret that contains the certificate.
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	secret.Certificate `json:",inline"`
+
+	// Position of the certificate in the chain of the secret, starting with 0 for the leaf certificate.
+	ChainIndex int `json:"chainIndex"`
+
+	// Full days until the certificate expires. Negative if it has expired already.
+	DaysUntilExpiry int               `json:"daysUntilExpiry"`
+	Status          CertificateStatus `json:"status"`
+
+	// Ingresses that reference the secret in their TLS configuration.
+	Ingresses []IngressReference `json:"ingresses"`
+}
+
+// CertificateList contains all certificates found in TLS secrets and secrets referenced by ingresses.
+type CertificateList struct {
+	api.ListMeta `json:"listMeta"`
+	Items        []Certificate `json:"items"`
+
+	// Number of days before expiry from which certificates are reported as expiring soon.
+	ExpiryThreshold int `json:"expiryThreshold"`
+
+	// Number of expired and soon expiring certificates, before filtering.
+	Expired      int `json:"expired"`
+	ExpiringSoon int `json:"expiringSoon"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetCertificateList scans all secrets of type kubernetes.io/tls and all secrets referenced by ingress TLS
+// configurations and returns the certificates they contain. Certificates that expire within the threshold in days
+// are reported as expiring soon.
+func GetCertificateList(client kubernetes.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery, threshold int) (*CertificateList, error) {
+	log.Printf("Getting list of certificates in %s namespace", nsQuery.ToRequestParam())
+
+	if threshold <= 0 {
+		threshold = DefaultExpiryThreshold
+	}
+
+	channels := &common.ResourceChannels{
+		IngressList: common.GetIngressListChannel(client, nsQuery, 1),
+	}
+
+	secretList, err := client.CoreV1().Secrets(nsQuery.ToRequestParam()).List(context.TODO(), metaV1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("type", string(v1.SecretTypeTLS)).String(),
+	})
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	ingresses := <-channels.IngressList.List
+	err = <-channels.IngressList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	secrets := make([]v1.Secret, 0)
+	if secretList != nil {
+		for _, item := range secretList.Items {
+			if item.Type == v1.SecretTypeTLS && nsQuery.Matches(item.Namespace) {
+				secrets = append(secrets, item)
+			}
+		}
+	}
+
+	var ingressItems []extensions.Ingress
+	if ingresses != nil {
+		ingressItems = ingresses.Items
+	}
+
+	referenced, referenceErrors, err := getReferencedSecrets(client, secrets, ingressItems)
+	if err != nil {
+		return nil, err
+	}
+	secrets = append(secrets, referenced...)
+	nonCriticalErrors = errors.MergeErrors(nonCriticalErrors, referenceErrors)
+
+	return toCertificateList(secrets, ingressItems, nonCriticalErrors, dsQuery, threshold, time.Now()), nil
+}
+
+// getReferencedSecrets returns the secrets referenced by ingresses that are not TLS secrets and thus not part of
+// the list already. Missing secrets are returned as non-critical errors.
+func getReferencedSecrets(client kubernetes.Interface, secrets []v1.Secret,
+	ingresses []extensions.Ingress) ([]v1.Secret, []error, error) {
+	known := make(map[string]bool)
+	for _, item := range secrets {
+		known[item.Namespace+"/"+item.Name] = true
+	}
+
+	referenced := make([]v1.Secret, 0)
+	nonCriticalErrors := make([]error, 0)
+	for _, ingress := range ingresses {
+		for _, tls := range ingress.Spec.TLS {
+			key := ingress.Namespace + "/" + tls.SecretName
+			if len(tls.SecretName) == 0 || known[key] {
+				continue
+			}
+			known[key] = true
+
+			item, err := client.CoreV1().Secrets(ingress.Namespace).Get(context.TODO(), tls.SecretName,
+				metaV1.GetOptions{})
+			if errors.IsNotFoundError(err) {
+				nonCriticalErrors = append(nonCriticalErrors, errors.NewNotFound(fmt.Sprintf(
+					"Secret %s referenced by ingress %s not found", key, ingress.Name)))
+				continue
+			}
+
+			var criticalError error
+			nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+			if criticalError != nil {
+				return nil, nil, criticalError
+			}
+			if err == nil {
+				referenced = append(referenced, *item)
+			}
+		}
+	}
+
+	return referenced, nonCriticalErrors, nil
+}
+
+func toCertificateList(secrets []v1.Secret, ingresses []extensions.Ingress, nonCriticalErrors []error,
+	dsQuery *dataselect.DataSelectQuery, threshold int, now time.Time) *CertificateList {
+	result := &CertificateList{
+		Items:           make([]Certificate, 0),
+		ExpiryThreshold: threshold,
+		Errors:          nonCriticalErrors,
+	}
+
+	references := getIngressReferences(ingresses)
+	certificates := make([]Certificate, 0)
+	for _, item := range secrets {
+		chain, err := secret.ParseCertificates(item.Data[v1.TLSCertKey], now)
+		if err != nil {
+			result.Errors = append(result.Errors, errors.NewInvalid(fmt.Sprintf(
+				"Could not parse certificate of secret %s/%s: %s", item.Namespace, item.Name, err.Error())))
+			continue
+		}
+
+		for i, parsed := range chain {
+			certificate := toCertificate(item, parsed, i, threshold, now)
+			certificate.Ingresses = references[item.Namespace+"/"+item.Name]
+			if certificate.Ingresses == nil {
+				certificate.Ingresses = make([]IngressReference, 0)
+			}
+
+			switch certificate.Status {
+			case CertificateStatusExpired:
+				result.Expired++
+			case CertificateStatusExpiringSoon:
+				result.ExpiringSoon++
+			}
+			certificates = append(certificates, certificate)
+		}
+	}
+
+	cells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(certificates), dsQuery)
+	result.Items = fromCells(cells)
+	result.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	return result
+}
+
+func toCertificate(item v1.Secret, parsed secret.Certificate, chainIndex, threshold int,
+	now time.Time) Certificate {
+	remaining := parsed.NotAfter.Sub(now)
+	parsed.ExpiringSoon = !parsed.Expired && remaining < time.Duration(threshold)*24*time.Hour
+
+	status := CertificateStatusValid
+	switch {
+	case parsed.Expired:
+		status = CertificateStatusExpired
+	case parsed.ExpiringSoon:
+		status = CertificateStatusExpiringSoon
+	}
+
+	return Certificate{
+		ObjectMeta:      api.NewObjectMeta(item.ObjectMeta),
+		TypeMeta:        api.NewTypeMeta(api.ResourceKindSecret),
+		Certificate:     parsed,
+		ChainIndex:      chainIndex,
+		DaysUntilExpiry: int(math.Floor(remaining.Hours() / 24)),
+		Status:          status,
+	}
+}
+
+// getIngressReferences maps secrets to the ingresses that reference them in their TLS configuration.
+func getIngressReferences(ingresses []extensions.Ingress) map[string][]IngressReference {
+	references := make(map[string][]IngressReference)
+	for _, ingress := range ingresses {
+		for _, tls := range ingress.Spec.TLS {
+			if len(tls.SecretName) == 0 {
+				continue
+			}
+
+			key := ingress.Namespace + "/" + tls.SecretName
+			hosts := append([]string{}, tls.Hosts...)
+			sort.Strings(hosts)
+			references[key] = append(references[key], IngressReference{
+				Namespace: ingress.Namespace,
+				Name:      ingress.Name,
+				Hosts:     hosts,
+			})
+		}
+	}
+	return references
+}
@@ -0,0 +1,163 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
+)
+
+// newCertificate returns a PEM encoded self-signed certificate for the host.
+func newCertificate(t *testing.T, host string, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func newSecret(name string, secretType v1.SecretType, certificate []byte) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default"},
+		Type:       secretType,
+		Data:       map[string][]byte{v1.TLSCertKey: certificate},
+	}
+}
+
+func TestGetCertificateList(t *testing.T) {
+	now := time.Now()
+	valid := newSecret("valid", v1.SecretTypeTLS, newCertificate(t, "valid.example.com", now.Add(200*24*time.Hour)))
+	valid.Labels = map[string]string{"team": "web"}
+	client := fake.NewSimpleClientset(
+		valid,
+		newSecret("soon", v1.SecretTypeTLS, newCertificate(t, "soon.example.com", now.Add(10*24*time.Hour+time.Hour))),
+		newSecret("opaque", v1.SecretTypeOpaque, newCertificate(t, "old.example.com", now.Add(-47*time.Hour))),
+		newSecret("recent", v1.SecretTypeTLS, newCertificate(t, "recent.example.com", now.Add(-2*time.Hour))),
+		newSecret("broken", v1.SecretTypeTLS, []byte("-----BEGIN CERTIFICATE-----\nAAAA\n-----END CERTIFICATE-----\n")),
+		&extensions.Ingress{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: extensions.IngressSpec{TLS: []extensions.IngressTLS{
+				{Hosts: []string{"old.example.com"}, SecretName: "opaque"},
+				{Hosts: []string{"valid.example.com"}, SecretName: "valid"},
+				{Hosts: []string{"missing.example.com"}, SecretName: "missing"},
+			}},
+		},
+	)
+
+	dsQuery := dataselect.NewDataSelectQuery(dataselect.NoPagination,
+		dataselect.NewSortQuery([]string{"a", dataselect.DaysUntilExpiryProperty}), dataselect.NoFilter,
+		dataselect.NoMetrics)
+	actual, err := GetCertificateList(client, common.NewNamespaceQuery(nil), dsQuery, 0)
+	if err != nil {
+		t.Fatalf("GetCertificateList() unexpected error: %v", err)
+	}
+
+	type item struct {
+		name    string
+		days    int
+		status  CertificateStatus
+		ingress []IngressReference
+	}
+	expected := []item{
+		{"opaque", -2, CertificateStatusExpired, []IngressReference{
+			{Namespace: "default", Name: "web", Hosts: []string{"old.example.com"}}}},
+		{"recent", -1, CertificateStatusExpired, []IngressReference{}},
+		{"soon", 10, CertificateStatusExpiringSoon, []IngressReference{}},
+		{"valid", 199, CertificateStatusValid, []IngressReference{
+			{Namespace: "default", Name: "web", Hosts: []string{"valid.example.com"}}}},
+	}
+
+	items := make([]item, 0)
+	for _, certificate := range actual.Items {
+		items = append(items, item{certificate.ObjectMeta.Name, certificate.DaysUntilExpiry, certificate.Status,
+			certificate.Ingresses})
+	}
+	if !reflect.DeepEqual(items, expected) {
+		t.Errorf("GetCertificateList() == \n%#v\nexpected \n%#v", items, expected)
+	}
+
+	if actual.Expired != 2 || actual.ExpiringSoon != 1 || actual.ExpiryThreshold != DefaultExpiryThreshold {
+		t.Errorf("GetCertificateList() counted %d expired and %d expiring with threshold %d", actual.Expired,
+			actual.ExpiringSoon, actual.ExpiryThreshold)
+	}
+
+	// One error for the missing secret and one for the certificate that cannot be parsed.
+	if len(actual.Errors) != 2 {
+		t.Errorf("GetCertificateList() errors == %v, expected 2 errors", actual.Errors)
+	}
+
+	actual, err = GetCertificateList(client, common.NewNamespaceQuery(nil), dsQuery, 5)
+	if err != nil {
+		t.Fatalf("GetCertificateList() unexpected error: %v", err)
+	}
+	if actual.ExpiringSoon != 0 {
+		t.Errorf("GetCertificateList() with a threshold of 5 days counted %d expiring certificates",
+			actual.ExpiringSoon)
+	}
+
+	cases := []struct {
+		filterBy []string
+		expected []string
+	}{
+		{[]string{dataselect.DaysUntilExpiryProperty, "10"}, []string{"soon"}},
+		{[]string{dataselect.DaysUntilExpiryProperty, "a"}, []string{}},
+		{[]string{dataselect.LabelProperty, "team=web"}, []string{"valid"}},
+		{[]string{dataselect.LabelProperty, "team"}, []string{"valid"}},
+	}
+	for _, c := range cases {
+		dsQuery := dataselect.NewDataSelectQuery(dataselect.NoPagination, dataselect.NoSort,
+			dataselect.NewFilterQuery(c.filterBy), dataselect.NoMetrics)
+		actual, err := GetCertificateList(client, common.NewNamespaceQuery(nil), dsQuery, 0)
+		if err != nil {
+			t.Fatalf("GetCertificateList() unexpected error: %v", err)
+		}
+
+		names := make([]string, 0)
+		for _, certificate := range actual.Items {
+			names = append(names, certificate.ObjectMeta.Name)
+		}
+		if !reflect.DeepEqual(names, c.expected) {
+			t.Errorf("GetCertificateList() filtered by %v == %v, expected %v", c.filterBy, names, c.expected)
+		}
+	}
+}
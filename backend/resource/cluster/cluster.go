@@ -0,0 +1,235 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"log"
+
+	v1 "k8s.io/api/core/v1"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	metricapi "github.com/ycyxuehan/dashboard-gin/backend/integration/metric/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/clusterrole"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/clusterrolebinding"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
+	ns "github.com/ycyxuehan/dashboard-gin/backend/resource/namespace"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/node"
+	pv "github.com/ycyxuehan/dashboard-gin/backend/resource/persistentvolume"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/storageclass"
+)
+
+// Cluster structure contains all resource lists grouped into the cluster category.
+type Cluster struct {
+	NamespaceList ns.NamespaceList `json:"namespaceList"`
+
+	NodeList node.NodeList `json:"nodeList"`
+
+	PersistentVolumeList pv.PersistentVolumeList `json:"persistentVolumeList"`
+
+	StorageClassList storageclass.StorageClassList `json:"storageClassList"`
+
+	ClusterRoleList clusterrole.ClusterRoleList `json:"clusterRoleList"`
+
+	ClusterRoleBindingList clusterrolebinding.ClusterRoleBindingList `json:"clusterRoleBindingList"`
+
+	// Status holds number of resources in each state per resource kind. Only kinds that have a state are
+	// listed.
+	Status map[api.ResourceKind]common.ResourceStatus `json:"status"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetCluster returns a list of all cluster resources in the cluster.
+func GetCluster(client client.Interface, metricClient metricapi.MetricClient,
+	dsQuery *dataselect.DataSelectQuery) (*Cluster, error) {
+	log.Print("Getting cluster category")
+
+	// Namespaces, nodes and persistent volumes are read once more to compute the status of all of them, not only
+	// of the selected page.
+	channels := &common.ResourceChannels{
+		NamespaceList:          common.GetNamespaceListChannel(client, 2),
+		NodeList:               common.GetNodeListChannel(client, 2),
+		PersistentVolumeList:   common.GetPersistentVolumeListChannel(client, 2),
+		StorageClassList:       common.GetStorageClassListChannel(client, 1),
+		ClusterRoleList:        common.GetClusterRoleListChannel(client, 1),
+		ClusterRoleBindingList: common.GetClusterRoleBindingListChannel(client, 1),
+	}
+
+	return GetClusterFromChannels(client, channels, metricClient, dsQuery)
+}
+
+// GetClusterFromChannels returns a list of all cluster resources in the cluster, from the channel sources.
+// Namespace, node and persistent volume channels have to be readable twice.
+func GetClusterFromChannels(client client.Interface, channels *common.ResourceChannels,
+	metricClient metricapi.MetricClient, dsQuery *dataselect.DataSelectQuery) (*Cluster, error) {
+	numErrs := 6
+	errChan := make(chan error, numErrs)
+	nsChan := make(chan *ns.NamespaceList, 1)
+	nodeChan := make(chan *node.NodeList, 1)
+	pvChan := make(chan *pv.PersistentVolumeList, 1)
+	scChan := make(chan *storageclass.StorageClassList, 1)
+	crChan := make(chan *clusterrole.ClusterRoleList, 1)
+	crbChan := make(chan *clusterrolebinding.ClusterRoleBindingList, 1)
+
+	go func() {
+		items, err := ns.GetNamespaceListFromChannels(channels, dsQuery)
+		errChan <- err
+		nsChan <- items
+	}()
+
+	go func() {
+		items, err := node.GetNodeListFromChannels(client, channels, dsQuery, metricClient)
+		errChan <- err
+		nodeChan <- items
+	}()
+
+	go func() {
+		items, err := pv.GetPersistentVolumeListFromChannels(channels, dsQuery)
+		errChan <- err
+		pvChan <- items
+	}()
+
+	go func() {
+		items, err := storageclass.GetStorageClassListFromChannels(channels, dsQuery)
+		errChan <- err
+		scChan <- items
+	}()
+
+	go func() {
+		items, err := clusterrole.GetClusterRoleListFromChannels(channels, dsQuery)
+		errChan <- err
+		crChan <- items
+	}()
+
+	go func() {
+		items, err := clusterrolebinding.GetClusterRoleBindingListFromChannels(channels, dsQuery)
+		errChan <- err
+		crbChan <- items
+	}()
+
+	for i := 0; i < numErrs; i++ {
+		err := <-errChan
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cluster := &Cluster{
+		NamespaceList:          *(<-nsChan),
+		NodeList:               *(<-nodeChan),
+		PersistentVolumeList:   *(<-pvChan),
+		StorageClassList:       *(<-scChan),
+		ClusterRoleList:        *(<-crChan),
+		ClusterRoleBindingList: *(<-crbChan),
+	}
+
+	// Errors were already handled while reading the lists above.
+	namespaces := <-channels.NamespaceList.List
+	<-channels.NamespaceList.Error
+	nodes := <-channels.NodeList.List
+	<-channels.NodeList.Error
+	persistentVolumes := <-channels.PersistentVolumeList.List
+	<-channels.PersistentVolumeList.Error
+
+	cluster.Status = map[api.ResourceKind]common.ResourceStatus{
+		api.ResourceKindNamespace:        getNamespaceStatus(namespaces),
+		api.ResourceKindNode:             getNodeStatus(nodes),
+		api.ResourceKindPersistentVolume: getPersistentVolumeStatus(persistentVolumes),
+	}
+
+	cluster.Errors = errors.MergeErrors(cluster.NamespaceList.Errors, cluster.NodeList.Errors,
+		cluster.PersistentVolumeList.Errors, cluster.StorageClassList.Errors, cluster.ClusterRoleList.Errors,
+		cluster.ClusterRoleBindingList.Errors)
+
+	return cluster, nil
+}
+
+func getNamespaceStatus(list *v1.NamespaceList) common.ResourceStatus {
+	info := common.ResourceStatus{}
+	if list == nil {
+		return info
+	}
+
+	for _, namespace := range list.Items {
+		switch namespace.Status.Phase {
+		case v1.NamespaceActive:
+			info.Running++
+		case v1.NamespaceTerminating:
+			info.Terminating++
+		default:
+			info.Unknown++
+		}
+	}
+
+	return info
+}
+
+// getNodeStatus counts ready nodes as running and nodes that report not ready as failed.
+func getNodeStatus(list *v1.NodeList) common.ResourceStatus {
+	info := common.ResourceStatus{}
+	if list == nil {
+		return info
+	}
+
+	for _, item := range list.Items {
+		status := v1.ConditionUnknown
+		for _, condition := range item.Status.Conditions {
+			if condition.Type == v1.NodeReady {
+				status = condition.Status
+			}
+		}
+
+		switch status {
+		case v1.ConditionTrue:
+			info.Running++
+		case v1.ConditionFalse:
+			info.Failed++
+		default:
+			info.Unknown++
+		}
+	}
+
+	return info
+}
+
+// getPersistentVolumeStatus counts available and bound volumes as running and released volumes, which wait to
+// be reclaimed, as succeeded.
+func getPersistentVolumeStatus(list *v1.PersistentVolumeList) common.ResourceStatus {
+	info := common.ResourceStatus{}
+	if list == nil {
+		return info
+	}
+
+	for _, volume := range list.Items {
+		switch volume.Status.Phase {
+		case v1.VolumeAvailable, v1.VolumeBound:
+			info.Running++
+		case v1.VolumePending:
+			info.Pending++
+		case v1.VolumeReleased:
+			info.Succeeded++
+		case v1.VolumeFailed:
+			info.Failed++
+		default:
+			info.Unknown++
+		}
+	}
+
+	return info
+}
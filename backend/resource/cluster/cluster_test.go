@@ -0,0 +1,83 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
+)
+
+func getReadyNode(name string, ready v1.ConditionStatus) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metaV1.ObjectMeta{Name: name},
+		Status: v1.NodeStatus{Conditions: []v1.NodeCondition{
+			{Type: v1.NodeReady, Status: ready},
+		}},
+	}
+}
+
+func TestGetCluster(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Namespace{
+			ObjectMeta: metaV1.ObjectMeta{Name: "default"},
+			Status:     v1.NamespaceStatus{Phase: v1.NamespaceActive},
+		},
+		&v1.Namespace{
+			ObjectMeta: metaV1.ObjectMeta{Name: "old"},
+			Status:     v1.NamespaceStatus{Phase: v1.NamespaceTerminating},
+		},
+		getReadyNode("node-1", v1.ConditionTrue),
+		getReadyNode("node-2", v1.ConditionFalse),
+		&v1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "node-3"}},
+		&v1.PersistentVolume{
+			ObjectMeta: metaV1.ObjectMeta{Name: "pv-1"},
+			Status:     v1.PersistentVolumeStatus{Phase: v1.VolumeBound},
+		},
+		&v1.PersistentVolume{
+			ObjectMeta: metaV1.ObjectMeta{Name: "pv-2"},
+			Status:     v1.PersistentVolumeStatus{Phase: v1.VolumeReleased},
+		},
+	)
+
+	dsQuery := dataselect.NewDataSelectQuery(dataselect.NewPaginationQuery(1, 0), dataselect.NoSort,
+		dataselect.NoFilter, dataselect.NoMetrics)
+	cluster, err := GetCluster(client, nil, dsQuery)
+	if err != nil {
+		t.Fatalf("GetCluster() returned error: %s", err)
+	}
+
+	if len(cluster.NodeList.Nodes) != 1 || cluster.NodeList.ListMeta.TotalItems != 3 {
+		t.Errorf("GetCluster() returned %d of %d nodes, expected 1 of 3", len(cluster.NodeList.Nodes),
+			cluster.NodeList.ListMeta.TotalItems)
+	}
+
+	// Status is computed from all resources, not only from the selected page.
+	expected := map[api.ResourceKind]common.ResourceStatus{
+		api.ResourceKindNamespace:        {Running: 1, Terminating: 1},
+		api.ResourceKindNode:             {Running: 1, Failed: 1, Unknown: 1},
+		api.ResourceKindPersistentVolume: {Running: 1, Succeeded: 1},
+	}
+	if !reflect.DeepEqual(cluster.Status, expected) {
+		t.Errorf("GetCluster() status == %+v, expected %+v", cluster.Status, expected)
+	}
+}
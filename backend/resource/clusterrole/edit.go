@@ -0,0 +1,103 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterrole
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	rbac "k8s.io/api/rbac/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sClient "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/permission"
+)
+
+// ClusterRoleSpec is a specification of a cluster role to create or update.
+type ClusterRoleSpec struct {
+	// Name is only used on creation.
+	Name string `json:"name"`
+
+	// Labels replace the labels of the cluster role if set.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	Rules []rbac.PolicyRule `json:"rules"`
+}
+
+// CreateClusterRole creates a cluster role, after checking its rules against discovery.
+func CreateClusterRole(client k8sClient.Interface, spec *ClusterRoleSpec) (*ClusterRoleDetail, error) {
+	log.Printf("Creating cluster role %s", spec.Name)
+
+	if len(spec.Name) == 0 {
+		return nil, errors.NewInvalid("Name of the cluster role is required")
+	}
+	if err := permission.ValidateRules(client, spec.Rules, true); err != nil {
+		return nil, err
+	}
+
+	role := &rbac.ClusterRole{
+		ObjectMeta: metaV1.ObjectMeta{Name: spec.Name, Labels: spec.Labels},
+		Rules:      spec.Rules,
+	}
+
+	created, err := client.RbacV1().ClusterRoles().Create(context.TODO(), role, metaV1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := toClusterRoleDetail(*created)
+	return &result, nil
+}
+
+// UpdateClusterRole replaces the rules of the cluster role with the given name. Rules of aggregated cluster roles
+// are managed by the controller manager and cannot be changed.
+func UpdateClusterRole(client k8sClient.Interface, name string, spec *ClusterRoleSpec) (*ClusterRoleDetail, error) {
+	log.Printf("Updating cluster role %s", name)
+
+	if err := permission.ValidateRules(client, spec.Rules, true); err != nil {
+		return nil, err
+	}
+
+	role, err := client.RbacV1().ClusterRoles().Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if role.AggregationRule != nil {
+		return nil, errors.NewInvalid(fmt.Sprintf("Rules of cluster role %s are aggregated from other cluster "+
+			"roles and cannot be changed", name))
+	}
+
+	if spec.Labels != nil {
+		role.Labels = spec.Labels
+	}
+	role.Rules = spec.Rules
+
+	updated, err := client.RbacV1().ClusterRoles().Update(context.TODO(), role, metaV1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := toClusterRoleDetail(*updated)
+	return &result, nil
+}
+
+// DeleteClusterRole deletes the cluster role with the given name.
+func DeleteClusterRole(client k8sClient.Interface, name string) error {
+	log.Printf("Deleting cluster role %s", name)
+	return client.RbacV1().ClusterRoles().Delete(context.TODO(), name, metaV1.DeleteOptions{})
+}
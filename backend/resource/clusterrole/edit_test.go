@@ -0,0 +1,69 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterrole
+
+import (
+	"testing"
+
+	rbac "k8s.io/api/rbac/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestUpdateClusterRole(t *testing.T) {
+	rules := []rbac.PolicyRule{{Verbs: []string{"get"}, NonResourceURLs: []string{"/healthz"}}}
+
+	cases := []struct {
+		info    string
+		role    *rbac.ClusterRole
+		isError bool
+	}{
+		{
+			"plain cluster role",
+			&rbac.ClusterRole{ObjectMeta: metaV1.ObjectMeta{Name: "health-reader"}},
+			false,
+		},
+		{
+			"aggregated cluster role",
+			&rbac.ClusterRole{
+				ObjectMeta: metaV1.ObjectMeta{Name: "health-reader"},
+				AggregationRule: &rbac.AggregationRule{ClusterRoleSelectors: []metaV1.LabelSelector{
+					{MatchLabels: map[string]string{"aggregate": "true"}},
+				}},
+			},
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.info, func(t *testing.T) {
+			client := fake.NewSimpleClientset(c.role)
+			actual, err := UpdateClusterRole(client, "health-reader", &ClusterRoleSpec{Rules: rules})
+			if c.isError {
+				if err == nil {
+					t.Errorf("UpdateClusterRole() returned no error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("UpdateClusterRole() returned error: %v", err)
+			}
+			if len(actual.Rules) != 1 || actual.Rules[0].NonResourceURLs[0] != "/healthz" {
+				t.Errorf("Unexpected rules: %+v", actual.Rules)
+			}
+		})
+	}
+}
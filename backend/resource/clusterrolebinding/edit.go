@@ -0,0 +1,142 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterrolebinding
+
+import (
+	"context"
+	"log"
+
+	rbac "k8s.io/api/rbac/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sClient "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/permission"
+)
+
+// ClusterRoleBindingSpec is a specification of a cluster role binding to create or update.
+type ClusterRoleBindingSpec struct {
+	// Name is only used on creation.
+	Name string `json:"name"`
+
+	// Labels replace the labels of the cluster role binding if set.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// RoleRef cannot be changed after creation. It may be left empty on update.
+	RoleRef rbac.RoleRef `json:"roleRef"`
+
+	Subjects []rbac.Subject `json:"subjects"`
+}
+
+// CreateClusterRoleBinding creates a cluster role binding.
+func CreateClusterRoleBinding(client k8sClient.Interface, spec *ClusterRoleBindingSpec) (*ClusterRoleBindingDetail,
+	error) {
+	log.Printf("Creating cluster role binding %s", spec.Name)
+
+	if len(spec.Name) == 0 {
+		return nil, errors.NewInvalid("Name of the cluster role binding is required")
+	}
+
+	roleRef, err := permission.ValidateRoleRef(spec.RoleRef, false)
+	if err != nil {
+		return nil, err
+	}
+
+	subjects, err := permission.ValidateSubjects(spec.Subjects, "")
+	if err != nil {
+		return nil, err
+	}
+
+	binding := &rbac.ClusterRoleBinding{
+		ObjectMeta: metaV1.ObjectMeta{Name: spec.Name, Labels: spec.Labels},
+		RoleRef:    roleRef,
+		Subjects:   subjects,
+	}
+
+	created, err := client.RbacV1().ClusterRoleBindings().Create(context.TODO(), binding, metaV1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := toClusterRoleBindingDetail(*created)
+	return &result, nil
+}
+
+// UpdateClusterRoleBinding replaces the subjects of the cluster role binding with the given name.
+func UpdateClusterRoleBinding(client k8sClient.Interface, name string,
+	spec *ClusterRoleBindingSpec) (*ClusterRoleBindingDetail, error) {
+	log.Printf("Updating cluster role binding %s", name)
+
+	subjects, err := permission.ValidateSubjects(spec.Subjects, "")
+	if err != nil {
+		return nil, err
+	}
+
+	binding, err := client.RbacV1().ClusterRoleBindings().Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(spec.RoleRef.Name) > 0 && (spec.RoleRef.Name != binding.RoleRef.Name ||
+		spec.RoleRef.Kind != binding.RoleRef.Kind) {
+		return nil, errors.NewInvalid("Role reference of a binding cannot be changed, create a new binding instead")
+	}
+
+	if spec.Labels != nil {
+		binding.Labels = spec.Labels
+	}
+	binding.Subjects = subjects
+
+	updated, err := client.RbacV1().ClusterRoleBindings().Update(context.TODO(), binding, metaV1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := toClusterRoleBindingDetail(*updated)
+	return &result, nil
+}
+
+// PatchClusterRoleBindingSubjects adds subjects to and removes subjects from the cluster role binding with the
+// given name.
+func PatchClusterRoleBindingSubjects(client k8sClient.Interface, name string,
+	patch *permission.SubjectPatch) (*ClusterRoleBindingDetail, error) {
+	log.Printf("Patching subjects of cluster role binding %s", name)
+
+	binding, err := client.RbacV1().ClusterRoleBindings().Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := permission.GetSubjectsPatch(binding.Subjects, binding.ResourceVersion, "", patch)
+	if err != nil {
+		return nil, err
+	}
+
+	patched, err := client.RbacV1().ClusterRoleBindings().Patch(context.TODO(), name, types.MergePatchType, data,
+		metaV1.PatchOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := toClusterRoleBindingDetail(*patched)
+	return &result, nil
+}
+
+// DeleteClusterRoleBinding deletes the cluster role binding with the given name.
+func DeleteClusterRoleBinding(client k8sClient.Interface, name string) error {
+	log.Printf("Deleting cluster role binding %s", name)
+	return client.RbacV1().ClusterRoleBindings().Delete(context.TODO(), name, metaV1.DeleteOptions{})
+}
@@ -16,6 +16,10 @@ package common
 
 import api "k8s.io/api/core/v1"
 
+// AllNamespacesPlaceholder is used as namespace path parameter of routes that require it, but ignore it for cluster
+// scoped objects or select objects of all namespaces.
+const AllNamespacesPlaceholder = "_all"
+
 // NamespaceQuery is a query for namespaces of a list of objects.
 // There's three cases:
 // 1. No namespace selected: this means "user namespaces" query, i.e., all except kube-system
@@ -0,0 +1,140 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"log"
+
+	v1 "k8s.io/api/core/v1"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/configmap"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/persistentvolumeclaim"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/secret"
+)
+
+// Config structure contains all resource lists grouped into the config and storage category.
+type Config struct {
+	ConfigMapList configmap.ConfigMapList `json:"configMapList"`
+
+	PersistentVolumeClaimList persistentvolumeclaim.PersistentVolumeClaimList `json:"persistentVolumeClaimList"`
+
+	SecretList secret.SecretList `json:"secretList"`
+
+	// Status holds number of resources in each state per resource kind. Only kinds that have a state are
+	// listed.
+	Status map[api.ResourceKind]common.ResourceStatus `json:"status"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetConfig returns a list of all config and storage resources in the cluster.
+func GetConfig(client client.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*Config, error) {
+	log.Printf("Getting config and storage resources in namespace %s", nsQuery.ToRequestParam())
+
+	// Persistent volume claims are read once more to compute the status of all claims, not only of the selected
+	// page.
+	channels := &common.ResourceChannels{
+		ConfigMapList:             common.GetConfigMapListChannel(client, nsQuery, 1),
+		SecretList:                common.GetSecretListChannel(client, nsQuery, 1),
+		PersistentVolumeClaimList: common.GetPersistentVolumeClaimListChannel(client, nsQuery, 2),
+	}
+
+	return GetConfigFromChannels(channels, nsQuery, dsQuery)
+}
+
+// GetConfigFromChannels returns a list of all config and storage resources in the cluster, from the channel
+// sources. Persistent volume claim channel has to be readable twice.
+func GetConfigFromChannels(channels *common.ResourceChannels, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*Config, error) {
+	numErrs := 3
+	errChan := make(chan error, numErrs)
+	configMapChan := make(chan *configmap.ConfigMapList, 1)
+	secretChan := make(chan *secret.SecretList, 1)
+	pvcChan := make(chan *persistentvolumeclaim.PersistentVolumeClaimList, 1)
+
+	go func() {
+		items, err := configmap.GetConfigMapListFromChannels(channels, dsQuery)
+		errChan <- err
+		configMapChan <- items
+	}()
+
+	go func() {
+		items, err := secret.GetSecretListFromChannels(channels, dsQuery)
+		errChan <- err
+		secretChan <- items
+	}()
+
+	go func() {
+		items, err := persistentvolumeclaim.GetPersistentVolumeClaimListFromChannels(channels, nsQuery, dsQuery)
+		errChan <- err
+		pvcChan <- items
+	}()
+
+	for i := 0; i < numErrs; i++ {
+		err := <-errChan
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	config := &Config{
+		ConfigMapList:             *(<-configMapChan),
+		SecretList:                *(<-secretChan),
+		PersistentVolumeClaimList: *(<-pvcChan),
+	}
+
+	// Errors were already handled while reading the list above.
+	claims := <-channels.PersistentVolumeClaimList.List
+	<-channels.PersistentVolumeClaimList.Error
+
+	config.Status = map[api.ResourceKind]common.ResourceStatus{
+		api.ResourceKindPersistentVolumeClaim: getPersistentVolumeClaimStatus(claims),
+	}
+
+	config.Errors = errors.MergeErrors(config.ConfigMapList.Errors, config.SecretList.Errors,
+		config.PersistentVolumeClaimList.Errors)
+
+	return config, nil
+}
+
+// getPersistentVolumeClaimStatus counts bound claims as running and lost claims as failed.
+func getPersistentVolumeClaimStatus(list *v1.PersistentVolumeClaimList) common.ResourceStatus {
+	info := common.ResourceStatus{}
+	if list == nil {
+		return info
+	}
+
+	for _, claim := range list.Items {
+		switch claim.Status.Phase {
+		case v1.ClaimBound:
+			info.Running++
+		case v1.ClaimPending:
+			info.Pending++
+		case v1.ClaimLost:
+			info.Failed++
+		default:
+			info.Unknown++
+		}
+	}
+
+	return info
+}
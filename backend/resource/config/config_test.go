@@ -0,0 +1,61 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
+)
+
+func TestGetConfig(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.ConfigMap{ObjectMeta: metaV1.ObjectMeta{Name: "cm", Namespace: "default"}},
+		&v1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "secret", Namespace: "default"}},
+		&v1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "secret", Namespace: "other"}},
+		&v1.PersistentVolumeClaim{
+			ObjectMeta: metaV1.ObjectMeta{Name: "bound", Namespace: "default"},
+			Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimBound},
+		},
+		&v1.PersistentVolumeClaim{
+			ObjectMeta: metaV1.ObjectMeta{Name: "pending", Namespace: "default"},
+			Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending},
+		},
+	)
+
+	config, err := GetConfig(client, common.NewSameNamespaceQuery("default"), dataselect.NoDataSelect)
+	if err != nil {
+		t.Fatalf("GetConfig() returned error: %s", err)
+	}
+
+	if config.ConfigMapList.ListMeta.TotalItems != 1 || config.SecretList.ListMeta.TotalItems != 1 ||
+		config.PersistentVolumeClaimList.ListMeta.TotalItems != 2 {
+		t.Errorf("GetConfig() returned unexpected lists: %+v", config)
+	}
+
+	expected := map[api.ResourceKind]common.ResourceStatus{
+		api.ResourceKindPersistentVolumeClaim: {Running: 1, Pending: 1},
+	}
+	if !reflect.DeepEqual(config.Status, expected) {
+		t.Errorf("GetConfig() status == %+v, expected %+v", config.Status, expected)
+	}
+}
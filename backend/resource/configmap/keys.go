@@ -0,0 +1,155 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmap
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/deployment"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/pod"
+)
+
+// ConfigMapKeySpec is a specification of the value of a single key.
+type ConfigMapKeySpec struct {
+	Value string `json:"value"`
+}
+
+// ConfigMapKeyChange is the result of a change of a single key. It lists the consumers of the key, so that the
+// impact of the change is visible.
+type ConfigMapKeyChange struct {
+	ConfigMap *ConfigMapDetail `json:"configMap"`
+	Consumers *pod.Consumers   `json:"consumers"`
+
+	// Deployments restarted after the change.
+	Rolled []pod.Consumer `json:"rolled"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// CreateConfigMapKey adds a key to a config map. Fails if the key exists already. If roll is set, the consuming
+// deployments are restarted afterwards.
+func CreateConfigMapKey(client kubernetes.Interface, namespace, name, key string, spec *ConfigMapKeySpec,
+	roll bool) (*ConfigMapKeyChange, error) {
+	log.Printf("Creating key %s of %s config map in %s namespace", key, name, namespace)
+
+	if messages := validation.IsConfigMapKey(key); len(messages) > 0 {
+		return nil, errors.NewInvalid(fmt.Sprintf("Invalid key %s: %s", key, strings.Join(messages, ", ")))
+	}
+
+	return changeConfigMapKey(client, namespace, name, key, roll, func(configMap *v1.ConfigMap) error {
+		_, inData := configMap.Data[key]
+		_, inBinaryData := configMap.BinaryData[key]
+		if inData || inBinaryData {
+			return k8serrors.NewAlreadyExists(v1.Resource("configmaps"), fmt.Sprintf("%s/%s", name, key))
+		}
+
+		if configMap.Data == nil {
+			configMap.Data = make(map[string]string)
+		}
+		configMap.Data[key] = spec.Value
+		return nil
+	})
+}
+
+// UpdateConfigMapKey changes the value of an existing key. Binary keys cannot be updated, as the value is a string.
+// If roll is set, the consuming deployments are restarted afterwards.
+func UpdateConfigMapKey(client kubernetes.Interface, namespace, name, key string, spec *ConfigMapKeySpec,
+	roll bool) (*ConfigMapKeyChange, error) {
+	log.Printf("Updating key %s of %s config map in %s namespace", key, name, namespace)
+
+	return changeConfigMapKey(client, namespace, name, key, roll, func(configMap *v1.ConfigMap) error {
+		if _, ok := configMap.BinaryData[key]; ok {
+			return errors.NewInvalid(fmt.Sprintf("Key %s of %s config map holds binary data, binary keys cannot be "+
+				"edited as strings", key, name))
+		}
+		if _, ok := configMap.Data[key]; !ok {
+			return errors.NewNotFound(fmt.Sprintf("Key %s not found in %s config map", key, name))
+		}
+		configMap.Data[key] = spec.Value
+		return nil
+	})
+}
+
+// DeleteConfigMapKey removes a key. If roll is set, the consuming deployments are restarted afterwards.
+func DeleteConfigMapKey(client kubernetes.Interface, namespace, name, key string,
+	roll bool) (*ConfigMapKeyChange, error) {
+	log.Printf("Deleting key %s of %s config map in %s namespace", key, name, namespace)
+
+	return changeConfigMapKey(client, namespace, name, key, roll, func(configMap *v1.ConfigMap) error {
+		_, inData := configMap.Data[key]
+		_, inBinaryData := configMap.BinaryData[key]
+		if !inData && !inBinaryData {
+			return errors.NewNotFound(fmt.Sprintf("Key %s not found in %s config map", key, name))
+		}
+
+		delete(configMap.Data, key)
+		delete(configMap.BinaryData, key)
+		return nil
+	})
+}
+
+// changeConfigMapKey applies the change to the config map and updates it. The update fails with a conflict if the
+// config map was changed in the meantime.
+func changeConfigMapKey(client kubernetes.Interface, namespace, name, key string, roll bool,
+	change func(configMap *v1.ConfigMap) error) (*ConfigMapKeyChange, error) {
+	configMap, err := client.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := change(configMap); err != nil {
+		return nil, err
+	}
+
+	updated, err := client.CoreV1().ConfigMaps(namespace).Update(context.TODO(), configMap, metaV1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ConfigMapKeyChange{
+		ConfigMap: getConfigMapDetail(updated),
+		Rolled:    make([]pod.Consumer, 0),
+		Errors:    make([]error, 0),
+	}
+
+	// The config map is changed already, so failures from here on are only reported.
+	result.Consumers, err = pod.GetConsumers(client, pod.ConfigSource{
+		Kind:      api.ResourceKindConfigMap,
+		Namespace: namespace,
+		Name:      name,
+		Key:       key,
+	})
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return result, nil
+	}
+
+	if roll {
+		result.Rolled, result.Errors = deployment.RollDeployments(client, namespace, result.Consumers)
+	}
+	return result, nil
+}
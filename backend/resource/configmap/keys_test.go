@@ -0,0 +1,97 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmap
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/pod"
+)
+
+func TestConfigMapKeys(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.ConfigMap{
+			ObjectMeta: metaV1.ObjectMeta{Name: "config", Namespace: "default"},
+			Data:       map[string]string{"level": "info"},
+			BinaryData: map[string][]byte{"blob": {0xff}},
+		},
+		&v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec: v1.PodSpec{Volumes: []v1.Volume{{Name: "config", VolumeSource: v1.VolumeSource{
+				ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: "config"}},
+			}}}},
+		},
+	)
+
+	change, err := CreateConfigMapKey(client, "default", "config", "mode", &ConfigMapKeySpec{Value: "fast"}, true)
+	if err != nil {
+		t.Fatalf("CreateConfigMapKey() unexpected error: %v", err)
+	}
+	expectedData := map[string]string{"level": "info", "mode": "fast"}
+	if !reflect.DeepEqual(change.ConfigMap.Data, expectedData) {
+		t.Errorf("CreateConfigMapKey() data == %#v, expected %#v", change.ConfigMap.Data, expectedData)
+	}
+	expectedPods := []pod.Consumer{{
+		Kind:   api.ResourceKindPod,
+		Name:   "app",
+		Usages: []pod.Usage{{Type: pod.UsageTypeVolume, Volume: "config", Keys: []string{}}},
+	}}
+	if !reflect.DeepEqual(change.Consumers.Pods, expectedPods) || len(change.Rolled) > 0 {
+		t.Errorf("CreateConfigMapKey() consumers == %#v, rolled %#v, expected %#v", change.Consumers.Pods,
+			change.Rolled, expectedPods)
+	}
+
+	_, err = CreateConfigMapKey(client, "default", "config", "mode", &ConfigMapKeySpec{}, false)
+	if !k8serrors.IsAlreadyExists(err) {
+		t.Errorf("CreateConfigMapKey() of an existing key == %v, expected already exists", err)
+	}
+
+	change, err = UpdateConfigMapKey(client, "default", "config", "level", &ConfigMapKeySpec{Value: "debug"}, false)
+	if err != nil {
+		t.Fatalf("UpdateConfigMapKey() unexpected error: %v", err)
+	}
+	if change.ConfigMap.Data["level"] != "debug" {
+		t.Errorf("UpdateConfigMapKey() data == %#v, expected level to be debug", change.ConfigMap.Data)
+	}
+
+	_, err = UpdateConfigMapKey(client, "default", "config", "missing", &ConfigMapKeySpec{}, false)
+	if !k8serrors.IsNotFound(err) {
+		t.Errorf("UpdateConfigMapKey() of a missing key == %v, expected not found", err)
+	}
+
+	_, err = UpdateConfigMapKey(client, "default", "config", "blob", &ConfigMapKeySpec{Value: "text"}, false)
+	if !k8serrors.IsInvalid(err) {
+		t.Errorf("UpdateConfigMapKey() of a binary key == %v, expected invalid", err)
+	}
+
+	change, err = DeleteConfigMapKey(client, "default", "config", "mode", false)
+	if err != nil {
+		t.Fatalf("DeleteConfigMapKey() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(change.ConfigMap.Data, map[string]string{"level": "debug"}) {
+		t.Errorf("DeleteConfigMapKey() data == %#v", change.ConfigMap.Data)
+	}
+
+	if _, err := CreateConfigMapKey(client, "default", "config", "a/b", &ConfigMapKeySpec{}, false); err == nil {
+		t.Error("CreateConfigMapKey() expected an error for an invalid key")
+	}
+}
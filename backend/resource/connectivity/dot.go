@@ -0,0 +1,69 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connectivity
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+)
+
+// nodeShapes are Graphviz shapes of node kinds. Workloads are drawn as boxes.
+var nodeShapes = map[api.ResourceKind]string{
+	api.ResourceKindIngress: "hexagon",
+	api.ResourceKindService: "ellipse",
+	ResourceKindExternal:    "diamond",
+}
+
+// edgeStyles are Graphviz styles of edge types.
+var edgeStyles = map[EdgeType]string{
+	ServiceEdge:       "solid",
+	IngressEdge:       "bold",
+	NetworkPolicyEdge: "dashed",
+}
+
+// ToDOT renders the graph in Graphviz DOT language.
+func (g *Graph) ToDOT() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "digraph %s {\n", quote(g.Namespace))
+	b.WriteString("\trankdir=LR;\n")
+
+	for _, node := range g.Nodes {
+		shape, exists := nodeShapes[node.Kind]
+		if !exists {
+			shape = "box"
+		}
+		fmt.Fprintf(&b, "\t%s [label=%s, shape=%s];\n", quote(node.ID),
+			quote(fmt.Sprintf("%s\n(%s)", node.Name, node.Kind)), shape)
+	}
+
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "\t%s -> %s [label=%s, style=%s];\n", quote(edge.From), quote(edge.To),
+			quote(edge.Label), edgeStyles[edge.Type])
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Returns DOT quoted string. New lines are kept as DOT line breaks.
+func quote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	return `"` + value + `"`
+}
@@ -0,0 +1,407 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connectivity
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	networkingV1 "k8s.io/api/networking/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/controller"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/networkpolicy"
+)
+
+// ResourceKindExternal is the kind of nodes that represent peers of network policies outside of the namespace,
+// i.e. IP blocks, other namespaces or any peer.
+const ResourceKindExternal api.ResourceKind = "external"
+
+// EdgeType describes where an edge of the graph comes from.
+type EdgeType string
+
+const (
+	// ServiceEdge connects a service with workloads that have pods matching its selector.
+	ServiceEdge EdgeType = "service"
+	// IngressEdge connects an ingress with its backend services.
+	IngressEdge EdgeType = "ingress"
+	// NetworkPolicyEdge connects peers between which network policy rule allows traffic.
+	NetworkPolicyEdge EdgeType = "networkpolicy"
+)
+
+// Node is a workload, service, ingress or external peer.
+type Node struct {
+	// ID is unique within the graph and is made of kind and name.
+	ID   string           `json:"id"`
+	Kind api.ResourceKind `json:"kind"`
+	Name string           `json:"name"`
+
+	// Pods and ContainerImages are only set for workloads.
+	Pods            int      `json:"pods,omitempty"`
+	ContainerImages []string `json:"containerImages,omitempty"`
+}
+
+// Edge is a directed connection between nodes, i.e. the direction of traffic.
+type Edge struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	Type EdgeType `json:"type"`
+
+	// Label describes ports, hosts and paths or network policy of the edge.
+	Label string `json:"label,omitempty"`
+}
+
+// Graph of workloads, services and ingresses in a namespace.
+type Graph struct {
+	Namespace string  `json:"namespace"`
+	Nodes     []Node  `json:"nodes"`
+	Edges     []Edge  `json:"edges"`
+	Errors    []error `json:"errors"`
+}
+
+// graphBuilder keeps nodes and edges while the graph is being built.
+type graphBuilder struct {
+	graph *Graph
+	nodes map[string]*Node
+	edges map[Edge]bool
+
+	// workloads maps running pods to ids of workload nodes.
+	workloads map[*v1.Pod]string
+}
+
+// GetConnectivityGraph returns graph of given namespace. Pods are grouped into workloads by their controllers,
+// replica sets that belong to a deployment are shown as the deployment. Completed pods are left out, because they
+// do not take part in any traffic.
+func GetConnectivityGraph(client client.Interface, namespace string) (*Graph, error) {
+	log.Printf("Getting connectivity graph of %s namespace", namespace)
+
+	pods, err := client.CoreV1().Pods(namespace).List(context.TODO(), api.ListEverything)
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	services, err := client.CoreV1().Services(namespace).List(context.TODO(), api.ListEverything)
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	ingresses, err := client.ExtensionsV1beta1().Ingresses(namespace).List(context.TODO(), api.ListEverything)
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	policies, err := client.NetworkingV1().NetworkPolicies(namespace).List(context.TODO(), api.ListEverything)
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	graph := &Graph{Namespace: namespace, Nodes: make([]Node, 0), Edges: make([]Edge, 0), Errors: nonCriticalErrors}
+	builder := &graphBuilder{
+		graph:     graph,
+		nodes:     map[string]*Node{},
+		edges:     map[Edge]bool{},
+		workloads: map[*v1.Pod]string{},
+	}
+
+	builder.addWorkloads(client, namespace, pods.Items)
+	for _, service := range services.Items {
+		builder.addService(&service)
+	}
+	for _, ingress := range ingresses.Items {
+		builder.addIngress(&ingress)
+	}
+	for _, policy := range policies.Items {
+		builder.addNetworkPolicy(&policy)
+	}
+
+	return builder.build(), nil
+}
+
+// Groups running pods into workload nodes. Controllers are only fetched once.
+func (b *graphBuilder) addWorkloads(client client.Interface, namespace string, pods []v1.Pod) {
+	owners := map[types.UID]*Node{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+
+		ref := metaV1.GetControllerOf(pod)
+		if ref == nil {
+			node := b.addNode(api.ResourceKindPod, pod.Name)
+			node.ContainerImages = common.GetContainerImages(&pod.Spec)
+			node.Pods++
+			b.workloads[pod] = node.ID
+			continue
+		}
+
+		node, exists := owners[ref.UID]
+		if !exists {
+			node = b.addOwner(client, namespace, *ref, pods)
+			owners[ref.UID] = node
+		}
+		node.Pods++
+		b.workloads[pod] = node.ID
+	}
+}
+
+// Returns workload node of the controller. Unknown controllers are added without container images.
+func (b *graphBuilder) addOwner(client client.Interface, namespace string, ref metaV1.OwnerReference,
+	pods []v1.Pod) *Node {
+	rc, err := controller.NewResourceController(ref, namespace, client)
+	if err != nil {
+		return b.addNode(api.ResourceKind(strings.ToLower(ref.Kind)), ref.Name)
+	}
+
+	owner := rc.Get(pods, []v1.Event{})
+	kind, name := owner.TypeMeta.Kind, owner.ObjectMeta.Name
+	if rs, ok := rc.(controller.ReplicaSetController); ok {
+		if deployment := metaV1.GetControllerOf(&rs); deployment != nil && deployment.Kind == "Deployment" {
+			kind, name = api.ResourceKindDeployment, deployment.Name
+		}
+	}
+
+	node := b.addNode(kind, name)
+	node.ContainerImages = mergeImages(node.ContainerImages, owner.ContainerImages)
+	return node
+}
+
+func (b *graphBuilder) addService(service *v1.Service) {
+	node := b.addNode(api.ResourceKindService, service.Name)
+	if len(service.Spec.Selector) == 0 {
+		return
+	}
+
+	ports := make([]string, 0, len(service.Spec.Ports))
+	for _, port := range service.Spec.Ports {
+		ports = append(ports, fmt.Sprintf("%s/%d", port.Protocol, port.Port))
+	}
+
+	selector := labels.SelectorFromSet(service.Spec.Selector)
+	for _, workload := range b.selectWorkloads(selector) {
+		b.addEdge(Edge{From: node.ID, To: workload, Type: ServiceEdge, Label: strings.Join(ports, ", ")})
+	}
+}
+
+func (b *graphBuilder) addIngress(ingress *extensions.Ingress) {
+	node := b.addNode(api.ResourceKindIngress, ingress.Name)
+	if ingress.Spec.Backend != nil {
+		b.addIngressBackend(node, ingress.Spec.Backend, "*")
+	}
+
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		host := rule.Host
+		if len(host) == 0 {
+			host = "*"
+		}
+		for _, path := range rule.HTTP.Paths {
+			b.addIngressBackend(node, &path.Backend, host+path.Path)
+		}
+	}
+}
+
+// Backends that point to services missing in the namespace are left out.
+func (b *graphBuilder) addIngressBackend(node *Node, backend *extensions.IngressBackend, label string) {
+	service, exists := b.nodes[nodeID(api.ResourceKindService, backend.ServiceName)]
+	if !exists {
+		return
+	}
+
+	b.addEdge(Edge{From: node.ID, To: service.ID, Type: IngressEdge,
+		Label: fmt.Sprintf("%s -> %s", label, backend.ServicePort.String())})
+}
+
+// Adds edges for every allow rule of the policy. Rules without peers allow traffic from or to any peer.
+func (b *graphBuilder) addNetworkPolicy(policy *networkingV1.NetworkPolicy) {
+	selector, err := metaV1.LabelSelectorAsSelector(&policy.Spec.PodSelector)
+	if err != nil {
+		return
+	}
+
+	targets := b.selectWorkloads(selector)
+	if len(targets) == 0 {
+		return
+	}
+
+	if networkpolicy.HasPolicyType(policy, networkpolicy.Ingress) {
+		for _, rule := range policy.Spec.Ingress {
+			label := policy.Name + ": " + formatPorts(rule.Ports)
+			for _, peer := range b.selectPeers(rule.From) {
+				for _, target := range targets {
+					b.addEdge(Edge{From: peer, To: target, Type: NetworkPolicyEdge, Label: label})
+				}
+			}
+		}
+	}
+
+	if networkpolicy.HasPolicyType(policy, networkpolicy.Egress) {
+		for _, rule := range policy.Spec.Egress {
+			label := policy.Name + ": " + formatPorts(rule.Ports)
+			for _, peer := range b.selectPeers(rule.To) {
+				for _, target := range targets {
+					b.addEdge(Edge{From: target, To: peer, Type: NetworkPolicyEdge, Label: label})
+				}
+			}
+		}
+	}
+}
+
+// Returns ids of nodes matching policy peers. Pod selectors without namespace selector are resolved to workloads,
+// other peers are added as external nodes.
+func (b *graphBuilder) selectPeers(peers []networkingV1.NetworkPolicyPeer) []string {
+	if len(peers) == 0 {
+		return []string{b.addNode(ResourceKindExternal, "any").ID}
+	}
+
+	result := make([]string, 0)
+	for _, peer := range peers {
+		switch {
+		case peer.IPBlock != nil:
+			name := peer.IPBlock.CIDR
+			if len(peer.IPBlock.Except) > 0 {
+				name += " except " + strings.Join(peer.IPBlock.Except, ", ")
+			}
+			result = append(result, b.addNode(ResourceKindExternal, name).ID)
+		case peer.NamespaceSelector != nil:
+			name := "namespaces " + metaV1.FormatLabelSelector(peer.NamespaceSelector)
+			if peer.PodSelector != nil {
+				name += ", pods " + metaV1.FormatLabelSelector(peer.PodSelector)
+			}
+			result = append(result, b.addNode(ResourceKindExternal, name).ID)
+		case peer.PodSelector != nil:
+			selector, err := metaV1.LabelSelectorAsSelector(peer.PodSelector)
+			if err == nil {
+				result = append(result, b.selectWorkloads(selector)...)
+			}
+		}
+	}
+
+	return result
+}
+
+// Returns ids of workloads with at least one pod matching the selector.
+func (b *graphBuilder) selectWorkloads(selector labels.Selector) []string {
+	matched := map[string]bool{}
+	for pod, workload := range b.workloads {
+		if selector.Matches(labels.Set(pod.Labels)) {
+			matched[workload] = true
+		}
+	}
+
+	result := make([]string, 0, len(matched))
+	for workload := range matched {
+		result = append(result, workload)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (b *graphBuilder) addNode(kind api.ResourceKind, name string) *Node {
+	id := nodeID(kind, name)
+	if node, exists := b.nodes[id]; exists {
+		return node
+	}
+
+	node := &Node{ID: id, Kind: kind, Name: name}
+	b.nodes[id] = node
+	return node
+}
+
+func (b *graphBuilder) addEdge(edge Edge) {
+	b.edges[edge] = true
+}
+
+// Returns the graph with nodes and edges sorted, so that the output is stable.
+func (b *graphBuilder) build() *Graph {
+	for _, node := range b.nodes {
+		b.graph.Nodes = append(b.graph.Nodes, *node)
+	}
+	sort.Slice(b.graph.Nodes, func(i, j int) bool { return b.graph.Nodes[i].ID < b.graph.Nodes[j].ID })
+
+	for edge := range b.edges {
+		b.graph.Edges = append(b.graph.Edges, edge)
+	}
+	sort.Slice(b.graph.Edges, func(i, j int) bool {
+		x, y := b.graph.Edges[i], b.graph.Edges[j]
+		if x.From != y.From {
+			return x.From < y.From
+		}
+		if x.To != y.To {
+			return x.To < y.To
+		}
+		if x.Type != y.Type {
+			return x.Type < y.Type
+		}
+		return x.Label < y.Label
+	})
+
+	return b.graph
+}
+
+func nodeID(kind api.ResourceKind, name string) string {
+	return string(kind) + "/" + name
+}
+
+func formatPorts(ports []networkingV1.NetworkPolicyPort) string {
+	if len(ports) == 0 {
+		return "all ports"
+	}
+
+	result := make([]string, 0, len(ports))
+	for _, port := range ports {
+		protocol := v1.ProtocolTCP
+		if port.Protocol != nil {
+			protocol = *port.Protocol
+		}
+		if port.Port == nil {
+			result = append(result, string(protocol))
+		} else {
+			result = append(result, fmt.Sprintf("%s/%s", protocol, port.Port.String()))
+		}
+	}
+
+	return strings.Join(result, ", ")
+}
+
+func mergeImages(images []string, other []string) []string {
+	for _, image := range other {
+		found := false
+		for _, existing := range images {
+			found = found || existing == image
+		}
+		if !found {
+			images = append(images, image)
+		}
+	}
+	return images
+}
@@ -0,0 +1,156 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connectivity
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	networkingV1 "k8s.io/api/networking/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newPod(name string, labels map[string]string, phase v1.PodPhase, owner *metaV1.OwnerReference) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Namespace: "default", Name: name, Labels: labels},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "main", Image: name + ":latest"}}},
+		Status:     v1.PodStatus{Phase: phase},
+	}
+	if owner != nil {
+		pod.OwnerReferences = []metaV1.OwnerReference{*owner}
+	}
+	return pod
+}
+
+func newGraphFixture() *fake.Clientset {
+	controller := true
+	replicas := int32(2)
+	rsRef := &metaV1.OwnerReference{Kind: "ReplicaSet", Name: "web-1234", UID: types.UID("rs"), Controller: &controller}
+	jobRef := &metaV1.OwnerReference{Kind: "Job", Name: "migrate", UID: types.UID("job"), Controller: &controller}
+	tcp := v1.ProtocolTCP
+	port := intstr.FromInt(5432)
+
+	return fake.NewSimpleClientset(
+		&apps.ReplicaSet{
+			ObjectMeta: metaV1.ObjectMeta{Namespace: "default", Name: "web-1234", UID: types.UID("rs"),
+				OwnerReferences: []metaV1.OwnerReference{{Kind: "Deployment", Name: "web", Controller: &controller}}},
+			Spec: apps.ReplicaSetSpec{
+				Replicas: &replicas,
+				Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: []v1.Container{{Image: "web:1.0"}}}},
+			},
+		},
+		newPod("web-1234-a", map[string]string{"app": "web"}, v1.PodRunning, rsRef),
+		newPod("web-1234-b", map[string]string{"app": "web"}, v1.PodRunning, rsRef),
+		newPod("db", map[string]string{"app": "db"}, v1.PodRunning, nil),
+		newPod("migrate-x", map[string]string{"app": "db"}, v1.PodSucceeded, jobRef),
+		&v1.Service{
+			ObjectMeta: metaV1.ObjectMeta{Namespace: "default", Name: "web"},
+			Spec: v1.ServiceSpec{
+				Selector: map[string]string{"app": "web"},
+				Ports:    []v1.ServicePort{{Protocol: v1.ProtocolTCP, Port: 80}},
+			},
+		},
+		&v1.Service{ObjectMeta: metaV1.ObjectMeta{Namespace: "default", Name: "external"}},
+		&extensions.Ingress{
+			ObjectMeta: metaV1.ObjectMeta{Namespace: "default", Name: "web"},
+			Spec: extensions.IngressSpec{Rules: []extensions.IngressRule{{
+				Host: "example.com",
+				IngressRuleValue: extensions.IngressRuleValue{HTTP: &extensions.HTTPIngressRuleValue{
+					Paths: []extensions.HTTPIngressPath{
+						{Path: "/", Backend: extensions.IngressBackend{ServiceName: "web",
+							ServicePort: intstr.FromInt(80)}},
+						{Path: "/missing", Backend: extensions.IngressBackend{ServiceName: "missing",
+							ServicePort: intstr.FromInt(80)}},
+					},
+				}},
+			}}},
+		},
+		&networkingV1.NetworkPolicy{
+			ObjectMeta: metaV1.ObjectMeta{Namespace: "default", Name: "db"},
+			Spec: networkingV1.NetworkPolicySpec{
+				PodSelector: metaV1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+				Ingress: []networkingV1.NetworkPolicyIngressRule{{
+					From: []networkingV1.NetworkPolicyPeer{
+						{PodSelector: &metaV1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+						{IPBlock: &networkingV1.IPBlock{CIDR: "10.0.0.0/8"}},
+					},
+					Ports: []networkingV1.NetworkPolicyPort{{Protocol: &tcp, Port: &port}},
+				}},
+			},
+		},
+	)
+}
+
+func TestGetConnectivityGraph(t *testing.T) {
+	expected := &Graph{
+		Namespace: "default",
+		Nodes: []Node{
+			{ID: "deployment/web", Kind: "deployment", Name: "web", Pods: 2, ContainerImages: []string{"web:1.0"}},
+			{ID: "external/10.0.0.0/8", Kind: ResourceKindExternal, Name: "10.0.0.0/8"},
+			{ID: "ingress/web", Kind: "ingress", Name: "web"},
+			{ID: "pod/db", Kind: "pod", Name: "db", Pods: 1, ContainerImages: []string{"db:latest"}},
+			{ID: "service/external", Kind: "service", Name: "external"},
+			{ID: "service/web", Kind: "service", Name: "web"},
+		},
+		Edges: []Edge{
+			{From: "deployment/web", To: "pod/db", Type: NetworkPolicyEdge, Label: "db: TCP/5432"},
+			{From: "external/10.0.0.0/8", To: "pod/db", Type: NetworkPolicyEdge, Label: "db: TCP/5432"},
+			{From: "ingress/web", To: "service/web", Type: IngressEdge, Label: "example.com/ -> 80"},
+			{From: "service/web", To: "deployment/web", Type: ServiceEdge, Label: "TCP/80"},
+		},
+		Errors: []error{},
+	}
+
+	actual, err := GetConnectivityGraph(newGraphFixture(), "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetConnectivityGraph(client, default) == \n%+v, expected \n%+v", actual, expected)
+	}
+}
+
+func TestGraphToDOT(t *testing.T) {
+	graph := &Graph{
+		Namespace: "default",
+		Nodes: []Node{
+			{ID: "service/web", Kind: "service", Name: "web"},
+			{ID: `pod/"quoted"`, Kind: "pod", Name: `"quoted"`},
+		},
+		Edges: []Edge{{From: "service/web", To: `pod/"quoted"`, Type: ServiceEdge, Label: "TCP/80"}},
+	}
+
+	expected := strings.Join([]string{
+		`digraph "default" {`,
+		"\trankdir=LR;",
+		`	"service/web" [label="web\n(service)", shape=ellipse];`,
+		`	"pod/\"quoted\"" [label="\"quoted\"\n(pod)", shape=box];`,
+		`	"service/web" -> "pod/\"quoted\"" [label="TCP/80", style=solid];`,
+		"}",
+		"",
+	}, "\n")
+
+	if actual := graph.ToDOT(); actual != expected {
+		t.Errorf("ToDOT() == \n%s, expected \n%s", actual, expected)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cronjob
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	batch2 "k8s.io/api/batch/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+)
+
+const (
+	// defaultPreviewCount is the number of upcoming runs returned if no count is given.
+	defaultPreviewCount = 5
+
+	// maxPreviewCount is the highest number of upcoming runs that can be previewed.
+	maxPreviewCount = 100
+)
+
+// now is replaced in tests to get predictable schedule previews.
+var now = time.Now
+
+// ScheduleSpec contains the new schedule of a cron job.
+type ScheduleSpec struct {
+	Schedule string `json:"schedule"`
+}
+
+// SchedulePreview is the result of a schedule update. Run times are given in UTC, the cron job controller
+// interprets schedules in the time zone of the controller manager, which usually is UTC as well.
+type SchedulePreview struct {
+	DryRun   bool          `json:"dryRun"`
+	Schedule string        `json:"schedule"`
+	NextRuns []metaV1.Time `json:"nextRuns"`
+}
+
+// SuspendCronJob stops the cron job with the given name from scheduling new jobs. Running jobs are not affected.
+func SuspendCronJob(client client.Interface, namespace, name string) (*batch2.CronJob, error) {
+	log.Printf("Suspending cron job %s in %s namespace", name, namespace)
+	return setCronJobSuspend(client, namespace, name, true)
+}
+
+// ResumeCronJob lets the cron job with the given name schedule jobs again.
+func ResumeCronJob(client client.Interface, namespace, name string) (*batch2.CronJob, error) {
+	log.Printf("Resuming cron job %s in %s namespace", name, namespace)
+	return setCronJobSuspend(client, namespace, name, false)
+}
+
+func setCronJobSuspend(client client.Interface, namespace, name string, suspend bool) (*batch2.CronJob, error) {
+	patch := []byte(fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend))
+	return client.BatchV1beta1().CronJobs(namespace).Patch(context.TODO(), name, types.MergePatchType, patch,
+		metaV1.PatchOptions{})
+}
+
+// UpdateCronJobSchedule validates the schedule and sets it on the cron job with the given name. The result
+// contains the next count run times of the new schedule. In dry run mode the update is only validated by the API
+// server.
+func UpdateCronJobSchedule(client client.Interface, namespace, name string, spec *ScheduleSpec, count int,
+	dryRun bool) (*SchedulePreview, error) {
+	log.Printf("Updating schedule of cron job %s in %s namespace to %q, dry run: %t", name, namespace,
+		spec.Schedule, dryRun)
+
+	if count < 0 || count > maxPreviewCount {
+		return nil, errors.NewInvalid(fmt.Sprintf("Preview count must be between 0 and %d", maxPreviewCount))
+	} else if count == 0 {
+		count = defaultPreviewCount
+	}
+
+	parsed, err := parseSchedule(spec.Schedule)
+	if err != nil {
+		return nil, errors.NewInvalid(err.Error())
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"spec": map[string]string{"schedule": spec.Schedule}})
+	if err != nil {
+		return nil, err
+	}
+
+	options := metaV1.PatchOptions{}
+	if dryRun {
+		options.DryRun = []string{metaV1.DryRunAll}
+	}
+
+	cronJob, err := client.BatchV1beta1().CronJobs(namespace).Patch(context.TODO(), name, types.MergePatchType,
+		patch, options)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &SchedulePreview{DryRun: dryRun, Schedule: cronJob.Spec.Schedule, NextRuns: make([]metaV1.Time, 0)}
+	for _, run := range nextRuns(parsed, now().UTC(), count) {
+		preview.NextRuns = append(preview.NextRuns, metaV1.NewTime(run))
+	}
+
+	return preview, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cronjob
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	batch2 "k8s.io/api/batch/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSuspendAndResumeCronJob(t *testing.T) {
+	client := fake.NewSimpleClientset(&batch2.CronJob{
+		ObjectMeta: metaV1.ObjectMeta{Name: "backup", Namespace: "default"},
+		Spec:       batch2.CronJobSpec{Schedule: "0 * * * *"},
+	})
+
+	cronJob, err := SuspendCronJob(client, "default", "backup")
+	if err != nil {
+		t.Fatalf("SuspendCronJob() returned error: %s", err)
+	}
+	if cronJob.Spec.Suspend == nil || !*cronJob.Spec.Suspend {
+		t.Errorf("SuspendCronJob() should suspend the cron job, got %#v", cronJob.Spec.Suspend)
+	}
+
+	cronJob, err = ResumeCronJob(client, "default", "backup")
+	if err != nil {
+		t.Fatalf("ResumeCronJob() returned error: %s", err)
+	}
+	if cronJob.Spec.Suspend == nil || *cronJob.Spec.Suspend {
+		t.Errorf("ResumeCronJob() should resume the cron job, got %#v", cronJob.Spec.Suspend)
+	}
+
+	if _, err := SuspendCronJob(client, "default", "missing"); err == nil {
+		t.Errorf("SuspendCronJob() of a missing cron job should return an error")
+	}
+}
+
+func TestUpdateCronJobSchedule(t *testing.T) {
+	defer func(original func() time.Time) { now = original }(now)
+	now = func() time.Time { return time.Date(2020, time.January, 30, 10, 17, 42, 0, time.UTC) }
+
+	client := fake.NewSimpleClientset(&batch2.CronJob{
+		ObjectMeta: metaV1.ObjectMeta{Name: "backup", Namespace: "default"},
+		Spec:       batch2.CronJobSpec{Schedule: "0 * * * *"},
+	})
+
+	actual, err := UpdateCronJobSchedule(client, "default", "backup", &ScheduleSpec{Schedule: "0 0 * * *"}, 2,
+		false)
+	if err != nil {
+		t.Fatalf("UpdateCronJobSchedule() returned error: %s", err)
+	}
+
+	expected := &SchedulePreview{
+		Schedule: "0 0 * * *",
+		NextRuns: []metaV1.Time{
+			metaV1.NewTime(time.Date(2020, time.January, 31, 0, 0, 0, 0, time.UTC)),
+			metaV1.NewTime(time.Date(2020, time.February, 1, 0, 0, 0, 0, time.UTC)),
+		},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("UpdateCronJobSchedule() == %#v, expected %#v", actual, expected)
+	}
+
+	cases := []struct {
+		schedule string
+		count    int
+	}{
+		{"0 0 * *", 2},
+		{"0 0 * * *", -1},
+		{"0 0 * * *", maxPreviewCount + 1},
+	}
+	for _, c := range cases {
+		_, err := UpdateCronJobSchedule(client, "default", "backup", &ScheduleSpec{Schedule: c.schedule}, c.count,
+			false)
+		if err == nil {
+			t.Errorf("UpdateCronJobSchedule(%q, %d) == nil, expected error", c.schedule, c.count)
+		}
+	}
+
+	if len(client.Actions()) != 1 {
+		t.Errorf("Invalid schedules should not be sent to the API server, got actions %v", client.Actions())
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cronjob
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/job"
+)
+
+// SuccessTrend tells whether recent runs of a cron job succeed more or less often than older ones.
+type SuccessTrend string
+
+// List of all success trends.
+const (
+	SuccessTrendImproving SuccessTrend = "Improving"
+	SuccessTrendStable    SuccessTrend = "Stable"
+	SuccessTrendDegrading SuccessTrend = "Degrading"
+
+	// SuccessTrendUnknown is used if there are too few finished runs to compare.
+	SuccessTrendUnknown SuccessTrend = "Unknown"
+)
+
+// minTrendRuns is the number of finished runs needed to compute a trend.
+const minTrendRuns = 4
+
+// CronJobHistory is the run history of a cron job. It only covers the jobs retained by the history limits of the
+// cron job, so the success rate is computed over at most successfulJobsHistoryLimit successful and
+// failedJobsHistoryLimit failed runs.
+type CronJobHistory struct {
+	SuccessfulJobsHistoryLimit *int32 `json:"successfulJobsHistoryLimit,omitempty"`
+	FailedJobsHistoryLimit     *int32 `json:"failedJobsHistoryLimit,omitempty"`
+
+	// Runs are ordered from the newest to the oldest one.
+	Runs []job.JobRun `json:"runs"`
+
+	Active    int `json:"active"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+
+	// SuccessRate is the share of successful runs among the finished ones, nil if no run has finished yet.
+	SuccessRate *float64 `json:"successRate,omitempty"`
+
+	// Trend compares the success rate of the newer half of the finished runs to the older half.
+	Trend SuccessTrend `json:"trend"`
+
+	LastSuccessfulRun *metaV1.Time `json:"lastSuccessfulRun,omitempty"`
+
+	// LastSuccessfulRunAgeSeconds is the time since the last successful run finished.
+	LastSuccessfulRunAgeSeconds *int64 `json:"lastSuccessfulRunAgeSeconds,omitempty"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetCronJobHistory returns the run history of the cron job with the given name.
+func GetCronJobHistory(client client.Interface, namespace, name string) (*CronJobHistory, error) {
+	log.Printf("Getting run history of cron job %s in %s namespace", name, namespace)
+
+	cronJob, err := client.BatchV1beta1().CronJobs(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	channels := &common.ResourceChannels{
+		JobList: common.GetJobListChannel(client, common.NewSameNamespaceQuery(namespace), 1),
+		PodList: common.GetPodListChannel(client, common.NewSameNamespaceQuery(namespace), 1),
+	}
+
+	jobs := <-channels.JobList.List
+	err = <-channels.JobList.Error
+	if err != nil {
+		return nil, err
+	}
+
+	pods := <-channels.PodList.List
+	err = <-channels.PodList.Error
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	current := now()
+	history := &CronJobHistory{
+		SuccessfulJobsHistoryLimit: cronJob.Spec.SuccessfulJobsHistoryLimit,
+		FailedJobsHistoryLimit:     cronJob.Spec.FailedJobsHistoryLimit,
+		Runs:                       make([]job.JobRun, 0),
+		Trend:                      SuccessTrendUnknown,
+		Errors:                     nonCriticalErrors,
+	}
+
+	podItems := make([]v1.Pod, 0)
+	if pods != nil {
+		podItems = pods.Items
+	}
+
+	owned := filterJobsByOwnerUID(cronJob.UID, jobs.Items)
+	sort.SliceStable(owned, func(i, j int) bool { return getRunTime(owned[i]).After(getRunTime(owned[j])) })
+	for i := range owned {
+		history.Runs = append(history.Runs, job.ToJobRun(&owned[i], podItems, current))
+	}
+
+	history.summarize(current)
+	return history, nil
+}
+
+// getRunTime returns the time the job started, or the time it was created if it has not started yet.
+func getRunTime(item batch.Job) time.Time {
+	if item.Status.StartTime != nil {
+		return item.Status.StartTime.Time
+	}
+	return item.CreationTimestamp.Time
+}
+
+func (history *CronJobHistory) summarize(current time.Time) {
+	// Finished runs in chronological order.
+	finished := make([]bool, 0)
+	for i := len(history.Runs) - 1; i >= 0; i-- {
+		run := history.Runs[i]
+		switch run.Status {
+		case job.JobStatusComplete:
+			history.Succeeded++
+			finished = append(finished, true)
+			if history.LastSuccessfulRun == nil || run.CompletionTime.After(history.LastSuccessfulRun.Time) {
+				history.LastSuccessfulRun = run.CompletionTime
+			}
+		case job.JobStatusFailed:
+			history.Failed++
+			finished = append(finished, false)
+		default:
+			history.Active++
+		}
+	}
+
+	if len(finished) > 0 {
+		rate := successRate(finished)
+		history.SuccessRate = &rate
+	}
+
+	if history.LastSuccessfulRun != nil {
+		age := int64(current.Sub(history.LastSuccessfulRun.Time) / time.Second)
+		history.LastSuccessfulRunAgeSeconds = &age
+	}
+
+	if len(finished) >= minTrendRuns {
+		older, newer := successRate(finished[:len(finished)/2]), successRate(finished[len(finished)/2:])
+		switch {
+		case newer > older:
+			history.Trend = SuccessTrendImproving
+		case newer < older:
+			history.Trend = SuccessTrendDegrading
+		default:
+			history.Trend = SuccessTrendStable
+		}
+	}
+}
+
+func successRate(results []bool) float64 {
+	succeeded := 0
+	for _, result := range results {
+		if result {
+			succeeded++
+		}
+	}
+	return float64(succeeded) / float64(len(results))
+}
@@ -0,0 +1,144 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cronjob
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	batch "k8s.io/api/batch/v1"
+	batch2 "k8s.io/api/batch/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/job"
+)
+
+func newHistoryJob(name string, owner types.UID, start time.Time, conditionType batch.JobConditionType) *batch.Job {
+	startTime := metaV1.NewTime(start)
+	result := &batch.Job{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			OwnerReferences: []metaV1.OwnerReference{{UID: owner}},
+		},
+		Spec:   batch.JobSpec{Selector: &metaV1.LabelSelector{MatchLabels: map[string]string{"job-name": name}}},
+		Status: batch.JobStatus{StartTime: &startTime},
+	}
+
+	if len(conditionType) > 0 {
+		result.Status.Conditions = []batch.JobCondition{{
+			Type:               conditionType,
+			Status:             v1.ConditionTrue,
+			LastTransitionTime: metaV1.NewTime(start.Add(time.Minute)),
+		}}
+	}
+	return result
+}
+
+func TestGetCronJobHistory(t *testing.T) {
+	defer func(original func() time.Time) { now = original }(now)
+	start := time.Date(2020, time.January, 30, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return start.Add(10 * time.Hour) }
+
+	limit := int32(3)
+	client := fake.NewSimpleClientset(
+		&batch2.CronJob{
+			ObjectMeta: metaV1.ObjectMeta{Name: "backup", Namespace: "default", UID: "cron-uid"},
+			Spec: batch2.CronJobSpec{
+				SuccessfulJobsHistoryLimit: &limit,
+				FailedJobsHistoryLimit:     &limit,
+			},
+		},
+		newHistoryJob("backup-1", "cron-uid", start.Add(time.Hour), batch.JobComplete),
+		newHistoryJob("backup-2", "cron-uid", start.Add(2*time.Hour), batch.JobComplete),
+		newHistoryJob("backup-3", "cron-uid", start.Add(3*time.Hour), batch.JobFailed),
+		newHistoryJob("backup-4", "cron-uid", start.Add(4*time.Hour), batch.JobFailed),
+		newHistoryJob("backup-5", "cron-uid", start.Add(5*time.Hour), ""),
+		newHistoryJob("other", "other-uid", start.Add(6*time.Hour), batch.JobFailed),
+	)
+
+	actual, err := GetCronJobHistory(client, "default", "backup")
+	if err != nil {
+		t.Fatalf("GetCronJobHistory() returned error: %s", err)
+	}
+
+	names := make([]string, 0)
+	for _, run := range actual.Runs {
+		names = append(names, run.Name)
+	}
+	expectedNames := []string{"backup-5", "backup-4", "backup-3", "backup-2", "backup-1"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("GetCronJobHistory() returned runs %v, expected %v", names, expectedNames)
+	}
+
+	if actual.Runs[0].Status != job.JobStatusRunning || actual.Runs[0].DurationSeconds != 5*3600 {
+		t.Errorf("GetCronJobHistory() should report running jobs with elapsed time, got %#v", actual.Runs[0])
+	}
+
+	lastSuccessfulRun := metaV1.NewTime(start.Add(2*time.Hour + time.Minute))
+	age := int64(8*3600 - 60)
+	rate := 0.5
+	expected := &CronJobHistory{
+		SuccessfulJobsHistoryLimit:  &limit,
+		FailedJobsHistoryLimit:      &limit,
+		Runs:                        actual.Runs,
+		Active:                      1,
+		Succeeded:                   2,
+		Failed:                      2,
+		SuccessRate:                 &rate,
+		Trend:                       SuccessTrendDegrading,
+		LastSuccessfulRun:           &lastSuccessfulRun,
+		LastSuccessfulRunAgeSeconds: &age,
+		Errors:                      []error{},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetCronJobHistory() == %#v, expected %#v", actual, expected)
+	}
+}
+
+func TestCronJobHistoryTrend(t *testing.T) {
+	cases := []struct {
+		statuses []job.JobStatusType
+		expected SuccessTrend
+	}{
+		{[]job.JobStatusType{job.JobStatusComplete, job.JobStatusFailed}, SuccessTrendUnknown},
+		{
+			[]job.JobStatusType{job.JobStatusComplete, job.JobStatusComplete, job.JobStatusFailed,
+				job.JobStatusFailed},
+			SuccessTrendImproving,
+		},
+		{
+			[]job.JobStatusType{job.JobStatusComplete, job.JobStatusFailed, job.JobStatusComplete,
+				job.JobStatusFailed, job.JobStatusRunning},
+			SuccessTrendStable,
+		},
+	}
+
+	for _, c := range cases {
+		history := &CronJobHistory{Trend: SuccessTrendUnknown}
+		for _, status := range c.statuses {
+			history.Runs = append(history.Runs, job.JobRun{Status: status, CompletionTime: &metaV1.Time{}})
+		}
+
+		history.summarize(time.Now())
+		if history.Trend != c.expected {
+			t.Errorf("summarize(%v) computed trend %s, expected %s", c.statuses, history.Trend, c.expected)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cronjob
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// schedule computes the activation times of a cron job schedule.
+type schedule interface {
+	// next returns the first activation time after t, or a zero time if there is none within the search limit.
+	next(t time.Time) time.Time
+}
+
+// cronField is the range and the accepted names of one field of a cron expression.
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = cronField{name: "day of week", min: 0, max: 6, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// scheduleDescriptors are the predefined schedules accepted by the cron job controller.
+var scheduleDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// maxScheduleSearch limits how far into the future activation times are searched, so that schedules that never
+// match, like the 30th of February, terminate.
+const maxScheduleSearch = 5 * 366 * 24 * time.Hour
+
+// cronSchedule is a standard five field cron expression. Every field is a bit set of matching values.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// domStar and dowStar are set if the day fields are unrestricted. If both day fields are restricted, a day
+	// matches if either of them matches.
+	domStar, dowStar bool
+}
+
+// everySchedule activates in a constant interval, as in "@every 1h30m".
+type everySchedule struct {
+	interval time.Duration
+}
+
+// parseSchedule parses a cron job schedule the same way the cron job controller does. It accepts five field cron
+// expressions, predefined descriptors like "@daily" and "@every <duration>".
+func parseSchedule(expression string) (schedule, error) {
+	expression = strings.TrimSpace(expression)
+	if len(expression) == 0 {
+		return nil, fmt.Errorf("Schedule must not be empty")
+	}
+
+	if strings.HasPrefix(expression, "@every ") {
+		interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expression, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid interval in schedule %q: %s", expression, err)
+		}
+		if interval < time.Second {
+			interval = time.Second
+		}
+		return everySchedule{interval: interval - interval%time.Second}, nil
+	}
+
+	if strings.HasPrefix(expression, "@") {
+		descriptor, ok := scheduleDescriptors[strings.ToLower(expression)]
+		if !ok {
+			return nil, fmt.Errorf("Unknown schedule descriptor: %s", expression)
+		}
+		expression = descriptor
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("Schedule %q must have 5 fields, found %d", expression, len(fields))
+	}
+
+	result := &cronSchedule{}
+	var err error
+	if result.minute, _, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if result.hour, _, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if result.dom, result.domStar, err = domField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if result.month, _, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if result.dow, result.dowStar, err = dowField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// parse returns the bit set of values matched by a comma separated list of values, ranges and steps, and whether
+// the field starts with a wildcard.
+func (field cronField) parse(value string) (uint64, bool, error) {
+	var bits uint64
+	for _, part := range strings.Split(value, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rangePart = part[:i]
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, false, fmt.Errorf("Invalid step in %s field: %s", field.name, part)
+			}
+		}
+
+		start, end := field.min, field.max
+		switch {
+		case rangePart == "*" || rangePart == "?":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if start, err = field.value(bounds[0]); err != nil {
+				return 0, false, err
+			}
+			if end, err = field.value(bounds[1]); err != nil {
+				return 0, false, err
+			}
+			if start > end {
+				return 0, false, fmt.Errorf("Invalid range in %s field: %s", field.name, part)
+			}
+		default:
+			var err error
+			if start, err = field.value(rangePart); err != nil {
+				return 0, false, err
+			}
+			// A single value without a step matches only itself, with a step it starts a range up to the maximum.
+			if !strings.Contains(part, "/") {
+				end = start
+			}
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, strings.HasPrefix(value, "*") || strings.HasPrefix(value, "?"), nil
+}
+
+func (field cronField) value(value string) (int, error) {
+	result, ok := field.names[strings.ToLower(value)]
+	if !ok {
+		var err error
+		if result, err = strconv.Atoi(value); err != nil {
+			return 0, fmt.Errorf("Invalid value in %s field: %s", field.name, value)
+		}
+	}
+
+	if result < field.min || result > field.max {
+		return 0, fmt.Errorf("Value %d of %s field is out of range %d-%d", result, field.name, field.min,
+			field.max)
+	}
+	return result, nil
+}
+
+func (s *cronSchedule) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxScheduleSearch)
+
+	for t.Before(limit) {
+		if !matches(s.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !matches(s.hour, t.Hour()) {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if !matches(s.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := matches(s.dom, t.Day())
+	dowMatch := matches(s.dow, int(t.Weekday()))
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func (s everySchedule) next(t time.Time) time.Time {
+	return t.Add(s.interval - time.Duration(t.Nanosecond()))
+}
+
+func matches(bits uint64, value int) bool {
+	return bits&(1<<uint(value)) != 0
+}
+
+// nextRuns returns up to count activation times of the schedule after t.
+func nextRuns(s schedule, t time.Time, count int) []time.Time {
+	result := make([]time.Time, 0, count)
+	for len(result) < count {
+		t = s.next(t)
+		if t.IsZero() {
+			break
+		}
+		result = append(result, t)
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cronjob
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	start := time.Date(2020, time.January, 30, 10, 17, 42, 0, time.UTC)
+	cases := []struct {
+		schedule string
+		expected []time.Time
+	}{
+		{
+			"*/15 * * * *",
+			[]time.Time{
+				time.Date(2020, time.January, 30, 10, 30, 0, 0, time.UTC),
+				time.Date(2020, time.January, 30, 10, 45, 0, 0, time.UTC),
+				time.Date(2020, time.January, 30, 11, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			"0 9-17/4 * * mon-fri",
+			[]time.Time{
+				time.Date(2020, time.January, 30, 13, 0, 0, 0, time.UTC),
+				time.Date(2020, time.January, 30, 17, 0, 0, 0, time.UTC),
+				time.Date(2020, time.January, 31, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			"30 2 29 2 *",
+			[]time.Time{
+				time.Date(2020, time.February, 29, 2, 30, 0, 0, time.UTC),
+				time.Date(2024, time.February, 29, 2, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			// Both day fields are restricted, so days matching either of them are taken.
+			"0 0 1 * SUN",
+			[]time.Time{
+				time.Date(2020, time.February, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2020, time.February, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2020, time.February, 9, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			"@monthly",
+			[]time.Time{
+				time.Date(2020, time.February, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			"@every 90m",
+			[]time.Time{
+				time.Date(2020, time.January, 30, 11, 47, 42, 0, time.UTC),
+				time.Date(2020, time.January, 30, 13, 17, 42, 0, time.UTC),
+			},
+		},
+		{
+			"0 0 30 2 *",
+			[]time.Time{},
+		},
+	}
+
+	for _, c := range cases {
+		parsed, err := parseSchedule(c.schedule)
+		if err != nil {
+			t.Errorf("parseSchedule(%q) returned error: %s", c.schedule, err)
+			continue
+		}
+
+		actual := nextRuns(parsed, start, len(c.expected))
+		if len(c.expected) == 0 {
+			actual = nextRuns(parsed, start, 1)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("nextRuns(%q) == %v, expected %v", c.schedule, actual, c.expected)
+		}
+	}
+}
+
+func TestParseScheduleShouldRejectInvalidSchedules(t *testing.T) {
+	cases := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 7",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * foo *",
+		"@fortnightly",
+		"@every day",
+	}
+
+	for _, c := range cases {
+		if _, err := parseSchedule(c); err == nil {
+			t.Errorf("parseSchedule(%q) == nil, expected error", c)
+		}
+	}
+}
@@ -110,11 +110,19 @@ func (self *DataSelector) Filter() *DataSelector {
 		matches := true
 		for _, filterBy := range self.DataSelectQuery.FilterQuery.FilterByList {
 			v := c.GetProperty(filterBy.Property)
-			if v == nil || !v.Contains(filterBy.Value) {
+			if v == nil {
+				matches = false
+				break
+			}
+			value, err := toComparableValue(v, filterBy.Value)
+			if err != nil || !v.Contains(value) {
 				matches = false
 				break
 			}
 		}
+		if matches && self.DataSelectQuery.FilterQuery.Expression != nil {
+			matches = self.DataSelectQuery.FilterQuery.Expression.Matches(c)
+		}
 		if matches {
 			filteredList = append(filteredList, c)
 		}
@@ -124,6 +132,22 @@ func (self *DataSelector) Filter() *DataSelector {
 	return self
 }
 
+// toComparableValue converts filter value to the type of given property. Values of filterBy parameter are always
+// strings, so they are parsed the same way as values of filter expressions. Labels accept strings as selectors.
+func toComparableValue(property ComparableValue, value ComparableValue) (ComparableValue, error) {
+	raw, ok := value.(StdComparableString)
+	if !ok {
+		return value, nil
+	}
+
+	switch property.(type) {
+	case StdComparableString, StdComparableLabels:
+		return value, nil
+	}
+
+	return parseComparableValue(property, string(raw))
+}
+
 func (self *DataSelector) getMetrics(metricClient metricapi.MetricClient) (
 	[]metricapi.MetricPromises, error) {
 	metricPromises := make([]metricapi.MetricPromises, 0)
@@ -73,6 +73,8 @@ var NoSort = &SortQuery{
 
 type FilterQuery struct {
 	FilterByList []FilterBy
+	// Expression is an optional filter expression that data cells have to match in addition to FilterByList.
+	Expression FilterExpression
 }
 
 type FilterBy struct {
@@ -164,3 +166,16 @@ func NewFilterQuery(filterByListRaw []string) *FilterQuery {
 		FilterByList: filterByList,
 	}
 }
+
+// NewFilterExpressionQuery returns a copy of given filter query that additionally requires data cells to match
+// given expression. Nil expression leaves the query as it is.
+func NewFilterExpressionQuery(filterQuery *FilterQuery, expression FilterExpression) *FilterQuery {
+	if expression == nil {
+		return filterQuery
+	}
+
+	return &FilterQuery{
+		FilterByList: filterQuery.FilterByList,
+		Expression:   expression,
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataselect
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// FilterOperator is an operator used by FilterTerm to match property of data cell against given value.
+type FilterOperator string
+
+// List of all operators supported by filter expressions.
+const (
+	// FilterOperatorContains matches when property value contains given value. It is the operator used by
+	// legacy filterBy query.
+	FilterOperatorContains FilterOperator = ":"
+	// FilterOperatorEqual matches when property value is equal to given value.
+	FilterOperatorEqual FilterOperator = "="
+	// FilterOperatorNotEqual matches when property value is not equal to given value.
+	FilterOperatorNotEqual FilterOperator = "!="
+	// FilterOperatorPrefix matches when property value starts with given value.
+	FilterOperatorPrefix FilterOperator = "^="
+	// FilterOperatorRegex matches when property value matches given regular expression.
+	FilterOperatorRegex FilterOperator = "~="
+	// FilterOperatorGreater matches when property value is greater than given value.
+	FilterOperatorGreater FilterOperator = ">"
+	// FilterOperatorGreaterOrEqual matches when property value is greater than or equal to given value.
+	FilterOperatorGreaterOrEqual FilterOperator = ">="
+	// FilterOperatorLess matches when property value is less than given value.
+	FilterOperatorLess FilterOperator = "<"
+	// FilterOperatorLessOrEqual matches when property value is less than or equal to given value.
+	FilterOperatorLessOrEqual FilterOperator = "<="
+)
+
+// FilterOperators lists all supported operators. Longer operators are listed before their prefixes, so the
+// first operator that matches given input is always the longest one.
+var FilterOperators = []FilterOperator{
+	FilterOperatorNotEqual,
+	FilterOperatorPrefix,
+	FilterOperatorRegex,
+	FilterOperatorGreaterOrEqual,
+	FilterOperatorLessOrEqual,
+	FilterOperatorEqual,
+	FilterOperatorGreater,
+	FilterOperatorLess,
+	FilterOperatorContains,
+}
+
+// filterTimeLayouts are layouts accepted as values of terms that are compared against time properties.
+var filterTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+
+// FilterExpression is a parsed filter expression that can be evaluated against any data cell.
+type FilterExpression interface {
+	// Matches returns true if given data cell satisfies the expression.
+	Matches(DataCell) bool
+}
+
+// FilterTerm matches a single property of data cell against given value using given operator.
+type FilterTerm struct {
+	Property PropertyName
+	Operator FilterOperator
+	Value    string
+
+	regex *regexp.Regexp
+}
+
+// NewFilterTerm creates filter term and validates its operator. Regular expressions are compiled upfront, so
+// invalid ones are reported to the caller instead of silently not matching anything.
+func NewFilterTerm(property PropertyName, operator FilterOperator, value string) (*FilterTerm, error) {
+	term := &FilterTerm{Property: property, Operator: operator, Value: value}
+
+	switch operator {
+	case FilterOperatorContains, FilterOperatorEqual, FilterOperatorNotEqual, FilterOperatorPrefix,
+		FilterOperatorGreater, FilterOperatorGreaterOrEqual, FilterOperatorLess, FilterOperatorLessOrEqual:
+	case FilterOperatorRegex:
+		regex, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q for property %s: %s", value, property, err)
+		}
+		term.regex = regex
+	default:
+		return nil, fmt.Errorf("unsupported filter operator %q", operator)
+	}
+
+	return term, nil
+}
+
+// Matches implements FilterExpression. Data cells that do not support the property never match.
+func (self *FilterTerm) Matches(cell DataCell) bool {
+	property := cell.GetProperty(self.Property)
+	if property == nil {
+		return false
+	}
+
+	switch self.Operator {
+	case FilterOperatorPrefix:
+		return strings.HasPrefix(comparableToString(property), self.Value)
+	case FilterOperatorRegex:
+		return self.regex.MatchString(comparableToString(property))
+	}
+
+	value, err := parseComparableValue(property, self.Value)
+	if err != nil {
+		return false
+	}
+
+	switch self.Operator {
+	case FilterOperatorContains:
+		return property.Contains(value)
+	case FilterOperatorEqual:
+		return property.Compare(value) == 0
+	case FilterOperatorNotEqual:
+		return property.Compare(value) != 0
+	case FilterOperatorGreater:
+		return property.Compare(value) > 0
+	case FilterOperatorGreaterOrEqual:
+		return property.Compare(value) >= 0
+	case FilterOperatorLess:
+		return property.Compare(value) < 0
+	case FilterOperatorLessOrEqual:
+		return property.Compare(value) <= 0
+	default:
+		return false
+	}
+}
+
+// LabelFilterTerm matches labels of data cell against a label selector, i.e. label:app=web.
+type LabelFilterTerm struct {
+	Selector labels.Selector
+}
+
+// NewLabelFilterTerm creates filter term from label selector written in standard Kubernetes syntax.
+func NewLabelFilterTerm(selector string) (*LabelFilterTerm, error) {
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LabelFilterTerm{Selector: parsed}, nil
+}
+
+// Matches implements FilterExpression. Data cells that do not expose labels never match.
+func (self *LabelFilterTerm) Matches(cell DataCell) bool {
+	cellLabels, ok := cell.GetProperty(LabelProperty).(StdComparableLabels)
+	if !ok {
+		return false
+	}
+
+	return self.Selector.Matches(labels.Set(cellLabels))
+}
+
+// NotFilterExpression negates wrapped expression.
+type NotFilterExpression struct {
+	Expression FilterExpression
+}
+
+// Matches implements FilterExpression.
+func (self *NotFilterExpression) Matches(cell DataCell) bool {
+	return !self.Expression.Matches(cell)
+}
+
+// AndFilterExpression matches when all of its expressions match.
+type AndFilterExpression struct {
+	Expressions []FilterExpression
+}
+
+// Matches implements FilterExpression.
+func (self *AndFilterExpression) Matches(cell DataCell) bool {
+	for _, expression := range self.Expressions {
+		if !expression.Matches(cell) {
+			return false
+		}
+	}
+	return true
+}
+
+// OrFilterExpression matches when at least one of its expressions matches.
+type OrFilterExpression struct {
+	Expressions []FilterExpression
+}
+
+// Matches implements FilterExpression.
+func (self *OrFilterExpression) Matches(cell DataCell) bool {
+	for _, expression := range self.Expressions {
+		if expression.Matches(cell) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseComparableValue converts raw value to the same comparable type as given property, so they can be
+// compared with each other.
+func parseComparableValue(property ComparableValue, raw string) (ComparableValue, error) {
+	switch property.(type) {
+	case StdComparableString:
+		return StdComparableString(raw), nil
+	case StdComparableInt:
+		if value, err := strconv.Atoi(raw); err == nil {
+			return StdComparableInt(value), nil
+		}
+		// Allow quantities like 8Gi for properties holding amount of resources.
+		quantity, err := resource.ParseQuantity(raw)
+		if err != nil {
+			return nil, err
+		}
+		return StdComparableInt(quantity.Value()), nil
+	case StdComparableFloat:
+		if value, err := strconv.ParseFloat(raw, 64); err == nil {
+			return StdComparableFloat(value), nil
+		}
+		// Allow quantities like 500m for properties holding amount of resources.
+		quantity, err := resource.ParseQuantity(raw)
+		if err != nil {
+			return nil, err
+		}
+		return StdComparableFloat(float64(quantity.MilliValue()) / 1000), nil
+	case StdComparableRFC3339Timestamp:
+		value, err := parseFilterTime(raw)
+		if err != nil {
+			return nil, err
+		}
+		return StdComparableRFC3339Timestamp(value.Format(time.RFC3339)), nil
+	case StdComparableTime:
+		value, err := parseFilterTime(raw)
+		if err != nil {
+			return nil, err
+		}
+		return StdComparableTime(value), nil
+	case StdComparableLabels:
+		selector, err := labels.ConvertSelectorToLabelsMap(raw)
+		if err != nil {
+			return nil, err
+		}
+		return StdComparableLabels(selector), nil
+	default:
+		return nil, fmt.Errorf("filtering is not supported for values of type %T", property)
+	}
+}
+
+func parseFilterTime(raw string) (time.Time, error) {
+	var err error
+	for _, layout := range filterTimeLayouts {
+		var value time.Time
+		if value, err = time.Parse(layout, raw); err == nil {
+			return value, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// comparableToString returns string representation of comparable value used by text based operators.
+func comparableToString(value ComparableValue) string {
+	switch v := value.(type) {
+	case StdComparableString:
+		return string(v)
+	case StdComparableInt:
+		return strconv.Itoa(int(v))
+	case StdComparableFloat:
+		return strconv.FormatFloat(float64(v), 'f', -1, 64)
+	case StdComparableRFC3339Timestamp:
+		return string(v)
+	case StdComparableTime:
+		return time.Time(v).UTC().Format(time.RFC3339)
+	case StdComparableLabels:
+		return labels.Set(v).String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
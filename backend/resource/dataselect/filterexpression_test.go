@@ -0,0 +1,239 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataselect
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type FilterExpressionTestCell struct {
+	Name     string
+	Id       int
+	Created  time.Time
+	Labels   map[string]string
+	Restarts int
+	Ratio    float64
+}
+
+func (self FilterExpressionTestCell) GetProperty(name PropertyName) ComparableValue {
+	switch name {
+	case NameProperty:
+		return StdComparableString(self.Name)
+	case CreationTimestampProperty:
+		return StdComparableTime(self.Created)
+	case StatusProperty:
+		return StdComparableRFC3339Timestamp(self.Created.Format(time.RFC3339))
+	case LabelProperty:
+		return StdComparableLabels(self.Labels)
+	case "restarts":
+		return StdComparableInt(self.Restarts)
+	case "readyRatio":
+		return StdComparableFloat(self.Ratio)
+	default:
+		return nil
+	}
+}
+
+func getFilterExpressionCellList() []DataCell {
+	day := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []DataCell{
+		FilterExpressionTestCell{"nginx-1", 1, day, map[string]string{"app": "web"}, 0, 1},
+		FilterExpressionTestCell{"nginx-2", 2, day.Add(24 * time.Hour), map[string]string{"app": "web"}, 5, 0.5},
+		FilterExpressionTestCell{"redis-1", 3, day.Add(48 * time.Hour), map[string]string{"app": "db"}, 1, 1},
+		FilterExpressionTestCell{"kube-proxy", 4, day.Add(72 * time.Hour), nil, 12, 0},
+	}
+}
+
+func mustFilterTerm(property PropertyName, operator FilterOperator, value string) *FilterTerm {
+	term, err := NewFilterTerm(property, operator, value)
+	if err != nil {
+		panic(err)
+	}
+	return term
+}
+
+func mustLabelFilterTerm(selector string) *LabelFilterTerm {
+	term, err := NewLabelFilterTerm(selector)
+	if err != nil {
+		panic(err)
+	}
+	return term
+}
+
+func TestFilterExpression(t *testing.T) {
+	cases := []struct {
+		info       string
+		expression FilterExpression
+		expected   []int
+	}{
+		{
+			"contains",
+			mustFilterTerm(NameProperty, FilterOperatorContains, "-1"),
+			[]int{1, 3},
+		},
+		{
+			"equal",
+			mustFilterTerm(NameProperty, FilterOperatorEqual, "nginx-2"),
+			[]int{2},
+		},
+		{
+			"not equal",
+			mustFilterTerm(NameProperty, FilterOperatorNotEqual, "nginx-2"),
+			[]int{1, 3, 4},
+		},
+		{
+			"prefix",
+			mustFilterTerm(NameProperty, FilterOperatorPrefix, "nginx"),
+			[]int{1, 2},
+		},
+		{
+			"regex",
+			mustFilterTerm(NameProperty, FilterOperatorRegex, "^[a-z]+-[0-9]$"),
+			[]int{1, 2, 3},
+		},
+		{
+			"numeric comparison",
+			mustFilterTerm("restarts", FilterOperatorGreaterOrEqual, "5"),
+			[]int{2, 4},
+		},
+		{
+			"numeric comparison with invalid number does not match",
+			mustFilterTerm("restarts", FilterOperatorGreater, "five"),
+			[]int{},
+		},
+		{
+			"numeric comparison with quantity",
+			mustFilterTerm("restarts", FilterOperatorLess, "0.01k"),
+			[]int{1, 2, 3},
+		},
+		{
+			"time comparison",
+			mustFilterTerm(CreationTimestampProperty, FilterOperatorGreater, "2020-01-02"),
+			[]int{3, 4},
+		},
+		{
+			"RFC3339 timestamp comparison",
+			mustFilterTerm(StatusProperty, FilterOperatorLess, "2020-01-02T00:00:00Z"),
+			[]int{1},
+		},
+		{
+			"label selector",
+			mustLabelFilterTerm("app=web"),
+			[]int{1, 2},
+		},
+		{
+			"label selector with set based requirement",
+			mustLabelFilterTerm("app notin (web)"),
+			[]int{3, 4},
+		},
+		{
+			"unsupported property does not match",
+			mustFilterTerm(NamespaceProperty, FilterOperatorEqual, ""),
+			[]int{},
+		},
+		{
+			"negation",
+			&NotFilterExpression{Expression: mustFilterTerm(NameProperty, FilterOperatorPrefix, "nginx")},
+			[]int{3, 4},
+		},
+		{
+			"conjunction",
+			&AndFilterExpression{Expressions: []FilterExpression{
+				mustLabelFilterTerm("app=web"),
+				mustFilterTerm("restarts", FilterOperatorGreater, "0"),
+			}},
+			[]int{2},
+		},
+		{
+			"disjunction",
+			&OrFilterExpression{Expressions: []FilterExpression{
+				mustLabelFilterTerm("app=db"),
+				mustFilterTerm("restarts", FilterOperatorGreater, "10"),
+			}},
+			[]int{3, 4},
+		},
+	}
+
+	for _, c := range cases {
+		selectableData := DataSelector{
+			GenericDataList: getFilterExpressionCellList(),
+			DataSelectQuery: &DataSelectQuery{FilterQuery: NewFilterExpressionQuery(NoFilter, c.expression)},
+		}
+
+		actual := []int{}
+		for _, cell := range selectableData.Filter().GenericDataList {
+			actual = append(actual, cell.(FilterExpressionTestCell).Id)
+		}
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Filter: %s. Got %v, expected %v.", c.info, actual, c.expected)
+		}
+	}
+}
+
+func TestNewFilterTermInvalid(t *testing.T) {
+	if _, err := NewFilterTerm(NameProperty, FilterOperatorRegex, "[a-"); err == nil {
+		t.Error("NewFilterTerm() should fail for invalid regular expression")
+	}
+
+	if _, err := NewFilterTerm(NameProperty, "??", "a"); err == nil {
+		t.Error("NewFilterTerm() should fail for unsupported operator")
+	}
+
+	if _, err := NewLabelFilterTerm("app=web="); err == nil {
+		t.Error("NewLabelFilterTerm() should fail for invalid selector")
+	}
+}
+
+func TestNewFilterExpressionQueryDoesNotModifyNoFilter(t *testing.T) {
+	NewFilterExpressionQuery(NoFilter, mustFilterTerm(NameProperty, FilterOperatorEqual, "a"))
+	if NoFilter.Expression != nil {
+		t.Error("NewFilterExpressionQuery() should not modify given filter query")
+	}
+}
+
+func TestFilterBy(t *testing.T) {
+	cases := []struct {
+		filterBy []string
+		expected []int
+	}{
+		{[]string{"name", "nginx"}, []int{1, 2}},
+		{[]string{"restarts", "5"}, []int{2}},
+		{[]string{"restarts", "abc"}, []int{}},
+		{[]string{"readyRatio", "0.5"}, []int{2}},
+		{[]string{"readyRatio", "1", "name", "redis"}, []int{3}},
+		{[]string{"label", "app"}, []int{1, 2, 3}},
+		{[]string{"label", "app=db"}, []int{3}},
+		{[]string{"creationTimestamp", "2020-01-02T00:00:00Z"}, []int{2}},
+	}
+
+	for _, c := range cases {
+		selectableData := DataSelector{
+			GenericDataList: getFilterExpressionCellList(),
+			DataSelectQuery: &DataSelectQuery{FilterQuery: NewFilterQuery(c.filterBy)},
+		}
+
+		actual := []int{}
+		for _, cell := range selectableData.Filter().GenericDataList {
+			actual = append(actual, cell.(FilterExpressionTestCell).Id)
+		}
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Filter(%v): got %v, expected %v.", c.filterBy, actual, c.expected)
+		}
+	}
+}
@@ -25,4 +25,30 @@ const (
 	NamespaceProperty         = "namespace"
 	StatusProperty            = "status"
 	TypeProperty              = "type"
+	LabelProperty             = "label"
+	ImageProperty             = "image"
+
+	// Pod specific properties.
+	RestartsProperty = "restarts"
+	NodeNameProperty = "nodeName"
+	QOSClassProperty = "qosClass"
+	IPProperty       = "ip"
+
+	// Deployment specific properties.
+	ReadyRatioProperty = "readyRatio"
+
+	// Node specific properties.
+	RolesProperty             = "roles"
+	AllocatableCPUProperty    = "allocatableCPU"
+	AllocatableMemoryProperty = "allocatableMemory"
+	TaintCountProperty        = "taintCount"
+
+	// Persistent volume claim specific properties.
+	CapacityProperty     = "capacity"
+	StorageClassProperty = "storageClass"
+
+	// Certificate specific properties.
+	SubjectProperty         = "subject"
+	IssuerProperty          = "issuer"
+	DaysUntilExpiryProperty = "daysUntilExpiry"
 )
@@ -17,6 +17,8 @@ package dataselect
 import (
 	"strings"
 	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // ----------------------- Standard Comparable Types ------------------------
@@ -36,6 +38,17 @@ func (self StdComparableInt) Contains(otherV ComparableValue) bool {
 	return self.Compare(otherV) == 0
 }
 
+type StdComparableFloat float64
+
+func (self StdComparableFloat) Compare(otherV ComparableValue) int {
+	other := otherV.(StdComparableFloat)
+	return floatsCompare(float64(self), float64(other))
+}
+
+func (self StdComparableFloat) Contains(otherV ComparableValue) bool {
+	return self.Compare(otherV) == 0
+}
+
 type StdComparableString string
 
 func (self StdComparableString) Compare(otherV ComparableValue) int {
@@ -79,6 +92,34 @@ func (self StdComparableTime) Contains(otherV ComparableValue) bool {
 	return self.Compare(otherV) == 0
 }
 
+// StdComparableLabels compares label sets by their canonical string representation. Label set contains other
+// label set if it has all of its keys with the same values. Strings, i.e. values of legacy filterBy parameter, are
+// parsed as label selectors.
+type StdComparableLabels map[string]string
+
+func (self StdComparableLabels) Compare(otherV ComparableValue) int {
+	switch other := otherV.(type) {
+	case StdComparableLabels:
+		return strings.Compare(labels.Set(self).String(), labels.Set(other).String())
+	case StdComparableString:
+		return strings.Compare(labels.Set(self).String(), string(other))
+	default:
+		return -1
+	}
+}
+
+func (self StdComparableLabels) Contains(otherV ComparableValue) bool {
+	switch other := otherV.(type) {
+	case StdComparableLabels:
+		return labels.SelectorFromSet(labels.Set(other)).Matches(labels.Set(self))
+	case StdComparableString:
+		selector, err := labels.Parse(string(other))
+		return err == nil && selector.Matches(labels.Set(self))
+	default:
+		return false
+	}
+}
+
 // Int comparison functions. Similar to strings.Compare.
 func intsCompare(a, b int) int {
 	if a > b {
@@ -89,6 +130,15 @@ func intsCompare(a, b int) int {
 	return -1
 }
 
+func floatsCompare(a, b float64) int {
+	if a > b {
+		return 1
+	} else if a == b {
+		return 0
+	}
+	return -1
+}
+
 func ints64Compare(a, b int64) int {
 	if a > b {
 		return 1
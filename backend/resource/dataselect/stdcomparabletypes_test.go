@@ -162,3 +162,25 @@ func TestStdComparableRFC3339Timestamp(t *testing.T) {
 		}
 	}
 }
+
+func TestStdComparableLabelsContains(t *testing.T) {
+	cases := []struct {
+		a        StdComparableLabels
+		b        ComparableValue
+		expected bool
+	}{
+		{StdComparableLabels{"app": "web", "tier": "front"}, StdComparableLabels{"app": "web"}, true},
+		{StdComparableLabels{"app": "web"}, StdComparableLabels{"app": "db"}, false},
+		{StdComparableLabels{"app": "web"}, StdComparableString("app"), true},
+		{StdComparableLabels{"app": "web"}, StdComparableString("app=web,tier"), false},
+		{StdComparableLabels{"app": "web"}, StdComparableString("app in (web, db)"), true},
+		{StdComparableLabels{"app": "web"}, StdComparableString("app=("), false},
+		{StdComparableLabels{"app": "web"}, StdComparableInt(1), false},
+	}
+	for _, c := range cases {
+		actual := c.a.Contains(c.b)
+		if actual != c.expected {
+			t.Errorf("Contains(%+v) == %+v, expected %+v", c.b, actual, c.expected)
+		}
+	}
+}
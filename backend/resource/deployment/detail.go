@@ -20,6 +20,7 @@ import (
 
 	"github.com/ycyxuehan/dashboard-gin/backend/errors"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/poddisruptionbudget"
 	apps "k8s.io/api/apps/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -75,6 +76,9 @@ type DeploymentDetail struct {
 	// Optional field that specifies the number of old Replica Sets to retain to allow rollback.
 	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit"`
 
+	// Pod disruption budgets that match the pods of the deployment and limit their voluntary evictions.
+	ProtectingBudgets []poddisruptionbudget.PodDisruptionBudget `json:"protectingBudgets"`
+
 	// List of non-critical errors, that occurred during resource retrieval.
 	Errors []error `json:"errors"`
 }
@@ -125,6 +129,12 @@ func GetDeploymentDetail(client client.Interface, namespace string, deploymentNa
 		return nil, criticalError
 	}
 
+	budgets, err := poddisruptionbudget.GetProtectingBudgets(client, namespace, deployment.Spec.Template.Labels)
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
 	// Extra Info
 	var rollingUpdateStrategy *RollingUpdateStrategy
 	if deployment.Spec.Strategy.RollingUpdate != nil {
@@ -143,6 +153,7 @@ func GetDeploymentDetail(client client.Interface, namespace string, deploymentNa
 		MinReadySeconds:       deployment.Spec.MinReadySeconds,
 		RollingUpdateStrategy: rollingUpdateStrategy,
 		RevisionHistoryLimit:  deployment.Spec.RevisionHistoryLimit,
+		ProtectingBudgets:     budgets,
 		Errors:                nonCriticalErrors,
 	}, nil
 }
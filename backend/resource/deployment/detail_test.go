@@ -21,8 +21,10 @@ import (
 	"github.com/ycyxuehan/dashboard-gin/backend/api"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/poddisruptionbudget"
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1beta1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/fake"
@@ -94,6 +96,32 @@ func TestGetDeploymentDetail(t *testing.T) {
 	maxSurge := intstr.FromInt(1)
 	maxUnavailable := intstr.FromString("25%")
 
+	minAvailable := intstr.FromInt(3)
+	pdbList := &policy.PodDisruptionBudgetList{
+		Items: []policy.PodDisruptionBudget{
+			{
+				ObjectMeta: metaV1.ObjectMeta{Name: "pdb-1", Namespace: "ns-1"},
+				Spec: policy.PodDisruptionBudgetSpec{
+					MinAvailable: &minAvailable,
+					Selector:     &metaV1.LabelSelector{MatchLabels: map[string]string{"track": "beta"}},
+				},
+				Status: policy.PodDisruptionBudgetStatus{
+					CurrentHealthy:     4,
+					DesiredHealthy:     3,
+					DisruptionsAllowed: 1,
+					ExpectedPods:       4,
+				},
+			},
+			{
+				ObjectMeta: metaV1.ObjectMeta{Name: "pdb-2", Namespace: "ns-1"},
+				Spec: policy.PodDisruptionBudgetSpec{
+					MinAvailable: &minAvailable,
+					Selector:     &metaV1.LabelSelector{MatchLabels: map[string]string{"track": "stable"}},
+				},
+			},
+		},
+	}
+
 	cases := []struct {
 		namespace, name string
 		expectedActions []string
@@ -102,7 +130,7 @@ func TestGetDeploymentDetail(t *testing.T) {
 	}{
 		{
 			"ns-1", "dp-1",
-			[]string{"get", "list", "list", "list"},
+			[]string{"get", "list", "list", "list", "list"},
 			deployment,
 			&DeploymentDetail{
 				Deployment: Deployment{
@@ -138,13 +166,24 @@ func TestGetDeploymentDetail(t *testing.T) {
 					MaxSurge:       &maxSurge,
 					MaxUnavailable: &maxUnavailable,
 				},
+				ProtectingBudgets: []poddisruptionbudget.PodDisruptionBudget{
+					{
+						ObjectMeta:         api.ObjectMeta{Name: "pdb-1", Namespace: "ns-1"},
+						TypeMeta:           api.TypeMeta{Kind: api.ResourceKindPodDisruptionBudget},
+						MinAvailable:       &minAvailable,
+						CurrentHealthy:     4,
+						DesiredHealthy:     3,
+						DisruptionsAllowed: 1,
+						ExpectedPods:       4,
+					},
+				},
 				Errors: []error{},
 			},
 		},
 	}
 
 	for _, c := range cases {
-		fakeClient := fake.NewSimpleClientset(c.deployment, replicaSetList, podList, eventList)
+		fakeClient := fake.NewSimpleClientset(c.deployment, replicaSetList, podList, eventList, pdbList)
 		dataselect.DefaultDataSelectWithMetrics.MetricQuery = dataselect.NoMetrics
 		actual, _ := GetDeploymentDetail(fakeClient, c.namespace, c.name)
 
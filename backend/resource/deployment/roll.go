@@ -0,0 +1,64 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/pod"
+)
+
+// restartedAtAnnotation is set on the pod template to roll out new pods, the same way kubectl does it.
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// RollDeployments restarts the pods of all consuming deployments by changing their pod template, the same way
+// "kubectl rollout restart" does. Returns the restarted deployments and the errors of the ones that could not be
+// restarted.
+func RollDeployments(client client.Interface, namespace string, consumers *pod.Consumers) ([]pod.Consumer,
+	[]error) {
+	rolled := make([]pod.Consumer, 0)
+	rollErrors := make([]error, 0)
+
+	patch, _ := json.Marshal(map[string]interface{}{"spec": map[string]interface{}{"template": map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]string{
+			restartedAtAnnotation: time.Now().Format(time.RFC3339),
+		}},
+	}}})
+
+	for _, workload := range consumers.Workloads {
+		if workload.Kind != api.ResourceKindDeployment {
+			continue
+		}
+
+		log.Printf("Restarting deployment %s in %s namespace", workload.Name, namespace)
+		_, err := client.AppsV1().Deployments(namespace).Patch(context.TODO(), workload.Name, types.MergePatchType,
+			patch, metaV1.PatchOptions{})
+		if err != nil {
+			rollErrors = append(rollErrors, err)
+			continue
+		}
+		rolled = append(rolled, workload)
+	}
+
+	return rolled, rollErrors
+}
@@ -0,0 +1,60 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/pod"
+)
+
+func TestRollDeployments(t *testing.T) {
+	client := fake.NewSimpleClientset(&apps.Deployment{
+		ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default"},
+	})
+
+	consumers := &pod.Consumers{
+		Workloads: []pod.Consumer{
+			{Kind: api.ResourceKindDeployment, Name: "web"},
+			{Kind: api.ResourceKindStatefulSet, Name: "db"},
+		},
+	}
+
+	rolled, rollErrors := RollDeployments(client, "default", consumers)
+	if len(rollErrors) > 0 || !reflect.DeepEqual(rolled, consumers.Workloads[:1]) {
+		t.Errorf("RollDeployments() == %#v, %v, expected only the deployment to be rolled", rolled, rollErrors)
+	}
+
+	deployment, err := client.AppsV1().Deployments("default").Get(context.TODO(), "web", metaV1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := deployment.Spec.Template.Annotations[restartedAtAnnotation]; !ok {
+		t.Errorf("expected %s annotation on the pod template", restartedAtAnnotation)
+	}
+
+	consumers.Workloads = []pod.Consumer{{Kind: api.ResourceKindDeployment, Name: "missing"}}
+	rolled, rollErrors = RollDeployments(client, "default", consumers)
+	if len(rolled) != 0 || len(rollErrors) != 1 {
+		t.Errorf("RollDeployments() == %#v, %v, expected an error for a missing deployment", rolled, rollErrors)
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnosis
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+)
+
+// Severity tells how likely a cause keeps the workload from becoming ready.
+type Severity string
+
+// List of all severities.
+const (
+	SeverityCritical Severity = "Critical"
+	SeverityWarning  Severity = "Warning"
+	SeverityInfo     Severity = "Info"
+)
+
+// Reason is a short, machine understandable name of a probable root cause.
+type Reason string
+
+// List of all reasons the analyzer can find. Reasons are ranked by their score, root causes like a missing secret
+// rank higher than the symptoms they cause, like a failing image pull.
+const (
+	ReasonImagePullSecretMissing Reason = "ImagePullSecretMissing"
+	ReasonVolumeClaimMissing     Reason = "PersistentVolumeClaimMissing"
+	ReasonQuotaExceeded          Reason = "QuotaExceeded"
+	ReasonLimitRangeViolated     Reason = "LimitRangeViolated"
+	ReasonImagePullFailed        Reason = "ImagePullFailed"
+	ReasonContainerConfigError   Reason = "ContainerConfigError"
+	ReasonVolumeClaimNotBound    Reason = "PersistentVolumeClaimNotBound"
+	ReasonUnschedulable          Reason = "Unschedulable"
+	ReasonNodeNotReady           Reason = "NodeNotReady"
+	ReasonOOMKilled              Reason = "OOMKilled"
+	ReasonCrashLoopBackOff       Reason = "CrashLoopBackOff"
+	ReasonNodePressure           Reason = "NodePressure"
+	ReasonEvicted                Reason = "Evicted"
+	ReasonReadinessProbeFailed   Reason = "ReadinessProbeFailed"
+	ReasonWarningEvent           Reason = "WarningEvent"
+)
+
+var reasonScores = map[Reason]int{
+	ReasonImagePullSecretMissing: 100,
+	ReasonVolumeClaimMissing:     95,
+	ReasonQuotaExceeded:          90,
+	ReasonLimitRangeViolated:     90,
+	ReasonImagePullFailed:        85,
+	ReasonContainerConfigError:   85,
+	ReasonVolumeClaimNotBound:    80,
+	ReasonUnschedulable:          75,
+	ReasonNodeNotReady:           70,
+	ReasonOOMKilled:              65,
+	ReasonCrashLoopBackOff:       60,
+	ReasonNodePressure:           55,
+	ReasonEvicted:                50,
+	ReasonReadinessProbeFailed:   40,
+	ReasonWarningEvent:           10,
+}
+
+// ObjectReference points to an object involved in a cause.
+type ObjectReference struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// Cause is a probable root cause of a workload not being ready. Causes with the same reason and message found on
+// multiple pods are merged.
+type Cause struct {
+	Reason   Reason   `json:"reason"`
+	Severity Severity `json:"severity"`
+	Score    int      `json:"score"`
+	Message  string   `json:"message"`
+
+	// Count is the number of times the cause was found, e.g. the number of pods it affects.
+	Count int `json:"count"`
+
+	Objects []ObjectReference `json:"objects"`
+}
+
+// Diagnosis is the ranked list of probable root causes for a pod or a controller that is not ready.
+type Diagnosis struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	Pods      int `json:"pods"`
+	ReadyPods int `json:"readyPods"`
+
+	// Causes are ordered from the most to the least probable one.
+	Causes []Cause `json:"causes"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// analyzer collects causes and caches the objects read while walking the pods of a workload.
+type analyzer struct {
+	client    client.Interface
+	namespace string
+
+	causes map[string]*Cause
+	order  []string
+	errors []error
+
+	nodes   map[string]*v1.Node
+	secrets map[string]bool
+	claims  map[string]*v1.PersistentVolumeClaim
+}
+
+// GetDiagnosis analyzes the pods of the pod or controller with the given kind and name. It walks pod conditions,
+// container states, events, persistent volume claims, image pull secrets, nodes, resource quotas and limit ranges
+// and returns the probable root causes ranked by their likelihood.
+func GetDiagnosis(client client.Interface, kind, namespace, name string) (*Diagnosis, error) {
+	log.Printf("Diagnosing %s %s in %s namespace", kind, name, namespace)
+
+	target, err := getTarget(client, strings.ToLower(kind), namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &analyzer{
+		client:    client,
+		namespace: namespace,
+		causes:    make(map[string]*Cause),
+		errors:    make([]error, 0),
+		nodes:     make(map[string]*v1.Node),
+		secrets:   make(map[string]bool),
+		claims:    make(map[string]*v1.PersistentVolumeClaim),
+	}
+
+	events, err := client.CoreV1().Events(namespace).List(context.TODO(), api.ListEverything)
+	if err = a.handleError(err); err != nil {
+		return nil, err
+	}
+
+	eventsByUID := make(map[types.UID][]v1.Event)
+	if events != nil {
+		for _, event := range events.Items {
+			eventsByUID[event.InvolvedObject.UID] = append(eventsByUID[event.InvolvedObject.UID], event)
+		}
+	}
+
+	diagnosis := &Diagnosis{Kind: target.kind, Namespace: namespace, Name: name, Pods: len(target.pods)}
+	for _, uid := range target.controllerUIDs {
+		if err := a.analyzeControllerEvents(eventsByUID[uid]); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range target.pods {
+		pod := &target.pods[i]
+		if isPodReady(pod) {
+			diagnosis.ReadyPods++
+			continue
+		}
+		if err := a.analyzePod(pod, eventsByUID[pod.UID]); err != nil {
+			return nil, err
+		}
+	}
+
+	diagnosis.Causes = a.rankedCauses()
+	diagnosis.Errors = a.errors
+	return diagnosis, nil
+}
+
+// addCause records a cause, merging it with an earlier cause with the same reason and message.
+func (a *analyzer) addCause(reason Reason, severity Severity, message string, objects ...ObjectReference) {
+	key := string(reason) + "/" + message
+	cause, ok := a.causes[key]
+	if !ok {
+		cause = &Cause{Reason: reason, Severity: severity, Score: reasonScores[reason], Message: message,
+			Objects: make([]ObjectReference, 0)}
+		a.causes[key] = cause
+		a.order = append(a.order, key)
+	}
+
+	cause.Count++
+	for _, object := range objects {
+		if !containsReference(cause.Objects, object) {
+			cause.Objects = append(cause.Objects, object)
+		}
+	}
+}
+
+func (a *analyzer) rankedCauses() []Cause {
+	result := make([]Cause, 0)
+	for _, key := range a.order {
+		result = append(result, *a.causes[key])
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].Count > result[j].Count
+	})
+	return result
+}
+
+// handleError keeps non-critical errors, like missing permissions to read nodes, and returns critical ones.
+func (a *analyzer) handleError(err error) error {
+	var criticalError error
+	a.errors, criticalError = errors.AppendError(err, a.errors)
+	return criticalError
+}
+
+func isPodReady(pod *v1.Pod) bool {
+	if pod.Status.Phase == v1.PodSucceeded {
+		return true
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func containsReference(references []ObjectReference, reference ObjectReference) bool {
+	for _, item := range references {
+		if item == reference {
+			return true
+		}
+	}
+	return false
+}
+
+func reference(kind, namespace, name string) ObjectReference {
+	return ObjectReference{Kind: kind, Namespace: namespace, Name: name}
+}
+
+func podReference(pod *v1.Pod) ObjectReference {
+	return reference(api.ResourceKindPod, pod.Namespace, pod.Name)
+}
+
+func containerMessage(container string, format string, args ...interface{}) string {
+	return fmt.Sprintf("Container %s: ", container) + fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,223 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnosis
+
+import (
+	"reflect"
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func getReasons(causes []Cause) []Reason {
+	result := make([]Reason, 0)
+	for _, cause := range causes {
+		result = append(result, cause.Reason)
+	}
+	return result
+}
+
+func TestGetDiagnosisOfPod(t *testing.T) {
+	storageClass := "fast"
+	pod := &v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default", UID: "pod-uid"},
+		Spec: v1.PodSpec{
+			NodeName:         "node-1",
+			ImagePullSecrets: []v1.LocalObjectReference{{Name: "registry"}},
+			Volumes: []v1.Volume{{
+				Name: "data",
+				VolumeSource: v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "data"},
+				},
+			}},
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+			ContainerStatuses: []v1.ContainerStatus{{
+				Name: "web",
+				State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{
+					Reason: "ImagePullBackOff", Message: "Back-off pulling image",
+				}},
+			}},
+		},
+	}
+
+	client := fake.NewSimpleClientset(
+		pod,
+		&v1.PersistentVolumeClaim{
+			ObjectMeta: metaV1.ObjectMeta{Name: "data", Namespace: "default"},
+			Spec:       v1.PersistentVolumeClaimSpec{StorageClassName: &storageClass},
+			Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending},
+		},
+		&v1.Node{
+			ObjectMeta: metaV1.ObjectMeta{Name: "node-1"},
+			Status: v1.NodeStatus{Conditions: []v1.NodeCondition{
+				{Type: v1.NodeReady, Status: v1.ConditionTrue},
+				{Type: v1.NodeMemoryPressure, Status: v1.ConditionTrue},
+			}},
+		},
+		&v1.Event{
+			ObjectMeta:     metaV1.ObjectMeta{Name: "web.1", Namespace: "default"},
+			InvolvedObject: v1.ObjectReference{UID: "pod-uid", Kind: "Pod", Name: "web"},
+			Type:           v1.EventTypeWarning,
+			Reason:         "FailedMount",
+			Message:        "Unable to attach volumes",
+		},
+	)
+
+	actual, err := GetDiagnosis(client, "Pod", "default", "web")
+	if err != nil {
+		t.Fatalf("GetDiagnosis() returned error: %s", err)
+	}
+
+	podRef := ObjectReference{Kind: "pod", Namespace: "default", Name: "web"}
+	expected := &Diagnosis{
+		Kind:      "pod",
+		Namespace: "default",
+		Name:      "web",
+		Pods:      1,
+		Causes: []Cause{
+			{
+				Reason: ReasonImagePullSecretMissing, Severity: SeverityCritical, Score: 100, Count: 1,
+				Message: "Image pull secret registry does not exist",
+				Objects: []ObjectReference{podRef, {Kind: "secret", Namespace: "default", Name: "registry"}},
+			},
+			{
+				Reason: ReasonImagePullFailed, Severity: SeverityCritical, Score: 85, Count: 1,
+				Message: "Container web: ImagePullBackOff: Back-off pulling image",
+				Objects: []ObjectReference{podRef},
+			},
+			{
+				Reason: ReasonVolumeClaimNotBound, Severity: SeverityCritical, Score: 80, Count: 1,
+				Message: "Persistent volume claim data is Pending",
+				Objects: []ObjectReference{
+					podRef,
+					{Kind: "persistentvolumeclaim", Namespace: "default", Name: "data"},
+					{Kind: "storageclass", Name: "fast"},
+				},
+			},
+			{
+				Reason: ReasonNodePressure, Severity: SeverityWarning, Score: 55, Count: 1,
+				Message: "Node node-1 has MemoryPressure",
+				Objects: []ObjectReference{podRef, {Kind: "node", Name: "node-1"}},
+			},
+			{
+				Reason: ReasonWarningEvent, Severity: SeverityInfo, Score: 10, Count: 1,
+				Message: "FailedMount: Unable to attach volumes",
+				Objects: []ObjectReference{podRef},
+			},
+		},
+		Errors: []error{},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetDiagnosis() == %#v, expected %#v", actual, expected)
+	}
+}
+
+func TestGetDiagnosisOfDeployment(t *testing.T) {
+	controller := true
+	labels := map[string]string{"app": "web"}
+	newPod := func(name string, ready bool, status v1.ContainerStatus) *v1.Pod {
+		readyStatus := v1.ConditionFalse
+		if ready {
+			readyStatus = v1.ConditionTrue
+		}
+		return &v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+			Spec: v1.PodSpec{Containers: []v1.Container{{
+				Name: "web",
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("64Mi")},
+				},
+			}}},
+			Status: v1.PodStatus{
+				Conditions:        []v1.PodCondition{{Type: v1.PodReady, Status: readyStatus}},
+				ContainerStatuses: []v1.ContainerStatus{status},
+			},
+		}
+	}
+
+	crashing := v1.ContainerStatus{
+		Name:         "web",
+		RestartCount: 4,
+		State:        v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+			ExitCode: 137, Reason: "OOMKilled",
+		}},
+	}
+
+	client := fake.NewSimpleClientset(
+		&apps.Deployment{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default", UID: "deployment-uid"},
+			Spec:       apps.DeploymentSpec{Selector: &metaV1.LabelSelector{MatchLabels: labels}},
+		},
+		&apps.ReplicaSet{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name: "web-1", Namespace: "default", UID: "replicaset-uid", Labels: labels,
+				OwnerReferences: []metaV1.OwnerReference{{UID: "deployment-uid", Controller: &controller}},
+			},
+		},
+		newPod("web-1-a", true, v1.ContainerStatus{Name: "web", Ready: true}),
+		newPod("web-1-b", false, crashing),
+		newPod("web-1-c", false, crashing),
+		&v1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: "other", Namespace: "default"}},
+		&v1.Event{
+			ObjectMeta:     metaV1.ObjectMeta{Name: "web-1.1", Namespace: "default"},
+			InvolvedObject: v1.ObjectReference{UID: "replicaset-uid", Kind: "ReplicaSet", Name: "web-1"},
+			Type:           v1.EventTypeWarning,
+			Reason:         "FailedCreate",
+			Message: `Error creating: pods "web-1-d" is forbidden: exceeded quota: compute, requested: ` +
+				`memory=64Mi, used: memory=1Gi, limited: memory=1Gi`,
+		},
+	)
+
+	actual, err := GetDiagnosis(client, "deployment", "default", "web")
+	if err != nil {
+		t.Fatalf("GetDiagnosis() returned error: %s", err)
+	}
+
+	if actual.Pods != 3 || actual.ReadyPods != 1 {
+		t.Errorf("GetDiagnosis() found %d pods with %d ready, expected 3 pods with 1 ready", actual.Pods,
+			actual.ReadyPods)
+	}
+
+	expectedReasons := []Reason{ReasonQuotaExceeded, ReasonOOMKilled, ReasonCrashLoopBackOff}
+	if reasons := getReasons(actual.Causes); !reflect.DeepEqual(reasons, expectedReasons) {
+		t.Fatalf("GetDiagnosis() found causes %v, expected %v", reasons, expectedReasons)
+	}
+
+	expectedQuota := []ObjectReference{
+		{Kind: "replicaset", Namespace: "default", Name: "web-1"},
+		{Kind: "resourcequota", Namespace: "default", Name: "compute"},
+	}
+	if !reflect.DeepEqual(actual.Causes[0].Objects, expectedQuota) {
+		t.Errorf("GetDiagnosis() should point to the quota, got %v", actual.Causes[0].Objects)
+	}
+
+	oom := actual.Causes[1]
+	if oom.Count != 2 || oom.Message != "Container web: killed for exceeding its memory limit of 64Mi" {
+		t.Errorf("GetDiagnosis() should merge causes of all pods, got %#v", oom)
+	}
+}
+
+func TestGetDiagnosisShouldRejectUnsupportedKinds(t *testing.T) {
+	if _, err := GetDiagnosis(fake.NewSimpleClientset(), "service", "default", "web"); err == nil {
+		t.Errorf("GetDiagnosis() of a service should return an error")
+	}
+}
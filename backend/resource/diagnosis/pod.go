@@ -0,0 +1,313 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnosis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+)
+
+// imagePullReasons are the waiting reasons of containers whose image cannot be pulled.
+var imagePullReasons = map[string]bool{
+	"ErrImagePull":      true,
+	"ImagePullBackOff":  true,
+	"InvalidImageName":  true,
+	"ErrImageNeverPull": true,
+}
+
+// containerConfigReasons are the waiting reasons of containers that cannot be created, usually because of a
+// missing config map or secret.
+var containerConfigReasons = map[string]bool{
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+}
+
+// explainedEventReasons are pod event reasons already covered by pod conditions and container states.
+var explainedEventReasons = map[string]bool{
+	"FailedScheduling": true,
+	"BackOff":          true,
+	"Unhealthy":        true,
+	"Failed":           true,
+	"Evicted":          true,
+}
+
+// nodePressureConditions are the node conditions that make the kubelet evict pods.
+var nodePressureConditions = []v1.NodeConditionType{
+	v1.NodeMemoryPressure,
+	v1.NodeDiskPressure,
+	v1.NodePIDPressure,
+}
+
+// analyzePod records the causes of a single pod not being ready.
+func (a *analyzer) analyzePod(pod *v1.Pod, events []v1.Event) error {
+	if pod.Status.Reason == "Evicted" {
+		a.addCause(ReasonEvicted, SeverityWarning, pod.Status.Message, podReference(pod))
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse &&
+			condition.Reason == v1.PodReasonUnschedulable {
+			a.addCause(ReasonUnschedulable, SeverityCritical, condition.Message, podReference(pod))
+		}
+	}
+
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			if err := a.checkClaim(pod, volume.PersistentVolumeClaim.ClaimName); err != nil {
+				return err
+			}
+		}
+	}
+
+	statuses := append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...),
+		pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if err := a.checkContainer(pod, status, events); err != nil {
+			return err
+		}
+	}
+
+	if len(pod.Spec.NodeName) > 0 {
+		if err := a.checkNode(pod); err != nil {
+			return err
+		}
+	}
+
+	for _, event := range events {
+		if event.Type == v1.EventTypeWarning && !explainedEventReasons[event.Reason] {
+			a.addCause(ReasonWarningEvent, SeverityInfo, fmt.Sprintf("%s: %s", event.Reason, event.Message),
+				podReference(pod))
+		}
+	}
+
+	return nil
+}
+
+func (a *analyzer) checkContainer(pod *v1.Pod, status v1.ContainerStatus, events []v1.Event) error {
+	if waiting := status.State.Waiting; waiting != nil {
+		switch {
+		case imagePullReasons[waiting.Reason]:
+			if err := a.checkImagePullSecrets(pod); err != nil {
+				return err
+			}
+			a.addCause(ReasonImagePullFailed, SeverityCritical,
+				containerMessage(status.Name, "%s: %s", waiting.Reason, waiting.Message), podReference(pod))
+		case containerConfigReasons[waiting.Reason]:
+			a.addCause(ReasonContainerConfigError, SeverityCritical,
+				containerMessage(status.Name, "%s", waiting.Message), podReference(pod))
+		case waiting.Reason == "CrashLoopBackOff":
+			message := containerMessage(status.Name, "restarted %d times", status.RestartCount)
+			if last := status.LastTerminationState.Terminated; last != nil {
+				message = containerMessage(status.Name, "restarted %d times, last exit code %d (%s)",
+					status.RestartCount, last.ExitCode, last.Reason)
+			}
+			a.addCause(ReasonCrashLoopBackOff, SeverityCritical, message, podReference(pod))
+		}
+	}
+
+	for _, terminated := range []*v1.ContainerStateTerminated{status.State.Terminated,
+		status.LastTerminationState.Terminated} {
+		if terminated != nil && terminated.Reason == "OOMKilled" {
+			message := containerMessage(status.Name, "killed because it ran out of memory, no memory limit set")
+			if limit, ok := getMemoryLimit(pod, status.Name); ok {
+				message = containerMessage(status.Name, "killed for exceeding its memory limit of %s", limit)
+			}
+			a.addCause(ReasonOOMKilled, SeverityCritical, message, podReference(pod))
+			break
+		}
+	}
+
+	if status.State.Running != nil && !status.Ready && hasReadinessProbe(pod, status.Name) {
+		message := containerMessage(status.Name, "running but not ready")
+		for _, event := range events {
+			if event.Reason == "Unhealthy" && strings.HasPrefix(event.Message, "Readiness probe failed") {
+				message = containerMessage(status.Name, "%s", event.Message)
+			}
+		}
+		a.addCause(ReasonReadinessProbeFailed, SeverityWarning, message, podReference(pod))
+	}
+
+	return nil
+}
+
+func (a *analyzer) checkImagePullSecrets(pod *v1.Pod) error {
+	for _, secret := range pod.Spec.ImagePullSecrets {
+		exists, ok := a.secrets[secret.Name]
+		if !ok {
+			_, err := a.client.CoreV1().Secrets(pod.Namespace).Get(context.TODO(), secret.Name, metaV1.GetOptions{})
+			if err != nil && !k8serrors.IsNotFound(err) {
+				if err := a.handleError(err); err != nil {
+					return err
+				}
+				continue
+			}
+			exists = err == nil
+			a.secrets[secret.Name] = exists
+		}
+
+		if !exists {
+			a.addCause(ReasonImagePullSecretMissing, SeverityCritical,
+				fmt.Sprintf("Image pull secret %s does not exist", secret.Name), podReference(pod),
+				reference(api.ResourceKindSecret, pod.Namespace, secret.Name))
+		}
+	}
+	return nil
+}
+
+func (a *analyzer) checkClaim(pod *v1.Pod, name string) error {
+	claim, ok := a.claims[name]
+	if !ok {
+		var err error
+		claim, err = a.client.CoreV1().PersistentVolumeClaims(pod.Namespace).Get(context.TODO(), name,
+			metaV1.GetOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return a.handleError(err)
+		}
+		if err != nil {
+			claim = nil
+		}
+		a.claims[name] = claim
+	}
+
+	claimReference := reference(api.ResourceKindPersistentVolumeClaim, pod.Namespace, name)
+	if claim == nil {
+		a.addCause(ReasonVolumeClaimMissing, SeverityCritical,
+			fmt.Sprintf("Persistent volume claim %s does not exist", name), podReference(pod), claimReference)
+		return nil
+	}
+
+	if claim.Status.Phase != v1.ClaimBound {
+		objects := []ObjectReference{podReference(pod), claimReference}
+		if claim.Spec.StorageClassName != nil && len(*claim.Spec.StorageClassName) > 0 {
+			objects = append(objects, reference(api.ResourceKindStorageClass, "", *claim.Spec.StorageClassName))
+		}
+		a.addCause(ReasonVolumeClaimNotBound, SeverityCritical,
+			fmt.Sprintf("Persistent volume claim %s is %s", name, claim.Status.Phase), objects...)
+	}
+	return nil
+}
+
+func (a *analyzer) checkNode(pod *v1.Pod) error {
+	name := pod.Spec.NodeName
+	node, ok := a.nodes[name]
+	if !ok {
+		var err error
+		node, err = a.client.CoreV1().Nodes().Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return a.handleError(err)
+		}
+		if err != nil {
+			node = nil
+		}
+		a.nodes[name] = node
+	}
+
+	nodeReference := reference(api.ResourceKindNode, "", name)
+	if node == nil {
+		a.addCause(ReasonNodeNotReady, SeverityCritical, fmt.Sprintf("Node %s does not exist", name),
+			podReference(pod), nodeReference)
+		return nil
+	}
+
+	ready := false
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady && condition.Status == v1.ConditionTrue {
+			ready = true
+		}
+	}
+	if !ready {
+		a.addCause(ReasonNodeNotReady, SeverityCritical, fmt.Sprintf("Node %s is not ready", name),
+			podReference(pod), nodeReference)
+	}
+
+	for _, pressure := range nodePressureConditions {
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == pressure && condition.Status == v1.ConditionTrue {
+				a.addCause(ReasonNodePressure, SeverityWarning, fmt.Sprintf("Node %s has %s", name, pressure),
+					podReference(pod), nodeReference)
+			}
+		}
+	}
+	return nil
+}
+
+// analyzeControllerEvents records why a controller could not create its pods. Quota and limit range violations
+// are only visible here, as the rejected pods never exist.
+func (a *analyzer) analyzeControllerEvents(events []v1.Event) error {
+	for _, event := range events {
+		if event.Type != v1.EventTypeWarning {
+			continue
+		}
+
+		owner := reference(strings.ToLower(event.InvolvedObject.Kind), a.namespace, event.InvolvedObject.Name)
+		switch {
+		case strings.Contains(event.Message, "exceeded quota: "):
+			quota := strings.SplitN(strings.SplitN(event.Message, "exceeded quota: ", 2)[1], ",", 2)[0]
+			a.addCause(ReasonQuotaExceeded, SeverityCritical, event.Message, owner,
+				reference(api.ResourceKindResourceQuota, a.namespace, quota))
+		case strings.Contains(event.Message, "per Container") || strings.Contains(event.Message, "per Pod") ||
+			strings.Contains(event.Message, "LimitRange"):
+			objects, err := a.getLimitRanges()
+			if err != nil {
+				return err
+			}
+			a.addCause(ReasonLimitRangeViolated, SeverityCritical, event.Message, append(objects, owner)...)
+		default:
+			a.addCause(ReasonWarningEvent, SeverityInfo, fmt.Sprintf("%s: %s", event.Reason, event.Message),
+				owner)
+		}
+	}
+	return nil
+}
+
+func (a *analyzer) getLimitRanges() ([]ObjectReference, error) {
+	result := make([]ObjectReference, 0)
+	limitRanges, err := a.client.CoreV1().LimitRanges(a.namespace).List(context.TODO(), api.ListEverything)
+	if err != nil {
+		return result, a.handleError(err)
+	}
+
+	for _, item := range limitRanges.Items {
+		result = append(result, reference(api.ResourceKindLimitRange, item.Namespace, item.Name))
+	}
+	return result, nil
+}
+
+func getMemoryLimit(pod *v1.Pod, container string) (string, bool) {
+	for _, item := range append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		if item.Name == container {
+			if limit, ok := item.Resources.Limits[v1.ResourceMemory]; ok {
+				return limit.String(), true
+			}
+		}
+	}
+	return "", false
+}
+
+func hasReadinessProbe(pod *v1.Pod, container string) bool {
+	for _, item := range pod.Spec.Containers {
+		if item.Name == container {
+			return item.ReadinessProbe != nil
+		}
+	}
+	return false
+}
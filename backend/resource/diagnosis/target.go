@@ -0,0 +1,134 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnosis
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+)
+
+// target is the pod or controller being diagnosed together with its pods.
+type target struct {
+	kind string
+	pods []v1.Pod
+
+	// controllerUIDs are the UIDs of the controller and the controllers it manages, e.g. the replica sets of a
+	// deployment. Their events tell why pods could not be created.
+	controllerUIDs []types.UID
+}
+
+func getTarget(client client.Interface, kind, namespace, name string) (*target, error) {
+	var selector *metaV1.LabelSelector
+	var uid types.UID
+
+	switch kind {
+	case api.ResourceKindPod:
+		pod, err := client.CoreV1().Pods(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &target{kind: kind, pods: []v1.Pod{*pod}}, nil
+	case api.ResourceKindDeployment:
+		deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return getControllerTarget(client, kind, namespace, deployment.UID, deployment.Spec.Selector, true)
+	case api.ResourceKindReplicaSet:
+		replicaSet, err := client.AppsV1().ReplicaSets(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, uid = replicaSet.Spec.Selector, replicaSet.UID
+	case api.ResourceKindStatefulSet:
+		statefulSet, err := client.AppsV1().StatefulSets(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, uid = statefulSet.Spec.Selector, statefulSet.UID
+	case api.ResourceKindDaemonSet:
+		daemonSet, err := client.AppsV1().DaemonSets(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, uid = daemonSet.Spec.Selector, daemonSet.UID
+	case api.ResourceKindJob:
+		job, err := client.BatchV1().Jobs(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, uid = job.Spec.Selector, job.UID
+	case api.ResourceKindReplicationController:
+		rc, err := client.CoreV1().ReplicationControllers(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, uid = &metaV1.LabelSelector{MatchLabels: rc.Spec.Selector}, rc.UID
+	default:
+		return nil, errors.NewInvalid(fmt.Sprintf("Diagnosis is not supported for resource kind: %s", kind))
+	}
+
+	return getControllerTarget(client, kind, namespace, uid, selector, false)
+}
+
+// getControllerTarget lists the pods matching the selector of a controller. Replica sets of deployments are
+// included, as pod creation failures are reported on them.
+func getControllerTarget(client client.Interface, kind, namespace string, uid types.UID,
+	labelSelector *metaV1.LabelSelector, withReplicaSets bool) (*target, error) {
+	result := &target{kind: kind, pods: make([]v1.Pod, 0), controllerUIDs: []types.UID{uid}}
+
+	selector, err := metaV1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+	// An empty selector would match every pod of the namespace.
+	if selector.Empty() {
+		return result, nil
+	}
+
+	options := metaV1.ListOptions{LabelSelector: selector.String()}
+	pods, err := client.CoreV1().Pods(namespace).List(context.TODO(), options)
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		if selector.Matches(labels.Set(pod.Labels)) {
+			result.pods = append(result.pods, pod)
+		}
+	}
+
+	if withReplicaSets {
+		replicaSets, err := client.AppsV1().ReplicaSets(namespace).List(context.TODO(), options)
+		if err != nil {
+			return nil, err
+		}
+		for _, replicaSet := range replicaSets.Items {
+			if controller := metaV1.GetControllerOf(&replicaSet); controller != nil && controller.UID == uid {
+				result.controllerUIDs = append(result.controllerUIDs, replicaSet.UID)
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"log"
+
+	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/ingress"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/service"
+)
+
+// Discovery structure contains all resource lists grouped into the discovery and load balancing category.
+type Discovery struct {
+	ServiceList service.ServiceList `json:"serviceList"`
+
+	IngressList ingress.IngressList `json:"ingressList"`
+
+	// Status holds number of resources in each state per resource kind.
+	Status map[api.ResourceKind]common.ResourceStatus `json:"status"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetDiscovery returns a list of all discovery and load balancing resources in the cluster.
+func GetDiscovery(client client.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*Discovery, error) {
+	log.Printf("Getting discovery and load balancing resources in namespace %s", nsQuery.ToRequestParam())
+
+	// Every list is read once more to compute the status of all resources, not only of the selected page.
+	channels := &common.ResourceChannels{
+		ServiceList: common.GetServiceListChannel(client, nsQuery, 2),
+		IngressList: common.GetIngressListChannel(client, nsQuery, 2),
+	}
+
+	return GetDiscoveryFromChannels(channels, dsQuery)
+}
+
+// GetDiscoveryFromChannels returns a list of all discovery and load balancing resources in the cluster, from
+// the channel sources. Every channel has to be readable twice.
+func GetDiscoveryFromChannels(channels *common.ResourceChannels,
+	dsQuery *dataselect.DataSelectQuery) (*Discovery, error) {
+	numErrs := 2
+	errChan := make(chan error, numErrs)
+	svcChan := make(chan *service.ServiceList, 1)
+	ingressChan := make(chan *ingress.IngressList, 1)
+
+	go func() {
+		items, err := service.GetServiceListFromChannels(channels, dsQuery)
+		errChan <- err
+		svcChan <- items
+	}()
+
+	go func() {
+		items, err := ingress.GetIngressListFromChannels(channels, dsQuery)
+		errChan <- err
+		ingressChan <- items
+	}()
+
+	for i := 0; i < numErrs; i++ {
+		err := <-errChan
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	discovery := &Discovery{
+		ServiceList: *(<-svcChan),
+		IngressList: *(<-ingressChan),
+	}
+
+	// Errors were already handled while reading the lists above.
+	services := <-channels.ServiceList.List
+	<-channels.ServiceList.Error
+	ingresses := <-channels.IngressList.List
+	<-channels.IngressList.Error
+
+	discovery.Status = map[api.ResourceKind]common.ResourceStatus{
+		api.ResourceKindService: getServiceStatus(services),
+		api.ResourceKindIngress: getIngressStatus(ingresses),
+	}
+
+	discovery.Errors = errors.MergeErrors(discovery.ServiceList.Errors, discovery.IngressList.Errors)
+
+	return discovery, nil
+}
+
+// getServiceStatus counts load balancer services that still wait for an external address as pending and all
+// other services as running.
+func getServiceStatus(list *v1.ServiceList) common.ResourceStatus {
+	info := common.ResourceStatus{}
+	if list == nil {
+		return info
+	}
+
+	for _, svc := range list.Items {
+		if svc.Spec.Type == v1.ServiceTypeLoadBalancer && len(svc.Status.LoadBalancer.Ingress) == 0 {
+			info.Pending++
+		} else {
+			info.Running++
+		}
+	}
+
+	return info
+}
+
+// getIngressStatus counts ingresses that were not assigned an address yet as pending and all other ingresses
+// as running.
+func getIngressStatus(list *extensions.IngressList) common.ResourceStatus {
+	info := common.ResourceStatus{}
+	if list == nil {
+		return info
+	}
+
+	for _, item := range list.Items {
+		if len(item.Status.LoadBalancer.Ingress) == 0 {
+			info.Pending++
+		} else {
+			info.Running++
+		}
+	}
+
+	return info
+}
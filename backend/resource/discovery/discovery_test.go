@@ -0,0 +1,66 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
+)
+
+func TestGetDiscovery(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Service{
+			ObjectMeta: metaV1.ObjectMeta{Name: "internal", Namespace: "default"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeClusterIP},
+		},
+		&v1.Service{
+			ObjectMeta: metaV1.ObjectMeta{Name: "public", Namespace: "default"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+		},
+		&extensions.Ingress{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default"},
+			Status: extensions.IngressStatus{LoadBalancer: v1.LoadBalancerStatus{
+				Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4"}},
+			}},
+		},
+	)
+
+	discovery, err := GetDiscovery(client, common.NewSameNamespaceQuery("default"), dataselect.NoDataSelect)
+	if err != nil {
+		t.Fatalf("GetDiscovery() returned error: %s", err)
+	}
+
+	if len(discovery.ServiceList.Services) != 2 || len(discovery.IngressList.Items) != 1 {
+		t.Errorf("GetDiscovery() returned %d services and %d ingresses, expected 2 and 1",
+			len(discovery.ServiceList.Services), len(discovery.IngressList.Items))
+	}
+
+	expected := map[api.ResourceKind]common.ResourceStatus{
+		api.ResourceKindService: {Running: 1, Pending: 1},
+		api.ResourceKindIngress: {Running: 1},
+	}
+	if !reflect.DeepEqual(discovery.Status, expected) {
+		t.Errorf("GetDiscovery() status == %+v, expected %+v", discovery.Status, expected)
+	}
+}
@@ -18,7 +18,7 @@ import (
 	"context"
 	"log"
 
-	autoscaling "k8s.io/api/autoscaling/v1"
+	autoscaling "k8s.io/api/autoscaling/v2beta2"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	client "k8s.io/client-go/kubernetes"
 )
@@ -31,13 +31,19 @@ type HorizontalPodAutoscalerDetail struct {
 	CurrentReplicas int32    `json:"currentReplicas"`
 	DesiredReplicas int32    `json:"desiredReplicas"`
 	LastScaleTime   *v1.Time `json:"lastScaleTime"`
+
+	// Metrics are the metrics the autoscaler scales on, with their targets and current values.
+	Metrics []Metric `json:"metrics"`
+
+	// Behavior contains the scale up and scale down policies, nil if the defaults are used.
+	Behavior *autoscaling.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
 }
 
 // GetHorizontalPodAutoscalerDetail returns detailed information about a horizontal pod autoscaler
 func GetHorizontalPodAutoscalerDetail(client client.Interface, namespace string, name string) (*HorizontalPodAutoscalerDetail, error) {
 	log.Printf("Getting details of %s horizontal pod autoscaler", name)
 
-	rawHorizontalPodAutoscaler, err := client.AutoscalingV1().HorizontalPodAutoscalers(namespace).Get(context.TODO(), name, v1.GetOptions{})
+	rawHorizontalPodAutoscaler, err := client.AutoscalingV2beta2().HorizontalPodAutoscalers(namespace).Get(context.TODO(), name, v1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -47,9 +53,11 @@ func GetHorizontalPodAutoscalerDetail(client client.Interface, namespace string,
 
 func getHorizontalPodAutoscalerDetail(hpa *autoscaling.HorizontalPodAutoscaler) *HorizontalPodAutoscalerDetail {
 	return &HorizontalPodAutoscalerDetail{
-		HorizontalPodAutoscaler: toHorizontalPodAutoScaler(hpa),
+		HorizontalPodAutoscaler: toHorizontalPodAutoscalerFromV2(hpa),
 		CurrentReplicas:         hpa.Status.CurrentReplicas,
 		DesiredReplicas:         hpa.Status.DesiredReplicas,
 		LastScaleTime:           hpa.Status.LastScaleTime,
+		Metrics:                 getMetrics(hpa.Spec.Metrics, hpa.Status.CurrentMetrics),
+		Behavior:                hpa.Spec.Behavior,
 	}
 }
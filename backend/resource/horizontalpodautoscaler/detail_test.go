@@ -19,7 +19,9 @@ import (
 	"testing"
 
 	"github.com/ycyxuehan/dashboard-gin/backend/api"
-	autoscaling "k8s.io/api/autoscaling/v1"
+	autoscaling "k8s.io/api/autoscaling/v2beta2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 )
@@ -27,6 +29,18 @@ import (
 // func GetHorizontalPodAutoscalerDetail(client *client.Client, namespace string, name string) (*HorizontalPodAutoscalerDetail, error)
 
 func TestGetHorizontalPodAutoscalerDetail(t *testing.T) {
+	targetUtilization, currentUtilization := int32(60), int32(75)
+	queueTarget := resource.MustParse("30")
+	window := int32(300)
+	behavior := &autoscaling.HorizontalPodAutoscalerBehavior{
+		ScaleDown: &autoscaling.HPAScalingRules{
+			StabilizationWindowSeconds: &window,
+			Policies: []autoscaling.HPAScalingPolicy{
+				{Type: autoscaling.PercentScalingPolicy, Value: 10, PeriodSeconds: 60},
+			},
+		},
+	}
+
 	cases := []struct {
 		namespace, name string
 		expectedActions []string
@@ -62,6 +76,80 @@ func TestGetHorizontalPodAutoscalerDetail(t *testing.T) {
 				},
 				CurrentReplicas: 1,
 				DesiredReplicas: 2,
+				Metrics:         []Metric{},
+			},
+		},
+		{
+			"test-ns", "test-name",
+			[]string{"get"},
+			&autoscaling.HorizontalPodAutoscaler{
+				ObjectMeta: metaV1.ObjectMeta{Name: "test-name", Namespace: "test-ns"},
+				Spec: autoscaling.HorizontalPodAutoscalerSpec{
+					ScaleTargetRef: autoscaling.CrossVersionObjectReference{Kind: "Deployment", Name: "web"},
+					MaxReplicas:    5,
+					Metrics: []autoscaling.MetricSpec{
+						{
+							Type: autoscaling.ResourceMetricSourceType,
+							Resource: &autoscaling.ResourceMetricSource{
+								Name: v1.ResourceCPU,
+								Target: autoscaling.MetricTarget{
+									Type:               autoscaling.UtilizationMetricType,
+									AverageUtilization: &targetUtilization,
+								},
+							},
+						},
+						{
+							Type: autoscaling.ExternalMetricSourceType,
+							External: &autoscaling.ExternalMetricSource{
+								Metric: autoscaling.MetricIdentifier{Name: "queue_length"},
+								Target: autoscaling.MetricTarget{
+									Type:  autoscaling.ValueMetricType,
+									Value: &queueTarget,
+								},
+							},
+						},
+					},
+					Behavior: behavior,
+				},
+				Status: autoscaling.HorizontalPodAutoscalerStatus{
+					CurrentMetrics: []autoscaling.MetricStatus{{
+						Type: autoscaling.ResourceMetricSourceType,
+						Resource: &autoscaling.ResourceMetricStatus{
+							Name:    v1.ResourceCPU,
+							Current: autoscaling.MetricValueStatus{AverageUtilization: &currentUtilization},
+						},
+					}},
+				},
+			},
+			&HorizontalPodAutoscalerDetail{
+				HorizontalPodAutoscaler: HorizontalPodAutoscaler{
+					ObjectMeta:                      api.ObjectMeta{Name: "test-name", Namespace: "test-ns"},
+					TypeMeta:                        api.TypeMeta{Kind: api.ResourceKindHorizontalPodAutoscaler},
+					ScaleTargetRef:                  ScaleTargetRef{Kind: "Deployment", Name: "web"},
+					MaxReplicas:                     5,
+					TargetCPUUtilizationPercentage:  &targetUtilization,
+					CurrentCPUUtilizationPercentage: &currentUtilization,
+				},
+				Metrics: []Metric{
+					{
+						Type: autoscaling.ResourceMetricSourceType,
+						Name: "cpu",
+						Target: autoscaling.MetricTarget{
+							Type:               autoscaling.UtilizationMetricType,
+							AverageUtilization: &targetUtilization,
+						},
+						Current: &autoscaling.MetricValueStatus{AverageUtilization: &currentUtilization},
+					},
+					{
+						Type: autoscaling.ExternalMetricSourceType,
+						Name: "queue_length",
+						Target: autoscaling.MetricTarget{
+							Type:  autoscaling.ValueMetricType,
+							Value: &queueTarget,
+						},
+					},
+				},
+				Behavior: behavior,
 			},
 		},
 	}
@@ -0,0 +1,249 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package horizontalpodautoscaler
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	autoscaling "k8s.io/api/autoscaling/v2beta2"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/scaling"
+)
+
+const (
+	// defaultScaleTargetAPIVersion is used if the scale target reference does not set an API version.
+	defaultScaleTargetAPIVersion = "apps/v1"
+
+	// maxPolicyPeriodSeconds and maxStabilizationWindowSeconds are the limits enforced by the API server.
+	maxPolicyPeriodSeconds        = 1800
+	maxStabilizationWindowSeconds = 3600
+)
+
+// checkScaleTarget is replaced in tests, as reading the scale subresource needs a real API server.
+var checkScaleTarget = scaling.CheckScaleTarget
+
+// HorizontalPodAutoscalerSpec is a specification of a horizontal pod autoscaler to create or update. Metrics and
+// behavior use the autoscaling/v2beta2 API.
+type HorizontalPodAutoscalerSpec struct {
+	// Name and Namespace are only used on creation.
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+
+	// Labels replace the labels of the autoscaler if set.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	ScaleTargetRef autoscaling.CrossVersionObjectReference `json:"scaleTargetRef"`
+	MinReplicas    *int32                                  `json:"minReplicas,omitempty"`
+	MaxReplicas    int32                                   `json:"maxReplicas"`
+
+	Metrics  []autoscaling.MetricSpec                     `json:"metrics,omitempty"`
+	Behavior *autoscaling.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
+}
+
+// CreateHorizontalPodAutoscaler creates an autoscaler based on given specification, after checking that its
+// scale target exists and can be scaled.
+func CreateHorizontalPodAutoscaler(client client.Interface, cfg *rest.Config,
+	spec *HorizontalPodAutoscalerSpec) (*HorizontalPodAutoscalerDetail, error) {
+	log.Printf("Creating horizontal pod autoscaler %s in %s namespace", spec.Name, spec.Namespace)
+
+	if len(spec.Name) == 0 {
+		return nil, errors.NewInvalid("Name of the horizontal pod autoscaler is required")
+	}
+	if err := validateSpec(cfg, spec.Namespace, spec); err != nil {
+		return nil, err
+	}
+
+	hpa := &autoscaling.HorizontalPodAutoscaler{
+		ObjectMeta: metaV1.ObjectMeta{Name: spec.Name, Namespace: spec.Namespace, Labels: spec.Labels},
+	}
+	setSpec(hpa, spec)
+
+	created, err := client.AutoscalingV2beta2().HorizontalPodAutoscalers(spec.Namespace).Create(context.TODO(), hpa,
+		metaV1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return getHorizontalPodAutoscalerDetail(created), nil
+}
+
+// UpdateHorizontalPodAutoscaler replaces scale target, replica limits, metrics and behavior of the autoscaler
+// with the given name.
+func UpdateHorizontalPodAutoscaler(client client.Interface, cfg *rest.Config, namespace, name string,
+	spec *HorizontalPodAutoscalerSpec) (*HorizontalPodAutoscalerDetail, error) {
+	log.Printf("Updating horizontal pod autoscaler %s in %s namespace", name, namespace)
+
+	if err := validateSpec(cfg, namespace, spec); err != nil {
+		return nil, err
+	}
+
+	hpa, err := client.AutoscalingV2beta2().HorizontalPodAutoscalers(namespace).Get(context.TODO(), name,
+		metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.Labels != nil {
+		hpa.Labels = spec.Labels
+	}
+	setSpec(hpa, spec)
+
+	updated, err := client.AutoscalingV2beta2().HorizontalPodAutoscalers(namespace).Update(context.TODO(), hpa,
+		metaV1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return getHorizontalPodAutoscalerDetail(updated), nil
+}
+
+// DeleteHorizontalPodAutoscaler deletes the autoscaler with the given name. The replicas of its scale target are
+// left as they are.
+func DeleteHorizontalPodAutoscaler(client client.Interface, namespace, name string) error {
+	log.Printf("Deleting horizontal pod autoscaler %s in %s namespace", name, namespace)
+	return client.AutoscalingV2beta2().HorizontalPodAutoscalers(namespace).Delete(context.TODO(), name,
+		metaV1.DeleteOptions{})
+}
+
+func setSpec(hpa *autoscaling.HorizontalPodAutoscaler, spec *HorizontalPodAutoscalerSpec) {
+	hpa.Spec.ScaleTargetRef = spec.ScaleTargetRef
+	hpa.Spec.MinReplicas = spec.MinReplicas
+	hpa.Spec.MaxReplicas = spec.MaxReplicas
+	hpa.Spec.Metrics = spec.Metrics
+	hpa.Spec.Behavior = spec.Behavior
+}
+
+// validateSpec checks the spec before it is sent to the API server, so that users get a precise error message,
+// and checks that the scale target can be scaled. A missing API version of the scale target is defaulted.
+func validateSpec(cfg *rest.Config, namespace string, spec *HorizontalPodAutoscalerSpec) error {
+	target := &spec.ScaleTargetRef
+	if len(target.Kind) == 0 || len(target.Name) == 0 {
+		return errors.NewInvalid("Kind and name of the scale target are required")
+	}
+	if len(target.APIVersion) == 0 {
+		target.APIVersion = defaultScaleTargetAPIVersion
+	}
+
+	if spec.MaxReplicas < 1 {
+		return errors.NewInvalid("Max replicas must be at least 1")
+	}
+	if spec.MinReplicas != nil && (*spec.MinReplicas < 1 || *spec.MinReplicas > spec.MaxReplicas) {
+		return errors.NewInvalid("Min replicas must be between 1 and max replicas")
+	}
+
+	for i, metric := range spec.Metrics {
+		if err := validateMetric(metric); err != nil {
+			return errors.NewInvalid(fmt.Sprintf("Metric %d: %s", i, err))
+		}
+	}
+
+	if spec.Behavior != nil {
+		if err := validateScalingRules(spec.Behavior.ScaleUp); err != nil {
+			return errors.NewInvalid(fmt.Sprintf("Scale up behavior: %s", err))
+		}
+		if err := validateScalingRules(spec.Behavior.ScaleDown); err != nil {
+			return errors.NewInvalid(fmt.Sprintf("Scale down behavior: %s", err))
+		}
+	}
+
+	err := checkScaleTarget(rest.CopyConfig(cfg), target.APIVersion, target.Kind, namespace, target.Name)
+	if errors.IsNotFoundError(err) {
+		return errors.NewInvalid(fmt.Sprintf("Scale target %s %s does not exist or cannot be scaled",
+			target.Kind, target.Name))
+	}
+	return err
+}
+
+func validateMetric(metric autoscaling.MetricSpec) error {
+	var target autoscaling.MetricTarget
+	switch {
+	case metric.Type == autoscaling.ResourceMetricSourceType && metric.Resource != nil:
+		target = metric.Resource.Target
+	case metric.Type == autoscaling.PodsMetricSourceType && metric.Pods != nil:
+		target = metric.Pods.Target
+	case metric.Type == autoscaling.ObjectMetricSourceType && metric.Object != nil:
+		if len(metric.Object.DescribedObject.Kind) == 0 || len(metric.Object.DescribedObject.Name) == 0 {
+			return fmt.Errorf("kind and name of the described object are required")
+		}
+		target = metric.Object.Target
+	case metric.Type == autoscaling.ExternalMetricSourceType && metric.External != nil:
+		target = metric.External.Target
+	default:
+		return fmt.Errorf("type %q must be Resource, Pods, Object or External with a matching source",
+			metric.Type)
+	}
+
+	switch target.Type {
+	case autoscaling.UtilizationMetricType:
+		if metric.Type != autoscaling.ResourceMetricSourceType {
+			return fmt.Errorf("utilization targets are only supported by resource metrics")
+		}
+		if target.AverageUtilization == nil || *target.AverageUtilization < 1 {
+			return fmt.Errorf("utilization target requires a positive average utilization")
+		}
+	case autoscaling.ValueMetricType:
+		if metric.Type == autoscaling.ResourceMetricSourceType || metric.Type == autoscaling.PodsMetricSourceType {
+			return fmt.Errorf("value targets are only supported by object and external metrics")
+		}
+		if target.Value == nil || target.Value.Sign() <= 0 {
+			return fmt.Errorf("value target requires a positive value")
+		}
+	case autoscaling.AverageValueMetricType:
+		if target.AverageValue == nil || target.AverageValue.Sign() <= 0 {
+			return fmt.Errorf("average value target requires a positive average value")
+		}
+	default:
+		return fmt.Errorf("target type %q must be Utilization, Value or AverageValue", target.Type)
+	}
+
+	return nil
+}
+
+func validateScalingRules(rules *autoscaling.HPAScalingRules) error {
+	if rules == nil {
+		return nil
+	}
+
+	if window := rules.StabilizationWindowSeconds; window != nil &&
+		(*window < 0 || *window > maxStabilizationWindowSeconds) {
+		return fmt.Errorf("stabilization window must be between 0 and %d seconds", maxStabilizationWindowSeconds)
+	}
+
+	if rules.SelectPolicy != nil {
+		switch *rules.SelectPolicy {
+		case autoscaling.MaxPolicySelect, autoscaling.MinPolicySelect, autoscaling.DisabledPolicySelect:
+		default:
+			return fmt.Errorf("unsupported select policy: %s", *rules.SelectPolicy)
+		}
+	}
+
+	for _, policy := range rules.Policies {
+		if policy.Type != autoscaling.PodsScalingPolicy && policy.Type != autoscaling.PercentScalingPolicy {
+			return fmt.Errorf("unsupported scaling policy type: %s", policy.Type)
+		}
+		if policy.Value < 1 {
+			return fmt.Errorf("scaling policy value must be positive")
+		}
+		if policy.PeriodSeconds < 1 || policy.PeriodSeconds > maxPolicyPeriodSeconds {
+			return fmt.Errorf("scaling policy period must be between 1 and %d seconds", maxPolicyPeriodSeconds)
+		}
+	}
+
+	return nil
+}
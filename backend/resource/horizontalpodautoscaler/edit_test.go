@@ -0,0 +1,228 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package horizontalpodautoscaler
+
+import (
+	"context"
+	"testing"
+
+	autoscaling "k8s.io/api/autoscaling/v2beta2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+)
+
+func int32Pointer(value int32) *int32 {
+	return &value
+}
+
+func stubScaleTarget(t *testing.T, existing string) func() {
+	original := checkScaleTarget
+	checkScaleTarget = func(cfg *rest.Config, apiVersion, kind, namespace, name string) error {
+		if apiVersion != "apps/v1" {
+			t.Errorf("checkScaleTarget() called with API version %s, expected apps/v1", apiVersion)
+		}
+		if name != existing {
+			return errors.NewNotFound(name)
+		}
+		return nil
+	}
+	return func() { checkScaleTarget = original }
+}
+
+func newCPUMetric(utilization int32) autoscaling.MetricSpec {
+	return autoscaling.MetricSpec{
+		Type: autoscaling.ResourceMetricSourceType,
+		Resource: &autoscaling.ResourceMetricSource{
+			Name: v1.ResourceCPU,
+			Target: autoscaling.MetricTarget{
+				Type:               autoscaling.UtilizationMetricType,
+				AverageUtilization: &utilization,
+			},
+		},
+	}
+}
+
+func TestCreateAndUpdateHorizontalPodAutoscaler(t *testing.T) {
+	defer stubScaleTarget(t, "web")()
+	client := fake.NewSimpleClientset()
+
+	spec := &HorizontalPodAutoscalerSpec{
+		Name:           "web",
+		Namespace:      "default",
+		ScaleTargetRef: autoscaling.CrossVersionObjectReference{Kind: "Deployment", Name: "web"},
+		MinReplicas:    int32Pointer(2),
+		MaxReplicas:    10,
+		Metrics:        []autoscaling.MetricSpec{newCPUMetric(60)},
+	}
+	created, err := CreateHorizontalPodAutoscaler(client, &rest.Config{}, spec)
+	if err != nil {
+		t.Fatalf("CreateHorizontalPodAutoscaler() returned error: %s", err)
+	}
+	if created.TargetCPUUtilizationPercentage == nil || *created.TargetCPUUtilizationPercentage != 60 {
+		t.Errorf("CreateHorizontalPodAutoscaler() == %#v, expected CPU target of 60", created)
+	}
+
+	queueTarget := resource.MustParse("100")
+	spec.MaxReplicas = 20
+	spec.Labels = map[string]string{"team": "web"}
+	spec.Metrics = append(spec.Metrics, autoscaling.MetricSpec{
+		Type: autoscaling.PodsMetricSourceType,
+		Pods: &autoscaling.PodsMetricSource{
+			Metric: autoscaling.MetricIdentifier{Name: "requests_per_second"},
+			Target: autoscaling.MetricTarget{Type: autoscaling.AverageValueMetricType, AverageValue: &queueTarget},
+		},
+	})
+	spec.Behavior = &autoscaling.HorizontalPodAutoscalerBehavior{
+		ScaleUp: &autoscaling.HPAScalingRules{
+			Policies: []autoscaling.HPAScalingPolicy{{Type: autoscaling.PodsScalingPolicy, Value: 4, PeriodSeconds: 60}},
+		},
+	}
+	if _, err := UpdateHorizontalPodAutoscaler(client, &rest.Config{}, "default", "web", spec); err != nil {
+		t.Fatalf("UpdateHorizontalPodAutoscaler() returned error: %s", err)
+	}
+
+	hpa, err := client.AutoscalingV2beta2().HorizontalPodAutoscalers("default").Get(context.TODO(), "web",
+		metaV1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get() returned error: %s", err)
+	}
+	if hpa.Spec.MaxReplicas != 20 || len(hpa.Spec.Metrics) != 2 || hpa.Spec.Behavior == nil ||
+		hpa.Labels["team"] != "web" {
+		t.Errorf("UpdateHorizontalPodAutoscaler() stored %#v", hpa)
+	}
+
+	if err := DeleteHorizontalPodAutoscaler(client, "default", "web"); err != nil {
+		t.Fatalf("DeleteHorizontalPodAutoscaler() returned error: %s", err)
+	}
+	_, err = client.AutoscalingV2beta2().HorizontalPodAutoscalers("default").Get(context.TODO(), "web",
+		metaV1.GetOptions{})
+	if !errors.IsNotFoundError(err) {
+		t.Errorf("DeleteHorizontalPodAutoscaler() should delete the autoscaler, got %v", err)
+	}
+}
+
+func TestCreateHorizontalPodAutoscalerShouldValidateSpec(t *testing.T) {
+	defer stubScaleTarget(t, "web")()
+
+	value := resource.MustParse("10")
+	target := autoscaling.CrossVersionObjectReference{Kind: "Deployment", Name: "web"}
+	cases := []*HorizontalPodAutoscalerSpec{
+		{ScaleTargetRef: target, MaxReplicas: 1},
+		{Name: "web", MaxReplicas: 1},
+		{Name: "web", ScaleTargetRef: target},
+		{Name: "web", ScaleTargetRef: target, MaxReplicas: 2, MinReplicas: int32Pointer(3)},
+		{Name: "web", ScaleTargetRef: autoscaling.CrossVersionObjectReference{Kind: "Deployment", Name: "other"},
+			MaxReplicas: 1},
+		{Name: "web", ScaleTargetRef: target, MaxReplicas: 1, Metrics: []autoscaling.MetricSpec{newCPUMetric(0)}},
+		{Name: "web", ScaleTargetRef: target, MaxReplicas: 1, Metrics: []autoscaling.MetricSpec{
+			{Type: autoscaling.ExternalMetricSourceType},
+		}},
+		{Name: "web", ScaleTargetRef: target, MaxReplicas: 1, Metrics: []autoscaling.MetricSpec{{
+			Type: autoscaling.PodsMetricSourceType,
+			Pods: &autoscaling.PodsMetricSource{
+				Metric: autoscaling.MetricIdentifier{Name: "requests"},
+				Target: autoscaling.MetricTarget{Type: autoscaling.ValueMetricType, Value: &value},
+			},
+		}}},
+		{Name: "web", ScaleTargetRef: target, MaxReplicas: 1, Behavior: &autoscaling.HorizontalPodAutoscalerBehavior{
+			ScaleDown: &autoscaling.HPAScalingRules{
+				Policies: []autoscaling.HPAScalingPolicy{{Type: autoscaling.PercentScalingPolicy, Value: 10}},
+			},
+		}},
+	}
+
+	for _, c := range cases {
+		client := fake.NewSimpleClientset()
+		_, err := CreateHorizontalPodAutoscaler(client, &rest.Config{}, c)
+		if err == nil {
+			t.Errorf("CreateHorizontalPodAutoscaler(%#v) == nil, expected error", c)
+		}
+		if len(client.Actions()) > 0 {
+			t.Errorf("CreateHorizontalPodAutoscaler(%#v) should not create invalid autoscalers", c)
+		}
+	}
+}
+
+func TestGetMetricsShouldMatchObjectMetricsByObject(t *testing.T) {
+	value, current := resource.MustParse("10"), resource.MustParse("7")
+	newObjectMetric := func(name string) autoscaling.MetricSpec {
+		return autoscaling.MetricSpec{
+			Type: autoscaling.ObjectMetricSourceType,
+			Object: &autoscaling.ObjectMetricSource{
+				DescribedObject: autoscaling.CrossVersionObjectReference{Kind: "Ingress", Name: name},
+				Metric:          autoscaling.MetricIdentifier{Name: "requests"},
+				Target:          autoscaling.MetricTarget{Type: autoscaling.ValueMetricType, Value: &value},
+			},
+		}
+	}
+
+	metrics := getMetrics(
+		[]autoscaling.MetricSpec{newObjectMetric("main"), newObjectMetric("canary")},
+		[]autoscaling.MetricStatus{{
+			Type: autoscaling.ObjectMetricSourceType,
+			Object: &autoscaling.ObjectMetricStatus{
+				DescribedObject: autoscaling.CrossVersionObjectReference{Kind: "Ingress", Name: "canary"},
+				Metric:          autoscaling.MetricIdentifier{Name: "requests"},
+				Current:         autoscaling.MetricValueStatus{Value: &current},
+			},
+		}},
+	)
+
+	if metrics[0].Current != nil || metrics[1].Current == nil || metrics[1].Current.Value.Cmp(current) != 0 {
+		t.Errorf("getMetrics() == %#v, expected current value only for the canary ingress", metrics)
+	}
+}
+
+func TestGetMetricsShouldMatchMetricsBySelector(t *testing.T) {
+	value := resource.MustParse("10")
+	queue := func(name string) *metaV1.LabelSelector {
+		return &metaV1.LabelSelector{MatchLabels: map[string]string{"queue": name}}
+	}
+	newExternalMetric := func(selector *metaV1.LabelSelector) autoscaling.MetricSpec {
+		return autoscaling.MetricSpec{
+			Type: autoscaling.ExternalMetricSourceType,
+			External: &autoscaling.ExternalMetricSource{
+				Metric: autoscaling.MetricIdentifier{Name: "messages", Selector: selector},
+				Target: autoscaling.MetricTarget{Type: autoscaling.ValueMetricType, Value: &value},
+			},
+		}
+	}
+	newExternalStatus := func(selector *metaV1.LabelSelector, current resource.Quantity) autoscaling.MetricStatus {
+		return autoscaling.MetricStatus{
+			Type: autoscaling.ExternalMetricSourceType,
+			External: &autoscaling.ExternalMetricStatus{
+				Metric:  autoscaling.MetricIdentifier{Name: "messages", Selector: selector},
+				Current: autoscaling.MetricValueStatus{Value: &current},
+			},
+		}
+	}
+
+	orders, payments := resource.MustParse("3"), resource.MustParse("8")
+	metrics := getMetrics(
+		[]autoscaling.MetricSpec{newExternalMetric(queue("orders")), newExternalMetric(queue("payments"))},
+		[]autoscaling.MetricStatus{newExternalStatus(queue("orders"), orders),
+			newExternalStatus(queue("payments"), payments)},
+	)
+
+	if metrics[0].Current == nil || metrics[0].Current.Value.Cmp(orders) != 0 ||
+		metrics[1].Current == nil || metrics[1].Current.Value.Cmp(payments) != 0 {
+		t.Errorf("getMetrics() == %#v, expected current values matched by selector", metrics)
+	}
+}
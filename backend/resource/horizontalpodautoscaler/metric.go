@@ -0,0 +1,141 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package horizontalpodautoscaler
+
+import (
+	autoscaling "k8s.io/api/autoscaling/v2beta2"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+)
+
+// Metric is a metric an autoscaler scales on, together with its current value.
+type Metric struct {
+	Type autoscaling.MetricSourceType `json:"type"`
+
+	// Name is the resource name of resource metrics and the metric name of all other metrics.
+	Name string `json:"name"`
+
+	// Selector narrows down the metric series of pods, object and external metrics.
+	Selector *metaV1.LabelSelector `json:"selector,omitempty"`
+
+	// DescribedObject is the object an object metric describes.
+	DescribedObject *autoscaling.CrossVersionObjectReference `json:"describedObject,omitempty"`
+
+	Target autoscaling.MetricTarget `json:"target"`
+
+	// Current is nil if the autoscaler did not read the metric yet.
+	Current *autoscaling.MetricValueStatus `json:"current,omitempty"`
+}
+
+// getMetrics pairs the metrics in the spec of an autoscaler with their current values from the status.
+func getMetrics(specs []autoscaling.MetricSpec, statuses []autoscaling.MetricStatus) []Metric {
+	current := make(map[string]autoscaling.MetricValueStatus)
+	for _, status := range statuses {
+		if key, value, ok := getMetricStatusValue(status); ok {
+			current[key] = value
+		}
+	}
+
+	result := make([]Metric, 0)
+	for _, spec := range specs {
+		metric := Metric{Type: spec.Type}
+		switch {
+		case spec.Type == autoscaling.ResourceMetricSourceType && spec.Resource != nil:
+			metric.Name = string(spec.Resource.Name)
+			metric.Target = spec.Resource.Target
+		case spec.Type == autoscaling.PodsMetricSourceType && spec.Pods != nil:
+			metric.Name = spec.Pods.Metric.Name
+			metric.Selector = spec.Pods.Metric.Selector
+			metric.Target = spec.Pods.Target
+		case spec.Type == autoscaling.ObjectMetricSourceType && spec.Object != nil:
+			object := spec.Object.DescribedObject
+			metric.Name = spec.Object.Metric.Name
+			metric.Selector = spec.Object.Metric.Selector
+			metric.DescribedObject = &object
+			metric.Target = spec.Object.Target
+		case spec.Type == autoscaling.ExternalMetricSourceType && spec.External != nil:
+			metric.Name = spec.External.Metric.Name
+			metric.Selector = spec.External.Metric.Selector
+			metric.Target = spec.External.Target
+		}
+
+		if value, ok := current[getMetricKey(metric.Type, metric.Name, metric.Selector, metric.DescribedObject)]; ok {
+			metric.Current = &value
+		}
+		result = append(result, metric)
+	}
+
+	return result
+}
+
+func getMetricStatusValue(status autoscaling.MetricStatus) (string, autoscaling.MetricValueStatus, bool) {
+	switch {
+	case status.Type == autoscaling.ResourceMetricSourceType && status.Resource != nil:
+		return getMetricKey(status.Type, string(status.Resource.Name), nil, nil), status.Resource.Current, true
+	case status.Type == autoscaling.PodsMetricSourceType && status.Pods != nil:
+		return getMetricKey(status.Type, status.Pods.Metric.Name, status.Pods.Metric.Selector, nil),
+			status.Pods.Current, true
+	case status.Type == autoscaling.ObjectMetricSourceType && status.Object != nil:
+		return getMetricKey(status.Type, status.Object.Metric.Name, status.Object.Metric.Selector,
+			&status.Object.DescribedObject), status.Object.Current, true
+	case status.Type == autoscaling.ExternalMetricSourceType && status.External != nil:
+		return getMetricKey(status.Type, status.External.Metric.Name, status.External.Metric.Selector, nil),
+			status.External.Current, true
+	}
+	return "", autoscaling.MetricValueStatus{}, false
+}
+
+// getMetricKey identifies a metric by its type, name, selector and the described object, so that metrics with the same
+// name but different series or objects are not mixed up.
+func getMetricKey(metricType autoscaling.MetricSourceType, name string, selector *metaV1.LabelSelector,
+	object *autoscaling.CrossVersionObjectReference) string {
+	key := string(metricType) + "/" + name
+	if selector != nil {
+		key += "/" + metaV1.FormatLabelSelector(selector)
+	}
+	if object != nil {
+		key += "/" + object.Kind + "/" + object.Name
+	}
+	return key
+}
+
+// toHorizontalPodAutoscalerFromV2 converts an autoscaling/v2beta2 autoscaler to the list view. CPU utilization
+// is taken from the CPU resource metric, if the autoscaler has one.
+func toHorizontalPodAutoscalerFromV2(hpa *autoscaling.HorizontalPodAutoscaler) HorizontalPodAutoscaler {
+	result := HorizontalPodAutoscaler{
+		ObjectMeta: api.NewObjectMeta(hpa.ObjectMeta),
+		TypeMeta:   api.NewTypeMeta(api.ResourceKindHorizontalPodAutoscaler),
+		ScaleTargetRef: ScaleTargetRef{
+			Kind: hpa.Spec.ScaleTargetRef.Kind,
+			Name: hpa.Spec.ScaleTargetRef.Name,
+		},
+		MinReplicas: hpa.Spec.MinReplicas,
+		MaxReplicas: hpa.Spec.MaxReplicas,
+	}
+
+	for _, metric := range getMetrics(hpa.Spec.Metrics, hpa.Status.CurrentMetrics) {
+		if metric.Type != autoscaling.ResourceMetricSourceType || metric.Name != string(v1.ResourceCPU) {
+			continue
+		}
+		result.TargetCPUUtilizationPercentage = metric.Target.AverageUtilization
+		if metric.Current != nil {
+			result.CurrentCPUUtilizationPercentage = metric.Current.AverageUtilization
+		}
+	}
+
+	return result
+}
@@ -58,6 +58,19 @@ func GetIngressList(client client.Interface, namespace *common.NamespaceQuery,
 	return toIngressList(ingressList.Items, nonCriticalErrors, dsQuery), nil
 }
 
+// GetIngressListFromChannels returns a list of all ingresses in the cluster reading required resource list once
+// from the channels.
+func GetIngressListFromChannels(channels *common.ResourceChannels, dsQuery *dataselect.DataSelectQuery) (*IngressList, error) {
+	ingresses := <-channels.IngressList.List
+	err := <-channels.IngressList.Error
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	return toIngressList(ingresses.Items, nonCriticalErrors, dsQuery), nil
+}
+
 func getEndpoints(ingress *extensions.Ingress) []common.Endpoint {
 	endpoints := make([]common.Endpoint, 0)
 	if len(ingress.Status.LoadBalancer.Ingress) > 0 {
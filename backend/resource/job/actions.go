@@ -0,0 +1,167 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+)
+
+// generatedLabels are set by the job controller on jobs with a generated selector. They have to be removed before
+// a job can be copied, as they are bound to the UID of the original job.
+var generatedLabels = []string{"controller-uid", "job-name"}
+
+// now is replaced in tests to get predictable job ages.
+var now = time.Now
+
+// JobCleanup lists the finished jobs that were deleted by a cleanup.
+type JobCleanup struct {
+	DryRun  bool     `json:"dryRun"`
+	Deleted []string `json:"deleted"`
+
+	// List of non-critical errors, that occurred during deletion.
+	Errors []error `json:"errors"`
+}
+
+// DeleteFinishedJobs deletes all complete and failed jobs in the namespaces that finished more than olderThan ago.
+// Pods of the jobs are deleted in the background. The age has to be positive, so that jobs that have just finished
+// are never deleted by accident.
+func DeleteFinishedJobs(client client.Interface, nsQuery *common.NamespaceQuery, olderThan time.Duration,
+	dryRun bool) (*JobCleanup, error) {
+	log.Printf("Deleting jobs in %s namespace finished more than %s ago, dry run: %t", nsQuery.ToRequestParam(),
+		olderThan, dryRun)
+
+	if olderThan <= 0 {
+		return nil, errors.NewInvalid("Job age must be positive")
+	}
+
+	jobs, err := client.BatchV1().Jobs(nsQuery.ToRequestParam()).List(context.TODO(), api.ListEverything)
+	if err != nil {
+		return nil, err
+	}
+
+	propagationPolicy := metaV1.DeletePropagationBackground
+	options := metaV1.DeleteOptions{PropagationPolicy: &propagationPolicy}
+	if dryRun {
+		options.DryRun = []string{metaV1.DryRunAll}
+	}
+
+	result := &JobCleanup{DryRun: dryRun, Deleted: make([]string, 0), Errors: make([]error, 0)}
+	deadline := now().Add(-olderThan)
+	for _, item := range jobs.Items {
+		finishTime := getJobFinishTime(&item)
+		if !nsQuery.Matches(item.Namespace) || finishTime == nil || finishTime.After(deadline) {
+			continue
+		}
+
+		err := client.BatchV1().Jobs(item.Namespace).Delete(context.TODO(), item.Name, options)
+		if errors.IsNotFoundError(err) {
+			// Deleted in the meantime, e.g. by the history limit of its cron job.
+			continue
+		}
+		if err != nil {
+			result.Errors, err = errors.AppendError(err, result.Errors)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		result.Deleted = append(result.Deleted, item.Name)
+	}
+
+	return result, nil
+}
+
+// getJobFinishTime returns the time the job completed or failed, or nil if it is still running.
+func getJobFinishTime(job *batch.Job) *time.Time {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != v1.ConditionTrue {
+			continue
+		}
+
+		if condition.Type == batch.JobComplete && job.Status.CompletionTime != nil {
+			return &job.Status.CompletionTime.Time
+		}
+		if condition.Type == batch.JobComplete || condition.Type == batch.JobFailed {
+			return &condition.LastTransitionTime.Time
+		}
+	}
+	return nil
+}
+
+// RerunJob creates a fresh copy of the failed job with the given name. The copy gets a new name and a new
+// selector generated by the job controller. The failed job is kept for inspection.
+func RerunJob(client client.Interface, namespace, name string) (*batch.Job, error) {
+	log.Printf("Rerunning job %s in %s namespace", name, namespace)
+
+	job, err := client.BatchV1().Jobs(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if getJobStatus(job).Status != JobStatusFailed {
+		return nil, errors.NewInvalid(fmt.Sprintf("Job %s has not failed and cannot be rerun", name))
+	}
+
+	// job name cannot exceed DNS1053LabelMaxLength (52 characters)
+	var newJobName string
+	if len(job.Name) < 42 {
+		newJobName = job.Name + "-rerun-" + rand.String(3)
+	} else {
+		newJobName = job.Name[0:41] + "-rerun-" + rand.String(3)
+	}
+
+	spec := *job.Spec.DeepCopy()
+	if spec.ManualSelector == nil || !*spec.ManualSelector {
+		spec.Selector = nil
+		spec.Template.Labels = withoutGeneratedLabels(spec.Template.Labels)
+	}
+
+	jobToCreate := &batch.Job{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:            newJobName,
+			Namespace:       namespace,
+			Labels:          withoutGeneratedLabels(job.Labels),
+			Annotations:     job.Annotations,
+			OwnerReferences: job.OwnerReferences,
+		},
+		Spec: spec,
+	}
+
+	return client.BatchV1().Jobs(namespace).Create(context.TODO(), jobToCreate, metaV1.CreateOptions{})
+}
+
+func withoutGeneratedLabels(labels map[string]string) map[string]string {
+	result := make(map[string]string)
+	for k, v := range labels {
+		result[k] = v
+	}
+	for _, label := range generatedLabels {
+		delete(result, label)
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+)
+
+func newFinishedJob(name string, conditionType batch.JobConditionType, finishTime time.Time) *batch.Job {
+	return &batch.Job{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default"},
+		Status: batch.JobStatus{
+			Conditions: []batch.JobCondition{{
+				Type:               conditionType,
+				Status:             v1.ConditionTrue,
+				LastTransitionTime: metaV1.NewTime(finishTime),
+			}},
+		},
+	}
+}
+
+func TestDeleteFinishedJobs(t *testing.T) {
+	defer func(original func() time.Time) { now = original }(now)
+	current := time.Date(2020, time.January, 30, 10, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+
+	completed := newFinishedJob("completed", batch.JobComplete, current)
+	completionTime := metaV1.NewTime(current.Add(-2 * time.Hour))
+	completed.Status.CompletionTime = &completionTime
+
+	client := fake.NewSimpleClientset(
+		completed,
+		newFinishedJob("failed", batch.JobFailed, current.Add(-3*time.Hour)),
+		newFinishedJob("recent", batch.JobFailed, current.Add(-time.Minute)),
+		&batch.Job{ObjectMeta: metaV1.ObjectMeta{Name: "running", Namespace: "default"}},
+	)
+	other := newFinishedJob("other", batch.JobFailed, current.Add(-3*time.Hour))
+	other.Namespace = "other"
+	if _, err := client.BatchV1().Jobs("other").Create(context.TODO(), other, metaV1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := DeleteFinishedJobs(client, common.NewNamespaceQuery([]string{"default"}), time.Hour, false)
+	if err != nil {
+		t.Fatalf("DeleteFinishedJobs() returned error: %s", err)
+	}
+
+	expected := &JobCleanup{Deleted: []string{"completed", "failed"}, Errors: []error{}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("DeleteFinishedJobs() == %#v, expected %#v", actual, expected)
+	}
+
+	jobs, _ := client.BatchV1().Jobs("default").List(context.TODO(), metaV1.ListOptions{})
+	remaining := make([]string, 0)
+	for _, item := range jobs.Items {
+		remaining = append(remaining, item.Name)
+	}
+	if !reflect.DeepEqual(remaining, []string{"recent", "running"}) {
+		t.Errorf("DeleteFinishedJobs() should keep recent and running jobs, got %v", remaining)
+	}
+
+	for _, age := range []time.Duration{-time.Second, 0} {
+		if _, err := DeleteFinishedJobs(client, common.NewNamespaceQuery(nil), age, false); err == nil {
+			t.Errorf("DeleteFinishedJobs() with age %s should return an error", age)
+		}
+	}
+
+	actual, err = DeleteFinishedJobs(client, common.NewNamespaceQuery(nil), time.Hour, false)
+	if err != nil {
+		t.Fatalf("DeleteFinishedJobs() returned error: %s", err)
+	}
+	if !reflect.DeepEqual(actual.Deleted, []string{"other"}) {
+		t.Errorf("DeleteFinishedJobs() in all namespaces deleted %v, expected [other]", actual.Deleted)
+	}
+}
+
+func TestRerunJob(t *testing.T) {
+	manualSelector := true
+	failed := newFinishedJob("migrate", batch.JobFailed, time.Now())
+	failed.Labels = map[string]string{"app": "db", "controller-uid": "123", "job-name": "migrate"}
+	failed.Spec = batch.JobSpec{
+		Selector: &metaV1.LabelSelector{MatchLabels: map[string]string{"controller-uid": "123"}},
+		Template: v1.PodTemplateSpec{
+			ObjectMeta: metaV1.ObjectMeta{
+				Labels: map[string]string{"app": "db", "controller-uid": "123", "job-name": "migrate"},
+			},
+		},
+	}
+	manual := newFinishedJob("manual", batch.JobFailed, time.Now())
+	manual.Spec = batch.JobSpec{
+		ManualSelector: &manualSelector,
+		Selector:       &metaV1.LabelSelector{MatchLabels: map[string]string{"job-name": "manual"}},
+		Template: v1.PodTemplateSpec{
+			ObjectMeta: metaV1.ObjectMeta{Labels: map[string]string{"job-name": "manual"}},
+		},
+	}
+	completed := newFinishedJob("completed", batch.JobComplete, time.Now())
+
+	client := fake.NewSimpleClientset(failed, manual, completed)
+
+	actual, err := RerunJob(client, "default", "migrate")
+	if err != nil {
+		t.Fatalf("RerunJob() returned error: %s", err)
+	}
+
+	if !strings.HasPrefix(actual.Name, "migrate-rerun-") {
+		t.Errorf("RerunJob() should create a job named after the failed job, got %s", actual.Name)
+	}
+	if actual.Spec.Selector != nil {
+		t.Errorf("RerunJob() should let the job controller generate a selector, got %#v", actual.Spec.Selector)
+	}
+	expectedLabels := map[string]string{"app": "db"}
+	if !reflect.DeepEqual(actual.Labels, expectedLabels) ||
+		!reflect.DeepEqual(actual.Spec.Template.Labels, expectedLabels) {
+		t.Errorf("RerunJob() should remove generated labels, got %v and %v", actual.Labels,
+			actual.Spec.Template.Labels)
+	}
+	if len(actual.Status.Conditions) != 0 {
+		t.Errorf("RerunJob() should create a fresh job, got status %#v", actual.Status)
+	}
+
+	actual, err = RerunJob(client, "default", "manual")
+	if err != nil {
+		t.Fatalf("RerunJob() returned error: %s", err)
+	}
+	if !reflect.DeepEqual(actual.Spec.Selector, manual.Spec.Selector) {
+		t.Errorf("RerunJob() should keep manual selectors, got %#v", actual.Spec.Selector)
+	}
+
+	if _, err := RerunJob(client, "default", "completed"); err == nil {
+		t.Errorf("RerunJob() of a completed job should return an error")
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+)
+
+// PodFailure describes why a pod of a job failed. Container failures are taken from the termination state of the
+// container, pod failures without a failed container, like exceeded deadlines or evictions, from the pod status.
+type PodFailure struct {
+	PodName    string       `json:"podName"`
+	Container  string       `json:"container,omitempty"`
+	ExitCode   int32        `json:"exitCode,omitempty"`
+	Reason     string       `json:"reason,omitempty"`
+	Message    string       `json:"message,omitempty"`
+	FinishedAt *metaV1.Time `json:"finishedAt,omitempty"`
+}
+
+// JobRun is a single run of a job with its outcome and duration.
+type JobRun struct {
+	Name           string        `json:"name"`
+	Status         JobStatusType `json:"status"`
+	StartTime      *metaV1.Time  `json:"startTime,omitempty"`
+	CompletionTime *metaV1.Time  `json:"completionTime,omitempty"`
+
+	// DurationSeconds is the time from start to completion, or the time elapsed so far for running jobs.
+	DurationSeconds int64 `json:"durationSeconds"`
+
+	Succeeded int32 `json:"succeeded"`
+	Failed    int32 `json:"failed"`
+
+	Failures []PodFailure `json:"failures"`
+}
+
+// GetJobRun returns the outcome, duration and pod failures of the job with the given name.
+func GetJobRun(client client.Interface, namespace, name string) (*JobRun, error) {
+	log.Printf("Getting run of job %s in %s namespace", name, namespace)
+
+	job, err := client.BatchV1().Jobs(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	pods := make([]v1.Pod, 0)
+	if job.Spec.Selector != nil {
+		list, err := client.CoreV1().Pods(namespace).List(context.TODO(), metaV1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(job.Spec.Selector.MatchLabels).String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		pods = list.Items
+	}
+
+	run := ToJobRun(job, pods, time.Now())
+	return &run, nil
+}
+
+// ToJobRun converts the job into a run. Pods not belonging to the job are ignored. The current time is used to
+// compute the duration of running jobs.
+func ToJobRun(job *batch.Job, pods []v1.Pod, now time.Time) JobRun {
+	run := JobRun{
+		Name:      job.Name,
+		Status:    getJobStatus(job).Status,
+		StartTime: job.Status.StartTime,
+		Succeeded: job.Status.Succeeded,
+		Failed:    job.Status.Failed,
+		Failures:  make([]PodFailure, 0),
+	}
+
+	if finishTime := getJobFinishTime(job); finishTime != nil {
+		completionTime := metaV1.NewTime(*finishTime)
+		run.CompletionTime = &completionTime
+	}
+
+	if run.StartTime != nil {
+		end := now
+		if run.CompletionTime != nil {
+			end = run.CompletionTime.Time
+		}
+		if end.After(run.StartTime.Time) {
+			run.DurationSeconds = int64(end.Sub(run.StartTime.Time) / time.Second)
+		}
+	}
+
+	if job.Spec.Selector != nil {
+		for _, pod := range common.FilterPodsForJob(*job, pods) {
+			run.Failures = append(run.Failures, getPodFailures(pod)...)
+		}
+	}
+	sort.SliceStable(run.Failures, func(i, j int) bool { return run.Failures[i].PodName < run.Failures[j].PodName })
+
+	return run
+}
+
+// getPodFailures returns the failed containers of the pod. Containers restarted by the kubelet report their
+// failure in the last termination state.
+func getPodFailures(pod v1.Pod) []PodFailure {
+	failures := make([]PodFailure, 0)
+
+	statuses := append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...),
+		pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		terminated := status.State.Terminated
+		if terminated == nil || terminated.ExitCode == 0 {
+			terminated = status.LastTerminationState.Terminated
+		}
+		if terminated == nil || terminated.ExitCode == 0 {
+			continue
+		}
+
+		finishedAt := terminated.FinishedAt
+		failures = append(failures, PodFailure{
+			PodName:    pod.Name,
+			Container:  status.Name,
+			ExitCode:   terminated.ExitCode,
+			Reason:     terminated.Reason,
+			Message:    terminated.Message,
+			FinishedAt: &finishedAt,
+		})
+	}
+
+	if len(failures) == 0 && pod.Status.Phase == v1.PodFailed {
+		failures = append(failures, PodFailure{
+			PodName: pod.Name,
+			Reason:  pod.Status.Reason,
+			Message: pod.Status.Message,
+		})
+	}
+
+	return failures
+}
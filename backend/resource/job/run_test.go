@@ -0,0 +1,141 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestToJobRun(t *testing.T) {
+	start := time.Date(2020, time.January, 30, 10, 0, 0, 0, time.UTC)
+	startTime := metaV1.NewTime(start)
+	finishedAt := metaV1.NewTime(start.Add(time.Minute))
+	selector := &metaV1.LabelSelector{MatchLabels: map[string]string{"job-name": "backup"}}
+
+	failed := newFinishedJob("backup", batch.JobFailed, start.Add(90*time.Second))
+	failed.Spec.Selector = selector
+	failed.Status.StartTime = &startTime
+	failed.Status.Failed = 2
+
+	running := &batch.Job{
+		ObjectMeta: metaV1.ObjectMeta{Name: "backup", Namespace: "default"},
+		Spec:       batch.JobSpec{Selector: selector},
+		Status:     batch.JobStatus{StartTime: &startTime},
+	}
+
+	pods := []v1.Pod{
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "backup-a", Namespace: "default", Labels: selector.MatchLabels},
+			Status: v1.PodStatus{
+				Phase: v1.PodFailed,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: "main",
+						State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+							ExitCode: 137, Reason: "OOMKilled", FinishedAt: finishedAt,
+						}},
+					},
+					{
+						Name:  "sidecar",
+						State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 0}},
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "backup-b", Namespace: "default", Labels: selector.MatchLabels},
+			Status: v1.PodStatus{
+				Phase:   v1.PodFailed,
+				Reason:  "DeadlineExceeded",
+				Message: "Pod was active too long",
+			},
+		},
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "other", Namespace: "default"},
+			Status:     v1.PodStatus{Phase: v1.PodFailed, Reason: "Evicted"},
+		},
+	}
+
+	completionTime := metaV1.NewTime(start.Add(90 * time.Second))
+	cases := []struct {
+		job      *batch.Job
+		pods     []v1.Pod
+		expected JobRun
+	}{
+		{
+			failed,
+			pods,
+			JobRun{
+				Name:            "backup",
+				Status:          JobStatusFailed,
+				StartTime:       &startTime,
+				CompletionTime:  &completionTime,
+				DurationSeconds: 90,
+				Failed:          2,
+				Failures: []PodFailure{
+					{PodName: "backup-a", Container: "main", ExitCode: 137, Reason: "OOMKilled",
+						FinishedAt: &finishedAt},
+					{PodName: "backup-b", Reason: "DeadlineExceeded", Message: "Pod was active too long"},
+				},
+			},
+		},
+		{
+			running,
+			nil,
+			JobRun{
+				Name:            "backup",
+				Status:          JobStatusRunning,
+				StartTime:       &startTime,
+				DurationSeconds: 300,
+				Failures:        []PodFailure{},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := ToJobRun(c.job, c.pods, start.Add(5*time.Minute))
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("ToJobRun() == %#v, expected %#v", actual, c.expected)
+		}
+	}
+}
+
+func TestGetPodFailuresShouldUseLastTerminationState(t *testing.T) {
+	pod := v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: "backup-a"},
+		Status: v1.PodStatus{
+			Phase: v1.PodRunning,
+			ContainerStatuses: []v1.ContainerStatus{{
+				Name:  "main",
+				State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+				LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+					ExitCode: 1, Reason: "Error",
+				}},
+			}},
+		},
+	}
+
+	expected := []PodFailure{{PodName: "backup-a", Container: "main", ExitCode: 1, Reason: "Error",
+		FinishedAt: &metaV1.Time{}}}
+	if actual := getPodFailures(pod); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getPodFailures() == %#v, expected %#v", actual, expected)
+	}
+}
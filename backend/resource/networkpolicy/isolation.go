@@ -0,0 +1,126 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"context"
+	"log"
+
+	networkingV1 "k8s.io/api/networking/v1"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+)
+
+// PodIsolation shows if a pod is isolated by network policies. Traffic of an isolated pod that is not allowed by
+// any of the policies selecting it is denied.
+type PodIsolation struct {
+	Name string `json:"name"`
+
+	// IngressIsolated and EgressIsolated are true if a policy of given type selects the pod.
+	IngressIsolated bool `json:"ingressIsolated"`
+	EgressIsolated  bool `json:"egressIsolated"`
+
+	// IngressDefaultDeny and EgressDefaultDeny are true if a policy without any rules for given type selects the
+	// pod, i.e. all traffic is denied unless other policies allow it.
+	IngressDefaultDeny bool `json:"ingressDefaultDeny"`
+	EgressDefaultDeny  bool `json:"egressDefaultDeny"`
+
+	// IngressPolicies and EgressPolicies are names of policies that select the pod.
+	IngressPolicies []string `json:"ingressPolicies"`
+	EgressPolicies  []string `json:"egressPolicies"`
+}
+
+// IsolationStatus summarizes isolation of pods in a namespace.
+type IsolationStatus struct {
+	Namespace string `json:"namespace"`
+
+	// IngressDefaultDeny and EgressDefaultDeny are true if a policy without any rules for given type selects all pods
+	// of the namespace.
+	IngressDefaultDeny bool `json:"ingressDefaultDeny"`
+	EgressDefaultDeny  bool `json:"egressDefaultDeny"`
+
+	// Number of pods isolated for ingress and egress.
+	IngressIsolated int `json:"ingressIsolated"`
+	EgressIsolated  int `json:"egressIsolated"`
+
+	Pods   []PodIsolation `json:"pods"`
+	Errors []error        `json:"errors"`
+}
+
+// GetIsolationStatus returns isolation status of all pods in given namespace.
+func GetIsolationStatus(client client.Interface, namespace string) (*IsolationStatus, error) {
+	log.Printf("Getting network isolation status of %s namespace", namespace)
+
+	policies, err := client.NetworkingV1().NetworkPolicies(namespace).List(context.TODO(), api.ListEverything)
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(context.TODO(), api.ListEverything)
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	result := &IsolationStatus{Namespace: namespace, Pods: make([]PodIsolation, 0), Errors: nonCriticalErrors}
+	for _, policy := range policies.Items {
+		if len(policy.Spec.PodSelector.MatchLabels) > 0 || len(policy.Spec.PodSelector.MatchExpressions) > 0 {
+			continue
+		}
+		result.IngressDefaultDeny = result.IngressDefaultDeny || isDefaultDeny(&policy, Ingress)
+		result.EgressDefaultDeny = result.EgressDefaultDeny || isDefaultDeny(&policy, Egress)
+	}
+
+	for _, pod := range pods.Items {
+		isolation := PodIsolation{Name: pod.Name, IngressPolicies: make([]string, 0),
+			EgressPolicies: make([]string, 0)}
+
+		for _, policy := range policies.Items {
+			if !selectorMatches(&policy.Spec.PodSelector, pod.Labels) {
+				continue
+			}
+			if HasPolicyType(&policy, Ingress) {
+				isolation.IngressPolicies = append(isolation.IngressPolicies, policy.Name)
+			}
+			if HasPolicyType(&policy, Egress) {
+				isolation.EgressPolicies = append(isolation.EgressPolicies, policy.Name)
+			}
+			isolation.IngressDefaultDeny = isolation.IngressDefaultDeny || isDefaultDeny(&policy, Ingress)
+			isolation.EgressDefaultDeny = isolation.EgressDefaultDeny || isDefaultDeny(&policy, Egress)
+		}
+
+		isolation.IngressIsolated = len(isolation.IngressPolicies) > 0
+		isolation.EgressIsolated = len(isolation.EgressPolicies) > 0
+		if isolation.IngressIsolated {
+			result.IngressIsolated++
+		}
+		if isolation.EgressIsolated {
+			result.EgressIsolated++
+		}
+
+		result.Pods = append(result.Pods, isolation)
+	}
+
+	return result, nil
+}
+
+// isDefaultDeny returns true if given policy denies all traffic in given direction, i.e. it isolates selected pods
+// without allowing any traffic.
+func isDefaultDeny(policy *networkingV1.NetworkPolicy, direction Direction) bool {
+	return HasPolicyType(policy, direction) && len(policyRules(policy, direction)) == 0
+}
@@ -0,0 +1,89 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"reflect"
+	"testing"
+
+	networkingV1 "k8s.io/api/networking/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetIsolationStatus(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newPod("default", "web", "10.0.0.1", map[string]string{"app": "web"}),
+		newPod("default", "db", "10.0.0.2", map[string]string{"app": "db"}),
+		newPod("other", "api", "10.0.1.1", map[string]string{"app": "db"}),
+		newPolicy("default", "deny-egress", nil, []networkingV1.PolicyType{networkingV1.PolicyTypeEgress}, nil, nil),
+		newPolicy("default", "db", map[string]string{"app": "db"}, nil, nil, nil),
+	)
+
+	expected := &IsolationStatus{
+		Namespace:          "default",
+		IngressDefaultDeny: false,
+		EgressDefaultDeny:  true,
+		IngressIsolated:    1,
+		EgressIsolated:     2,
+		Pods: []PodIsolation{
+			{Name: "web", EgressIsolated: true, IngressDefaultDeny: false, EgressDefaultDeny: true,
+				IngressPolicies: []string{}, EgressPolicies: []string{"deny-egress"}},
+			{Name: "db", IngressIsolated: true, EgressIsolated: true, IngressDefaultDeny: true, EgressDefaultDeny: true,
+				IngressPolicies: []string{"db"}, EgressPolicies: []string{"deny-egress"}},
+		},
+		Errors: []error{},
+	}
+
+	actual, err := GetIsolationStatus(client, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetIsolationStatus(client, default) == \n%+v, expected \n%+v", actual, expected)
+	}
+}
+
+func TestGetIsolationStatusAllowAll(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newPod("default", "web", "10.0.0.1", map[string]string{"app": "web"}),
+		newPolicy("default", "allow-ingress", nil, nil, []networkingV1.NetworkPolicyIngressRule{{}}, nil),
+		newPolicy("default", "allow-egress", nil, []networkingV1.PolicyType{networkingV1.PolicyTypeEgress}, nil,
+			[]networkingV1.NetworkPolicyEgressRule{{}}),
+	)
+
+	expected := &IsolationStatus{
+		Namespace:          "default",
+		IngressDefaultDeny: false,
+		EgressDefaultDeny:  false,
+		IngressIsolated:    1,
+		EgressIsolated:     1,
+		Pods: []PodIsolation{
+			{Name: "web", IngressIsolated: true, EgressIsolated: true, IngressDefaultDeny: false,
+				EgressDefaultDeny: false, IngressPolicies: []string{"allow-ingress"},
+				EgressPolicies: []string{"allow-egress"}},
+		},
+		Errors: []error{},
+	}
+
+	actual, err := GetIsolationStatus(client, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetIsolationStatus(client, default) == \n%+v, expected \n%+v", actual, expected)
+	}
+}
@@ -0,0 +1,491 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	v1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+)
+
+// Direction of the traffic as seen by the pod selected by a network policy.
+type Direction string
+
+const (
+	Ingress Direction = "ingress"
+	Egress  Direction = "egress"
+)
+
+// Endpoint is a source or destination of the simulated traffic. Exactly one of pod, namespace selector or CIDR
+// has to be set.
+type Endpoint struct {
+	// Namespace and Pod identify an existing pod.
+	Namespace string `json:"namespace,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+
+	// NamespaceSelector selects namespaces. Traffic is simulated for a pod with PodLabels in each of them.
+	NamespaceSelector *metaV1.LabelSelector `json:"namespaceSelector,omitempty"`
+	PodLabels         map[string]string     `json:"podLabels,omitempty"`
+
+	// CIDR is a range of IP addresses, usually outside of the cluster, e.g. 10.0.0.0/24.
+	CIDR string `json:"cidr,omitempty"`
+}
+
+// SimulationSpec describes the traffic that is checked against network policies.
+type SimulationSpec struct {
+	Source      Endpoint `json:"source"`
+	Destination Endpoint `json:"destination"`
+	Port        int32    `json:"port"`
+
+	// Protocol defaults to TCP.
+	Protocol v1.Protocol `json:"protocol,omitempty"`
+}
+
+// PolicyDecision is a network policy that took part in the decision.
+type PolicyDecision struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Direction Direction `json:"direction"`
+
+	// Rule is the index of the ingress or egress rule that allows the traffic. It is not set if no rule of the
+	// policy allows it.
+	Rule *int `json:"rule,omitempty"`
+}
+
+// DirectionResult is the outcome of policies that select the pod on one side of the traffic.
+type DirectionResult struct {
+	// Isolated is true if at least one policy selects the pod. Traffic of not isolated pods is always allowed.
+	Isolated bool `json:"isolated"`
+	Allowed  bool `json:"allowed"`
+
+	// Policies are the rules that allow the traffic or, if it is denied, the policies that isolate the pod.
+	Policies []PolicyDecision `json:"policies"`
+}
+
+// Verdict is the outcome for a single pair of resolved source and destination.
+type Verdict struct {
+	Source      Endpoint `json:"source"`
+	Destination Endpoint `json:"destination"`
+	Allowed     bool     `json:"allowed"`
+
+	// Egress is evaluated for sources inside the cluster and Ingress for destinations inside the cluster.
+	Egress  *DirectionResult `json:"egress,omitempty"`
+	Ingress *DirectionResult `json:"ingress,omitempty"`
+}
+
+// SimulationResult is the outcome of the simulation. Traffic is allowed only if all verdicts allow it.
+type SimulationResult struct {
+	Allowed  bool      `json:"allowed"`
+	Verdicts []Verdict `json:"verdicts"`
+}
+
+// peer is an endpoint resolved to a single pod or CIDR.
+type peer struct {
+	endpoint Endpoint
+
+	// namespace and pod are not set for CIDR. Pod resolved from namespace selector only has namespace and labels.
+	namespace *v1.Namespace
+	pod       *v1.Pod
+
+	// ipNet is the address of the pod or the CIDR. It is not set if the address is not known.
+	ipNet *net.IPNet
+}
+
+// SimulateTraffic evaluates network policies for the traffic described by given spec. Policies of the source
+// namespace are evaluated for egress and policies of the destination namespace for ingress, like network plugins
+// enforcing network policies do.
+func SimulateTraffic(client client.Interface, spec *SimulationSpec) (*SimulationResult, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	protocol := spec.Protocol
+	if len(protocol) == 0 {
+		protocol = v1.ProtocolTCP
+	}
+
+	log.Printf("Simulating %s traffic on port %d against network policies", protocol, spec.Port)
+
+	sources, err := resolveEndpoint(client, spec.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	destinations, err := resolveEndpoint(client, spec.Destination)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := map[string][]networkingV1.NetworkPolicy{}
+	getPolicies := func(namespace string) ([]networkingV1.NetworkPolicy, error) {
+		if result, exists := policies[namespace]; exists {
+			return result, nil
+		}
+		list, err := client.NetworkingV1().NetworkPolicies(namespace).List(context.TODO(), api.ListEverything)
+		if err != nil {
+			return nil, err
+		}
+		policies[namespace] = list.Items
+		return list.Items, nil
+	}
+
+	result := &SimulationResult{Allowed: true, Verdicts: make([]Verdict, 0)}
+	for _, source := range sources {
+		for _, destination := range destinations {
+			verdict := Verdict{Source: source.endpoint, Destination: destination.endpoint, Allowed: true}
+
+			if source.namespace != nil {
+				items, err := getPolicies(source.namespace.Name)
+				if err != nil {
+					return nil, err
+				}
+				verdict.Egress = evaluate(items, Egress, source, destination, destination, spec.Port, protocol)
+				verdict.Allowed = verdict.Egress.Allowed
+			}
+
+			if destination.namespace != nil {
+				items, err := getPolicies(destination.namespace.Name)
+				if err != nil {
+					return nil, err
+				}
+				verdict.Ingress = evaluate(items, Ingress, destination, source, destination, spec.Port, protocol)
+				verdict.Allowed = verdict.Allowed && verdict.Ingress.Allowed
+			}
+
+			result.Allowed = result.Allowed && verdict.Allowed
+			result.Verdicts = append(result.Verdicts, verdict)
+		}
+	}
+
+	return result, nil
+}
+
+// Validate checks if simulation spec is complete.
+func (spec *SimulationSpec) Validate() error {
+	if spec.Port < 1 || spec.Port > 65535 {
+		return errors.NewInvalid("Port must be between 1 and 65535")
+	}
+
+	switch spec.Protocol {
+	case "", v1.ProtocolTCP, v1.ProtocolUDP, v1.ProtocolSCTP:
+	default:
+		return errors.NewInvalid(fmt.Sprintf("Unsupported protocol %s", spec.Protocol))
+	}
+
+	if err := spec.Source.validate(); err != nil {
+		return errors.NewInvalid("Source: " + err.Error())
+	}
+
+	if err := spec.Destination.validate(); err != nil {
+		return errors.NewInvalid("Destination: " + err.Error())
+	}
+
+	if len(spec.Source.CIDR) > 0 && len(spec.Destination.CIDR) > 0 {
+		return errors.NewInvalid("Source or destination has to be inside the cluster")
+	}
+
+	return nil
+}
+
+func (e Endpoint) validate() error {
+	set := 0
+	if len(e.Pod) > 0 {
+		set++
+		if len(e.Namespace) == 0 {
+			return fmt.Errorf("namespace of pod %s is required", e.Pod)
+		}
+	}
+	if e.NamespaceSelector != nil {
+		set++
+		if _, err := metaV1.LabelSelectorAsSelector(e.NamespaceSelector); err != nil {
+			return err
+		}
+	}
+	if len(e.CIDR) > 0 {
+		set++
+		if _, _, err := net.ParseCIDR(e.CIDR); err != nil {
+			return err
+		}
+	}
+
+	if set != 1 {
+		return fmt.Errorf("exactly one of pod, namespace selector or CIDR is required")
+	}
+
+	return nil
+}
+
+// Resolves endpoint to peers. Namespace selector is resolved to a pod with given labels in every selected namespace.
+func resolveEndpoint(client client.Interface, endpoint Endpoint) ([]*peer, error) {
+	switch {
+	case len(endpoint.CIDR) > 0:
+		_, ipNet, _ := net.ParseCIDR(endpoint.CIDR)
+		return []*peer{{endpoint: Endpoint{CIDR: ipNet.String()}, ipNet: ipNet}}, nil
+	case len(endpoint.Pod) > 0:
+		pod, err := client.CoreV1().Pods(endpoint.Namespace).Get(context.TODO(), endpoint.Pod, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		namespace, err := client.CoreV1().Namespaces().Get(context.TODO(), endpoint.Namespace, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		result := &peer{
+			endpoint:  Endpoint{Namespace: pod.Namespace, Pod: pod.Name, PodLabels: pod.Labels},
+			namespace: namespace,
+			pod:       pod,
+		}
+		if ip := net.ParseIP(pod.Status.PodIP); ip != nil {
+			result.ipNet = &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)}
+		}
+		return []*peer{result}, nil
+	}
+
+	selector, _ := metaV1.LabelSelectorAsSelector(endpoint.NamespaceSelector)
+	namespaces, err := client.CoreV1().Namespaces().List(context.TODO(),
+		metaV1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(namespaces.Items) == 0 {
+		return nil, errors.NewInvalid(fmt.Sprintf("Namespace selector %s does not match any namespace",
+			selector.String()))
+	}
+
+	result := make([]*peer, 0, len(namespaces.Items))
+	for i := range namespaces.Items {
+		namespace := &namespaces.Items[i]
+		result = append(result, &peer{
+			endpoint:  Endpoint{Namespace: namespace.Name, PodLabels: endpoint.PodLabels},
+			namespace: namespace,
+			pod:       &v1.Pod{ObjectMeta: metaV1.ObjectMeta{Namespace: namespace.Name, Labels: endpoint.PodLabels}},
+		})
+	}
+
+	return result, nil
+}
+
+// Evaluates policies that select target pod for the traffic from or to remote peer. Named ports are resolved
+// against ports of destination pod.
+func evaluate(policies []networkingV1.NetworkPolicy, direction Direction, target, remote, destination *peer,
+	port int32, protocol v1.Protocol) *DirectionResult {
+	result := &DirectionResult{}
+	allowing := make([]PolicyDecision, 0)
+	denying := make([]PolicyDecision, 0)
+
+	for _, policy := range policies {
+		if !HasPolicyType(&policy, direction) || !selectorMatches(&policy.Spec.PodSelector, target.pod.Labels) {
+			continue
+		}
+
+		result.Isolated = true
+		allowed := false
+		for i, rule := range policyRules(&policy, direction) {
+			if rule.matches(policy.Namespace, remote, destination, port, protocol) {
+				index := i
+				allowing = append(allowing, PolicyDecision{Namespace: policy.Namespace, Name: policy.Name,
+					Direction: direction, Rule: &index})
+				allowed = true
+			}
+		}
+
+		if !allowed {
+			denying = append(denying, PolicyDecision{Namespace: policy.Namespace, Name: policy.Name,
+				Direction: direction})
+		}
+	}
+
+	result.Allowed = !result.Isolated || len(allowing) > 0
+	result.Policies = allowing
+	if !result.Allowed {
+		result.Policies = denying
+	}
+
+	return result
+}
+
+// policyRule is a common representation of ingress and egress rules.
+type policyRule struct {
+	peers []networkingV1.NetworkPolicyPeer
+	ports []networkingV1.NetworkPolicyPort
+}
+
+func policyRules(policy *networkingV1.NetworkPolicy, direction Direction) []policyRule {
+	result := make([]policyRule, 0)
+	if direction == Ingress {
+		for _, rule := range policy.Spec.Ingress {
+			result = append(result, policyRule{peers: rule.From, ports: rule.Ports})
+		}
+	} else {
+		for _, rule := range policy.Spec.Egress {
+			result = append(result, policyRule{peers: rule.To, ports: rule.Ports})
+		}
+	}
+	return result
+}
+
+// Rule matches if any of its peers and any of its ports match. Empty list of peers or ports matches everything.
+func (rule policyRule) matches(policyNamespace string, remote, destination *peer, port int32,
+	protocol v1.Protocol) bool {
+	if !portMatches(rule.ports, destination, port, protocol) {
+		return false
+	}
+
+	if len(rule.peers) == 0 {
+		return true
+	}
+
+	for _, policyPeer := range rule.peers {
+		if peerMatches(policyPeer, policyNamespace, remote) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func peerMatches(policyPeer networkingV1.NetworkPolicyPeer, policyNamespace string, remote *peer) bool {
+	if policyPeer.IPBlock != nil {
+		return remote.ipNet != nil && ipBlockContains(policyPeer.IPBlock, remote.ipNet)
+	}
+
+	if remote.namespace == nil {
+		return false
+	}
+
+	if policyPeer.NamespaceSelector == nil {
+		if remote.namespace.Name != policyNamespace {
+			return false
+		}
+	} else if !selectorMatches(policyPeer.NamespaceSelector, remote.namespace.Labels) {
+		return false
+	}
+
+	return policyPeer.PodSelector == nil || selectorMatches(policyPeer.PodSelector, remote.pod.Labels)
+}
+
+// IP block contains the network if the whole network is inside of the block and does not overlap any exception.
+func ipBlockContains(block *networkingV1.IPBlock, ipNet *net.IPNet) bool {
+	_, blockNet, err := net.ParseCIDR(block.CIDR)
+	if err != nil || !blockNet.Contains(ipNet.IP) {
+		return false
+	}
+
+	blockOnes, _ := blockNet.Mask.Size()
+	ones, _ := ipNet.Mask.Size()
+	if blockOnes > ones {
+		return false
+	}
+
+	for _, except := range block.Except {
+		_, exceptNet, err := net.ParseCIDR(except)
+		if err == nil && (exceptNet.Contains(ipNet.IP) || ipNet.Contains(exceptNet.IP)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func portMatches(ports []networkingV1.NetworkPolicyPort, destination *peer, port int32,
+	protocol v1.Protocol) bool {
+	if len(ports) == 0 {
+		return true
+	}
+
+	for _, policyPort := range ports {
+		policyProtocol := v1.ProtocolTCP
+		if policyPort.Protocol != nil {
+			policyProtocol = *policyPort.Protocol
+		}
+
+		if policyProtocol != protocol {
+			continue
+		}
+
+		if policyPort.Port == nil {
+			return true
+		}
+
+		if policyPort.Port.Type == intstr.Int {
+			if policyPort.Port.IntVal == port {
+				return true
+			}
+		} else if namedPortMatches(destination, policyPort.Port.StrVal, port, protocol) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Named port can only be resolved against container ports of an existing destination pod.
+func namedPortMatches(destination *peer, name string, port int32, protocol v1.Protocol) bool {
+	if destination.pod == nil {
+		return false
+	}
+
+	for _, container := range destination.pod.Spec.Containers {
+		for _, containerPort := range container.Ports {
+			containerProtocol := containerPort.Protocol
+			if len(containerProtocol) == 0 {
+				containerProtocol = v1.ProtocolTCP
+			}
+			if containerPort.Name == name && containerPort.ContainerPort == port && containerProtocol == protocol {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// HasPolicyType returns true if the policy applies to given direction. Policy types default to ingress and, if
+// the policy has any egress rules, also to egress.
+func HasPolicyType(policy *networkingV1.NetworkPolicy, direction Direction) bool {
+	if len(policy.Spec.PolicyTypes) == 0 {
+		return direction == Ingress || len(policy.Spec.Egress) > 0
+	}
+
+	for _, policyType := range policy.Spec.PolicyTypes {
+		if (policyType == networkingV1.PolicyTypeIngress && direction == Ingress) ||
+			(policyType == networkingV1.PolicyTypeEgress && direction == Egress) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func selectorMatches(selector *metaV1.LabelSelector, set map[string]string) bool {
+	s, err := metaV1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false
+	}
+	return s.Matches(labels.Set(set))
+}
@@ -0,0 +1,232 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newNamespace(name string, labels map[string]string) *v1.Namespace {
+	return &v1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func newPod(namespace, name, ip string, labels map[string]string, ports ...v1.ContainerPort) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "main", Ports: ports}}},
+		Status:     v1.PodStatus{PodIP: ip},
+	}
+}
+
+func newPolicy(namespace, name string, selector map[string]string, types []networkingV1.PolicyType,
+	ingress []networkingV1.NetworkPolicyIngressRule,
+	egress []networkingV1.NetworkPolicyEgressRule) *networkingV1.NetworkPolicy {
+	return &networkingV1.NetworkPolicy{
+		ObjectMeta: metaV1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: networkingV1.NetworkPolicySpec{
+			PodSelector: metaV1.LabelSelector{MatchLabels: selector},
+			PolicyTypes: types,
+			Ingress:     ingress,
+			Egress:      egress,
+		},
+	}
+}
+
+func newPort(port intstr.IntOrString) []networkingV1.NetworkPolicyPort {
+	return []networkingV1.NetworkPolicyPort{{Port: &port}}
+}
+
+func rule(index int) *int {
+	return &index
+}
+
+func TestSimulateTraffic(t *testing.T) {
+	objects := []runtime.Object{
+		newNamespace("frontend", map[string]string{"team": "web"}),
+		newNamespace("backend", map[string]string{"team": "api"}),
+		newPod("frontend", "web", "10.0.1.1", map[string]string{"app": "web"}),
+		newPod("backend", "api", "10.0.2.1", map[string]string{"app": "api"},
+			v1.ContainerPort{Name: "http", ContainerPort: 8080}),
+		newPod("backend", "db", "10.0.2.2", map[string]string{"app": "db"}),
+		newPolicy("backend", "default-deny", nil, nil, nil, nil),
+		newPolicy("backend", "allow-web", map[string]string{"app": "api"}, nil,
+			[]networkingV1.NetworkPolicyIngressRule{
+				{
+					From: []networkingV1.NetworkPolicyPeer{{
+						NamespaceSelector: &metaV1.LabelSelector{MatchLabels: map[string]string{"team": "web"}},
+					}},
+					Ports: newPort(intstr.FromString("http")),
+				},
+				{
+					From: []networkingV1.NetworkPolicyPeer{{
+						IPBlock: &networkingV1.IPBlock{CIDR: "192.168.0.0/16", Except: []string{"192.168.1.0/24"}},
+					}},
+					Ports: newPort(intstr.FromInt(8080)),
+				},
+			}, nil),
+		newPolicy("frontend", "deny-egress", map[string]string{"app": "web"},
+			[]networkingV1.PolicyType{networkingV1.PolicyTypeEgress}, nil,
+			[]networkingV1.NetworkPolicyEgressRule{{
+				To: []networkingV1.NetworkPolicyPeer{{
+					NamespaceSelector: &metaV1.LabelSelector{},
+					PodSelector:       &metaV1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+				}},
+			}}),
+	}
+
+	web := Endpoint{Namespace: "frontend", Pod: "web"}
+	api := Endpoint{Namespace: "backend", Pod: "api"}
+	db := Endpoint{Namespace: "backend", Pod: "db"}
+
+	cases := []struct {
+		info          string
+		spec          *SimulationSpec
+		expected      bool
+		expectedRules []PolicyDecision
+	}{
+		{
+			"named port allowed for selected namespace",
+			&SimulationSpec{Source: web, Destination: api, Port: 8080},
+			true,
+			[]PolicyDecision{
+				{Namespace: "frontend", Name: "deny-egress", Direction: Egress, Rule: rule(0)},
+				{Namespace: "backend", Name: "allow-web", Direction: Ingress, Rule: rule(0)},
+			},
+		},
+		{
+			"port not allowed",
+			&SimulationSpec{Source: web, Destination: api, Port: 9090},
+			false,
+			[]PolicyDecision{
+				{Namespace: "frontend", Name: "deny-egress", Direction: Egress, Rule: rule(0)},
+				{Namespace: "backend", Name: "default-deny", Direction: Ingress},
+				{Namespace: "backend", Name: "allow-web", Direction: Ingress},
+			},
+		},
+		{
+			"protocol not allowed",
+			&SimulationSpec{Source: web, Destination: api, Port: 8080, Protocol: v1.ProtocolUDP},
+			false,
+			[]PolicyDecision{
+				{Namespace: "frontend", Name: "deny-egress", Direction: Egress, Rule: rule(0)},
+				{Namespace: "backend", Name: "default-deny", Direction: Ingress},
+				{Namespace: "backend", Name: "allow-web", Direction: Ingress},
+			},
+		},
+		{
+			"egress denied",
+			&SimulationSpec{Source: web, Destination: db, Port: 5432},
+			false,
+			[]PolicyDecision{
+				{Namespace: "frontend", Name: "deny-egress", Direction: Egress},
+				{Namespace: "backend", Name: "default-deny", Direction: Ingress},
+			},
+		},
+		{
+			"not isolated destination",
+			&SimulationSpec{Source: api, Destination: web, Port: 80},
+			true,
+			[]PolicyDecision{},
+		},
+		{
+			"namespace selector",
+			&SimulationSpec{
+				Source: Endpoint{
+					NamespaceSelector: &metaV1.LabelSelector{MatchLabels: map[string]string{"team": "web"}},
+					PodLabels:         map[string]string{"app": "other"},
+				},
+				Destination: api,
+				Port:        8080,
+			},
+			true,
+			[]PolicyDecision{{Namespace: "backend", Name: "allow-web", Direction: Ingress, Rule: rule(0)}},
+		},
+		{
+			"CIDR inside of IP block",
+			&SimulationSpec{Source: Endpoint{CIDR: "192.168.2.0/24"}, Destination: api, Port: 8080},
+			true,
+			[]PolicyDecision{{Namespace: "backend", Name: "allow-web", Direction: Ingress, Rule: rule(1)}},
+		},
+		{
+			"CIDR overlapping exception",
+			&SimulationSpec{Source: Endpoint{CIDR: "192.168.0.0/23"}, Destination: api, Port: 8080},
+			false,
+			[]PolicyDecision{
+				{Namespace: "backend", Name: "default-deny", Direction: Ingress},
+				{Namespace: "backend", Name: "allow-web", Direction: Ingress},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		client := fake.NewSimpleClientset(objects...)
+		result, err := SimulateTraffic(client, c.spec)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", c.info, err)
+		}
+
+		if result.Allowed != c.expected {
+			t.Errorf("%s: expected allowed %t, but got %t", c.info, c.expected, result.Allowed)
+		}
+
+		decisions := make([]PolicyDecision, 0)
+		for _, verdict := range result.Verdicts {
+			if verdict.Egress != nil {
+				decisions = append(decisions, verdict.Egress.Policies...)
+			}
+			if verdict.Ingress != nil {
+				decisions = append(decisions, verdict.Ingress.Policies...)
+			}
+		}
+
+		if !reflect.DeepEqual(decisions, c.expectedRules) {
+			t.Errorf("%s: expected policies %+v, but got %+v", c.info, c.expectedRules, decisions)
+		}
+	}
+}
+
+func TestSimulationSpecValidate(t *testing.T) {
+	pod := Endpoint{Namespace: "default", Pod: "test"}
+	cidr := Endpoint{CIDR: "10.0.0.0/8"}
+
+	cases := []struct {
+		spec     *SimulationSpec
+		expected bool
+	}{
+		{&SimulationSpec{Source: pod, Destination: cidr, Port: 443}, true},
+		{&SimulationSpec{Source: pod, Destination: pod, Port: 0}, false},
+		{&SimulationSpec{Source: pod, Destination: pod, Port: 80, Protocol: "ICMP"}, false},
+		{&SimulationSpec{Source: cidr, Destination: cidr, Port: 80}, false},
+		{&SimulationSpec{Source: Endpoint{Pod: "test"}, Destination: pod, Port: 80}, false},
+		{&SimulationSpec{Source: Endpoint{CIDR: "10.0.0.0"}, Destination: pod, Port: 80}, false},
+		{&SimulationSpec{Source: Endpoint{Namespace: "default", Pod: "test", CIDR: "10.0.0.0/8"},
+			Destination: pod, Port: 80}, false},
+	}
+
+	for _, c := range cases {
+		err := c.spec.Validate()
+		if (err == nil) != c.expected {
+			t.Errorf("Validate(%+v): expected valid %t, but got %v", c.spec, c.expected, err)
+		}
+	}
+}
@@ -15,11 +15,23 @@
 package node
 
 import (
+	"strings"
+
 	"github.com/ycyxuehan/dashboard-gin/backend/api"
 	metricapi "github.com/ycyxuehan/dashboard-gin/backend/integration/metric/api"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	nodeRoleLabelPrefix = "node-role.kubernetes.io/"
+	nodeRoleLabel       = "kubernetes.io/role"
+
+	nodeStatusReady    = "Ready"
+	nodeStatusNotReady = "NotReady"
+	nodeStatusUnknown  = "Unknown"
 )
 
 //getContainerImages returns container image strings from the given node.
@@ -33,6 +45,35 @@ func getContainerImages(node v1.Node) []string {
 	return containerImages
 }
 
+// getNodeRoles returns sorted roles of given node based on the node-role.kubernetes.io/<role> and
+// kubernetes.io/role labels.
+func getNodeRoles(node v1.Node) []string {
+	roles := sets.NewString()
+	for key, value := range node.Labels {
+		switch {
+		case strings.HasPrefix(key, nodeRoleLabelPrefix):
+			if role := strings.TrimPrefix(key, nodeRoleLabelPrefix); len(role) > 0 {
+				roles.Insert(role)
+			}
+		case key == nodeRoleLabel && len(value) > 0:
+			roles.Insert(value)
+		}
+	}
+	return roles.List()
+}
+
+// getNodeStatus returns Ready, NotReady or Unknown based on the ready condition of given node.
+func getNodeStatus(node v1.Node) string {
+	switch getNodeConditionStatus(node, v1.NodeReady) {
+	case v1.ConditionTrue:
+		return nodeStatusReady
+	case v1.ConditionFalse:
+		return nodeStatusNotReady
+	default:
+		return nodeStatusUnknown
+	}
+}
+
 // The code below allows to perform complex data section on []api.Node
 
 type NodeCell v1.Node
@@ -45,6 +86,18 @@ func (self NodeCell) GetProperty(name dataselect.PropertyName) dataselect.Compar
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.StatusProperty:
+		return dataselect.StdComparableString(getNodeStatus(v1.Node(self)))
+	case dataselect.RolesProperty:
+		return dataselect.StdComparableString(strings.Join(getNodeRoles(v1.Node(self)), ","))
+	case dataselect.AllocatableCPUProperty:
+		return dataselect.StdComparableFloat(float64(self.Status.Allocatable.Cpu().MilliValue()) / 1000)
+	case dataselect.AllocatableMemoryProperty:
+		return dataselect.StdComparableInt(self.Status.Allocatable.Memory().Value())
+	case dataselect.TaintCountProperty:
+		return dataselect.StdComparableInt(len(self.Spec.Taints))
+	case dataselect.LabelProperty:
+		return dataselect.StdComparableLabels(self.ObjectMeta.Labels)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
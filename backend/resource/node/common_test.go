@@ -0,0 +1,104 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodeCellGetProperty(t *testing.T) {
+	node := NodeCell{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: "node-1",
+			Labels: map[string]string{
+				"node-role.kubernetes.io/master": "",
+				"node-role.kubernetes.io/etcd":   "true",
+				"kubernetes.io/role":             "infra",
+			},
+		},
+		Spec: v1.NodeSpec{
+			Taints: []v1.Taint{{Key: "a", Effect: v1.TaintEffectNoSchedule}, {Key: "b", Effect: v1.TaintEffectNoExecute}},
+		},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("3500m"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+			},
+			Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionFalse}},
+		},
+	}
+
+	cases := []struct {
+		property dataselect.PropertyName
+		expected dataselect.ComparableValue
+	}{
+		{dataselect.StatusProperty, dataselect.StdComparableString("NotReady")},
+		{dataselect.RolesProperty, dataselect.StdComparableString("etcd,infra,master")},
+		{dataselect.AllocatableCPUProperty, dataselect.StdComparableFloat(3.5)},
+		{dataselect.AllocatableMemoryProperty, dataselect.StdComparableInt(2 * 1024 * 1024 * 1024)},
+		{dataselect.TaintCountProperty, dataselect.StdComparableInt(2)},
+	}
+
+	for _, c := range cases {
+		actual := node.GetProperty(c.property)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetProperty(%s) == %#v, expected %#v", c.property, actual, c.expected)
+		}
+	}
+}
+
+func TestSortAndFilterNodesByProperties(t *testing.T) {
+	newNode := func(name string, memory string, ready v1.ConditionStatus) v1.Node {
+		return v1.Node{
+			ObjectMeta: metaV1.ObjectMeta{Name: name},
+			Status: v1.NodeStatus{
+				Allocatable: v1.ResourceList{v1.ResourceMemory: resource.MustParse(memory)},
+				Conditions:  []v1.NodeCondition{{Type: v1.NodeReady, Status: ready}},
+			},
+		}
+	}
+
+	nodes := []v1.Node{
+		newNode("small", "1Gi", v1.ConditionTrue),
+		newNode("large", "16Gi", v1.ConditionTrue),
+		newNode("broken", "8Gi", v1.ConditionFalse),
+	}
+
+	term, err := dataselect.NewFilterTerm(dataselect.StatusProperty, dataselect.FilterOperatorEqual, "Ready")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dsQuery := dataselect.NewDataSelectQuery(dataselect.NoPagination,
+		dataselect.NewSortQuery([]string{"d", dataselect.AllocatableMemoryProperty}),
+		dataselect.NewFilterExpressionQuery(dataselect.NoFilter, term), dataselect.NoMetrics)
+
+	cells, _ := dataselect.GenericDataSelectWithFilter(toCells(nodes), dsQuery)
+	actual := []string{}
+	for _, node := range fromCells(cells) {
+		actual = append(actual, node.Name)
+	}
+
+	expected := []string{"large", "small"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GenericDataSelectWithFilter() == %v, expected %v", actual, expected)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sClient "k8s.io/client-go/kubernetes"
+)
+
+// CordonNode marks the node with the given name as unschedulable, so that no new pods are placed on it.
+func CordonNode(client k8sClient.Interface, name string) (*v1.Node, error) {
+	log.Printf("Cordoning node %s", name)
+	return setNodeUnschedulable(client, name, true)
+}
+
+// UncordonNode marks the node with the given name as schedulable again.
+func UncordonNode(client k8sClient.Interface, name string) (*v1.Node, error) {
+	log.Printf("Uncordoning node %s", name)
+	return setNodeUnschedulable(client, name, false)
+}
+
+func setNodeUnschedulable(client k8sClient.Interface, name string, unschedulable bool) (*v1.Node, error) {
+	patch := []byte(fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable))
+	return client.CoreV1().Nodes().Patch(context.TODO(), name, types.StrategicMergePatchType, patch,
+		metaV1.PatchOptions{})
+}
@@ -0,0 +1,322 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8sClient "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+)
+
+const (
+	// defaultDrainTimeout is used when the drain spec does not set a timeout.
+	defaultDrainTimeout = 5 * time.Minute
+)
+
+var (
+	// evictionRetryInterval is the time to wait before an eviction blocked by a pod disruption budget is retried.
+	evictionRetryInterval = 5 * time.Second
+
+	// podDeletionPollInterval is the time between checks whether an evicted pod is gone.
+	podDeletionPollInterval = time.Second
+)
+
+// DrainPhase is the phase of a drain operation.
+type DrainPhase string
+
+// List of all drain operation phases.
+const (
+	DrainPhaseRunning   DrainPhase = "Running"
+	DrainPhaseSucceeded DrainPhase = "Succeeded"
+	DrainPhaseFailed    DrainPhase = "Failed"
+)
+
+// DrainPodPhase is the phase of a single pod during a drain operation.
+type DrainPodPhase string
+
+// List of all pod phases of a drain operation.
+const (
+	DrainPodPhasePending  DrainPodPhase = "Pending"
+	DrainPodPhaseBlocked  DrainPodPhase = "Blocked"
+	DrainPodPhaseEvicting DrainPodPhase = "Evicting"
+	DrainPodPhaseEvicted  DrainPodPhase = "Evicted"
+	DrainPodPhaseSkipped  DrainPodPhase = "Skipped"
+	DrainPodPhaseFailed   DrainPodPhase = "Failed"
+)
+
+// DrainSpec contains the options of a drain operation.
+type DrainSpec struct {
+	// GracePeriodSeconds overrides the termination grace period of evicted pods. Pod defaults are used if not set.
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
+
+	// TimeoutSeconds is the time after which the drain gives up. Defaults to 5 minutes.
+	TimeoutSeconds int64 `json:"timeoutSeconds"`
+}
+
+// DrainPod is the drain status of a single pod.
+type DrainPod struct {
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace"`
+	Phase     DrainPodPhase `json:"phase"`
+	Message   string        `json:"message,omitempty"`
+}
+
+// DrainOperation is the pollable status of a node drain.
+type DrainOperation struct {
+	NodeName       string       `json:"nodeName"`
+	Phase          DrainPhase   `json:"phase"`
+	Message        string       `json:"message,omitempty"`
+	StartTime      metaV1.Time  `json:"startTime"`
+	CompletionTime *metaV1.Time `json:"completionTime,omitempty"`
+	Pods           []DrainPod   `json:"pods"`
+}
+
+// drainOperations keeps the last drain operation of every node.
+var drainOperations = struct {
+	sync.RWMutex
+	operations map[string]*DrainOperation
+}{operations: make(map[string]*DrainOperation)}
+
+// GetDrainOperation returns the status of the last drain operation of the node with the given name. The node is
+// read first, so that operations are only visible to users that can see the node.
+func GetDrainOperation(client k8sClient.Interface, name string) (*DrainOperation, error) {
+	if _, err := client.CoreV1().Nodes().Get(context.TODO(), name, metaV1.GetOptions{}); err != nil {
+		return nil, err
+	}
+
+	drainOperations.RLock()
+	defer drainOperations.RUnlock()
+
+	operation, ok := drainOperations.operations[name]
+	if !ok {
+		return nil, errors.NewNotFound(fmt.Sprintf("No drain operation found for node %s", name))
+	}
+
+	return operation.copy(), nil
+}
+
+// DrainNode cordons the node with the given name and starts evicting its pods in the background. DaemonSet and
+// mirror pods are skipped, as they would be recreated on the node right away. Evictions blocked by a pod
+// disruption budget are retried until the timeout. Progress can be polled with GetDrainOperation.
+func DrainNode(client k8sClient.Interface, name string, spec *DrainSpec) (*DrainOperation, error) {
+	log.Printf("Draining node %s", name)
+
+	timeout := defaultDrainTimeout
+	if spec.TimeoutSeconds < 0 {
+		return nil, errors.NewInvalid("Drain timeout must not be negative")
+	} else if spec.TimeoutSeconds > 0 {
+		timeout = time.Duration(spec.TimeoutSeconds) * time.Second
+	}
+
+	operation := &DrainOperation{
+		NodeName:  name,
+		Phase:     DrainPhaseRunning,
+		StartTime: metaV1.Now(),
+		Pods:      make([]DrainPod, 0),
+	}
+
+	// The operation is registered before the node is cordoned, so that concurrent drains of the same node are
+	// rejected. The lock is not held during API calls, so that slow calls do not block other nodes.
+	drainOperations.Lock()
+	previous, ok := drainOperations.operations[name]
+	if ok && previous.Phase == DrainPhaseRunning {
+		drainOperations.Unlock()
+		return nil, errors.NewInvalid(fmt.Sprintf("Node %s is already being drained", name))
+	}
+	drainOperations.operations[name] = operation
+	drainOperations.Unlock()
+
+	pods, err := cordonAndListPods(client, name)
+	if err != nil {
+		drainOperations.Lock()
+		if previous != nil {
+			drainOperations.operations[name] = previous
+		} else {
+			delete(drainOperations.operations, name)
+		}
+		drainOperations.Unlock()
+		return nil, err
+	}
+
+	drainOperations.Lock()
+	defer drainOperations.Unlock()
+
+	toEvict := make([]v1.Pod, 0)
+	for _, item := range pods.Items {
+		drainPod := DrainPod{Name: item.Name, Namespace: item.Namespace, Phase: DrainPodPhasePending}
+		if reason := getDrainSkipReason(item); len(reason) > 0 {
+			drainPod.Phase = DrainPodPhaseSkipped
+			drainPod.Message = reason
+		} else {
+			toEvict = append(toEvict, item)
+		}
+		operation.Pods = append(operation.Pods, drainPod)
+	}
+
+	go evictPods(client, operation, toEvict, spec.GracePeriodSeconds, timeout)
+
+	return operation.copy(), nil
+}
+
+func cordonAndListPods(client k8sClient.Interface, name string) (*v1.PodList, error) {
+	node, err := CordonNode(client, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return getNodePods(client, *node)
+}
+
+// getDrainSkipReason returns why the pod is left on the node during a drain, or an empty string if the pod should
+// be evicted.
+func getDrainSkipReason(pod v1.Pod) string {
+	if _, ok := pod.Annotations[v1.MirrorPodAnnotationKey]; ok {
+		return "Mirror pod"
+	}
+
+	if controller := metaV1.GetControllerOf(&pod); controller != nil && controller.Kind == "DaemonSet" {
+		return "Managed by DaemonSet"
+	}
+
+	return ""
+}
+
+func evictPods(client k8sClient.Interface, operation *DrainOperation, pods []v1.Pod, gracePeriodSeconds *int64,
+	timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := range pods {
+		wg.Add(1)
+		go func(pod v1.Pod) {
+			defer wg.Done()
+			phase, message := evictPod(ctx, client, operation, pod, gracePeriodSeconds)
+			updateDrainPod(operation, pod, phase, message)
+		}(pods[i])
+	}
+	wg.Wait()
+
+	drainOperations.Lock()
+	defer drainOperations.Unlock()
+
+	failed := 0
+	for _, drainPod := range operation.Pods {
+		if drainPod.Phase == DrainPodPhaseFailed {
+			failed++
+		}
+	}
+
+	now := metaV1.Now()
+	operation.CompletionTime = &now
+	if failed > 0 {
+		operation.Phase = DrainPhaseFailed
+		operation.Message = fmt.Sprintf("%d pod(s) could not be evicted", failed)
+	} else {
+		operation.Phase = DrainPhaseSucceeded
+	}
+	log.Printf("Draining node %s finished with phase %s", operation.NodeName, operation.Phase)
+}
+
+// evictPod evicts the pod through the eviction subresource and waits until it is deleted. Evictions rejected
+// because of a pod disruption budget are retried until the context is done.
+func evictPod(ctx context.Context, client k8sClient.Interface, operation *DrainOperation, pod v1.Pod,
+	gracePeriodSeconds *int64) (DrainPodPhase, string) {
+	eviction := &policy.Eviction{
+		ObjectMeta:    metaV1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		DeleteOptions: &metaV1.DeleteOptions{GracePeriodSeconds: gracePeriodSeconds},
+	}
+
+	for {
+		err := client.PolicyV1beta1().Evictions(pod.Namespace).Evict(ctx, eviction)
+		if err == nil || k8serrors.IsNotFound(err) {
+			break
+		}
+
+		if !k8serrors.IsTooManyRequests(err) {
+			return DrainPodPhaseFailed, err.Error()
+		}
+
+		updateDrainPod(operation, pod, DrainPodPhaseBlocked, err.Error())
+		select {
+		case <-ctx.Done():
+			return DrainPodPhaseFailed, "Timed out waiting for pod disruption budget: " + err.Error()
+		case <-time.After(evictionRetryInterval):
+		}
+	}
+
+	updateDrainPod(operation, pod, DrainPodPhaseEvicting, "")
+	err := wait.PollImmediateUntil(podDeletionPollInterval, func() (bool, error) {
+		return isPodDeleted(ctx, client, pod.Namespace, pod.Name, pod.UID)
+	}, ctx.Done())
+	if err != nil {
+		if err == wait.ErrWaitTimeout {
+			return DrainPodPhaseFailed, "Timed out waiting for pod deletion"
+		}
+		return DrainPodPhaseFailed, err.Error()
+	}
+
+	return DrainPodPhaseEvicted, ""
+}
+
+// isPodDeleted checks whether the pod is gone. A pod with the same name but a different UID was recreated by its
+// controller and counts as deleted.
+func isPodDeleted(ctx context.Context, client k8sClient.Interface, namespace, name string,
+	uid types.UID) (bool, error) {
+	pod, err := client.CoreV1().Pods(namespace).Get(ctx, name, metaV1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return pod.UID != uid, nil
+}
+
+func updateDrainPod(operation *DrainOperation, pod v1.Pod, phase DrainPodPhase, message string) {
+	drainOperations.Lock()
+	defer drainOperations.Unlock()
+
+	for i := range operation.Pods {
+		if operation.Pods[i].Name == pod.Name && operation.Pods[i].Namespace == pod.Namespace {
+			operation.Pods[i].Phase = phase
+			operation.Pods[i].Message = message
+		}
+	}
+}
+
+func (operation *DrainOperation) copy() *DrainOperation {
+	result := *operation
+	result.Pods = make([]DrainPod, len(operation.Pods))
+	copy(result.Pods, operation.Pods)
+	if operation.CompletionTime != nil {
+		completionTime := *operation.CompletionTime
+		result.CompletionTime = &completionTime
+	}
+	return &result
+}
@@ -0,0 +1,207 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func getDrainTestPod(name string, annotations map[string]string, owners ...metaV1.OwnerReference) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			UID:             types.UID("uid-" + name),
+			Annotations:     annotations,
+			OwnerReferences: owners,
+		},
+		Spec: v1.PodSpec{NodeName: "test-node"},
+	}
+}
+
+// getDrainTestClient returns a client that deletes pods on eviction. Evictions of the "protected" pod are
+// rejected once, as if a pod disruption budget blocked them.
+func getDrainTestClient() *fake.Clientset {
+	isController := true
+	client := fake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "test-node"}},
+		getDrainTestPod("web", nil),
+		getDrainTestPod("protected", nil),
+		getDrainTestPod("static", map[string]string{v1.MirrorPodAnnotationKey: "hash"}),
+		getDrainTestPod("logger", nil, metaV1.OwnerReference{Kind: "DaemonSet", Name: "logger",
+			Controller: &isController}),
+	)
+
+	blocked := false
+	client.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+
+		eviction := action.(k8stesting.CreateAction).GetObject().(*policy.Eviction)
+		if eviction.Name == "protected" && !blocked {
+			blocked = true
+			return true, nil, k8serrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's "+
+				"disruption budget.", 1)
+		}
+
+		return true, nil, client.Tracker().Delete(v1.SchemeGroupVersion.WithResource("pods"),
+			eviction.Namespace, eviction.Name)
+	})
+
+	return client
+}
+
+func waitForDrain(t *testing.T, client *fake.Clientset, name string) *DrainOperation {
+	for i := 0; i < 300; i++ {
+		operation, err := GetDrainOperation(client, name)
+		if err != nil {
+			t.Fatalf("GetDrainOperation() returned error: %s", err)
+		}
+		if operation.Phase != DrainPhaseRunning {
+			return operation
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("Drain of node %s did not finish", name)
+	return nil
+}
+
+func TestDrainNode(t *testing.T) {
+	evictionRetryInterval = time.Millisecond
+	podDeletionPollInterval = time.Millisecond
+
+	client := getDrainTestClient()
+	operation, err := DrainNode(client, "test-node", &DrainSpec{})
+	if err != nil {
+		t.Fatalf("DrainNode() returned error: %s", err)
+	}
+	if operation.Phase != DrainPhaseRunning {
+		t.Errorf("DrainNode() returned phase %s, expected %s", operation.Phase, DrainPhaseRunning)
+	}
+
+	operation = waitForDrain(t, client, "test-node")
+	expected := []DrainPod{
+		{Name: "web", Namespace: "default", Phase: DrainPodPhaseEvicted},
+		{Name: "protected", Namespace: "default", Phase: DrainPodPhaseEvicted},
+		{Name: "static", Namespace: "default", Phase: DrainPodPhaseSkipped, Message: "Mirror pod"},
+		{Name: "logger", Namespace: "default", Phase: DrainPodPhaseSkipped, Message: "Managed by DaemonSet"},
+	}
+	if operation.Phase != DrainPhaseSucceeded || operation.CompletionTime == nil ||
+		!reflect.DeepEqual(operation.Pods, expected) {
+		t.Errorf("DrainNode() finished with %s, %#v, expected %s, %#v", operation.Phase, operation.Pods,
+			DrainPhaseSucceeded, expected)
+	}
+
+	node, _ := client.CoreV1().Nodes().Get(context.TODO(), "test-node", metaV1.GetOptions{})
+	if !node.Spec.Unschedulable {
+		t.Errorf("DrainNode() did not cordon the node")
+	}
+}
+
+func TestDrainNodeTimeout(t *testing.T) {
+	evictionRetryInterval = time.Millisecond
+	podDeletionPollInterval = time.Millisecond
+
+	client := fake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "blocked-node"}},
+		&v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "protected", Namespace: "default"},
+			Spec:       v1.PodSpec{NodeName: "blocked-node"},
+		},
+	)
+	client.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return action.GetSubresource() == "eviction", nil, k8serrors.NewTooManyRequests("blocked", 1)
+	})
+
+	if _, err := DrainNode(client, "blocked-node", &DrainSpec{TimeoutSeconds: 1}); err != nil {
+		t.Fatalf("DrainNode() returned error: %s", err)
+	}
+
+	if _, err := DrainNode(client, "blocked-node", &DrainSpec{}); err == nil {
+		t.Errorf("DrainNode() expected error for node that is already being drained")
+	}
+
+	operation := waitForDrain(t, client, "blocked-node")
+	if operation.Phase != DrainPhaseFailed || operation.Pods[0].Phase != DrainPodPhaseFailed {
+		t.Errorf("DrainNode() finished with %s, %#v, expected %s", operation.Phase, operation.Pods,
+			DrainPhaseFailed)
+	}
+}
+
+func TestGetDrainOperationNotFound(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "idle-node"}})
+	if _, err := GetDrainOperation(client, "idle-node"); err == nil {
+		t.Errorf("GetDrainOperation() expected error for node without drain operation")
+	}
+}
+
+func TestDrainNodeDoesNotBlockDuringAPICalls(t *testing.T) {
+	entered, release := make(chan struct{}), make(chan struct{})
+	client := fake.NewSimpleClientset(&v1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "slow-node"}})
+	client.PrependReactor("patch", "nodes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		close(entered)
+		<-release
+		return true, nil, k8serrors.NewServiceUnavailable("apiserver is slow")
+	})
+
+	result := make(chan error)
+	go func() {
+		_, err := DrainNode(client, "slow-node", &DrainSpec{})
+		result <- err
+	}()
+	<-entered
+
+	done := make(chan struct{})
+	go func() {
+		idleClient := fake.NewSimpleClientset(&v1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "idle-node"}})
+		_, _ = GetDrainOperation(idleClient, "idle-node")
+		if _, err := DrainNode(client, "slow-node", &DrainSpec{}); err == nil {
+			t.Errorf("DrainNode() expected error for node that is already being drained")
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Drain operations of other nodes are blocked by a running API call")
+	}
+
+	close(release)
+	if err := <-result; err == nil {
+		t.Fatalf("DrainNode() expected error when node cannot be cordoned")
+	}
+
+	drainOperations.RLock()
+	_, exists := drainOperations.operations["slow-node"]
+	drainOperations.RUnlock()
+	if exists {
+		t.Errorf("DrainNode() kept operation of node that could not be cordoned")
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/ycyxuehan/dashboard-gin/backend/api"
 	"github.com/ycyxuehan/dashboard-gin/backend/errors"
 	metricapi "github.com/ycyxuehan/dashboard-gin/backend/integration/metric/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
 )
 
@@ -58,6 +59,20 @@ func GetNodeList(client client.Interface, dsQuery *dataselect.DataSelectQuery, m
 	return toNodeList(client, nodes.Items, nonCriticalErrors, dsQuery, metricClient), nil
 }
 
+// GetNodeListFromChannels returns a list of all Nodes in the cluster reading required resource list once from the
+// channels. Client is still needed to get pods of each node on the requested page.
+func GetNodeListFromChannels(client client.Interface, channels *common.ResourceChannels,
+	dsQuery *dataselect.DataSelectQuery, metricClient metricapi.MetricClient) (*NodeList, error) {
+	nodes := <-channels.NodeList.List
+	err := <-channels.NodeList.Error
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	return toNodeList(client, nodes.Items, nonCriticalErrors, dsQuery, metricClient), nil
+}
+
 func toNodeList(client client.Interface, nodes []v1.Node, nonCriticalErrors []error, dsQuery *dataselect.DataSelectQuery,
 	metricClient metricapi.MetricClient) *NodeList {
 	nodeList := &NodeList{
@@ -0,0 +1,387 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	k8sClient "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+)
+
+// NodeMetadataSpec describes changes of node labels and taints. Taints are identified by key and effect, so adding
+// a taint with an existing key and effect updates its value. A taint to remove without an effect removes all taints
+// with the given key.
+type NodeMetadataSpec struct {
+	Labels       map[string]string `json:"labels,omitempty"`
+	RemoveLabels []string          `json:"removeLabels,omitempty"`
+	Taints       []v1.Taint        `json:"taints,omitempty"`
+	RemoveTaints []v1.Taint        `json:"removeTaints,omitempty"`
+}
+
+// NodeMetadataUpdate is the result of a label and taint update of one or more nodes.
+type NodeMetadataUpdate struct {
+	// DryRun is true if the nodes were not changed and the result is only a preview.
+	DryRun bool `json:"dryRun"`
+
+	Nodes []NodeMetadata `json:"nodes"`
+
+	// List of non-critical errors, that occurred while updating the nodes.
+	Errors []error `json:"errors"`
+}
+
+// NodeMetadata contains labels and taints of a node after the update, together with the pods that would be evicted
+// by added NoExecute taints.
+type NodeMetadata struct {
+	Name        string            `json:"name"`
+	Labels      map[string]string `json:"labels"`
+	Taints      []v1.Taint        `json:"taints"`
+	EvictedPods []TaintEvictedPod `json:"evictedPods"`
+}
+
+// TaintEvictedPod is a pod that does not tolerate a NoExecute taint added to its node.
+type TaintEvictedPod struct {
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace"`
+	Taint     v1.Taint `json:"taint"`
+
+	// TolerationSeconds is set if the pod tolerates the taint only for a limited time before it is evicted.
+	TolerationSeconds *int64 `json:"tolerationSeconds,omitempty"`
+
+	// CPURequests is number of allocated milicores.
+	CPURequests int64 `json:"cpuRequests"`
+
+	// MemoryRequests is number of allocated bytes.
+	MemoryRequests int64 `json:"memoryRequests"`
+}
+
+// UpdateNodeMetadata changes labels and taints of the node with the given name.
+func UpdateNodeMetadata(client k8sClient.Interface, name string, spec *NodeMetadataSpec,
+	dryRun bool) (*NodeMetadataUpdate, error) {
+	log.Printf("Updating labels and taints of node %s, dry run: %t", name, dryRun)
+
+	if err := validateNodeMetadataSpec(spec); err != nil {
+		return nil, err
+	}
+
+	node, err := client.CoreV1().Nodes().Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return updateNodesMetadata(client, []v1.Node{*node}, spec, dryRun)
+}
+
+// UpdateNodesMetadata changes labels and taints of all nodes matching the given label selector. An empty selector
+// is rejected, so that all nodes cannot be changed by accident.
+func UpdateNodesMetadata(client k8sClient.Interface, labelSelector string, spec *NodeMetadataSpec,
+	dryRun bool) (*NodeMetadataUpdate, error) {
+	log.Printf("Updating labels and taints of nodes selected by %s, dry run: %t", labelSelector, dryRun)
+
+	if len(strings.TrimSpace(labelSelector)) == 0 {
+		return nil, errors.NewInvalid("Label selector is required to update multiple nodes")
+	}
+
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, errors.NewInvalid(err.Error())
+	}
+
+	if err := validateNodeMetadataSpec(spec); err != nil {
+		return nil, err
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(context.TODO(), metaV1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updateNodesMetadata(client, nodes.Items, spec, dryRun)
+}
+
+func updateNodesMetadata(client k8sClient.Interface, nodes []v1.Node, spec *NodeMetadataSpec,
+	dryRun bool) (*NodeMetadataUpdate, error) {
+	result := &NodeMetadataUpdate{
+		DryRun: dryRun,
+		Nodes:  make([]NodeMetadata, 0),
+		Errors: make([]error, 0),
+	}
+
+	for _, node := range nodes {
+		metadata, err := updateNodeMetadata(client, node, spec, dryRun)
+		result.Errors, err = errors.AppendError(err, result.Errors)
+		if err != nil {
+			return nil, err
+		}
+
+		if metadata != nil {
+			result.Nodes = append(result.Nodes, *metadata)
+		}
+	}
+
+	return result, nil
+}
+
+func updateNodeMetadata(client k8sClient.Interface, node v1.Node, spec *NodeMetadataSpec,
+	dryRun bool) (*NodeMetadata, error) {
+	nodeLabels, labelPatch := applyLabels(node.Labels, spec)
+	taints, added := applyTaints(node.Spec.Taints, spec)
+
+	evictedPods, err := getTaintEvictedPods(client, node, added)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &NodeMetadata{
+		Name:        node.Name,
+		Labels:      nodeLabels,
+		Taints:      taints,
+		EvictedPods: evictedPods,
+	}
+
+	if dryRun || (len(labelPatch) == 0 && len(spec.Taints) == 0 && len(spec.RemoveTaints) == 0) {
+		return metadata, nil
+	}
+
+	patchMetadata := map[string]interface{}{"labels": labelPatch}
+	// Resource version makes the patch fail on concurrent changes, as the taint list is replaced as a whole.
+	if len(node.ResourceVersion) > 0 {
+		patchMetadata["resourceVersion"] = node.ResourceVersion
+	}
+
+	patch := map[string]interface{}{
+		"metadata": patchMetadata,
+		"spec":     map[string]interface{}{"taints": taints},
+	}
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := client.CoreV1().Nodes().Patch(context.TODO(), node.Name, types.StrategicMergePatchType, data,
+		metaV1.PatchOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	metadata.Labels = updated.Labels
+	metadata.Taints = updated.Spec.Taints
+	return metadata, nil
+}
+
+// applyLabels returns the labels after the update and the strategic merge patch of the labels. Removed labels are
+// set to nil in the patch.
+func applyLabels(current map[string]string, spec *NodeMetadataSpec) (map[string]string,
+	map[string]interface{}) {
+	result := make(map[string]string)
+	for key, value := range current {
+		result[key] = value
+	}
+
+	patch := make(map[string]interface{})
+	for _, key := range spec.RemoveLabels {
+		if _, ok := result[key]; ok {
+			delete(result, key)
+			patch[key] = nil
+		}
+	}
+
+	for key, value := range spec.Labels {
+		if current, ok := result[key]; !ok || current != value {
+			result[key] = value
+			patch[key] = value
+		}
+	}
+
+	return result, patch
+}
+
+// applyTaints returns the taints after the update and the NoExecute taints that were not present before.
+func applyTaints(current []v1.Taint, spec *NodeMetadataSpec) ([]v1.Taint, []v1.Taint) {
+	result := make([]v1.Taint, 0)
+	for _, taint := range current {
+		if !isTaintRemoved(taint, spec.RemoveTaints) {
+			result = append(result, taint)
+		}
+	}
+
+	added := make([]v1.Taint, 0)
+	for _, taint := range spec.Taints {
+		taint := taint
+		updated := false
+		for i := range result {
+			if result[i].MatchTaint(&taint) {
+				result[i] = taint
+				updated = true
+			}
+		}
+
+		if !updated {
+			result = append(result, taint)
+		}
+
+		if taint.Effect == v1.TaintEffectNoExecute && !containsTaint(current, taint) {
+			added = append(added, taint)
+		}
+	}
+
+	return result, added
+}
+
+func isTaintRemoved(taint v1.Taint, removed []v1.Taint) bool {
+	for _, item := range removed {
+		if item.Key == taint.Key && (len(item.Effect) == 0 || item.Effect == taint.Effect) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsTaint checks whether the same taint, including its value, is already present.
+func containsTaint(taints []v1.Taint, taint v1.Taint) bool {
+	for _, item := range taints {
+		if item.MatchTaint(&taint) && item.Value == taint.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// getTaintEvictedPods returns pods on the node that do not tolerate at least one of the given NoExecute taints.
+func getTaintEvictedPods(client k8sClient.Interface, node v1.Node, taints []v1.Taint) ([]TaintEvictedPod, error) {
+	result := make([]TaintEvictedPod, 0)
+	if len(taints) == 0 {
+		return result, nil
+	}
+
+	pods, err := getNodePods(client, node)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pod := range pods.Items {
+		taint, tolerationSeconds, evicted := getNotToleratedTaint(pod, taints)
+		if !evicted {
+			continue
+		}
+
+		reqs, _, err := PodRequestsAndLimits(&pod)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, TaintEvictedPod{
+			Name:              pod.Name,
+			Namespace:         pod.Namespace,
+			Taint:             taint,
+			TolerationSeconds: tolerationSeconds,
+			CPURequests:       reqs.Cpu().MilliValue(),
+			MemoryRequests:    reqs.Memory().Value(),
+		})
+	}
+
+	return result, nil
+}
+
+// getNotToleratedTaint returns the taint that evicts the pod. Pods that tolerate the taints only for a limited time
+// are evicted too, after the shortest of their toleration periods.
+func getNotToleratedTaint(pod v1.Pod, taints []v1.Taint) (v1.Taint, *int64, bool) {
+	var limited *v1.Taint
+	var limitedSeconds *int64
+
+	for i := range taints {
+		tolerated, seconds := getTolerationSeconds(pod.Spec.Tolerations, &taints[i])
+		if !tolerated {
+			return taints[i], nil, true
+		}
+
+		if seconds != nil && (limitedSeconds == nil || *seconds < *limitedSeconds) {
+			limited = &taints[i]
+			limitedSeconds = seconds
+		}
+	}
+
+	if limited != nil {
+		return *limited, limitedSeconds, true
+	}
+
+	return v1.Taint{}, nil, false
+}
+
+// getTolerationSeconds returns whether the taint is tolerated and for how long. No seconds mean forever.
+func getTolerationSeconds(tolerations []v1.Toleration, taint *v1.Taint) (bool, *int64) {
+	tolerated := false
+	var seconds *int64
+
+	for _, toleration := range tolerations {
+		if !toleration.ToleratesTaint(taint) {
+			continue
+		}
+
+		if toleration.TolerationSeconds == nil {
+			return true, nil
+		}
+
+		tolerated = true
+		if seconds == nil || *toleration.TolerationSeconds < *seconds {
+			value := *toleration.TolerationSeconds
+			seconds = &value
+		}
+	}
+
+	return tolerated, seconds
+}
+
+func validateNodeMetadataSpec(spec *NodeMetadataSpec) error {
+	for key, value := range spec.Labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return errors.NewInvalid(fmt.Sprintf("Invalid label key %s: %s", key, strings.Join(errs, "; ")))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return errors.NewInvalid(fmt.Sprintf("Invalid label value %s: %s", value, strings.Join(errs, "; ")))
+		}
+	}
+
+	for _, taint := range spec.Taints {
+		if errs := validation.IsQualifiedName(taint.Key); len(errs) > 0 {
+			return errors.NewInvalid(fmt.Sprintf("Invalid taint key %s: %s", taint.Key, strings.Join(errs, "; ")))
+		}
+		if errs := validation.IsValidLabelValue(taint.Value); len(errs) > 0 {
+			return errors.NewInvalid(fmt.Sprintf("Invalid taint value %s: %s", taint.Value,
+				strings.Join(errs, "; ")))
+		}
+
+		switch taint.Effect {
+		case v1.TaintEffectNoSchedule, v1.TaintEffectPreferNoSchedule, v1.TaintEffectNoExecute:
+		default:
+			return errors.NewInvalid(fmt.Sprintf("Invalid taint effect %s", taint.Effect))
+		}
+	}
+
+	return nil
+}
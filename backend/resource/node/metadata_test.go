@@ -0,0 +1,160 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+)
+
+func getMetadataTestClient() *fake.Clientset {
+	tolerationSeconds := int64(60)
+	return fake.NewSimpleClientset(
+		&v1.Node{
+			ObjectMeta: metaV1.ObjectMeta{Name: "node-1", Labels: map[string]string{"pool": "a", "zone": "x"}},
+			Spec: v1.NodeSpec{Taints: []v1.Taint{
+				{Key: "dedicated", Value: "db", Effect: v1.TaintEffectNoSchedule},
+				{Key: "gpu", Effect: v1.TaintEffectPreferNoSchedule},
+			}},
+		},
+		&v1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "node-2", Labels: map[string]string{"pool": "b"}}},
+		&v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: v1.PodSpec{
+				NodeName: "node-1",
+				Containers: []v1.Container{{Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("250m"),
+					v1.ResourceMemory: resource.MustParse("64Mi"),
+				}}}},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec: v1.PodSpec{
+				NodeName:    "node-1",
+				Tolerations: []v1.Toleration{{Key: "maintenance", Operator: v1.TolerationOpExists}},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "cache", Namespace: "default"},
+			Spec: v1.PodSpec{
+				NodeName: "node-1",
+				Tolerations: []v1.Toleration{{Key: "maintenance", Operator: v1.TolerationOpExists,
+					Effect: v1.TaintEffectNoExecute, TolerationSeconds: &tolerationSeconds}},
+			},
+		},
+	)
+}
+
+func TestUpdateNodeMetadata(t *testing.T) {
+	client := getMetadataTestClient()
+	spec := &NodeMetadataSpec{
+		Labels:       map[string]string{"pool": "c"},
+		RemoveLabels: []string{"zone"},
+		Taints:       []v1.Taint{{Key: "dedicated", Value: "cache", Effect: v1.TaintEffectNoSchedule}},
+		RemoveTaints: []v1.Taint{{Key: "gpu"}},
+	}
+
+	result, err := UpdateNodeMetadata(client, "node-1", spec, false)
+	if err != nil {
+		t.Fatalf("UpdateNodeMetadata() returned error: %s", err)
+	}
+
+	node, _ := client.CoreV1().Nodes().Get(context.TODO(), "node-1", metaV1.GetOptions{})
+	expectedLabels := map[string]string{"pool": "c"}
+	expectedTaints := []v1.Taint{{Key: "dedicated", Value: "cache", Effect: v1.TaintEffectNoSchedule}}
+	if !reflect.DeepEqual(node.Labels, expectedLabels) || !reflect.DeepEqual(node.Spec.Taints, expectedTaints) {
+		t.Errorf("UpdateNodeMetadata() changed node to %v, %v, expected %v, %v", node.Labels, node.Spec.Taints,
+			expectedLabels, expectedTaints)
+	}
+
+	if result.DryRun || len(result.Nodes) != 1 || len(result.Nodes[0].EvictedPods) != 0 {
+		t.Errorf("UpdateNodeMetadata() == %#v, expected single node without evicted pods", result)
+	}
+}
+
+func TestUpdateNodeMetadataDryRun(t *testing.T) {
+	client := getMetadataTestClient()
+	taint := v1.Taint{Key: "maintenance", Effect: v1.TaintEffectNoExecute}
+	tolerationSeconds := int64(60)
+
+	result, err := UpdateNodeMetadata(client, "node-1", &NodeMetadataSpec{Taints: []v1.Taint{taint}}, true)
+	if err != nil {
+		t.Fatalf("UpdateNodeMetadata() returned error: %s", err)
+	}
+
+	expected := []TaintEvictedPod{
+		{Name: "web", Namespace: "default", Taint: taint, CPURequests: 250, MemoryRequests: 64 * 1024 * 1024},
+		{Name: "cache", Namespace: "default", Taint: taint, TolerationSeconds: &tolerationSeconds},
+	}
+	if !result.DryRun || !reflect.DeepEqual(result.Nodes[0].EvictedPods, expected) {
+		t.Errorf("UpdateNodeMetadata() == %#v, expected %#v", result.Nodes[0].EvictedPods, expected)
+	}
+
+	node, _ := client.CoreV1().Nodes().Get(context.TODO(), "node-1", metaV1.GetOptions{})
+	if len(node.Spec.Taints) != 2 {
+		t.Errorf("UpdateNodeMetadata() changed node taints on dry run: %v", node.Spec.Taints)
+	}
+}
+
+func TestUpdateNodesMetadata(t *testing.T) {
+	client := getMetadataTestClient()
+	spec := &NodeMetadataSpec{Labels: map[string]string{"maintenance": "true"}}
+
+	result, err := UpdateNodesMetadata(client, "pool=b", spec, false)
+	if err != nil {
+		t.Fatalf("UpdateNodesMetadata() returned error: %s", err)
+	}
+	if len(result.Nodes) != 1 || result.Nodes[0].Name != "node-2" {
+		t.Errorf("UpdateNodesMetadata() == %#v, expected only node-2", result.Nodes)
+	}
+
+	node, _ := client.CoreV1().Nodes().Get(context.TODO(), "node-1", metaV1.GetOptions{})
+	if _, ok := node.Labels["maintenance"]; ok {
+		t.Errorf("UpdateNodesMetadata() changed node that does not match the selector")
+	}
+}
+
+func TestUpdateNodesMetadataValidation(t *testing.T) {
+	cases := []struct {
+		selector string
+		spec     *NodeMetadataSpec
+		expected error
+	}{
+		{
+			"", &NodeMetadataSpec{},
+			errors.NewInvalid("Label selector is required to update multiple nodes"),
+		},
+		{
+			"pool=a", &NodeMetadataSpec{Taints: []v1.Taint{{Key: "dedicated", Effect: "Sometimes"}}},
+			errors.NewInvalid("Invalid taint effect Sometimes"),
+		},
+	}
+
+	for _, c := range cases {
+		_, err := UpdateNodesMetadata(getMetadataTestClient(), c.selector, c.spec, false)
+		if !reflect.DeepEqual(err, c.expected) {
+			t.Errorf("UpdateNodesMetadata(%s) == %#v, expected %#v", c.selector, err, c.expected)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permission
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbac "k8s.io/api/rbac/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+)
+
+const (
+	// capabilityCacheTTL is the time a capability summary is reused for the same credentials and namespace.
+	capabilityCacheTTL = 30 * time.Second
+
+	// maxConcurrentReviews limits the number of access reviews that are sent at the same time.
+	maxConcurrentReviews = 10
+)
+
+// Action is an action the UI offers on resources.
+type Action string
+
+// List of actions covered by the capability summary.
+const (
+	ActionList   Action = "list"
+	ActionGet    Action = "get"
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+	ActionExec   Action = "exec"
+	ActionLogs   Action = "logs"
+	ActionScale  Action = "scale"
+)
+
+// clientTypeGroups maps the client types of api.KindToAPIMapping to the API groups the clients use.
+var clientTypeGroups = map[api.ClientType]string{
+	api.ClientTypeDefault:             "",
+	api.ClientTypeExtensionClient:     "extensions",
+	api.ClientTypeAppsClient:          "apps",
+	api.ClientTypeBatchClient:         "batch",
+	api.ClientTypeBetaBatchClient:     "batch",
+	api.ClientTypeAutoscalingClient:   "autoscaling",
+	api.ClientTypeStorageClient:       "storage.k8s.io",
+	api.ClientTypeRbacClient:          rbac.GroupName,
+	api.ClientTypeAPIExtensionsClient: "apiextensions.k8s.io",
+	api.ClientTypeNetworkingClient:    "networking.k8s.io",
+	api.ClientTypePolicyClient:        "policy",
+	api.ClientTypePluginsClient:       "dashboard.k8s.io",
+}
+
+// now is replaced in tests to check cache expiry.
+var now = time.Now
+
+// CapabilitySummary lists the actions the caller can perform on every resource kind in a namespace. Cluster scoped
+// kinds are checked without a namespace.
+type CapabilitySummary struct {
+	Namespace string `json:"namespace"`
+
+	// Capabilities maps resource kinds to the actions that apply to them and whether the caller may perform them.
+	Capabilities map[string]map[Action]bool `json:"capabilities"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// capabilityCheck is a single action on a resource kind together with the request it needs.
+type capabilityCheck struct {
+	kind       string
+	action     Action
+	attributes ResourceAttributes
+}
+
+type capabilityCacheEntry struct {
+	summary *CapabilitySummary
+	expires time.Time
+}
+
+// capabilityCache keeps summaries per credentials and namespace. Keys never contain the credentials themselves.
+var capabilityCache = struct {
+	sync.Mutex
+	entries map[string]capabilityCacheEntry
+}{entries: make(map[string]capabilityCacheEntry)}
+
+// GetCapabilitySummary returns the actions the caller can perform on all kinds in api.KindToAPIMapping. Namespaced
+// kinds are answered from a single self subject rules review. Access reviews are only sent for cluster scoped
+// kinds, and for actions the rules do not allow if the API server reports the rules as incomplete, e.g. because a
+// webhook authorizer is used. Summaries are cached for a short time under the given key, which identifies the
+// credentials of the caller. Nothing is cached if the key is empty.
+func GetCapabilitySummary(client client.Interface, cacheKey, namespace string) (*CapabilitySummary, error) {
+	if len(namespace) == 0 {
+		namespace = metaV1.NamespaceDefault
+	}
+
+	cacheKey = cacheKey + "/" + namespace
+	if summary := getCachedSummary(cacheKey); summary != nil {
+		return summary, nil
+	}
+	log.Printf("Getting capability summary in %s namespace", namespace)
+
+	rules, complete, nonCriticalErrors, err := getRules(client, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &CapabilitySummary{
+		Namespace:    namespace,
+		Capabilities: make(map[string]map[Action]bool),
+	}
+
+	pending := make([]capabilityCheck, 0)
+	for _, check := range getCapabilityChecks(namespace) {
+		if _, ok := summary.Capabilities[check.kind]; !ok {
+			summary.Capabilities[check.kind] = make(map[Action]bool)
+		}
+
+		if len(check.attributes.Namespace) > 0 {
+			allowed := rulesAllow(rules, check.attributes)
+			if allowed || complete {
+				summary.Capabilities[check.kind][check.action] = allowed
+				continue
+			}
+		}
+		pending = append(pending, check)
+	}
+
+	allowed, nonCriticalErrors, err := reviewAll(client, pending, nonCriticalErrors)
+	if err != nil {
+		return nil, err
+	}
+	for i, check := range pending {
+		summary.Capabilities[check.kind][check.action] = allowed[i]
+	}
+
+	summary.Errors = nonCriticalErrors
+	if len(cacheKey) > len(namespace)+1 {
+		setCachedSummary(cacheKey, summary)
+	}
+	return summary, nil
+}
+
+// getCapabilityChecks returns the checks for all kinds. Exec and logs only apply to pods, scale to scalable kinds.
+func getCapabilityChecks(namespace string) []capabilityCheck {
+	checks := make([]capabilityCheck, 0)
+	for kind, mapping := range api.KindToAPIMapping {
+		base := ResourceAttributes{APIGroup: clientTypeGroups[mapping.ClientType], Resource: mapping.Resource}
+		if mapping.Namespaced {
+			base.Namespace = namespace
+		}
+
+		add := func(action Action, verb, subresource string) {
+			attributes := base
+			attributes.Verb = verb
+			attributes.Subresource = subresource
+			checks = append(checks, capabilityCheck{kind: kind, action: action, attributes: attributes})
+		}
+
+		for _, action := range []Action{ActionList, ActionGet, ActionCreate, ActionUpdate, ActionDelete} {
+			add(action, string(action), "")
+		}
+
+		if kind == api.ResourceKindPod {
+			add(ActionExec, "create", "exec")
+			add(ActionLogs, "get", "log")
+		}
+
+		if api.ResourceKind(kind).Scalable() {
+			add(ActionScale, "update", "scale")
+		}
+	}
+	return checks
+}
+
+// getRules returns the rules of the caller in the namespace and whether they are complete. Rules that cannot be
+// reviewed are treated as incomplete.
+func getRules(client client.Interface, namespace string) ([]rbac.PolicyRule, bool, []error, error) {
+	review := &authorizationv1.SelfSubjectRulesReview{
+		Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+	}
+
+	result, err := client.AuthorizationV1().SelfSubjectRulesReviews().Create(context.TODO(), review,
+		metaV1.CreateOptions{})
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, false, nil, criticalError
+	}
+	if err != nil {
+		return nil, false, nonCriticalErrors, nil
+	}
+
+	rules := make([]rbac.PolicyRule, 0, len(result.Status.ResourceRules))
+	for _, rule := range result.Status.ResourceRules {
+		rules = append(rules, rbac.PolicyRule{
+			Verbs:         rule.Verbs,
+			APIGroups:     rule.APIGroups,
+			Resources:     rule.Resources,
+			ResourceNames: rule.ResourceNames,
+		})
+	}
+
+	return rules, !result.Status.Incomplete, nonCriticalErrors, nil
+}
+
+// reviewAll sends a self subject access review for every check, with at most maxConcurrentReviews at the same time.
+func reviewAll(client client.Interface, checks []capabilityCheck, nonCriticalErrors []error) ([]bool, []error,
+	error) {
+	allowed := make([]bool, len(checks))
+	reviewErrors := make([]error, len(checks))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, maxConcurrentReviews)
+	for i := range checks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			result, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(context.TODO(),
+				toSelfSubjectAccessReview(checks[i].attributes), metaV1.CreateOptions{})
+			if err != nil {
+				reviewErrors[i] = err
+				return
+			}
+			allowed[i] = result.Status.Allowed
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range reviewErrors {
+		var criticalError error
+		nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+		if criticalError != nil {
+			return nil, nil, criticalError
+		}
+	}
+	return allowed, nonCriticalErrors, nil
+}
+
+func getCachedSummary(key string) *CapabilitySummary {
+	capabilityCache.Lock()
+	defer capabilityCache.Unlock()
+
+	entry, ok := capabilityCache.entries[key]
+	if !ok || now().After(entry.expires) {
+		return nil
+	}
+	return entry.summary
+}
+
+// setCachedSummary stores the summary and drops expired entries, so that the cache does not grow with every token
+// that was ever used.
+func setCachedSummary(key string, summary *CapabilitySummary) {
+	capabilityCache.Lock()
+	defer capabilityCache.Unlock()
+
+	current := now()
+	for existing, entry := range capabilityCache.entries {
+		if current.After(entry.expires) {
+			delete(capabilityCache.entries, existing)
+		}
+	}
+	capabilityCache.entries[key] = capabilityCacheEntry{summary: summary, expires: current.Add(capabilityCacheTTL)}
+}
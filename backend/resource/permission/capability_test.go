@@ -0,0 +1,179 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permission
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+)
+
+// newCapabilityClient returns a client that answers rules reviews with the given rules and allows access reviews
+// for nodes only. The counters are increased for every review.
+func newCapabilityClient(rules []authorizationv1.ResourceRule, incomplete bool, rulesReviews,
+	accessReviews *int32) *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "selfsubjectrulesreviews",
+		func(action clienttesting.Action) (bool, runtime.Object, error) {
+			atomic.AddInt32(rulesReviews, 1)
+			review := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectRulesReview)
+			review.Status = authorizationv1.SubjectRulesReviewStatus{ResourceRules: rules, Incomplete: incomplete}
+			return true, review, nil
+		})
+	client.PrependReactor("create", "selfsubjectaccessreviews",
+		func(action clienttesting.Action) (bool, runtime.Object, error) {
+			atomic.AddInt32(accessReviews, 1)
+			review := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+			attributes := review.Spec.ResourceAttributes
+			review.Status.Allowed = attributes.Resource == "nodes" ||
+				(attributes.Resource == "secrets" && attributes.Verb == "get")
+			return true, review, nil
+		})
+	return client
+}
+
+func TestGetCapabilitySummary(t *testing.T) {
+	rules := []authorizationv1.ResourceRule{
+		{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods", "pods/log"}},
+		{Verbs: []string{"*"}, APIGroups: []string{"apps"}, Resources: []string{"deployments/scale"}},
+	}
+
+	cases := []struct {
+		name                  string
+		incomplete            bool
+		expected              map[string]map[Action]bool
+		expectedAccessReviews bool
+	}{
+		{
+			"complete rules",
+			false,
+			map[string]map[Action]bool{
+				api.ResourceKindPod: {
+					ActionList: true, ActionGet: true, ActionCreate: false, ActionUpdate: false,
+					ActionDelete: false, ActionExec: false, ActionLogs: true,
+				},
+				api.ResourceKindDeployment: {
+					ActionList: false, ActionGet: false, ActionCreate: false, ActionUpdate: false,
+					ActionDelete: false, ActionScale: true,
+				},
+				api.ResourceKindSecret: {
+					ActionList: false, ActionGet: false, ActionCreate: false, ActionUpdate: false,
+					ActionDelete: false,
+				},
+				api.ResourceKindNode: {
+					ActionList: true, ActionGet: true, ActionCreate: true, ActionUpdate: true,
+					ActionDelete: true,
+				},
+			},
+			false,
+		},
+		{
+			"incomplete rules",
+			true,
+			map[string]map[Action]bool{
+				api.ResourceKindSecret: {
+					ActionList: false, ActionGet: true, ActionCreate: false, ActionUpdate: false,
+					ActionDelete: false,
+				},
+			},
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var rulesReviews, accessReviews int32
+			client := newCapabilityClient(rules, c.incomplete, &rulesReviews, &accessReviews)
+
+			actual, err := GetCapabilitySummary(client, "", "ns-1")
+			if err != nil {
+				t.Fatalf("GetCapabilitySummary() unexpected error: %v", err)
+			}
+
+			if len(actual.Capabilities) != len(api.KindToAPIMapping) {
+				t.Errorf("expected %d kinds, got %d", len(api.KindToAPIMapping), len(actual.Capabilities))
+			}
+			for kind, expected := range c.expected {
+				if len(actual.Capabilities[kind]) != len(expected) {
+					t.Errorf("expected actions %v for %s, got %v", expected, kind, actual.Capabilities[kind])
+				}
+				for action, allowed := range expected {
+					if actual.Capabilities[kind][action] != allowed {
+						t.Errorf("expected %s on %s to be %v", action, kind, allowed)
+					}
+				}
+			}
+
+			if rulesReviews != 1 {
+				t.Errorf("expected 1 rules review, got %d", rulesReviews)
+			}
+			namespacedReviews := accessReviews > int32(countClusterScopedChecks())
+			if namespacedReviews != c.expectedAccessReviews {
+				t.Errorf("expected namespaced access reviews to be %v, got %d reviews", c.expectedAccessReviews,
+					accessReviews)
+			}
+		})
+	}
+}
+
+func TestGetCapabilitySummaryCache(t *testing.T) {
+	defer func() { now = time.Now }()
+	current := time.Now()
+	now = func() time.Time { return current }
+
+	var rulesReviews, accessReviews int32
+	client := newCapabilityClient(nil, false, &rulesReviews, &accessReviews)
+
+	for _, namespace := range []string{"ns-1", "ns-1", "ns-2"} {
+		if _, err := GetCapabilitySummary(client, "user-a", namespace); err != nil {
+			t.Fatalf("GetCapabilitySummary() unexpected error: %v", err)
+		}
+	}
+	if rulesReviews != 2 {
+		t.Errorf("expected 2 rules reviews for two namespaces, got %d", rulesReviews)
+	}
+
+	if _, err := GetCapabilitySummary(client, "user-b", "ns-1"); err != nil {
+		t.Fatalf("GetCapabilitySummary() unexpected error: %v", err)
+	}
+	if rulesReviews != 3 {
+		t.Errorf("expected a rules review for another user, got %d reviews", rulesReviews)
+	}
+
+	current = current.Add(capabilityCacheTTL + time.Second)
+	if _, err := GetCapabilitySummary(client, "user-a", "ns-1"); err != nil {
+		t.Fatalf("GetCapabilitySummary() unexpected error: %v", err)
+	}
+	if rulesReviews != 4 {
+		t.Errorf("expected a rules review after the cache expired, got %d reviews", rulesReviews)
+	}
+}
+
+func countClusterScopedChecks() int {
+	count := 0
+	for _, check := range getCapabilityChecks("ns-1") {
+		if len(check.attributes.Namespace) == 0 {
+			count++
+		}
+	}
+	return count
+}
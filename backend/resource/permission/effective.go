@@ -0,0 +1,114 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permission
+
+import (
+	"log"
+	"sort"
+
+	client "k8s.io/client-go/kubernetes"
+)
+
+// NamespacePermissions are the rules granted to a subject by role bindings in a single namespace.
+type NamespacePermissions struct {
+	Namespace string          `json:"namespace"`
+	Rules     []EffectiveRule `json:"rules"`
+}
+
+// EffectivePermissions are all rules granted to a subject through RBAC bindings.
+type EffectivePermissions struct {
+	Subject Subject `json:"subject"`
+
+	// Groups used to match group subjects of bindings, including implicit groups.
+	Groups []string `json:"groups"`
+
+	// Rules granted by cluster role bindings. They apply to cluster scoped resources and to all namespaces.
+	ClusterRules []EffectiveRule `json:"clusterRules"`
+
+	// Rules granted by role bindings, per namespace. Cluster rules are not repeated here.
+	Namespaces []NamespacePermissions `json:"namespaces"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetEffectivePermissions resolves all bindings that refer to the subject, or to one of its groups, into effective
+// rules. Role bindings are read from the given namespace only, or from all namespaces if it is empty. Only RBAC
+// objects the client can read are taken into account, which is reported through the non-critical errors.
+func GetEffectivePermissions(client client.Interface, subject Subject, groups []string,
+	namespace string) (*EffectivePermissions, error) {
+	if err := subject.Validate(); err != nil {
+		return nil, err
+	}
+	log.Printf("Getting effective permissions of %s %s", subject.Kind, subject.Name)
+
+	bindings, nonCriticalErrors, err := getBindings(client, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return toEffectivePermissions(bindings, subject, getGroups(subject, groups), nonCriticalErrors), nil
+}
+
+func toEffectivePermissions(bindings []binding, subject Subject, groups []string,
+	nonCriticalErrors []error) *EffectivePermissions {
+	clusterRules := newRuleSet()
+	namespaceRules := make(map[string]*ruleSet)
+
+	for _, item := range bindings {
+		if !bindsSubject(item, subject, groups) {
+			continue
+		}
+
+		set := clusterRules
+		if len(item.reference.Namespace) > 0 {
+			set = namespaceRules[item.reference.Namespace]
+			if set == nil {
+				set = newRuleSet()
+				namespaceRules[item.reference.Namespace] = set
+			}
+		}
+
+		for _, rule := range item.rules {
+			set.add(rule, item.reference)
+		}
+	}
+
+	result := &EffectivePermissions{
+		Subject:      subject,
+		Groups:       groups,
+		ClusterRules: clusterRules.list(),
+		Namespaces:   make([]NamespacePermissions, 0, len(namespaceRules)),
+		Errors:       nonCriticalErrors,
+	}
+
+	for namespace, set := range namespaceRules {
+		result.Namespaces = append(result.Namespaces, NamespacePermissions{Namespace: namespace, Rules: set.list()})
+	}
+	sort.Slice(result.Namespaces, func(i, j int) bool {
+		return result.Namespaces[i].Namespace < result.Namespaces[j].Namespace
+	})
+
+	return result
+}
+
+func bindsSubject(item binding, subject Subject, groups []string) bool {
+	for _, bindingSubject := range item.subjects {
+		if appliesTo(bindingSubject, item.reference.Namespace, subject, groups) {
+			return true
+		}
+	}
+	return false
+}
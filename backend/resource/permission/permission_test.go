@@ -0,0 +1,243 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permission
+
+import (
+	"reflect"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbac "k8s.io/api/rbac/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var (
+	podReaderRules = []rbac.PolicyRule{
+		{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+	}
+	deployerRules = []rbac.PolicyRule{
+		{Verbs: []string{"*"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}},
+	}
+)
+
+func getTestObjects() []runtime.Object {
+	return []runtime.Object{
+		&rbac.ClusterRole{ObjectMeta: metaV1.ObjectMeta{Name: "pod-reader"}, Rules: podReaderRules},
+		&rbac.Role{ObjectMeta: metaV1.ObjectMeta{Name: "deployer", Namespace: "ns-1"}, Rules: deployerRules},
+		&rbac.ClusterRoleBinding{
+			ObjectMeta: metaV1.ObjectMeta{Name: "all-pod-readers"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "pod-reader"},
+			Subjects:   []rbac.Subject{{Kind: rbac.GroupKind, Name: "system:serviceaccounts:ns-1"}},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: metaV1.ObjectMeta{Name: "deployers", Namespace: "ns-1"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "deployer"},
+			Subjects: []rbac.Subject{
+				{Kind: rbac.ServiceAccountKind, Name: "ci"},
+				{Kind: rbac.UserKind, Name: "alice"},
+			},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: metaV1.ObjectMeta{Name: "readers", Namespace: "ns-2"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "pod-reader"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "system:serviceaccount:ns-1:ci"}},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: metaV1.ObjectMeta{Name: "dangling", Namespace: "ns-2"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "missing"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "alice"}},
+		},
+	}
+}
+
+func TestGetEffectivePermissions(t *testing.T) {
+	clusterBinding := BindingReference{Kind: "ClusterRoleBinding", Name: "all-pod-readers",
+		RoleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "pod-reader"}}
+	deployersBinding := BindingReference{Kind: "RoleBinding", Name: "deployers", Namespace: "ns-1",
+		RoleRef: rbac.RoleRef{Kind: "Role", Name: "deployer"}}
+	readersBinding := BindingReference{Kind: "RoleBinding", Name: "readers", Namespace: "ns-2",
+		RoleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "pod-reader"}}
+
+	cases := []struct {
+		info      string
+		subject   Subject
+		namespace string
+		expected  *EffectivePermissions
+	}{
+		{
+			"service account through group, namespace defaulting and user name",
+			Subject{Kind: rbac.ServiceAccountKind, Name: "ci", Namespace: "ns-1"},
+			"",
+			&EffectivePermissions{
+				Subject: Subject{Kind: rbac.ServiceAccountKind, Name: "ci", Namespace: "ns-1"},
+				Groups: []string{"system:authenticated", "system:serviceaccounts",
+					"system:serviceaccounts:ns-1"},
+				ClusterRules: []EffectiveRule{
+					{PolicyRule: podReaderRules[0], Bindings: []BindingReference{clusterBinding}},
+				},
+				Namespaces: []NamespacePermissions{
+					{Namespace: "ns-1", Rules: []EffectiveRule{
+						{PolicyRule: deployerRules[0], Bindings: []BindingReference{deployersBinding}},
+					}},
+					{Namespace: "ns-2", Rules: []EffectiveRule{
+						{PolicyRule: podReaderRules[0], Bindings: []BindingReference{readersBinding}},
+					}},
+				},
+			},
+		},
+		{
+			"user restricted to namespace",
+			Subject{Kind: rbac.UserKind, Name: "alice"},
+			"ns-1",
+			&EffectivePermissions{
+				Subject:      Subject{Kind: rbac.UserKind, Name: "alice"},
+				Groups:       []string{"system:authenticated"},
+				ClusterRules: []EffectiveRule{},
+				Namespaces: []NamespacePermissions{
+					{Namespace: "ns-1", Rules: []EffectiveRule{
+						{PolicyRule: deployerRules[0], Bindings: []BindingReference{deployersBinding}},
+					}},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.info, func(t *testing.T) {
+			fakeClient := fake.NewSimpleClientset(getTestObjects()...)
+			actual, err := GetEffectivePermissions(fakeClient, c.subject, nil, c.namespace)
+			if err != nil {
+				t.Fatalf("GetEffectivePermissions() returned error: %v", err)
+			}
+
+			errs := actual.Errors
+			actual.Errors = nil
+			if !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf("GetEffectivePermissions() == \ngot: %#v, \nexpected %#v", actual, c.expected)
+			}
+
+			if len(c.namespace) == 0 && len(errs) != 1 {
+				t.Errorf("Expected the missing role to be reported, got %v", errs)
+			}
+		})
+	}
+}
+
+func TestGetEffectivePermissionsInvalidSubject(t *testing.T) {
+	subjects := []Subject{
+		{Kind: rbac.ServiceAccountKind, Name: "ci"},
+		{Kind: "Robot", Name: "r2"},
+		{Kind: rbac.UserKind},
+	}
+
+	for _, subject := range subjects {
+		if _, err := GetEffectivePermissions(fake.NewSimpleClientset(), subject, nil, ""); err == nil {
+			t.Errorf("Expected error for subject %+v", subject)
+		}
+	}
+}
+
+func TestGetWhoCan(t *testing.T) {
+	cases := []struct {
+		info       string
+		attributes ResourceAttributes
+		expected   []Subject
+	}{
+		{
+			"cluster and namespace bindings",
+			ResourceAttributes{Verb: "list", Resource: "pods", Namespace: "ns-2"},
+			[]Subject{
+				{Kind: rbac.GroupKind, Name: "system:serviceaccounts:ns-1"},
+				{Kind: rbac.UserKind, Name: "system:serviceaccount:ns-1:ci"},
+			},
+		},
+		{
+			"role bindings of other namespaces do not apply",
+			ResourceAttributes{Verb: "delete", APIGroup: "apps", Resource: "deployments", Namespace: "ns-2"},
+			[]Subject{},
+		},
+		{
+			"namespaced role binding",
+			ResourceAttributes{Verb: "UPDATE", APIGroup: "apps", Resource: "deployments", Namespace: "ns-1"},
+			[]Subject{
+				{Kind: rbac.ServiceAccountKind, Name: "ci", Namespace: "ns-1"},
+				{Kind: rbac.UserKind, Name: "alice"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.info, func(t *testing.T) {
+			fakeClient := fake.NewSimpleClientset(getTestObjects()...)
+			actual, err := GetWhoCan(fakeClient, c.attributes, nil)
+			if err != nil {
+				t.Fatalf("GetWhoCan() returned error: %v", err)
+			}
+
+			subjects := make([]Subject, 0)
+			for _, subject := range actual.Subjects {
+				subjects = append(subjects, subject.Subject)
+			}
+
+			if !reflect.DeepEqual(subjects, c.expected) {
+				t.Errorf("GetWhoCan() subjects == %v, expected %v", subjects, c.expected)
+			}
+			if actual.CallerAllowed != nil {
+				t.Errorf("Expected no confirmation without reviewer")
+			}
+		})
+	}
+}
+
+func TestGetWhoCanConfirmation(t *testing.T) {
+	var review *authorizationv1.SelfSubjectAccessReview
+	reviewer := func(r *authorizationv1.SelfSubjectAccessReview) bool {
+		review = r
+		return true
+	}
+
+	actual, err := GetWhoCan(fake.NewSimpleClientset(), ResourceAttributes{Verb: "get", Resource: "pods/log",
+		Namespace: "ns-1"}, reviewer)
+	if err != nil {
+		t.Fatalf("GetWhoCan() returned error: %v", err)
+	}
+
+	if actual.CallerAllowed == nil || !*actual.CallerAllowed {
+		t.Errorf("Expected caller to be allowed, got %v", actual.CallerAllowed)
+	}
+
+	expected := &authorizationv1.ResourceAttributes{Namespace: "ns-1", Verb: "get", Resource: "pods",
+		Subresource: "log"}
+	if review == nil || !reflect.DeepEqual(review.Spec.ResourceAttributes, expected) {
+		t.Errorf("Unexpected access review: %#v", review)
+	}
+}
+
+func TestGetWhoCanInvalidAttributes(t *testing.T) {
+	attributes := []ResourceAttributes{
+		{Resource: "pods"},
+		{Verb: "get"},
+		{Verb: "get", NonResourceURL: "/metrics", Namespace: "ns-1"},
+		{Verb: "get", Resource: "pods/log", Subresource: "exec"},
+	}
+
+	for _, item := range attributes {
+		if _, err := GetWhoCan(fake.NewSimpleClientset(), item, nil); err == nil {
+			t.Errorf("Expected error for attributes %+v", item)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permission
+
+import (
+	"fmt"
+	"sort"
+
+	rbac "k8s.io/api/rbac/v1"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+)
+
+// Groups every authenticated user and every service account belongs to.
+const (
+	authenticatedGroup   = "system:authenticated"
+	serviceAccountsGroup = "system:serviceaccounts"
+)
+
+// BindingReference points to the role binding or cluster role binding that grants a rule.
+type BindingReference struct {
+	Kind      string       `json:"kind"`
+	Name      string       `json:"name"`
+	Namespace string       `json:"namespace,omitempty"`
+	RoleRef   rbac.RoleRef `json:"roleRef"`
+}
+
+// binding is a role binding or cluster role binding together with the rules of the role it references.
+type binding struct {
+	reference BindingReference
+	subjects  []rbac.Subject
+	rules     []rbac.PolicyRule
+}
+
+// getBindings reads all roles and bindings visible to the client and resolves the roles referenced by the bindings.
+// Cluster role bindings are always read, role bindings and roles only in the given namespace, or in all namespaces
+// if it is empty. Bindings that reference missing roles are reported as non-critical errors.
+func getBindings(client client.Interface, namespace string) ([]binding, []error, error) {
+	nsQuery := common.NewSameNamespaceQuery(namespace)
+	channels := &common.ResourceChannels{
+		RoleList:               common.GetRoleListChannel(client, nsQuery, 1),
+		ClusterRoleList:        common.GetClusterRoleListChannel(client, 1),
+		RoleBindingList:        common.GetRoleBindingListChannel(client, nsQuery, 1),
+		ClusterRoleBindingList: common.GetClusterRoleBindingListChannel(client, 1),
+	}
+
+	roleList := <-channels.RoleList.List
+	err := <-channels.RoleList.Error
+	rolesListed := err == nil
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, nil, criticalError
+	}
+
+	clusterRoleList := <-channels.ClusterRoleList.List
+	err = <-channels.ClusterRoleList.Error
+	clusterRolesListed := err == nil
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, nil, criticalError
+	}
+
+	roleBindingList := <-channels.RoleBindingList.List
+	err = <-channels.RoleBindingList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, nil, criticalError
+	}
+
+	clusterRoleBindingList := <-channels.ClusterRoleBindingList.List
+	err = <-channels.ClusterRoleBindingList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, nil, criticalError
+	}
+
+	roles := make(map[string][]rbac.PolicyRule)
+	if rolesListed {
+		for _, role := range roleList.Items {
+			roles[role.Namespace+"/"+role.Name] = role.Rules
+		}
+	}
+
+	clusterRoles := make(map[string][]rbac.PolicyRule)
+	if clusterRolesListed {
+		for _, role := range clusterRoleList.Items {
+			clusterRoles[role.Name] = role.Rules
+		}
+	}
+
+	bindings := make([]binding, 0)
+	missing := make([]error, 0)
+	resolve := func(reference BindingReference, subjects []rbac.Subject) {
+		rules, ok := clusterRoles[reference.RoleRef.Name]
+		listed := clusterRolesListed
+		if reference.RoleRef.Kind == "Role" {
+			rules, ok = roles[reference.Namespace+"/"+reference.RoleRef.Name]
+			listed = rolesListed
+		}
+
+		if !ok {
+			// Roles that could not be listed are already reported by the list error.
+			if listed {
+				missing = append(missing, errors.NewNotFound(fmt.Sprintf("%s %s referenced by %s %s not found",
+					reference.RoleRef.Kind, reference.RoleRef.Name, reference.Kind, reference.Name)))
+			}
+			return
+		}
+
+		bindings = append(bindings, binding{reference: reference, subjects: subjects, rules: rules})
+	}
+
+	if clusterRoleBindingList != nil {
+		for _, item := range clusterRoleBindingList.Items {
+			resolve(BindingReference{Kind: "ClusterRoleBinding", Name: item.Name, RoleRef: item.RoleRef},
+				item.Subjects)
+		}
+	}
+
+	if roleBindingList != nil {
+		for _, item := range roleBindingList.Items {
+			resolve(BindingReference{Kind: "RoleBinding", Name: item.Name, Namespace: item.Namespace,
+				RoleRef: item.RoleRef}, item.Subjects)
+		}
+	}
+
+	return bindings, errors.MergeErrors(nonCriticalErrors, missing), nil
+}
+
+// Subject is a user, group or service account whose permissions are resolved.
+type Subject struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Validate checks whether the subject kind is known and service accounts have a namespace.
+func (subject Subject) Validate() error {
+	if len(subject.Name) == 0 {
+		return errors.NewInvalid("Subject name is required")
+	}
+
+	switch subject.Kind {
+	case rbac.UserKind, rbac.GroupKind:
+		return nil
+	case rbac.ServiceAccountKind:
+		if len(subject.Namespace) == 0 {
+			return errors.NewInvalid("Service account subjects require a namespace")
+		}
+		return nil
+	default:
+		return errors.NewInvalid(fmt.Sprintf("Unknown subject kind %q, must be one of %s, %s or %s",
+			subject.Kind, rbac.UserKind, rbac.GroupKind, rbac.ServiceAccountKind))
+	}
+}
+
+// getGroups returns the groups the subject is known to belong to. Group membership of users is decided by the
+// authenticator and is not stored in the cluster, so only implicit groups are added to the given ones.
+func getGroups(subject Subject, groups []string) []string {
+	set := make(map[string]bool)
+	for _, group := range groups {
+		if len(group) > 0 {
+			set[group] = true
+		}
+	}
+
+	switch subject.Kind {
+	case rbac.GroupKind:
+		set[subject.Name] = true
+	case rbac.UserKind:
+		set[authenticatedGroup] = true
+	case rbac.ServiceAccountKind:
+		set[authenticatedGroup] = true
+		set[serviceAccountsGroup] = true
+		set[serviceAccountsGroup+":"+subject.Namespace] = true
+	}
+
+	result := make([]string, 0, len(set))
+	for group := range set {
+		result = append(result, group)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// appliesTo checks whether the binding subject refers to the subject or to one of its groups. Service account
+// subjects without a namespace default to the namespace of the binding.
+func appliesTo(bindingSubject rbac.Subject, bindingNamespace string, subject Subject, groups []string) bool {
+	switch bindingSubject.Kind {
+	case rbac.UserKind:
+		if subject.Kind == rbac.ServiceAccountKind {
+			return bindingSubject.Name == serviceAccountUserName(subject.Namespace, subject.Name)
+		}
+		return subject.Kind == rbac.UserKind && bindingSubject.Name == subject.Name
+	case rbac.GroupKind:
+		for _, group := range groups {
+			if bindingSubject.Name == group {
+				return true
+			}
+		}
+		return false
+	case rbac.ServiceAccountKind:
+		namespace := bindingSubject.Namespace
+		if len(namespace) == 0 {
+			namespace = bindingNamespace
+		}
+		return subject.Kind == rbac.ServiceAccountKind && bindingSubject.Name == subject.Name &&
+			namespace == subject.Namespace
+	default:
+		return false
+	}
+}
+
+func serviceAccountUserName(namespace, name string) string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name)
+}
@@ -0,0 +1,207 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permission
+
+import (
+	"sort"
+	"strings"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+// ResourceAttributes describe a request that is checked against RBAC rules. Either NonResourceURL or Resource
+// is set.
+type ResourceAttributes struct {
+	Verb           string `json:"verb"`
+	APIGroup       string `json:"apiGroup"`
+	Resource       string `json:"resource,omitempty"`
+	Subresource    string `json:"subresource,omitempty"`
+	ResourceName   string `json:"resourceName,omitempty"`
+	Namespace      string `json:"namespace,omitempty"`
+	NonResourceURL string `json:"nonResourceURL,omitempty"`
+}
+
+// EffectiveRule is a policy rule granted to a subject together with the bindings that grant it. Rules that only
+// differ in verbs are merged into one.
+type EffectiveRule struct {
+	rbac.PolicyRule `json:",inline"`
+	Bindings        []BindingReference `json:"bindings"`
+}
+
+// ruleAllows checks whether the rule allows the request, following the matching rules of the RBAC authorizer.
+func ruleAllows(rule rbac.PolicyRule, attributes ResourceAttributes) bool {
+	if !hasOrWildcard(rule.Verbs, attributes.Verb) {
+		return false
+	}
+
+	if len(attributes.NonResourceURL) > 0 {
+		return nonResourceURLMatches(rule.NonResourceURLs, attributes.NonResourceURL)
+	}
+
+	return hasOrWildcard(rule.APIGroups, attributes.APIGroup) &&
+		resourceMatches(rule.Resources, attributes.Resource, attributes.Subresource) &&
+		resourceNameMatches(rule.ResourceNames, attributes.ResourceName)
+}
+
+func hasOrWildcard(values []string, value string) bool {
+	for _, item := range values {
+		if item == rbac.VerbAll || item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func resourceMatches(resources []string, resource, subresource string) bool {
+	combined := resource
+	if len(subresource) > 0 {
+		combined = resource + "/" + subresource
+	}
+
+	for _, item := range resources {
+		if item == rbac.ResourceAll || item == combined {
+			return true
+		}
+		if len(subresource) > 0 && item == rbac.ResourceAll+"/"+subresource {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceNameMatches checks the resource name. Rules with resource names never match requests without a name,
+// such as list or create.
+func resourceNameMatches(names []string, name string) bool {
+	if len(names) == 0 {
+		return true
+	}
+
+	for _, item := range names {
+		if item == name {
+			return true
+		}
+	}
+	return false
+}
+
+func nonResourceURLMatches(urls []string, url string) bool {
+	for _, item := range urls {
+		if item == rbac.NonResourceAll || item == url {
+			return true
+		}
+		if strings.HasSuffix(item, "*") && strings.HasPrefix(url, strings.TrimRight(item, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleSet merges the rules of several bindings into effective rules.
+type ruleSet struct {
+	rules map[string]*EffectiveRule
+}
+
+func newRuleSet() *ruleSet {
+	return &ruleSet{rules: make(map[string]*EffectiveRule)}
+}
+
+func (set *ruleSet) add(rule rbac.PolicyRule, reference BindingReference) {
+	key := strings.Join([]string{
+		joinSorted(rule.APIGroups),
+		joinSorted(rule.Resources),
+		joinSorted(rule.ResourceNames),
+		joinSorted(rule.NonResourceURLs),
+	}, "|")
+
+	effective, ok := set.rules[key]
+	if !ok {
+		effective = &EffectiveRule{
+			PolicyRule: rbac.PolicyRule{
+				APIGroups:       sortedCopy(rule.APIGroups),
+				Resources:       sortedCopy(rule.Resources),
+				ResourceNames:   sortedCopy(rule.ResourceNames),
+				NonResourceURLs: sortedCopy(rule.NonResourceURLs),
+			},
+			Bindings: make([]BindingReference, 0),
+		}
+		set.rules[key] = effective
+	}
+
+	effective.Verbs = mergeVerbs(effective.Verbs, rule.Verbs)
+
+	for _, item := range effective.Bindings {
+		if item.Kind == reference.Kind && item.Namespace == reference.Namespace && item.Name == reference.Name {
+			return
+		}
+	}
+	effective.Bindings = append(effective.Bindings, reference)
+}
+
+// list returns the effective rules ordered by API groups, resources, resource names and non-resource URLs.
+func (set *ruleSet) list() []EffectiveRule {
+	result := make([]EffectiveRule, 0, len(set.rules))
+	for _, rule := range set.rules {
+		result = append(result, *rule)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		for _, pair := range [][2][]string{
+			{a.APIGroups, b.APIGroups},
+			{a.Resources, b.Resources},
+			{a.ResourceNames, b.ResourceNames},
+			{a.NonResourceURLs, b.NonResourceURLs},
+		} {
+			if x, y := strings.Join(pair[0], ","), strings.Join(pair[1], ","); x != y {
+				return x < y
+			}
+		}
+		return false
+	})
+	return result
+}
+
+// mergeVerbs returns the sorted union of both verb lists. A wildcard replaces all other verbs.
+func mergeVerbs(verbs, other []string) []string {
+	set := make(map[string]bool)
+	for _, verb := range append(append([]string{}, verbs...), other...) {
+		set[verb] = true
+	}
+
+	if set[rbac.VerbAll] {
+		return []string{rbac.VerbAll}
+	}
+
+	result := make([]string, 0, len(set))
+	for verb := range set {
+		result = append(result, verb)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func sortedCopy(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	result := append([]string{}, values...)
+	sort.Strings(result)
+	return result
+}
+
+func joinSorted(values []string) string {
+	return strings.Join(sortedCopy(values), ",")
+}
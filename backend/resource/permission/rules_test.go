@@ -0,0 +1,134 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permission
+
+import (
+	"reflect"
+	"testing"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+func TestRuleAllows(t *testing.T) {
+	cases := []struct {
+		info       string
+		rule       rbac.PolicyRule
+		attributes ResourceAttributes
+		expected   bool
+	}{
+		{
+			"exact match",
+			rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+			ResourceAttributes{Verb: "get", Resource: "pods"},
+			true,
+		},
+		{
+			"other verb",
+			rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+			ResourceAttributes{Verb: "delete", Resource: "pods"},
+			false,
+		},
+		{
+			"other api group",
+			rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"deployments"}},
+			ResourceAttributes{Verb: "get", APIGroup: "apps", Resource: "deployments"},
+			false,
+		},
+		{
+			"wildcards",
+			rbac.PolicyRule{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+			ResourceAttributes{Verb: "create", APIGroup: "apps", Resource: "deployments", Subresource: "scale"},
+			true,
+		},
+		{
+			"resource does not cover subresource",
+			rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+			ResourceAttributes{Verb: "get", Resource: "pods", Subresource: "log"},
+			false,
+		},
+		{
+			"subresource wildcard",
+			rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"*/log"}},
+			ResourceAttributes{Verb: "get", Resource: "pods", Subresource: "log"},
+			true,
+		},
+		{
+			"resource name",
+			rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"secrets"},
+				ResourceNames: []string{"token"}},
+			ResourceAttributes{Verb: "get", Resource: "secrets", ResourceName: "token"},
+			true,
+		},
+		{
+			"resource name rule does not allow list",
+			rbac.PolicyRule{Verbs: []string{"list"}, APIGroups: []string{""}, Resources: []string{"secrets"},
+				ResourceNames: []string{"token"}},
+			ResourceAttributes{Verb: "list", Resource: "secrets"},
+			false,
+		},
+		{
+			"non-resource url prefix",
+			rbac.PolicyRule{Verbs: []string{"get"}, NonResourceURLs: []string{"/healthz/*"}},
+			ResourceAttributes{Verb: "get", NonResourceURL: "/healthz/etcd"},
+			true,
+		},
+		{
+			"resource rule does not allow non-resource url",
+			rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+			ResourceAttributes{Verb: "get", NonResourceURL: "/metrics"},
+			false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.info, func(t *testing.T) {
+			if actual := ruleAllows(c.rule, c.attributes); actual != c.expected {
+				t.Errorf("ruleAllows(%+v, %+v) == %t, expected %t", c.rule, c.attributes, actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestRuleSet(t *testing.T) {
+	first := BindingReference{Kind: "RoleBinding", Name: "first", Namespace: "ns-1"}
+	second := BindingReference{Kind: "RoleBinding", Name: "second", Namespace: "ns-1"}
+
+	set := newRuleSet()
+	set.add(rbac.PolicyRule{Verbs: []string{"list", "get"}, APIGroups: []string{""},
+		Resources: []string{"services", "pods"}}, first)
+	set.add(rbac.PolicyRule{Verbs: []string{"watch", "get"}, APIGroups: []string{""},
+		Resources: []string{"pods", "services"}}, second)
+	set.add(rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps"},
+		Resources: []string{"deployments"}}, first)
+	set.add(rbac.PolicyRule{Verbs: []string{"*"}, APIGroups: []string{"apps"},
+		Resources: []string{"deployments"}}, first)
+
+	expected := []EffectiveRule{
+		{
+			PolicyRule: rbac.PolicyRule{Verbs: []string{"get", "list", "watch"}, APIGroups: []string{""},
+				Resources: []string{"pods", "services"}},
+			Bindings: []BindingReference{first, second},
+		},
+		{
+			PolicyRule: rbac.PolicyRule{Verbs: []string{"*"}, APIGroups: []string{"apps"},
+				Resources: []string{"deployments"}},
+			Bindings: []BindingReference{first},
+		},
+	}
+
+	if actual := set.list(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("ruleSet.list() == \ngot: %#v, \nexpected %#v", actual, expected)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permission
+
+import (
+	"encoding/json"
+	"fmt"
+
+	rbac "k8s.io/api/rbac/v1"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+)
+
+// SubjectPatch adds subjects to or removes subjects from an existing binding.
+type SubjectPatch struct {
+	Add    []rbac.Subject `json:"add,omitempty"`
+	Remove []rbac.Subject `json:"remove,omitempty"`
+}
+
+// ValidateSubjects checks the subject kinds and fills in defaults: the RBAC API group for users and groups, and the
+// namespace of the binding for service accounts. Service accounts of cluster role bindings, which have no
+// namespace, must set one.
+func ValidateSubjects(subjects []rbac.Subject, namespace string) ([]rbac.Subject, error) {
+	result := make([]rbac.Subject, 0, len(subjects))
+	for _, subject := range subjects {
+		if len(subject.Name) == 0 {
+			return nil, errors.NewInvalid("Subject name is required")
+		}
+
+		switch subject.Kind {
+		case rbac.UserKind, rbac.GroupKind:
+			subject.APIGroup = rbac.GroupName
+			if len(subject.Namespace) > 0 {
+				return nil, errors.NewInvalid(fmt.Sprintf("%s %s must not set a namespace", subject.Kind,
+					subject.Name))
+			}
+		case rbac.ServiceAccountKind:
+			subject.APIGroup = ""
+			if len(subject.Namespace) == 0 {
+				subject.Namespace = namespace
+			}
+			if len(subject.Namespace) == 0 {
+				return nil, errors.NewInvalid(fmt.Sprintf("Service account %s requires a namespace", subject.Name))
+			}
+		default:
+			return nil, errors.NewInvalid(fmt.Sprintf("Unknown subject kind %q, must be one of %s, %s or %s",
+				subject.Kind, rbac.UserKind, rbac.GroupKind, rbac.ServiceAccountKind))
+		}
+
+		result = append(result, subject)
+	}
+	return result, nil
+}
+
+// ValidateRoleRef checks that the binding refers to a role it can bind and fills in the RBAC API group. Role
+// bindings can refer to roles in their namespace and to cluster roles, cluster role bindings only to cluster roles.
+func ValidateRoleRef(ref rbac.RoleRef, namespaced bool) (rbac.RoleRef, error) {
+	if len(ref.Name) == 0 {
+		return ref, errors.NewInvalid("Name of the referenced role is required")
+	}
+
+	if ref.Kind != "ClusterRole" && (ref.Kind != "Role" || !namespaced) {
+		if namespaced {
+			return ref, errors.NewInvalid(fmt.Sprintf("Role reference kind must be Role or ClusterRole, got %q",
+				ref.Kind))
+		}
+		return ref, errors.NewInvalid(fmt.Sprintf("Role reference kind must be ClusterRole, got %q", ref.Kind))
+	}
+
+	if len(ref.APIGroup) > 0 && ref.APIGroup != rbac.GroupName {
+		return ref, errors.NewInvalid(fmt.Sprintf("Role reference API group must be %s", rbac.GroupName))
+	}
+	ref.APIGroup = rbac.GroupName
+	return ref, nil
+}
+
+// GetSubjectsPatch returns a JSON merge patch that applies the subject patch to the current subjects of a binding.
+// The patch contains the resource version of the binding, so that it fails if the binding was changed in the
+// meantime. Subjects that are added twice are ignored, removing subjects that are not bound is an error.
+func GetSubjectsPatch(current []rbac.Subject, resourceVersion, namespace string, patch *SubjectPatch) ([]byte,
+	error) {
+	add, err := ValidateSubjects(patch.Add, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	remove, err := ValidateSubjects(patch.Remove, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, errors.NewInvalid("Subject patch must add or remove at least one subject")
+	}
+
+	subjects := make([]rbac.Subject, 0, len(current)+len(add))
+	for _, subject := range current {
+		if indexOfSubject(remove, subject, namespace) < 0 {
+			subjects = append(subjects, subject)
+		}
+	}
+
+	for _, subject := range remove {
+		if indexOfSubject(current, subject, namespace) < 0 {
+			return nil, errors.NewInvalid(fmt.Sprintf("%s %s is not bound", subject.Kind, subject.Name))
+		}
+	}
+
+	for _, subject := range add {
+		if indexOfSubject(subjects, subject, namespace) < 0 {
+			subjects = append(subjects, subject)
+		}
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]string{"resourceVersion": resourceVersion},
+		"subjects": subjects,
+	})
+}
+
+// indexOfSubject returns the index of the subject in the list, or -1. Service accounts without a namespace are
+// in the namespace of the binding, as they are bound by the authorizer.
+func indexOfSubject(subjects []rbac.Subject, subject rbac.Subject, namespace string) int {
+	for i, item := range subjects {
+		if item.Kind == subject.Kind && item.Name == subject.Name &&
+			subjectNamespace(item, namespace) == subjectNamespace(subject, namespace) {
+			return i
+		}
+	}
+	return -1
+}
+
+func subjectNamespace(subject rbac.Subject, namespace string) string {
+	if subject.Kind == rbac.ServiceAccountKind && len(subject.Namespace) == 0 {
+		return namespace
+	}
+	return subject.Namespace
+}
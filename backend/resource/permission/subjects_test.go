@@ -0,0 +1,129 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permission
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+func TestGetSubjectsPatch(t *testing.T) {
+	current := []rbac.Subject{
+		{Kind: rbac.ServiceAccountKind, Name: "ci"},
+		{Kind: rbac.UserKind, APIGroup: rbac.GroupName, Name: "alice"},
+	}
+
+	cases := []struct {
+		info     string
+		patch    *SubjectPatch
+		expected []rbac.Subject
+		isError  bool
+	}{
+		{
+			"add and remove",
+			&SubjectPatch{
+				Add:    []rbac.Subject{{Kind: rbac.GroupKind, Name: "admins"}},
+				Remove: []rbac.Subject{{Kind: rbac.ServiceAccountKind, Name: "ci", Namespace: "ns-1"}},
+			},
+			[]rbac.Subject{
+				{Kind: rbac.UserKind, APIGroup: rbac.GroupName, Name: "alice"},
+				{Kind: rbac.GroupKind, APIGroup: rbac.GroupName, Name: "admins"},
+			},
+			false,
+		},
+		{
+			"add existing subject",
+			&SubjectPatch{Add: []rbac.Subject{{Kind: rbac.UserKind, Name: "alice"}}},
+			current,
+			false,
+		},
+		{
+			"remove unbound subject",
+			&SubjectPatch{Remove: []rbac.Subject{{Kind: rbac.UserKind, Name: "bob"}}},
+			nil,
+			true,
+		},
+		{
+			"empty patch",
+			&SubjectPatch{},
+			nil,
+			true,
+		},
+		{
+			"invalid kind",
+			&SubjectPatch{Add: []rbac.Subject{{Kind: "Robot", Name: "r2"}}},
+			nil,
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.info, func(t *testing.T) {
+			data, err := GetSubjectsPatch(current, "42", "ns-1", c.patch)
+			if c.isError {
+				if err == nil {
+					t.Errorf("GetSubjectsPatch() returned no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetSubjectsPatch() returned error: %v", err)
+			}
+
+			actual := struct {
+				Metadata map[string]string `json:"metadata"`
+				Subjects []rbac.Subject    `json:"subjects"`
+			}{}
+			if err := json.Unmarshal(data, &actual); err != nil {
+				t.Fatalf("Could not unmarshal patch %s: %v", data, err)
+			}
+
+			if actual.Metadata["resourceVersion"] != "42" {
+				t.Errorf("Expected patch to contain resource version, got %s", data)
+			}
+			if !reflect.DeepEqual(actual.Subjects, c.expected) {
+				t.Errorf("GetSubjectsPatch() subjects == %#v, expected %#v", actual.Subjects, c.expected)
+			}
+		})
+	}
+}
+
+func TestValidateRoleRef(t *testing.T) {
+	cases := []struct {
+		ref        rbac.RoleRef
+		namespaced bool
+		isError    bool
+	}{
+		{rbac.RoleRef{Kind: "Role", Name: "deployer"}, true, false},
+		{rbac.RoleRef{Kind: "ClusterRole", Name: "view"}, true, false},
+		{rbac.RoleRef{Kind: "ClusterRole", Name: "view"}, false, false},
+		{rbac.RoleRef{Kind: "Role", Name: "deployer"}, false, true},
+		{rbac.RoleRef{Kind: "ClusterRole"}, false, true},
+		{rbac.RoleRef{Kind: "ClusterRole", Name: "view", APIGroup: "apps"}, false, true},
+	}
+
+	for _, c := range cases {
+		ref, err := ValidateRoleRef(c.ref, c.namespaced)
+		if c.isError != (err != nil) {
+			t.Errorf("ValidateRoleRef(%+v, %t) returned error %v", c.ref, c.namespaced, err)
+		}
+		if err == nil && ref.APIGroup != rbac.GroupName {
+			t.Errorf("Expected API group to be set, got %+v", ref)
+		}
+	}
+}
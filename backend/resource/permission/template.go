@@ -0,0 +1,194 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permission
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	rbac "k8s.io/api/rbac/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+)
+
+// templateLabel is set on roles and bindings created from a template to the name of the template.
+const templateLabel = "dashboard.kubernetes.io/rbac-template"
+
+var (
+	readVerbs  = []string{"get", "list", "watch"}
+	writeVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+)
+
+// Template is a built-in grant of common permissions within a namespace.
+type Template struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Rules       []rbac.PolicyRule `json:"rules"`
+}
+
+// templates are the built-in templates. Secrets are left out of all of them on purpose.
+var templates = []Template{
+	{
+		Name:        "namespace-viewer",
+		Description: "Read access to workloads, services, configuration except secrets, and events",
+		Rules: []rbac.PolicyRule{
+			{Verbs: readVerbs, APIGroups: []string{""}, Resources: []string{"pods", "services", "endpoints",
+				"configmaps", "persistentvolumeclaims", "events", "replicationcontrollers", "serviceaccounts"}},
+			{Verbs: readVerbs, APIGroups: []string{"apps"}, Resources: []string{"deployments", "replicasets",
+				"statefulsets", "daemonsets"}},
+			{Verbs: readVerbs, APIGroups: []string{"batch"}, Resources: []string{"jobs", "cronjobs"}},
+			{Verbs: readVerbs, APIGroups: []string{"autoscaling"}, Resources: []string{"horizontalpodautoscalers"}},
+			{Verbs: readVerbs, APIGroups: []string{"networking.k8s.io"}, Resources: []string{"ingresses",
+				"networkpolicies"}},
+			{Verbs: readVerbs, APIGroups: []string{"policy"}, Resources: []string{"poddisruptionbudgets"}},
+		},
+	},
+	{
+		Name:        "deployer",
+		Description: "Manage workloads, services, config maps and autoscalers, and restart pods",
+		Rules: []rbac.PolicyRule{
+			{Verbs: writeVerbs, APIGroups: []string{"apps"}, Resources: []string{"deployments", "replicasets",
+				"statefulsets", "daemonsets"}},
+			{Verbs: []string{"get", "update", "patch"}, APIGroups: []string{"apps"},
+				Resources: []string{"deployments/scale", "statefulsets/scale"}},
+			{Verbs: writeVerbs, APIGroups: []string{""}, Resources: []string{"services", "configmaps"}},
+			{Verbs: []string{"get", "list", "watch", "delete"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+			{Verbs: writeVerbs, APIGroups: []string{"autoscaling"}, Resources: []string{"horizontalpodautoscalers"}},
+		},
+	},
+	{
+		Name:        "log-reader",
+		Description: "List pods and read their logs",
+		Rules: []rbac.PolicyRule{
+			{Verbs: readVerbs, APIGroups: []string{""}, Resources: []string{"pods"}},
+			{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods/log"}},
+		},
+	},
+}
+
+// TemplateSpec is a specification of the role and binding to create from a template.
+type TemplateSpec struct {
+	Namespace string `json:"namespace"`
+
+	// Name of the role and the binding. Defaults to the template name.
+	Name string `json:"name,omitempty"`
+
+	Subjects []rbac.Subject `json:"subjects"`
+}
+
+// TemplateObjects are the role and role binding produced by a template.
+type TemplateObjects struct {
+	Role        rbac.Role        `json:"role"`
+	RoleBinding rbac.RoleBinding `json:"roleBinding"`
+}
+
+// GetTemplates returns all built-in templates.
+func GetTemplates() []Template {
+	return templates
+}
+
+// GetTemplateObjects returns the role and role binding the template produces for the given specification.
+func GetTemplateObjects(template string, spec *TemplateSpec) (*TemplateObjects, error) {
+	var found *Template
+	for i := range templates {
+		if templates[i].Name == template {
+			found = &templates[i]
+		}
+	}
+	if found == nil {
+		return nil, errors.NewNotFound(fmt.Sprintf("RBAC template %s not found", template))
+	}
+
+	if len(spec.Namespace) == 0 {
+		return nil, errors.NewInvalid("Namespace is required")
+	}
+	if len(spec.Subjects) == 0 {
+		return nil, errors.NewInvalid("At least one subject is required")
+	}
+
+	subjects, err := ValidateSubjects(spec.Subjects, spec.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	name := spec.Name
+	if len(name) == 0 {
+		name = found.Name
+	}
+
+	meta := metaV1.ObjectMeta{
+		Name:      name,
+		Namespace: spec.Namespace,
+		Labels:    map[string]string{templateLabel: found.Name},
+	}
+
+	rules := make([]rbac.PolicyRule, len(found.Rules))
+	for i, rule := range found.Rules {
+		rules[i] = *rule.DeepCopy()
+	}
+
+	return &TemplateObjects{
+		Role: rbac.Role{
+			TypeMeta:   metaV1.TypeMeta{APIVersion: rbac.SchemeGroupVersion.String(), Kind: "Role"},
+			ObjectMeta: meta,
+			Rules:      rules,
+		},
+		RoleBinding: rbac.RoleBinding{
+			TypeMeta:   metaV1.TypeMeta{APIVersion: rbac.SchemeGroupVersion.String(), Kind: "RoleBinding"},
+			ObjectMeta: *meta.DeepCopy(),
+			Subjects:   subjects,
+			RoleRef:    rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "Role", Name: name},
+		},
+	}, nil
+}
+
+// ApplyTemplate creates the role and role binding the template produces. If the binding cannot be created, the
+// role is deleted again.
+func ApplyTemplate(client client.Interface, template string, spec *TemplateSpec,
+	dryRun bool) (*TemplateObjects, error) {
+	objects, err := GetTemplateObjects(template, spec)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Creating role and role binding %s from %s template in %s namespace", objects.Role.Name, template,
+		spec.Namespace)
+
+	options := metaV1.CreateOptions{}
+	if dryRun {
+		options.DryRun = []string{metaV1.DryRunAll}
+	}
+
+	role, err := client.RbacV1().Roles(spec.Namespace).Create(context.TODO(), &objects.Role, options)
+	if err != nil {
+		return nil, err
+	}
+
+	binding, err := client.RbacV1().RoleBindings(spec.Namespace).Create(context.TODO(), &objects.RoleBinding,
+		options)
+	if err != nil {
+		if !dryRun {
+			if deleteErr := client.RbacV1().Roles(spec.Namespace).Delete(context.TODO(), role.Name,
+				metaV1.DeleteOptions{}); deleteErr != nil {
+				log.Printf("Could not delete role %s after failed binding: %s", role.Name, deleteErr)
+			}
+		}
+		return nil, err
+	}
+
+	return &TemplateObjects{Role: *role, RoleBinding: *binding}, nil
+}
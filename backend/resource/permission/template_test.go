@@ -0,0 +1,113 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permission
+
+import (
+	"context"
+	"testing"
+
+	rbac "k8s.io/api/rbac/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTemplatesAreValid(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.Resources = []*metaV1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metaV1.APIResource{
+			{Name: "pods", Verbs: writeVerbs}, {Name: "pods/log", Verbs: []string{"get"}},
+			{Name: "services", Verbs: writeVerbs}, {Name: "endpoints", Verbs: writeVerbs},
+			{Name: "configmaps", Verbs: writeVerbs}, {Name: "persistentvolumeclaims", Verbs: writeVerbs},
+			{Name: "events", Verbs: writeVerbs}, {Name: "replicationcontrollers", Verbs: writeVerbs},
+			{Name: "serviceaccounts", Verbs: writeVerbs},
+		}},
+		{GroupVersion: "apps/v1", APIResources: []metaV1.APIResource{
+			{Name: "deployments", Verbs: writeVerbs}, {Name: "replicasets", Verbs: writeVerbs},
+			{Name: "statefulsets", Verbs: writeVerbs}, {Name: "daemonsets", Verbs: writeVerbs},
+			{Name: "deployments/scale", Verbs: []string{"get", "update", "patch"}},
+			{Name: "statefulsets/scale", Verbs: []string{"get", "update", "patch"}},
+		}},
+		{GroupVersion: "batch/v1", APIResources: []metaV1.APIResource{{Name: "jobs", Verbs: writeVerbs}}},
+		{GroupVersion: "batch/v1beta1", APIResources: []metaV1.APIResource{{Name: "cronjobs", Verbs: writeVerbs}}},
+		{GroupVersion: "autoscaling/v1", APIResources: []metaV1.APIResource{
+			{Name: "horizontalpodautoscalers", Verbs: writeVerbs},
+		}},
+		{GroupVersion: "networking.k8s.io/v1", APIResources: []metaV1.APIResource{
+			{Name: "ingresses", Verbs: writeVerbs}, {Name: "networkpolicies", Verbs: writeVerbs},
+		}},
+		{GroupVersion: "policy/v1beta1", APIResources: []metaV1.APIResource{
+			{Name: "poddisruptionbudgets", Verbs: writeVerbs},
+		}},
+	}
+
+	for _, template := range GetTemplates() {
+		if err := ValidateRules(client, template.Rules, false); err != nil {
+			t.Errorf("Template %s has invalid rules: %v", template.Name, err)
+		}
+	}
+}
+
+func TestApplyTemplate(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	spec := &TemplateSpec{
+		Namespace: "ns-1",
+		Subjects:  []rbac.Subject{{Kind: rbac.ServiceAccountKind, Name: "ci"}},
+	}
+
+	objects, err := ApplyTemplate(client, "log-reader", spec, false)
+	if err != nil {
+		t.Fatalf("ApplyTemplate() returned error: %v", err)
+	}
+
+	binding, err := client.RbacV1().RoleBindings("ns-1").Get(context.TODO(), "log-reader", metaV1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected role binding to be created: %v", err)
+	}
+	if binding.RoleRef.Kind != "Role" || binding.RoleRef.Name != "log-reader" {
+		t.Errorf("Unexpected role reference: %+v", binding.RoleRef)
+	}
+	if binding.Subjects[0].Namespace != "ns-1" {
+		t.Errorf("Expected service account namespace to default to ns-1, got %+v", binding.Subjects[0])
+	}
+
+	role, err := client.RbacV1().Roles("ns-1").Get(context.TODO(), "log-reader", metaV1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected role to be created: %v", err)
+	}
+	if len(role.Rules) != 2 || role.Labels[templateLabel] != "log-reader" || len(objects.Role.Rules) != 2 {
+		t.Errorf("Unexpected role: %+v", role)
+	}
+}
+
+func TestApplyTemplateErrors(t *testing.T) {
+	cases := []struct {
+		template string
+		spec     *TemplateSpec
+	}{
+		{"unknown", &TemplateSpec{Namespace: "ns-1", Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "a"}}}},
+		{"deployer", &TemplateSpec{Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "a"}}}},
+		{"deployer", &TemplateSpec{Namespace: "ns-1"}},
+	}
+
+	for _, c := range cases {
+		client := fake.NewSimpleClientset()
+		if _, err := ApplyTemplate(client, c.template, c.spec, false); err == nil {
+			t.Errorf("ApplyTemplate(%s, %+v) returned no error", c.template, c.spec)
+		}
+		if len(client.Actions()) > 0 {
+			t.Errorf("Expected no objects to be created, got %v", client.Actions())
+		}
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permission
+
+import (
+	"fmt"
+	"strings"
+
+	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+)
+
+// specialVerbs are verbs that are not listed by discovery, because they are checked by the authorizer while
+// handling other requests, e.g. bind and escalate while creating bindings and roles.
+var specialVerbs = map[string]bool{
+	"use":         true,
+	"bind":        true,
+	"escalate":    true,
+	"impersonate": true,
+	"approve":     true,
+	"sign":        true,
+	"attest":      true,
+}
+
+// nonResourceVerbs are the lower case HTTP methods that rules for non-resource URLs can grant.
+var nonResourceVerbs = map[string]bool{
+	"get":     true,
+	"head":    true,
+	"post":    true,
+	"put":     true,
+	"patch":   true,
+	"delete":  true,
+	"options": true,
+}
+
+// apiIndex maps API groups to their resources and the verbs these support, as reported by discovery.
+type apiIndex struct {
+	groups map[string]map[string][]string
+
+	// failedGroups could not be discovered. Rules referring to them are not checked.
+	failedGroups map[string]bool
+}
+
+// ValidateRules checks the rules of a role against discovery. Unknown API groups, resources and verbs are
+// rejected, so that typos do not silently create rules that grant nothing. Non-resource URLs are only allowed for
+// cluster roles.
+func ValidateRules(client client.Interface, rules []rbac.PolicyRule, clusterRole bool) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	index, err := getAPIIndex(client.Discovery())
+	if err != nil {
+		return err
+	}
+
+	problems := make([]string, 0)
+	for i, rule := range rules {
+		for _, problem := range index.validateRule(rule, clusterRole) {
+			problems = append(problems, fmt.Sprintf("rule %d: %s", i, problem))
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.NewInvalid("Invalid rules: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func getAPIIndex(client discovery.DiscoveryInterface) (*apiIndex, error) {
+	index := &apiIndex{groups: make(map[string]map[string][]string), failedGroups: make(map[string]bool)}
+
+	_, resourceLists, err := client.ServerGroupsAndResources()
+	if err != nil {
+		failed, ok := err.(*discovery.ErrGroupDiscoveryFailed)
+		if !ok {
+			return nil, err
+		}
+		for groupVersion := range failed.Groups {
+			index.failedGroups[groupVersion.Group] = true
+		}
+	}
+
+	for _, list := range resourceLists {
+		groupVersion, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+
+		resources, ok := index.groups[groupVersion.Group]
+		if !ok {
+			resources = make(map[string][]string)
+			index.groups[groupVersion.Group] = resources
+		}
+		for _, resource := range list.APIResources {
+			resources[resource.Name] = append(resources[resource.Name], resource.Verbs...)
+		}
+	}
+
+	return index, nil
+}
+
+func (index *apiIndex) validateRule(rule rbac.PolicyRule, clusterRole bool) []string {
+	problems := make([]string, 0)
+	if len(rule.Verbs) == 0 {
+		problems = append(problems, "at least one verb is required")
+	}
+
+	if len(rule.NonResourceURLs) > 0 {
+		if !clusterRole {
+			problems = append(problems, "non-resource URLs are only allowed in cluster roles")
+		}
+		if len(rule.Resources) > 0 || len(rule.APIGroups) > 0 || len(rule.ResourceNames) > 0 {
+			problems = append(problems, "non-resource URLs cannot be combined with API groups or resources")
+		}
+		for _, url := range rule.NonResourceURLs {
+			if url != rbac.NonResourceAll && !strings.HasPrefix(url, "/") {
+				problems = append(problems, fmt.Sprintf("non-resource URL %q must start with /", url))
+			}
+		}
+		for _, verb := range rule.Verbs {
+			if verb != rbac.VerbAll && !nonResourceVerbs[verb] {
+				problems = append(problems, fmt.Sprintf("verb %q is not allowed for non-resource URLs", verb))
+			}
+		}
+		return problems
+	}
+
+	if len(rule.Resources) == 0 {
+		return append(problems, "either resources or non-resource URLs are required")
+	}
+	if len(rule.APIGroups) == 0 {
+		return append(problems, "API groups are required for resources, use \"\" for the core group")
+	}
+
+	verbs := make(map[string]bool)
+	checked := false
+	for _, group := range rule.APIGroups {
+		if group != rbac.APIGroupAll {
+			if index.failedGroups[group] {
+				continue
+			}
+			if _, ok := index.groups[group]; !ok {
+				problems = append(problems, fmt.Sprintf("unknown API group %q", group))
+				continue
+			}
+		}
+
+		for _, resource := range rule.Resources {
+			matched, found := index.matchResource(group, resource)
+			if !found {
+				problems = append(problems, fmt.Sprintf("unknown resource %q in API group %q", resource, group))
+				continue
+			}
+			checked = true
+			for _, verb := range matched {
+				verbs[verb] = true
+			}
+		}
+	}
+
+	if !checked {
+		return problems
+	}
+
+	for _, verb := range rule.Verbs {
+		if verb != rbac.VerbAll && !specialVerbs[verb] && !verbs[verb] {
+			problems = append(problems, fmt.Sprintf("verb %q is not supported by any of the resources", verb))
+		}
+	}
+	return problems
+}
+
+// matchResource returns the verbs supported by the resources in the group that the rule resource refers to, and
+// whether it refers to any resource at all. Both the group and the resource may be wildcards.
+func (index *apiIndex) matchResource(group, resource string) ([]string, bool) {
+	result := make([]string, 0)
+	found := false
+	for name, resources := range index.groups {
+		if group != rbac.APIGroupAll && group != name {
+			continue
+		}
+
+		for name, verbs := range resources {
+			if resource == rbac.ResourceAll || resource == name ||
+				strings.HasPrefix(resource, "*/") && strings.HasSuffix(name, resource[1:]) {
+				found = true
+				result = append(result, verbs...)
+			}
+		}
+	}
+
+	return result, found
+}
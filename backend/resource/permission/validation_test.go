@@ -0,0 +1,134 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permission
+
+import (
+	"strings"
+	"testing"
+
+	rbac "k8s.io/api/rbac/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newDiscoveryClient() *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.Resources = []*metaV1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metaV1.APIResource{
+				{Name: "pods", Verbs: []string{"create", "delete", "get", "list", "patch", "update", "watch"}},
+				{Name: "pods/log", Verbs: []string{"get"}},
+				{Name: "pods/exec", Verbs: []string{"create", "get"}},
+			},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metaV1.APIResource{
+				{Name: "deployments", Verbs: []string{"create", "delete", "get", "list", "patch", "update", "watch"}},
+				{Name: "deployments/scale", Verbs: []string{"get", "patch", "update"}},
+			},
+		},
+	}
+	return client
+}
+
+func TestValidateRules(t *testing.T) {
+	cases := []struct {
+		info        string
+		rules       []rbac.PolicyRule
+		clusterRole bool
+		expected    []string
+	}{
+		{
+			"valid rules",
+			[]rbac.PolicyRule{
+				{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods", "pods/log"}},
+				{Verbs: []string{"*"}, APIGroups: []string{"apps"}, Resources: []string{"*"}},
+				{Verbs: []string{"create"}, APIGroups: []string{"*"}, Resources: []string{"*/exec"}},
+			},
+			false,
+			nil,
+		},
+		{
+			"special verbs",
+			[]rbac.PolicyRule{{Verbs: []string{"escalate", "bind"}, APIGroups: []string{""},
+				Resources: []string{"pods"}}},
+			false,
+			nil,
+		},
+		{
+			"unknown group, resource and verb",
+			[]rbac.PolicyRule{
+				{Verbs: []string{"get"}, APIGroups: []string{"app"}, Resources: []string{"deployments"}},
+				{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"deployment"}},
+				{Verbs: []string{"delete"}, APIGroups: []string{""}, Resources: []string{"pods/log"}},
+			},
+			false,
+			[]string{
+				`rule 0: unknown API group "app"`,
+				`rule 1: unknown resource "deployment" in API group "apps"`,
+				`rule 2: verb "delete" is not supported by any of the resources`,
+			},
+		},
+		{
+			"non-resource urls in role",
+			[]rbac.PolicyRule{{Verbs: []string{"get"}, NonResourceURLs: []string{"/healthz"}}},
+			false,
+			[]string{"rule 0: non-resource URLs are only allowed in cluster roles"},
+		},
+		{
+			"non-resource urls in cluster role",
+			[]rbac.PolicyRule{{Verbs: []string{"get"}, NonResourceURLs: []string{"/healthz", "/metrics/*"}}},
+			true,
+			nil,
+		},
+		{
+			"missing fields",
+			[]rbac.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods"}},
+				{Verbs: []string{"get"}},
+				{Verbs: []string{"get"}, Resources: []string{"pods"}},
+			},
+			false,
+			[]string{
+				"rule 0: at least one verb is required",
+				"rule 1: either resources or non-resource URLs are required",
+				"rule 2: API groups are required for resources",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.info, func(t *testing.T) {
+			err := ValidateRules(newDiscoveryClient(), c.rules, c.clusterRole)
+			if len(c.expected) == 0 {
+				if err != nil {
+					t.Errorf("ValidateRules() returned error: %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("ValidateRules() returned no error, expected %v", c.expected)
+			}
+			for _, problem := range c.expected {
+				if !strings.Contains(err.Error(), problem) {
+					t.Errorf("ValidateRules() error %q does not contain %q", err.Error(), problem)
+				}
+			}
+		})
+	}
+}
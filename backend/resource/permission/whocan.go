@@ -0,0 +1,185 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permission
+
+import (
+	"log"
+	"sort"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbac "k8s.io/api/rbac/v1"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+)
+
+// AccessReviewer answers a self subject access review for the caller, i.e. ClientManager.CanI bound to a request.
+type AccessReviewer func(review *authorizationv1.SelfSubjectAccessReview) bool
+
+// WhoCanSubject is a subject that is allowed to perform a request, with the bindings that allow it.
+type WhoCanSubject struct {
+	Subject  `json:",inline"`
+	Bindings []BindingReference `json:"bindings"`
+}
+
+// WhoCan lists the subjects that RBAC allows to perform a request.
+type WhoCan struct {
+	Attributes ResourceAttributes `json:"attributes"`
+	Subjects   []WhoCanSubject    `json:"subjects"`
+
+	// Answer of the API server authorizer for the caller, only set if a confirmation was requested. Authorizers
+	// other than RBAC may allow requests that none of the listed subjects is bound to.
+	CallerAllowed *bool `json:"callerAllowed,omitempty"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetWhoCan returns all subjects bound to a role that allows the request. Cluster role bindings are always taken
+// into account, role bindings only for requests in their namespace. If a reviewer is given, the result is
+// confirmed by asking the API server whether the caller may perform the same request.
+func GetWhoCan(client client.Interface, attributes ResourceAttributes, reviewer AccessReviewer) (*WhoCan, error) {
+	attributes, err := normalizeAttributes(attributes)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Getting subjects that can %s %s%s in %q namespace", attributes.Verb, attributes.Resource,
+		attributes.NonResourceURL, attributes.Namespace)
+
+	bindings, nonCriticalErrors, err := getBindings(client, attributes.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := toWhoCan(bindings, attributes, nonCriticalErrors)
+	if reviewer != nil {
+		allowed := reviewer(toSelfSubjectAccessReview(attributes))
+		result.CallerAllowed = &allowed
+	}
+
+	return result, nil
+}
+
+func toWhoCan(bindings []binding, attributes ResourceAttributes, nonCriticalErrors []error) *WhoCan {
+	subjects := make(map[Subject]*WhoCanSubject)
+
+	for _, item := range bindings {
+		if len(item.reference.Namespace) > 0 && item.reference.Namespace != attributes.Namespace {
+			continue
+		}
+
+		if !rulesAllow(item.rules, attributes) {
+			continue
+		}
+
+		for _, bindingSubject := range item.subjects {
+			subject := Subject{Kind: bindingSubject.Kind, Name: bindingSubject.Name}
+			if bindingSubject.Kind == rbac.ServiceAccountKind {
+				subject.Namespace = bindingSubject.Namespace
+				if len(subject.Namespace) == 0 {
+					subject.Namespace = item.reference.Namespace
+				}
+			}
+
+			whoCanSubject, ok := subjects[subject]
+			if !ok {
+				whoCanSubject = &WhoCanSubject{Subject: subject, Bindings: make([]BindingReference, 0)}
+				subjects[subject] = whoCanSubject
+			}
+			whoCanSubject.Bindings = append(whoCanSubject.Bindings, item.reference)
+		}
+	}
+
+	result := &WhoCan{
+		Attributes: attributes,
+		Subjects:   make([]WhoCanSubject, 0, len(subjects)),
+		Errors:     nonCriticalErrors,
+	}
+	for _, subject := range subjects {
+		result.Subjects = append(result.Subjects, *subject)
+	}
+	sort.Slice(result.Subjects, func(i, j int) bool {
+		a, b := result.Subjects[i], result.Subjects[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	return result
+}
+
+func rulesAllow(rules []rbac.PolicyRule, attributes ResourceAttributes) bool {
+	for _, rule := range rules {
+		if ruleAllows(rule, attributes) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeAttributes validates the request and splits subresources given as part of the resource, e.g. pods/log.
+func normalizeAttributes(attributes ResourceAttributes) (ResourceAttributes, error) {
+	attributes.Verb = strings.ToLower(attributes.Verb)
+	if len(attributes.Verb) == 0 {
+		return attributes, errors.NewInvalid("Verb is required")
+	}
+
+	if len(attributes.NonResourceURL) > 0 {
+		if len(attributes.Resource) > 0 || len(attributes.Namespace) > 0 {
+			return attributes, errors.NewInvalid("Non-resource URLs cannot be combined with a resource or namespace")
+		}
+		return attributes, nil
+	}
+
+	if len(attributes.Resource) == 0 {
+		return attributes, errors.NewInvalid("Either a resource or a non-resource URL is required")
+	}
+
+	attributes.Resource = strings.ToLower(attributes.Resource)
+	if parts := strings.SplitN(attributes.Resource, "/", 2); len(parts) == 2 {
+		if len(attributes.Subresource) > 0 {
+			return attributes, errors.NewInvalid("Subresource is given twice")
+		}
+		attributes.Resource, attributes.Subresource = parts[0], parts[1]
+	}
+
+	return attributes, nil
+}
+
+func toSelfSubjectAccessReview(attributes ResourceAttributes) *authorizationv1.SelfSubjectAccessReview {
+	review := &authorizationv1.SelfSubjectAccessReview{}
+	if len(attributes.NonResourceURL) > 0 {
+		review.Spec.NonResourceAttributes = &authorizationv1.NonResourceAttributes{
+			Path: attributes.NonResourceURL,
+			Verb: attributes.Verb,
+		}
+		return review
+	}
+
+	review.Spec.ResourceAttributes = &authorizationv1.ResourceAttributes{
+		Namespace:   attributes.Namespace,
+		Verb:        attributes.Verb,
+		Group:       attributes.APIGroup,
+		Resource:    attributes.Resource,
+		Subresource: attributes.Subresource,
+		Name:        attributes.ResourceName,
+	}
+	return review
+}
@@ -95,12 +95,30 @@ func (self PersistentVolumeClaimCell) GetProperty(name dataselect.PropertyName)
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.StatusProperty:
+		return dataselect.StdComparableString(self.Status.Phase)
+	case dataselect.CapacityProperty:
+		capacity := self.Status.Capacity[api.ResourceStorage]
+		return dataselect.StdComparableInt(capacity.Value())
+	case dataselect.StorageClassProperty:
+		return dataselect.StdComparableString(getStorageClassName(api.PersistentVolumeClaim(self)))
+	case dataselect.LabelProperty:
+		return dataselect.StdComparableLabels(self.ObjectMeta.Labels)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
 	}
 }
 
+// getStorageClassName returns storage class of given claim. The deprecated beta annotation is used when
+// storage class is not set in the spec.
+func getStorageClassName(pvc api.PersistentVolumeClaim) string {
+	if pvc.Spec.StorageClassName != nil {
+		return *pvc.Spec.StorageClassName
+	}
+	return pvc.Annotations[api.BetaStorageClassAnnotation]
+}
+
 func toCells(std []api.PersistentVolumeClaim) []dataselect.DataCell {
 	cells := make([]dataselect.DataCell, len(std))
 	for i := range std {
@@ -0,0 +1,140 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	policy "k8s.io/api/policy/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	errorHandler "github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/controller"
+)
+
+// DeletePod deletes the pod gracefully. The termination grace period of the pod is used if no grace period is
+// given.
+func DeletePod(client kubernetes.Interface, namespace, name string, gracePeriodSeconds *int64) error {
+	log.Printf("Deleting pod %s in namespace %s", name, namespace)
+
+	if gracePeriodSeconds != nil && *gracePeriodSeconds < 0 {
+		return errorHandler.NewInvalid("Grace period must not be negative")
+	}
+
+	return client.CoreV1().Pods(namespace).Delete(context.TODO(), name, metaV1.DeleteOptions{
+		GracePeriodSeconds: gracePeriodSeconds,
+	})
+}
+
+// ForceDeletePod deletes the pod immediately, without waiting for the kubelet to confirm that the pod was
+// terminated. Containers may keep running on an unreachable node.
+func ForceDeletePod(client kubernetes.Interface, namespace, name string) error {
+	log.Printf("Force deleting pod %s in namespace %s", name, namespace)
+
+	gracePeriodSeconds := int64(0)
+	return client.CoreV1().Pods(namespace).Delete(context.TODO(), name, metaV1.DeleteOptions{
+		GracePeriodSeconds: &gracePeriodSeconds,
+	})
+}
+
+// EvictPod evicts the pod through the eviction subresource, which respects pod disruption budgets. Evictions
+// rejected by a budget are returned with the names of the budgets that cover the pod.
+func EvictPod(client kubernetes.Interface, namespace, name string, gracePeriodSeconds *int64) error {
+	log.Printf("Evicting pod %s in namespace %s", name, namespace)
+
+	if gracePeriodSeconds != nil && *gracePeriodSeconds < 0 {
+		return errorHandler.NewInvalid("Grace period must not be negative")
+	}
+
+	err := client.PolicyV1beta1().Evictions(namespace).Evict(context.TODO(), &policy.Eviction{
+		ObjectMeta:    metaV1.ObjectMeta{Name: name, Namespace: namespace},
+		DeleteOptions: &metaV1.DeleteOptions{GracePeriodSeconds: gracePeriodSeconds},
+	})
+	if k8serrors.IsTooManyRequests(err) {
+		return getEvictionRejectedError(client, namespace, name, err)
+	}
+
+	return err
+}
+
+// getEvictionRejectedError replaces the error of an eviction rejected by a pod disruption budget with a message,
+// that names the budgets covering the pod and how many disruptions they allow.
+func getEvictionRejectedError(client kubernetes.Interface, namespace, name string, err error) error {
+	pod, getErr := client.CoreV1().Pods(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if getErr != nil {
+		return err
+	}
+
+	budgets, listErr := client.PolicyV1beta1().PodDisruptionBudgets(namespace).List(context.TODO(),
+		metaV1.ListOptions{})
+	if listErr != nil {
+		return err
+	}
+
+	matching := make([]string, 0)
+	for _, budget := range budgets.Items {
+		selector, selectorErr := metaV1.LabelSelectorAsSelector(budget.Spec.Selector)
+		if selectorErr != nil || selector.Empty() || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		matching = append(matching, fmt.Sprintf("%s (%d disruptions allowed)", budget.Name,
+			budget.Status.DisruptionsAllowed))
+	}
+
+	if len(matching) == 0 {
+		return err
+	}
+
+	return k8serrors.NewTooManyRequests(fmt.Sprintf("Cannot evict pod %s, as it would violate pod disruption "+
+		"budget %s. Try again later or delete the pod instead.", name, strings.Join(matching, ", ")), 10)
+}
+
+// RestartPod deletes a pod that is managed by a controller, so that the controller replaces it with a new pod.
+// Pods without a controller are not deleted, as nothing would recreate them.
+func RestartPod(client kubernetes.Interface, namespace, name string) (*controller.ResourceOwner, error) {
+	log.Printf("Restarting pod %s in namespace %s", name, namespace)
+
+	pod, err := client.CoreV1().Pods(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := getPodController(client, common.NewSameNamespaceQuery(namespace), pod)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(owner.ObjectMeta.Name) == 0 {
+		return nil, errorHandler.NewInvalid(fmt.Sprintf("Pod %s is not managed by a supported controller and "+
+			"would not be recreated", name))
+	}
+
+	// Precondition makes sure that a pod recreated under the same name, i.e. by a stateful set, is not deleted.
+	err = client.CoreV1().Pods(namespace).Delete(context.TODO(), name, metaV1.DeleteOptions{
+		Preconditions: &metaV1.Preconditions{UID: &pod.UID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return owner, nil
+}
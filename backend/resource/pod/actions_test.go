@@ -0,0 +1,127 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func getActionTestPod(name string, owners ...metaV1.OwnerReference) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			UID:             types.UID("uid-" + name),
+			Labels:          map[string]string{"app": "web"},
+			OwnerReferences: owners,
+		},
+	}
+}
+
+func TestDeletePod(t *testing.T) {
+	gracePeriodSeconds := int64(5)
+	negative := int64(-1)
+
+	client := fake.NewSimpleClientset(getActionTestPod("web"), getActionTestPod("stuck"))
+	if err := DeletePod(client, "default", "web", &gracePeriodSeconds); err != nil {
+		t.Errorf("DeletePod() returned error: %s", err)
+	}
+	if err := ForceDeletePod(client, "default", "stuck"); err != nil {
+		t.Errorf("ForceDeletePod() returned error: %s", err)
+	}
+
+	pods, _ := client.CoreV1().Pods("default").List(context.TODO(), metaV1.ListOptions{})
+	if len(pods.Items) != 0 {
+		t.Errorf("DeletePod() and ForceDeletePod() left %d pods, expected none", len(pods.Items))
+	}
+
+	if err := DeletePod(client, "default", "web", &negative); err == nil {
+		t.Errorf("DeletePod() expected error for negative grace period")
+	}
+}
+
+func TestEvictPodRejectedByDisruptionBudget(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		getActionTestPod("web"),
+		&policy.PodDisruptionBudget{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web-pdb", Namespace: "default"},
+			Spec: policy.PodDisruptionBudgetSpec{
+				Selector: &metaV1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+		},
+		&policy.PodDisruptionBudget{
+			ObjectMeta: metaV1.ObjectMeta{Name: "db-pdb", Namespace: "default"},
+			Spec: policy.PodDisruptionBudgetSpec{
+				Selector: &metaV1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			},
+		},
+	)
+	client.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return action.GetSubresource() == "eviction", nil,
+			k8serrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 10)
+	})
+
+	err := EvictPod(client, "default", "web", nil)
+	if !k8serrors.IsTooManyRequests(err) || !strings.Contains(err.Error(), "web-pdb (0 disruptions allowed)") ||
+		strings.Contains(err.Error(), "db-pdb") {
+		t.Errorf("EvictPod() == %v, expected error naming web-pdb", err)
+	}
+}
+
+func TestRestartPod(t *testing.T) {
+	isController := true
+	rs := &apps.ReplicaSet{
+		ObjectMeta: metaV1.ObjectMeta{Name: "web-rs", Namespace: "default", UID: "rs-uid"},
+		Spec: apps.ReplicaSetSpec{
+			Selector: &metaV1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		},
+	}
+	client := fake.NewSimpleClientset(
+		rs,
+		getActionTestPod("web", metaV1.OwnerReference{Kind: "ReplicaSet", Name: "web-rs", UID: "rs-uid",
+			Controller: &isController}),
+		getActionTestPod("standalone"),
+	)
+
+	owner, err := RestartPod(client, "default", "web")
+	if err != nil {
+		t.Fatalf("RestartPod() returned error: %s", err)
+	}
+	if owner.ObjectMeta.Name != "web-rs" {
+		t.Errorf("RestartPod() returned owner %s, expected web-rs", owner.ObjectMeta.Name)
+	}
+	if _, err := client.CoreV1().Pods("default").Get(context.TODO(), "web", metaV1.GetOptions{}); err == nil {
+		t.Errorf("RestartPod() did not delete the pod")
+	}
+
+	if _, err := RestartPod(client, "default", "standalone"); err == nil {
+		t.Errorf("RestartPod() expected error for pod without controller")
+	}
+	if _, err := client.CoreV1().Pods("default").Get(context.TODO(), "standalone", metaV1.GetOptions{}); err != nil {
+		t.Errorf("RestartPod() deleted pod without controller")
+	}
+}
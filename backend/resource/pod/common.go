@@ -15,6 +15,8 @@
 package pod
 
 import (
+	"strings"
+
 	"github.com/ycyxuehan/dashboard-gin/backend/api"
 	metricapi "github.com/ycyxuehan/dashboard-gin/backend/integration/metric/api"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
@@ -101,6 +103,22 @@ func (self PodCell) GetProperty(name dataselect.PropertyName) dataselect.Compara
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.StatusProperty:
+		// Warning events are not available during data select, so pending pods with warnings are not reported
+		// as failed here.
+		return dataselect.StdComparableString(getPodStatusPhase(v1.Pod(self), nil))
+	case dataselect.RestartsProperty:
+		return dataselect.StdComparableInt(getRestartCount(v1.Pod(self)))
+	case dataselect.NodeNameProperty:
+		return dataselect.StdComparableString(self.Spec.NodeName)
+	case dataselect.QOSClassProperty:
+		return dataselect.StdComparableString(self.Status.QOSClass)
+	case dataselect.IPProperty:
+		return dataselect.StdComparableString(self.Status.PodIP)
+	case dataselect.ImageProperty:
+		return dataselect.StdComparableString(strings.Join(common.GetContainerImages(&self.Spec), ","))
+	case dataselect.LabelProperty:
+		return dataselect.StdComparableLabels(self.ObjectMeta.Labels)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
@@ -20,6 +20,7 @@ import (
 
 	"github.com/ycyxuehan/dashboard-gin/backend/api"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
 	v1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -244,3 +245,42 @@ func TestToPod(t *testing.T) {
 		}
 	}
 }
+
+func TestPodCellGetProperty(t *testing.T) {
+	pod := PodCell{
+		ObjectMeta: metaV1.ObjectMeta{Name: "test-pod"},
+		Spec: v1.PodSpec{
+			NodeName:   "node-1",
+			Containers: []v1.Container{{Image: "nginx:1.19"}, {Image: "busybox"}},
+		},
+		Status: v1.PodStatus{
+			Phase:    v1.PodPending,
+			PodIP:    "10.0.0.1",
+			QOSClass: v1.PodQOSBurstable,
+			ContainerStatuses: []v1.ContainerStatus{
+				{RestartCount: 2},
+				{RestartCount: 3},
+			},
+		},
+	}
+
+	cases := []struct {
+		property dataselect.PropertyName
+		expected dataselect.ComparableValue
+	}{
+		{dataselect.StatusProperty, dataselect.StdComparableString(v1.PodPending)},
+		{dataselect.RestartsProperty, dataselect.StdComparableInt(5)},
+		{dataselect.NodeNameProperty, dataselect.StdComparableString("node-1")},
+		{dataselect.QOSClassProperty, dataselect.StdComparableString(v1.PodQOSBurstable)},
+		{dataselect.IPProperty, dataselect.StdComparableString("10.0.0.1")},
+		{dataselect.ImageProperty, dataselect.StdComparableString("nginx:1.19,busybox")},
+		{dataselect.TaintCountProperty, nil},
+	}
+
+	for _, c := range cases {
+		actual := pod.GetProperty(c.property)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetProperty(%s) == %#v, expected %#v", c.property, actual, c.expected)
+		}
+	}
+}
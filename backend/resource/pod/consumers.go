@@ -0,0 +1,276 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+)
+
+// UsageType is the way a pod consumes a config map or a secret.
+type UsageType string
+
+// List of usage types.
+const (
+	UsageTypeEnv     UsageType = "env"
+	UsageTypeEnvFrom UsageType = "envFrom"
+	UsageTypeVolume  UsageType = "volume"
+)
+
+// ConfigSource is a config map or a secret consumed by pods.
+type ConfigSource struct {
+	// Kind is either api.ResourceKindConfigMap or api.ResourceKindSecret.
+	Kind      string
+	Namespace string
+	Name      string
+
+	// Key limits the consumers to the ones that use this key. All consumers are returned if it is empty.
+	Key string
+}
+
+// Usage is a single reference to a config source in a pod spec.
+type Usage struct {
+	Type      UsageType `json:"type"`
+	Container string    `json:"container,omitempty"`
+	Volume    string    `json:"volume,omitempty"`
+
+	// Keys used through the reference. Empty if all keys are used.
+	Keys []string `json:"keys"`
+}
+
+// Consumer is a pod or a workload, whose pod template consumes a config source.
+type Consumer struct {
+	Kind   string  `json:"kind"`
+	Name   string  `json:"name"`
+	Usages []Usage `json:"usages"`
+
+	// Controller of a consuming pod, if any.
+	OwnerKind string `json:"ownerKind,omitempty"`
+	OwnerName string `json:"ownerName,omitempty"`
+}
+
+// Consumers of a config source.
+type Consumers struct {
+	Pods      []Consumer `json:"pods"`
+	Workloads []Consumer `json:"workloads"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetConsumers returns the pods, deployments, stateful sets and daemon sets that consume the config source through
+// env, envFrom or volumes. Env references are matched with the same helpers evalValueFrom and evalEnvFrom use to
+// resolve them for pod details.
+func GetConsumers(client kubernetes.Interface, source ConfigSource) (*Consumers, error) {
+	log.Printf("Getting consumers of %s %s in %s namespace", source.Kind, source.Name, source.Namespace)
+
+	nsQuery := common.NewSameNamespaceQuery(source.Namespace)
+	channels := &common.ResourceChannels{
+		PodList:         common.GetPodListChannel(client, nsQuery, 1),
+		DeploymentList:  common.GetDeploymentListChannel(client, nsQuery, 1),
+		StatefulSetList: common.GetStatefulSetListChannel(client, nsQuery, 1),
+		DaemonSetList:   common.GetDaemonSetListChannel(client, nsQuery, 1),
+	}
+
+	pods := <-channels.PodList.List
+	err := <-channels.PodList.Error
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	deployments := <-channels.DeploymentList.List
+	err = <-channels.DeploymentList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	statefulSets := <-channels.StatefulSetList.List
+	err = <-channels.StatefulSetList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	daemonSets := <-channels.DaemonSetList.List
+	err = <-channels.DaemonSetList.Error
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	consumers := &Consumers{
+		Pods:      make([]Consumer, 0),
+		Workloads: make([]Consumer, 0),
+		Errors:    nonCriticalErrors,
+	}
+
+	if pods != nil {
+		for _, pod := range pods.Items {
+			if usages := getUsages(pod.Spec, source); len(usages) > 0 {
+				consumer := Consumer{Kind: api.ResourceKindPod, Name: pod.Name, Usages: usages}
+				if owner := metaV1.GetControllerOf(&pod); owner != nil {
+					consumer.OwnerKind = strings.ToLower(owner.Kind)
+					consumer.OwnerName = owner.Name
+				}
+				consumers.Pods = append(consumers.Pods, consumer)
+			}
+		}
+	}
+
+	addWorkload := func(kind, name string, spec v1.PodSpec) {
+		if usages := getUsages(spec, source); len(usages) > 0 {
+			consumers.Workloads = append(consumers.Workloads, Consumer{Kind: kind, Name: name, Usages: usages})
+		}
+	}
+	if deployments != nil {
+		for _, deployment := range deployments.Items {
+			addWorkload(api.ResourceKindDeployment, deployment.Name, deployment.Spec.Template.Spec)
+		}
+	}
+	if statefulSets != nil {
+		for _, statefulSet := range statefulSets.Items {
+			addWorkload(api.ResourceKindStatefulSet, statefulSet.Name, statefulSet.Spec.Template.Spec)
+		}
+	}
+	if daemonSets != nil {
+		for _, daemonSet := range daemonSets.Items {
+			addWorkload(api.ResourceKindDaemonSet, daemonSet.Name, daemonSet.Spec.Template.Spec)
+		}
+	}
+
+	sortConsumers(consumers.Pods)
+	sortConsumers(consumers.Workloads)
+	return consumers, nil
+}
+
+// getUsages returns all references to the config source in the pod spec, that use the key of the source.
+func getUsages(spec v1.PodSpec, source ConfigSource) []Usage {
+	usages := make([]Usage, 0)
+
+	containers := append(append([]v1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, container := range containers {
+		keys := make([]string, 0)
+		for _, env := range container.Env {
+			if key, ok := source.envKey(env.ValueFrom); ok && !containsKey(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) > 0 && source.usesAny(keys) {
+			sort.Strings(keys)
+			usages = append(usages, Usage{Type: UsageTypeEnv, Container: container.Name, Keys: keys})
+		}
+
+		for _, envFrom := range container.EnvFrom {
+			if source.isEnvFromSource(envFrom) {
+				usages = append(usages, Usage{Type: UsageTypeEnvFrom, Container: container.Name, Keys: []string{}})
+				break
+			}
+		}
+	}
+
+	for _, volume := range spec.Volumes {
+		keys, ok := source.volumeKeys(volume.VolumeSource)
+		if ok && (len(keys) == 0 || source.usesAny(keys)) {
+			usages = append(usages, Usage{Type: UsageTypeVolume, Volume: volume.Name, Keys: keys})
+		}
+	}
+
+	return usages
+}
+
+// envKey returns the key used by an env variable, if it references the source.
+func (source ConfigSource) envKey(valueFrom *v1.EnvVarSource) (string, bool) {
+	kind, name, key := keyReference(valueFrom)
+	return key, len(kind) > 0 && kind == source.Kind && name == source.Name
+}
+
+func (source ConfigSource) isEnvFromSource(envFrom v1.EnvFromSource) bool {
+	kind, name := envFromReference(envFrom)
+	return len(kind) > 0 && kind == source.Kind && name == source.Name
+}
+
+// volumeKeys returns the keys mounted by a volume, if it references the source. Keys are empty if all keys are
+// mounted. Projected volumes are checked for all of their sources.
+func (source ConfigSource) volumeKeys(volume v1.VolumeSource) ([]string, bool) {
+	var references [][]v1.KeyToPath
+	switch {
+	case source.Kind == api.ResourceKindConfigMap && volume.ConfigMap != nil && volume.ConfigMap.Name == source.Name:
+		references = append(references, volume.ConfigMap.Items)
+	case source.Kind == api.ResourceKindSecret && volume.Secret != nil && volume.Secret.SecretName == source.Name:
+		references = append(references, volume.Secret.Items)
+	case volume.Projected != nil:
+		for _, projection := range volume.Projected.Sources {
+			if source.Kind == api.ResourceKindConfigMap && projection.ConfigMap != nil &&
+				projection.ConfigMap.Name == source.Name {
+				references = append(references, projection.ConfigMap.Items)
+			}
+			if source.Kind == api.ResourceKindSecret && projection.Secret != nil &&
+				projection.Secret.Name == source.Name {
+				references = append(references, projection.Secret.Items)
+			}
+		}
+	}
+
+	if len(references) == 0 {
+		return nil, false
+	}
+
+	keys := make([]string, 0)
+	for _, items := range references {
+		if len(items) == 0 {
+			return []string{}, true
+		}
+		for _, item := range items {
+			if !containsKey(keys, item.Key) {
+				keys = append(keys, item.Key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys, true
+}
+
+// usesAny returns true if the source has no key or the key is one of the given keys.
+func (source ConfigSource) usesAny(keys []string) bool {
+	return len(source.Key) == 0 || containsKey(keys, source.Key)
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func sortConsumers(consumers []Consumer) {
+	sort.Slice(consumers, func(i, j int) bool {
+		return fmt.Sprintf("%s/%s", consumers[i].Kind, consumers[i].Name) <
+			fmt.Sprintf("%s/%s", consumers[j].Kind, consumers[j].Name)
+	})
+}
@@ -0,0 +1,137 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"reflect"
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+)
+
+var consumerPodSpec = v1.PodSpec{
+	Containers: []v1.Container{{
+		Name: "app",
+		Env: []v1.EnvVar{
+			{Name: "LEVEL", ValueFrom: &v1.EnvVarSource{ConfigMapKeyRef: &v1.ConfigMapKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "config"}, Key: "level"}}},
+			{Name: "PASSWORD", ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "config"}, Key: "password"}}},
+		},
+		EnvFrom: []v1.EnvFromSource{{SecretRef: &v1.SecretEnvSource{
+			LocalObjectReference: v1.LocalObjectReference{Name: "credentials"}}}},
+	}},
+	Volumes: []v1.Volume{
+		{Name: "files", VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{
+			LocalObjectReference: v1.LocalObjectReference{Name: "config"},
+			Items:                []v1.KeyToPath{{Key: "app.yaml", Path: "app.yaml"}},
+		}}},
+		{Name: "projected", VolumeSource: v1.VolumeSource{Projected: &v1.ProjectedVolumeSource{
+			Sources: []v1.VolumeProjection{{Secret: &v1.SecretProjection{
+				LocalObjectReference: v1.LocalObjectReference{Name: "credentials"}}}},
+		}}},
+	},
+}
+
+func TestGetUsages(t *testing.T) {
+	cases := []struct {
+		source   ConfigSource
+		expected []Usage
+	}{
+		{
+			ConfigSource{Kind: api.ResourceKindConfigMap, Name: "config"},
+			[]Usage{
+				{Type: UsageTypeEnv, Container: "app", Keys: []string{"level"}},
+				{Type: UsageTypeVolume, Volume: "files", Keys: []string{"app.yaml"}},
+			},
+		},
+		{
+			ConfigSource{Kind: api.ResourceKindConfigMap, Name: "config", Key: "app.yaml"},
+			[]Usage{{Type: UsageTypeVolume, Volume: "files", Keys: []string{"app.yaml"}}},
+		},
+		{
+			ConfigSource{Kind: api.ResourceKindConfigMap, Name: "config", Key: "other"},
+			[]Usage{},
+		},
+		{
+			ConfigSource{Kind: api.ResourceKindSecret, Name: "config"},
+			[]Usage{{Type: UsageTypeEnv, Container: "app", Keys: []string{"password"}}},
+		},
+		{
+			ConfigSource{Kind: api.ResourceKindSecret, Name: "credentials", Key: "token"},
+			[]Usage{
+				{Type: UsageTypeEnvFrom, Container: "app", Keys: []string{}},
+				{Type: UsageTypeVolume, Volume: "projected", Keys: []string{}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := getUsages(consumerPodSpec, c.source)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("getUsages(%#v) == \n%#v\nexpected \n%#v", c.source, actual, c.expected)
+		}
+	}
+}
+
+func TestGetConsumers(t *testing.T) {
+	replicas := int32(1)
+	client := fake.NewSimpleClientset(
+		&v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web-1", Namespace: "default", OwnerReferences: []metaV1.OwnerReference{
+				{Kind: "ReplicaSet", Name: "web-abc", Controller: &[]bool{true}[0]},
+			}},
+			Spec: consumerPodSpec,
+		},
+		&v1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: "other", Namespace: "default"}},
+		&apps.Deployment{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: apps.DeploymentSpec{
+				Replicas: &replicas,
+				Template: v1.PodTemplateSpec{Spec: consumerPodSpec},
+			},
+		},
+		&apps.StatefulSet{
+			ObjectMeta: metaV1.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec:       apps.StatefulSetSpec{Template: v1.PodTemplateSpec{Spec: consumerPodSpec}},
+		},
+	)
+
+	consumers, err := GetConsumers(client, ConfigSource{Kind: api.ResourceKindConfigMap, Namespace: "default",
+		Name: "config", Key: "level"})
+	if err != nil {
+		t.Fatalf("GetConsumers() unexpected error: %v", err)
+	}
+
+	usages := []Usage{{Type: UsageTypeEnv, Container: "app", Keys: []string{"level"}}}
+	expected := &Consumers{
+		Pods: []Consumer{
+			{Kind: api.ResourceKindPod, Name: "web-1", Usages: usages, OwnerKind: "replicaset", OwnerName: "web-abc"},
+		},
+		Workloads: []Consumer{
+			{Kind: api.ResourceKindDeployment, Name: "web", Usages: usages},
+			{Kind: api.ResourceKindStatefulSet, Name: "db", Usages: usages},
+		},
+		Errors: []error{},
+	}
+	if !reflect.DeepEqual(consumers, expected) {
+		t.Errorf("GetConsumers() == \n%#v\nexpected \n%#v", consumers, expected)
+	}
+}
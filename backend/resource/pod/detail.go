@@ -232,9 +232,8 @@ func toPodDetail(pod *v1.Pod, metrics []metricapi.Metric, configMaps *v1.ConfigM
 func evalEnvFrom(container v1.Container, configMaps *v1.ConfigMapList, secrets *v1.SecretList) []EnvVar {
 	vars := make([]EnvVar, 0)
 	for _, envFromVar := range container.EnvFrom {
-		switch {
-		case envFromVar.ConfigMapRef != nil:
-			name := envFromVar.ConfigMapRef.LocalObjectReference.Name
+		switch kind, name := envFromReference(envFromVar); kind {
+		case api.ResourceKindConfigMap:
 			for _, configMap := range configMaps.Items {
 				if configMap.ObjectMeta.Name == name {
 					for key, value := range configMap.Data {
@@ -256,8 +255,7 @@ func evalEnvFrom(container v1.Container, configMaps *v1.ConfigMapList, secrets *
 					break
 				}
 			}
-		case envFromVar.SecretRef != nil:
-			name := envFromVar.SecretRef.LocalObjectReference.Name
+		case api.ResourceKindSecret:
 			for _, secret := range secrets.Items {
 				if secret.ObjectMeta.Name == name {
 					for key, value := range secret.Data {
@@ -284,24 +282,49 @@ func evalEnvFrom(container v1.Container, configMaps *v1.ConfigMapList, secrets *
 	return vars
 }
 
+// envFromReference returns the kind, either api.ResourceKindConfigMap or api.ResourceKindSecret, and the name of the
+// config source of an envFrom entry. Kind is empty if the entry references neither.
+func envFromReference(envFrom v1.EnvFromSource) (string, string) {
+	switch {
+	case envFrom.ConfigMapRef != nil:
+		return api.ResourceKindConfigMap, envFrom.ConfigMapRef.LocalObjectReference.Name
+	case envFrom.SecretRef != nil:
+		return api.ResourceKindSecret, envFrom.SecretRef.LocalObjectReference.Name
+	}
+	return "", ""
+}
+
+// keyReference returns the kind, either api.ResourceKindConfigMap or api.ResourceKindSecret, the name and the key of
+// the config source an env value is taken from. Kind is empty if the value does not come from a config source.
+func keyReference(src *v1.EnvVarSource) (string, string, string) {
+	switch {
+	case src == nil:
+		return "", "", ""
+	case src.ConfigMapKeyRef != nil:
+		return api.ResourceKindConfigMap, src.ConfigMapKeyRef.LocalObjectReference.Name, src.ConfigMapKeyRef.Key
+	case src.SecretKeyRef != nil:
+		return api.ResourceKindSecret, src.SecretKeyRef.LocalObjectReference.Name, src.SecretKeyRef.Key
+	}
+	return "", "", ""
+}
+
 // evalValueFrom evaluates environment value from given source. For more details check:
 // https://github.com/kubernetes/kubernetes/blob/d82e51edc5f02bff39661203c9b503d054c3493b/pkg/kubectl/describe.go#L1056
 func evalValueFrom(src *v1.EnvVarSource, container *v1.Container, pod *v1.Pod,
 	configMaps *v1.ConfigMapList, secrets *v1.SecretList) string {
+	kind, name, key := keyReference(src)
 	switch {
-	case src.ConfigMapKeyRef != nil:
-		name := src.ConfigMapKeyRef.LocalObjectReference.Name
+	case kind == api.ResourceKindConfigMap:
 		for _, configMap := range configMaps.Items {
 			if configMap.ObjectMeta.Name == name {
-				return configMap.Data[src.ConfigMapKeyRef.Key]
+				return configMap.Data[key]
 			}
 		}
-	case src.SecretKeyRef != nil:
-		name := src.SecretKeyRef.LocalObjectReference.Name
+	case kind == api.ResourceKindSecret:
 		for _, secret := range secrets.Items {
 			if secret.ObjectMeta.Name == name {
 				return base64.StdEncoding.EncodeToString([]byte(
-					secret.Data[src.SecretKeyRef.Key]))
+					secret.Data[key]))
 			}
 		}
 	case src.ResourceFieldRef != nil:
@@ -0,0 +1,72 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package poddisruptionbudget
+
+import (
+	"context"
+	"log"
+
+	policy "k8s.io/api/policy/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// PodDisruptionBudgetDetail contains detailed information about a pod disruption budget.
+type PodDisruptionBudgetDetail struct {
+	// Extends list item structure.
+	PodDisruptionBudget `json:",inline"`
+
+	// Label query over pods whose evictions are managed by the budget.
+	Selector *metaV1.LabelSelector `json:"selector"`
+
+	// Pods whose eviction was processed by the API server but that were not deleted yet, with the time the eviction
+	// was processed.
+	DisruptedPods map[string]metaV1.Time `json:"disruptedPods"`
+
+	// Most recent generation observed when updating the status.
+	ObservedGeneration int64 `json:"observedGeneration"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetPodDisruptionBudgetDetail returns detailed information about a pod disruption budget.
+func GetPodDisruptionBudgetDetail(client client.Interface, namespace, name string) (*PodDisruptionBudgetDetail,
+	error) {
+	log.Printf("Getting details of %s pod disruption budget in %s namespace", name, namespace)
+
+	raw, err := client.PolicyV1beta1().PodDisruptionBudgets(namespace).Get(context.TODO(), name,
+		metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return getPodDisruptionBudgetDetail(raw), nil
+}
+
+func getPodDisruptionBudgetDetail(pdb *policy.PodDisruptionBudget) *PodDisruptionBudgetDetail {
+	disruptedPods := pdb.Status.DisruptedPods
+	if disruptedPods == nil {
+		disruptedPods = make(map[string]metaV1.Time)
+	}
+
+	return &PodDisruptionBudgetDetail{
+		PodDisruptionBudget: toPodDisruptionBudget(pdb),
+		Selector:            pdb.Spec.Selector,
+		DisruptedPods:       disruptedPods,
+		ObservedGeneration:  pdb.Status.ObservedGeneration,
+		Errors:              []error{},
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package poddisruptionbudget
+
+import (
+	"context"
+
+	policy "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
+)
+
+// PodDisruptionBudget contains information about a single pod disruption budget in the list.
+type PodDisruptionBudget struct {
+	api.ObjectMeta `json:"objectMeta"`
+	api.TypeMeta   `json:"typeMeta"`
+
+	// Minimum number or percentage of pods that must stay available. Only one of MinAvailable and MaxUnavailable
+	// is set.
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// Maximum number or percentage of pods that may be unavailable.
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// Number of currently healthy pods matched by the budget.
+	CurrentHealthy int32 `json:"currentHealthy"`
+
+	// Minimum number of healthy pods required by the budget.
+	DesiredHealthy int32 `json:"desiredHealthy"`
+
+	// Number of pods that can be evicted right now without violating the budget.
+	DisruptionsAllowed int32 `json:"disruptionsAllowed"`
+
+	// Total number of pods counted by the budget.
+	ExpectedPods int32 `json:"expectedPods"`
+}
+
+// PodDisruptionBudgetList contains a list of pod disruption budgets.
+type PodDisruptionBudgetList struct {
+	api.ListMeta `json:"listMeta"`
+	Items        []PodDisruptionBudget `json:"items"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetPodDisruptionBudgetList lists pod disruption budgets from given namespace using given data select query.
+func GetPodDisruptionBudgetList(client client.Interface, namespace *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*PodDisruptionBudgetList, error) {
+	pdbList, err := client.PolicyV1beta1().PodDisruptionBudgets(namespace.ToRequestParam()).List(context.TODO(),
+		api.ListEverything)
+
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	return toPodDisruptionBudgetList(pdbList.Items, nonCriticalErrors, dsQuery), nil
+}
+
+func toPodDisruptionBudget(pdb *policy.PodDisruptionBudget) PodDisruptionBudget {
+	return PodDisruptionBudget{
+		ObjectMeta:         api.NewObjectMeta(pdb.ObjectMeta),
+		TypeMeta:           api.NewTypeMeta(api.ResourceKindPodDisruptionBudget),
+		MinAvailable:       pdb.Spec.MinAvailable,
+		MaxUnavailable:     pdb.Spec.MaxUnavailable,
+		CurrentHealthy:     pdb.Status.CurrentHealthy,
+		DesiredHealthy:     pdb.Status.DesiredHealthy,
+		DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+		ExpectedPods:       pdb.Status.ExpectedPods,
+	}
+}
+
+func toPodDisruptionBudgetList(pdbs []policy.PodDisruptionBudget, nonCriticalErrors []error,
+	dsQuery *dataselect.DataSelectQuery) *PodDisruptionBudgetList {
+	result := &PodDisruptionBudgetList{
+		Items:  make([]PodDisruptionBudget, 0),
+		Errors: nonCriticalErrors,
+	}
+
+	pdbCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(pdbs), dsQuery)
+	pdbs = fromCells(pdbCells)
+	result.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+
+	for _, pdb := range pdbs {
+		result.Items = append(result.Items, toPodDisruptionBudget(&pdb))
+	}
+
+	return result
+}
@@ -0,0 +1,101 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package poddisruptionbudget
+
+import (
+	"reflect"
+	"testing"
+
+	policy "k8s.io/api/policy/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
+)
+
+func TestGetPodDisruptionBudgetList(t *testing.T) {
+	minAvailable := intstr.FromInt(2)
+	maxUnavailable := intstr.FromString("25%")
+
+	cases := []struct {
+		pdbList  *policy.PodDisruptionBudgetList
+		expected *PodDisruptionBudgetList
+	}{
+		{
+			&policy.PodDisruptionBudgetList{},
+			&PodDisruptionBudgetList{
+				ListMeta: api.ListMeta{},
+				Items:    []PodDisruptionBudget{},
+				Errors:   []error{},
+			},
+		},
+		{
+			&policy.PodDisruptionBudgetList{
+				Items: []policy.PodDisruptionBudget{
+					{
+						ObjectMeta: metaV1.ObjectMeta{Name: "pdb-1", Namespace: "ns-1"},
+						Spec:       policy.PodDisruptionBudgetSpec{MinAvailable: &minAvailable},
+						Status: policy.PodDisruptionBudgetStatus{
+							CurrentHealthy:     3,
+							DesiredHealthy:     2,
+							DisruptionsAllowed: 1,
+							ExpectedPods:       3,
+						},
+					},
+					{
+						ObjectMeta: metaV1.ObjectMeta{Name: "pdb-2", Namespace: "ns-1"},
+						Spec:       policy.PodDisruptionBudgetSpec{MaxUnavailable: &maxUnavailable},
+					},
+				},
+			},
+			&PodDisruptionBudgetList{
+				ListMeta: api.ListMeta{TotalItems: 2},
+				Items: []PodDisruptionBudget{
+					{
+						ObjectMeta:         api.ObjectMeta{Name: "pdb-1", Namespace: "ns-1"},
+						TypeMeta:           api.TypeMeta{Kind: api.ResourceKindPodDisruptionBudget},
+						MinAvailable:       &minAvailable,
+						CurrentHealthy:     3,
+						DesiredHealthy:     2,
+						DisruptionsAllowed: 1,
+						ExpectedPods:       3,
+					},
+					{
+						ObjectMeta:     api.ObjectMeta{Name: "pdb-2", Namespace: "ns-1"},
+						TypeMeta:       api.TypeMeta{Kind: api.ResourceKindPodDisruptionBudget},
+						MaxUnavailable: &maxUnavailable,
+					},
+				},
+				Errors: []error{},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		fakeClient := fake.NewSimpleClientset(c.pdbList)
+		actual, err := GetPodDisruptionBudgetList(fakeClient, common.NewNamespaceQuery(nil),
+			dataselect.NoDataSelect)
+		if err != nil {
+			t.Fatalf("GetPodDisruptionBudgetList() returned error: %v", err)
+		}
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetPodDisruptionBudgetList() == \ngot: %#v, \nexpected %#v", actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package poddisruptionbudget
+
+import (
+	"context"
+	"log"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	metricapi "github.com/ycyxuehan/dashboard-gin/backend/integration/metric/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/event"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/pod"
+)
+
+// GetPodDisruptionBudgetPods returns the list of pods matched by the pod disruption budget.
+func GetPodDisruptionBudgetPods(client client.Interface, metricClient metricapi.MetricClient,
+	dsQuery *dataselect.DataSelectQuery, namespace, name string) (*pod.PodList, error) {
+	log.Printf("Getting pods of %s pod disruption budget in %s namespace", name, namespace)
+
+	pdb, err := client.PolicyV1beta1().PodDisruptionBudgets(namespace).Get(context.TODO(), name,
+		metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := getPodSelector(pdb)
+	if err != nil {
+		return nil, err
+	}
+
+	if selector == nil {
+		return pod.EmptyPodList, nil
+	}
+
+	channels := &common.ResourceChannels{
+		PodList: common.GetPodListChannelWithOptions(client, common.NewSameNamespaceQuery(namespace),
+			metaV1.ListOptions{LabelSelector: selector.String()}, 1),
+	}
+
+	podList := <-channels.PodList.List
+	err = <-channels.PodList.Error
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	pods := make([]v1.Pod, 0)
+	if podList != nil {
+		pods = podList.Items
+	}
+
+	events, err := event.GetPodsEvents(client, namespace, pods)
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	result := pod.ToPodList(pods, events, nonCriticalErrors, dsQuery, metricClient)
+	return &result, nil
+}
+
+// GetProtectingBudgets returns the pod disruption budgets in the namespace that match pods with the given labels,
+// i.e. the budgets that limit voluntary evictions of a workload with this pod template.
+func GetProtectingBudgets(client client.Interface, namespace string,
+	podLabels map[string]string) ([]PodDisruptionBudget, error) {
+	result := make([]PodDisruptionBudget, 0)
+
+	pdbList, err := client.PolicyV1beta1().PodDisruptionBudgets(namespace).List(context.TODO(),
+		metaV1.ListOptions{})
+	if err != nil {
+		return result, err
+	}
+
+	for i := range pdbList.Items {
+		selector, err := getPodSelector(&pdbList.Items[i])
+		if err != nil || selector == nil {
+			continue
+		}
+
+		if selector.Matches(labels.Set(podLabels)) {
+			result = append(result, toPodDisruptionBudget(&pdbList.Items[i]))
+		}
+	}
+
+	return result, nil
+}
+
+// getPodSelector returns the pod selector of the budget, or nil if the budget matches no pods. In policy/v1beta1 a
+// missing or empty selector matches no pods, unlike most other label selectors.
+func getPodSelector(pdb *policy.PodDisruptionBudget) (labels.Selector, error) {
+	if pdb.Spec.Selector == nil ||
+		len(pdb.Spec.Selector.MatchLabels) == 0 && len(pdb.Spec.Selector.MatchExpressions) == 0 {
+		return nil, nil
+	}
+
+	return metaV1.LabelSelectorAsSelector(pdb.Spec.Selector)
+}
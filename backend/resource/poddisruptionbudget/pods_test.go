@@ -0,0 +1,105 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package poddisruptionbudget
+
+import (
+	"testing"
+
+	policy "k8s.io/api/policy/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetProtectingBudgets(t *testing.T) {
+	pdbList := &policy.PodDisruptionBudgetList{
+		Items: []policy.PodDisruptionBudget{
+			{
+				ObjectMeta: metaV1.ObjectMeta{Name: "by-label", Namespace: "ns-1"},
+				Spec: policy.PodDisruptionBudgetSpec{
+					Selector: &metaV1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				},
+			},
+			{
+				ObjectMeta: metaV1.ObjectMeta{Name: "by-expression", Namespace: "ns-1"},
+				Spec: policy.PodDisruptionBudgetSpec{
+					Selector: &metaV1.LabelSelector{
+						MatchExpressions: []metaV1.LabelSelectorRequirement{
+							{Key: "tier", Operator: metaV1.LabelSelectorOpIn, Values: []string{"frontend", "backend"}},
+						},
+					},
+				},
+			},
+			{
+				ObjectMeta: metaV1.ObjectMeta{Name: "empty-selector", Namespace: "ns-1"},
+				Spec:       policy.PodDisruptionBudgetSpec{Selector: &metaV1.LabelSelector{}},
+			},
+			{
+				ObjectMeta: metaV1.ObjectMeta{Name: "no-selector", Namespace: "ns-1"},
+			},
+			{
+				ObjectMeta: metaV1.ObjectMeta{Name: "other-namespace", Namespace: "ns-2"},
+				Spec: policy.PodDisruptionBudgetSpec{
+					Selector: &metaV1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		info      string
+		podLabels map[string]string
+		expected  []string
+	}{
+		{
+			"matching labels and expression",
+			map[string]string{"app": "web", "tier": "frontend"},
+			[]string{"by-label", "by-expression"},
+		},
+		{
+			"matching expression only",
+			map[string]string{"app": "api", "tier": "backend"},
+			[]string{"by-expression"},
+		},
+		{
+			"no labels",
+			nil,
+			[]string{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.info, func(t *testing.T) {
+			fakeClient := fake.NewSimpleClientset(pdbList)
+			actual, err := GetProtectingBudgets(fakeClient, "ns-1", c.podLabels)
+			if err != nil {
+				t.Fatalf("GetProtectingBudgets() returned error: %v", err)
+			}
+
+			names := make([]string, 0)
+			for _, pdb := range actual {
+				names = append(names, pdb.Name)
+			}
+
+			if len(names) != len(c.expected) {
+				t.Fatalf("GetProtectingBudgets() == %v, expected %v", names, c.expected)
+			}
+			for i := range names {
+				if names[i] != c.expected[i] {
+					t.Errorf("GetProtectingBudgets() == %v, expected %v", names, c.expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package role
+
+import (
+	"context"
+	"log"
+
+	rbac "k8s.io/api/rbac/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sClient "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/permission"
+)
+
+// RoleSpec is a specification of a role to create or update.
+type RoleSpec struct {
+	// Name is only used on creation.
+	Name string `json:"name"`
+
+	// Labels replace the labels of the role if set.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	Rules []rbac.PolicyRule `json:"rules"`
+}
+
+// CreateRole creates a role in the given namespace, after checking its rules against discovery.
+func CreateRole(client k8sClient.Interface, namespace string, spec *RoleSpec) (*RoleDetail, error) {
+	log.Printf("Creating role %s in %s namespace", spec.Name, namespace)
+
+	if len(spec.Name) == 0 {
+		return nil, errors.NewInvalid("Name of the role is required")
+	}
+	if err := permission.ValidateRules(client, spec.Rules, false); err != nil {
+		return nil, err
+	}
+
+	role := &rbac.Role{
+		ObjectMeta: metaV1.ObjectMeta{Name: spec.Name, Namespace: namespace, Labels: spec.Labels},
+		Rules:      spec.Rules,
+	}
+
+	created, err := client.RbacV1().Roles(namespace).Create(context.TODO(), role, metaV1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := toRoleDetail(*created)
+	return &result, nil
+}
+
+// UpdateRole replaces the rules of the role with the given name.
+func UpdateRole(client k8sClient.Interface, namespace, name string, spec *RoleSpec) (*RoleDetail, error) {
+	log.Printf("Updating role %s in %s namespace", name, namespace)
+
+	if err := permission.ValidateRules(client, spec.Rules, false); err != nil {
+		return nil, err
+	}
+
+	role, err := client.RbacV1().Roles(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.Labels != nil {
+		role.Labels = spec.Labels
+	}
+	role.Rules = spec.Rules
+
+	updated, err := client.RbacV1().Roles(namespace).Update(context.TODO(), role, metaV1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := toRoleDetail(*updated)
+	return &result, nil
+}
+
+// DeleteRole deletes the role with the given name. Bindings that refer to it are left in place and grant nothing
+// until a role with the same name is created again.
+func DeleteRole(client k8sClient.Interface, namespace, name string) error {
+	log.Printf("Deleting role %s in %s namespace", name, namespace)
+	return client.RbacV1().Roles(namespace).Delete(context.TODO(), name, metaV1.DeleteOptions{})
+}
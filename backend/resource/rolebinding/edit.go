@@ -0,0 +1,141 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rolebinding
+
+import (
+	"context"
+	"log"
+
+	rbac "k8s.io/api/rbac/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sClient "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/permission"
+)
+
+// RoleBindingSpec is a specification of a role binding to create or update.
+type RoleBindingSpec struct {
+	// Name is only used on creation.
+	Name string `json:"name"`
+
+	// Labels replace the labels of the role binding if set.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// RoleRef cannot be changed after creation. It may be left empty on update.
+	RoleRef rbac.RoleRef `json:"roleRef"`
+
+	Subjects []rbac.Subject `json:"subjects"`
+}
+
+// CreateRoleBinding creates a role binding in the given namespace.
+func CreateRoleBinding(client k8sClient.Interface, namespace string, spec *RoleBindingSpec) (*RoleBindingDetail,
+	error) {
+	log.Printf("Creating role binding %s in %s namespace", spec.Name, namespace)
+
+	if len(spec.Name) == 0 {
+		return nil, errors.NewInvalid("Name of the role binding is required")
+	}
+
+	roleRef, err := permission.ValidateRoleRef(spec.RoleRef, true)
+	if err != nil {
+		return nil, err
+	}
+
+	subjects, err := permission.ValidateSubjects(spec.Subjects, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	binding := &rbac.RoleBinding{
+		ObjectMeta: metaV1.ObjectMeta{Name: spec.Name, Namespace: namespace, Labels: spec.Labels},
+		RoleRef:    roleRef,
+		Subjects:   subjects,
+	}
+
+	created, err := client.RbacV1().RoleBindings(namespace).Create(context.TODO(), binding, metaV1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := toRoleBindingDetail(*created)
+	return &result, nil
+}
+
+// UpdateRoleBinding replaces the subjects of the role binding with the given name.
+func UpdateRoleBinding(client k8sClient.Interface, namespace, name string,
+	spec *RoleBindingSpec) (*RoleBindingDetail, error) {
+	log.Printf("Updating role binding %s in %s namespace", name, namespace)
+
+	subjects, err := permission.ValidateSubjects(spec.Subjects, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	binding, err := client.RbacV1().RoleBindings(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(spec.RoleRef.Name) > 0 && (spec.RoleRef.Name != binding.RoleRef.Name ||
+		spec.RoleRef.Kind != binding.RoleRef.Kind) {
+		return nil, errors.NewInvalid("Role reference of a binding cannot be changed, create a new binding instead")
+	}
+
+	if spec.Labels != nil {
+		binding.Labels = spec.Labels
+	}
+	binding.Subjects = subjects
+
+	updated, err := client.RbacV1().RoleBindings(namespace).Update(context.TODO(), binding, metaV1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := toRoleBindingDetail(*updated)
+	return &result, nil
+}
+
+// PatchRoleBindingSubjects adds subjects to and removes subjects from the role binding with the given name.
+func PatchRoleBindingSubjects(client k8sClient.Interface, namespace, name string,
+	patch *permission.SubjectPatch) (*RoleBindingDetail, error) {
+	log.Printf("Patching subjects of role binding %s in %s namespace", name, namespace)
+
+	binding, err := client.RbacV1().RoleBindings(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := permission.GetSubjectsPatch(binding.Subjects, binding.ResourceVersion, namespace, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	patched, err := client.RbacV1().RoleBindings(namespace).Patch(context.TODO(), name, types.MergePatchType, data,
+		metaV1.PatchOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := toRoleBindingDetail(*patched)
+	return &result, nil
+}
+
+// DeleteRoleBinding deletes the role binding with the given name.
+func DeleteRoleBinding(client k8sClient.Interface, namespace, name string) error {
+	log.Printf("Deleting role binding %s in %s namespace", name, namespace)
+	return client.RbacV1().RoleBindings(namespace).Delete(context.TODO(), name, metaV1.DeleteOptions{})
+}
@@ -0,0 +1,101 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rolebinding
+
+import (
+	"reflect"
+	"testing"
+
+	rbac "k8s.io/api/rbac/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/permission"
+)
+
+func TestCreateRoleBinding(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	spec := &RoleBindingSpec{
+		Name:     "deployers",
+		RoleRef:  rbac.RoleRef{Kind: "Role", Name: "deployer"},
+		Subjects: []rbac.Subject{{Kind: rbac.ServiceAccountKind, Name: "ci"}},
+	}
+
+	actual, err := CreateRoleBinding(client, "ns-1", spec)
+	if err != nil {
+		t.Fatalf("CreateRoleBinding() returned error: %v", err)
+	}
+
+	expectedRef := rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "Role", Name: "deployer"}
+	expectedSubjects := []rbac.Subject{{Kind: rbac.ServiceAccountKind, Name: "ci", Namespace: "ns-1"}}
+	if !reflect.DeepEqual(actual.RoleRef, expectedRef) || !reflect.DeepEqual(actual.Subjects, expectedSubjects) {
+		t.Errorf("CreateRoleBinding() == %+v, expected role reference %+v and subjects %+v", actual, expectedRef,
+			expectedSubjects)
+	}
+}
+
+func TestUpdateRoleBindingRoleRef(t *testing.T) {
+	client := fake.NewSimpleClientset(&rbac.RoleBinding{
+		ObjectMeta: metaV1.ObjectMeta{Name: "deployers", Namespace: "ns-1"},
+		RoleRef:    rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "Role", Name: "deployer"},
+	})
+
+	spec := &RoleBindingSpec{RoleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "admin"}}
+	if _, err := UpdateRoleBinding(client, "ns-1", "deployers", spec); err == nil {
+		t.Errorf("Expected changing the role reference to fail")
+	}
+
+	spec = &RoleBindingSpec{Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "alice"}}}
+	actual, err := UpdateRoleBinding(client, "ns-1", "deployers", spec)
+	if err != nil {
+		t.Fatalf("UpdateRoleBinding() returned error: %v", err)
+	}
+	if len(actual.Subjects) != 1 || actual.Subjects[0].Name != "alice" {
+		t.Errorf("Unexpected subjects: %+v", actual.Subjects)
+	}
+}
+
+func TestPatchRoleBindingSubjects(t *testing.T) {
+	client := fake.NewSimpleClientset(&rbac.RoleBinding{
+		ObjectMeta: metaV1.ObjectMeta{Name: "deployers", Namespace: "ns-1"},
+		RoleRef:    rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "Role", Name: "deployer"},
+		Subjects: []rbac.Subject{
+			{Kind: rbac.ServiceAccountKind, Name: "ci", Namespace: "ns-1"},
+			{Kind: rbac.UserKind, APIGroup: rbac.GroupName, Name: "alice"},
+		},
+	})
+
+	patch := &permission.SubjectPatch{
+		Add:    []rbac.Subject{{Kind: rbac.UserKind, Name: "bob"}},
+		Remove: []rbac.Subject{{Kind: rbac.UserKind, Name: "alice"}},
+	}
+	actual, err := PatchRoleBindingSubjects(client, "ns-1", "deployers", patch)
+	if err != nil {
+		t.Fatalf("PatchRoleBindingSubjects() returned error: %v", err)
+	}
+
+	expected := []rbac.Subject{
+		{Kind: rbac.ServiceAccountKind, Name: "ci", Namespace: "ns-1"},
+		{Kind: rbac.UserKind, APIGroup: rbac.GroupName, Name: "bob"},
+	}
+	if !reflect.DeepEqual(actual.Subjects, expected) {
+		t.Errorf("PatchRoleBindingSubjects() subjects == %+v, expected %+v", actual.Subjects, expected)
+	}
+
+	actions := client.Actions()
+	if verb := actions[len(actions)-1].GetVerb(); verb != "patch" {
+		t.Errorf("Expected subjects to be patched, got %s", verb)
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/customresourcedefinition"
+)
+
+// maxConcurrentCustomResourceLists limits number of custom resource object lists fetched at the same time, so
+// clusters with hundreds of CRDs do not flood the apiserver.
+const maxConcurrentCustomResourceLists = 10
+
+// customResource describes where objects of a single custom resource definition can be listed.
+type customResource struct {
+	// name is the name of the custom resource definition. It is used as the kind of its objects.
+	name       string
+	resource   schema.GroupVersionResource
+	namespaced bool
+}
+
+// customResourceObjectChannel is a list and error channels to searchable custom resource objects.
+type customResourceObjectChannel struct {
+	List  chan []searchable
+	Error chan []error
+}
+
+// getCustomResourceObjectChannel lists objects of all custom resource definitions in the background. Errors
+// are never critical, as a single broken definition should not break the whole search.
+func getCustomResourceObjectChannel(extClient apiextensionsclientset.Interface, dynamicClient dynamic.Interface,
+	nsQuery *common.NamespaceQuery, clusterScoped bool) customResourceObjectChannel {
+	channel := customResourceObjectChannel{
+		List:  make(chan []searchable, 1),
+		Error: make(chan []error, 1),
+	}
+
+	go func() {
+		objects, nonCriticalErrors := getCustomResourceObjects(extClient, dynamicClient, nsQuery, clusterScoped)
+		channel.List <- objects
+		channel.Error <- nonCriticalErrors
+	}()
+
+	return channel
+}
+
+func getCustomResourceObjects(extClient apiextensionsclientset.Interface, dynamicClient dynamic.Interface,
+	nsQuery *common.NamespaceQuery, clusterScoped bool) ([]searchable, []error) {
+	objects := make([]searchable, 0)
+	nonCriticalErrors := make([]error, 0)
+	if extClient == nil || dynamicClient == nil {
+		return objects, nonCriticalErrors
+	}
+
+	resources, err := getCustomResources(extClient)
+	if err != nil {
+		nonCriticalErrors = appendCustomResourceError(err, nonCriticalErrors)
+		return objects, nonCriticalErrors
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, maxConcurrentCustomResourceLists)
+	for _, resource := range resources {
+		if !resource.namespaced && !clusterScoped {
+			continue
+		}
+
+		wg.Add(1)
+		go func(resource customResource) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			resourceInterface := dynamicClient.Resource(resource.resource)
+			namespaceable := dynamic.ResourceInterface(resourceInterface)
+			if resource.namespaced {
+				namespaceable = resourceInterface.Namespace(nsQuery.ToRequestParam())
+			}
+			list, err := namespaceable.List(context.TODO(), api.ListEverything)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				nonCriticalErrors = appendCustomResourceError(err, nonCriticalErrors)
+				return
+			}
+			for _, item := range list.Items {
+				if resource.namespaced && !nsQuery.Matches(item.GetNamespace()) {
+					continue
+				}
+				objects = append(objects, searchable{
+					kind: resource.name,
+					objectMeta: metaV1.ObjectMeta{
+						Name:              item.GetName(),
+						Namespace:         item.GetNamespace(),
+						Labels:            item.GetLabels(),
+						Annotations:       item.GetAnnotations(),
+						CreationTimestamp: item.GetCreationTimestamp(),
+						UID:               item.GetUID(),
+					},
+				})
+			}
+		}(resource)
+	}
+	wg.Wait()
+
+	return objects, nonCriticalErrors
+}
+
+// appendCustomResourceError appends error to the list of non-critical errors. Errors that are critical for
+// other resources are only logged, so the rest of the search results can still be returned.
+func appendCustomResourceError(err error, nonCriticalErrors []error) []error {
+	nonCriticalErrors, criticalError := errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		log.Printf("Skipping custom resources in search: %s", criticalError)
+	}
+	return nonCriticalErrors
+}
+
+// getCustomResources lists custom resource definitions using the preferred version of extensions API.
+func getCustomResources(extClient apiextensionsclientset.Interface) ([]customResource, error) {
+	version, err := customresourcedefinition.GetExtensionsAPIVersion(extClient)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]customResource, 0)
+	if version == apiextensionsv1.SchemeGroupVersion.Version {
+		channel := common.GetCustomResourceDefinitionChannelV1(extClient, 1)
+		list := <-channel.List
+		if err := <-channel.Error; err != nil {
+			return nil, err
+		}
+		for _, crd := range list.Items {
+			version := servedVersionV1(&crd)
+			if len(version) == 0 {
+				continue
+			}
+			resources = append(resources, customResource{
+				name: crd.Name,
+				resource: schema.GroupVersionResource{
+					Group:    crd.Spec.Group,
+					Version:  version,
+					Resource: crd.Spec.Names.Plural,
+				},
+				namespaced: crd.Spec.Scope == apiextensionsv1.NamespaceScoped,
+			})
+		}
+		return resources, nil
+	}
+
+	channel := common.GetCustomResourceDefinitionChannelV1beta1(extClient, 1)
+	list := <-channel.List
+	if err := <-channel.Error; err != nil {
+		return nil, err
+	}
+	for _, crd := range list.Items {
+		version := servedVersionV1beta1(&crd)
+		if len(version) == 0 {
+			continue
+		}
+		resources = append(resources, customResource{
+			name: crd.Name,
+			resource: schema.GroupVersionResource{
+				Group:    crd.Spec.Group,
+				Version:  version,
+				Resource: crd.Spec.Names.Plural,
+			},
+			namespaced: crd.Spec.Scope == apiextensionsv1beta1.NamespaceScoped,
+		})
+	}
+	return resources, nil
+}
+
+// servedVersionV1 returns the version custom resources are listed with, i.e. the storage version if it is served and
+// the first served version otherwise. It is empty if no version is served.
+func servedVersionV1(crd *apiextensionsv1.CustomResourceDefinition) string {
+	result := ""
+	for _, version := range crd.Spec.Versions {
+		if version.Served && version.Storage {
+			return version.Name
+		}
+		if version.Served && len(result) == 0 {
+			result = version.Name
+		}
+	}
+	return result
+}
+
+// servedVersionV1beta1 is the same as servedVersionV1 for the v1beta1 API, where a single served version can be set
+// without the list of versions.
+func servedVersionV1beta1(crd *apiextensionsv1beta1.CustomResourceDefinition) string {
+	if len(crd.Spec.Versions) == 0 {
+		return crd.Spec.Version
+	}
+
+	result := ""
+	for _, version := range crd.Spec.Versions {
+		if version.Served && version.Storage {
+			return version.Name
+		}
+		if version.Served && len(result) == 0 {
+			result = version.Name
+		}
+	}
+	return result
+}
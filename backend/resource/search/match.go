@@ -0,0 +1,137 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"sort"
+	"strings"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Scores assigned to matches of the query against different fields of an object. Hit score is the highest
+// score of all its matches, so an exact name match always ranks above an object that only mentions the query
+// in one of its annotations.
+const (
+	scoreNameExact       = 100
+	scoreIPExact         = 90
+	scoreNamePrefix      = 75
+	scoreImageExact      = 60
+	scoreNameContains    = 50
+	scoreImageContains   = 40
+	scoreLabelExact      = 30
+	scoreLabelContains   = 20
+	scoreAnnotationMatch = 10
+)
+
+// List of fields that can be matched by the query.
+const (
+	MatchFieldName       = "name"
+	MatchFieldLabel      = "label"
+	MatchFieldAnnotation = "annotation"
+	MatchFieldImage      = "image"
+	MatchFieldIP         = "ip"
+)
+
+// lastAppliedConfigAnnotation holds a copy of the whole object, so matching it would make nearly every object
+// created by kubectl apply a hit.
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// SearchMatch describes a single field of an object that matched the query.
+type SearchMatch struct {
+	// Field is the name of the matched field, i.e. name, label or image.
+	Field string `json:"field"`
+
+	// Value is the matched value. Labels and annotations are represented as key=value.
+	Value string `json:"value"`
+}
+
+// searchable is an object that can be matched against the query together with data extracted from its spec
+// and status.
+type searchable struct {
+	kind       string
+	objectMeta metaV1.ObjectMeta
+	images     []string
+	ips        []string
+}
+
+// match matches given object against lower cased query. It returns the score of the best match and the list
+// of all matches. Score is 0 if nothing matched.
+func match(object searchable, query string) (int, []SearchMatch) {
+	score := 0
+	matches := make([]SearchMatch, 0)
+	add := func(s int, field, value string) {
+		if s > score {
+			score = s
+		}
+		matches = append(matches, SearchMatch{Field: field, Value: value})
+	}
+
+	name := strings.ToLower(object.objectMeta.Name)
+	switch {
+	case name == query:
+		add(scoreNameExact, MatchFieldName, object.objectMeta.Name)
+	case strings.HasPrefix(name, query):
+		add(scoreNamePrefix, MatchFieldName, object.objectMeta.Name)
+	case strings.Contains(name, query):
+		add(scoreNameContains, MatchFieldName, object.objectMeta.Name)
+	}
+
+	for _, ip := range object.ips {
+		if strings.ToLower(ip) == query {
+			add(scoreIPExact, MatchFieldIP, ip)
+		}
+	}
+
+	for _, image := range object.images {
+		lowerImage := strings.ToLower(image)
+		if lowerImage == query {
+			add(scoreImageExact, MatchFieldImage, image)
+		} else if strings.Contains(lowerImage, query) {
+			add(scoreImageContains, MatchFieldImage, image)
+		}
+	}
+
+	for _, key := range sortedKeys(object.objectMeta.Labels) {
+		label := key + "=" + object.objectMeta.Labels[key]
+		lowerLabel := strings.ToLower(label)
+		if lowerLabel == query || strings.ToLower(object.objectMeta.Labels[key]) == query {
+			add(scoreLabelExact, MatchFieldLabel, label)
+		} else if strings.Contains(lowerLabel, query) {
+			add(scoreLabelContains, MatchFieldLabel, label)
+		}
+	}
+
+	for _, key := range sortedKeys(object.objectMeta.Annotations) {
+		if key == lastAppliedConfigAnnotation {
+			continue
+		}
+		annotation := key + "=" + object.objectMeta.Annotations[key]
+		if strings.Contains(strings.ToLower(annotation), query) {
+			add(scoreAnnotationMatch, MatchFieldAnnotation, annotation)
+		}
+	}
+
+	return score, matches
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,309 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+)
+
+// readSearchables reads all lists from given channels and converts them to searchable objects. Channels of
+// cluster scoped objects are read only when they were created.
+func readSearchables(channels *common.ResourceChannels, nsQuery *common.NamespaceQuery) ([]searchable,
+	[]error, error) {
+	objects := make([]searchable, 0)
+	nonCriticalErrors := make([]error, 0)
+	handle := func(err error) error {
+		var criticalError error
+		nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+		return criticalError
+	}
+	add := func(kind string, meta metaV1.ObjectMeta, images, ips []string) {
+		if len(meta.Namespace) > 0 && !nsQuery.Matches(meta.Namespace) {
+			return
+		}
+		objects = append(objects, searchable{kind: kind, objectMeta: meta, images: images, ips: ips})
+	}
+
+	pods := <-channels.PodList.List
+	if err := handle(<-channels.PodList.Error); err != nil {
+		return nil, nil, err
+	}
+	for _, item := range pods.Items {
+		add(api.ResourceKindPod, item.ObjectMeta, getPodSpecImages(&item.Spec), getPodIPs(&item))
+	}
+
+	deployments := <-channels.DeploymentList.List
+	if err := handle(<-channels.DeploymentList.Error); err != nil {
+		return nil, nil, err
+	}
+	for _, item := range deployments.Items {
+		add(api.ResourceKindDeployment, item.ObjectMeta, getPodSpecImages(&item.Spec.Template.Spec), nil)
+	}
+
+	replicaSets := <-channels.ReplicaSetList.List
+	if err := handle(<-channels.ReplicaSetList.Error); err != nil {
+		return nil, nil, err
+	}
+	for _, item := range replicaSets.Items {
+		add(api.ResourceKindReplicaSet, item.ObjectMeta, getPodSpecImages(&item.Spec.Template.Spec), nil)
+	}
+
+	replicationControllers := <-channels.ReplicationControllerList.List
+	if err := handle(<-channels.ReplicationControllerList.Error); err != nil {
+		return nil, nil, err
+	}
+	for _, item := range replicationControllers.Items {
+		var images []string
+		if item.Spec.Template != nil {
+			images = getPodSpecImages(&item.Spec.Template.Spec)
+		}
+		add(api.ResourceKindReplicationController, item.ObjectMeta, images, nil)
+	}
+
+	daemonSets := <-channels.DaemonSetList.List
+	if err := handle(<-channels.DaemonSetList.Error); err != nil {
+		return nil, nil, err
+	}
+	for _, item := range daemonSets.Items {
+		add(api.ResourceKindDaemonSet, item.ObjectMeta, getPodSpecImages(&item.Spec.Template.Spec), nil)
+	}
+
+	statefulSets := <-channels.StatefulSetList.List
+	if err := handle(<-channels.StatefulSetList.Error); err != nil {
+		return nil, nil, err
+	}
+	for _, item := range statefulSets.Items {
+		add(api.ResourceKindStatefulSet, item.ObjectMeta, getPodSpecImages(&item.Spec.Template.Spec), nil)
+	}
+
+	jobs := <-channels.JobList.List
+	if err := handle(<-channels.JobList.Error); err != nil {
+		return nil, nil, err
+	}
+	for _, item := range jobs.Items {
+		add(api.ResourceKindJob, item.ObjectMeta, getPodSpecImages(&item.Spec.Template.Spec), nil)
+	}
+
+	cronJobs := <-channels.CronJobList.List
+	if err := handle(<-channels.CronJobList.Error); err != nil {
+		return nil, nil, err
+	}
+	for _, item := range cronJobs.Items {
+		add(api.ResourceKindCronJob, item.ObjectMeta,
+			getPodSpecImages(&item.Spec.JobTemplate.Spec.Template.Spec), nil)
+	}
+
+	services := <-channels.ServiceList.List
+	if err := handle(<-channels.ServiceList.Error); err != nil {
+		return nil, nil, err
+	}
+	for _, item := range services.Items {
+		add(api.ResourceKindService, item.ObjectMeta, nil, getServiceIPs(&item))
+	}
+
+	ingresses := <-channels.IngressList.List
+	if err := handle(<-channels.IngressList.Error); err != nil {
+		return nil, nil, err
+	}
+	for _, item := range ingresses.Items {
+		add(api.ResourceKindIngress, item.ObjectMeta, nil, getIngressIPs(&item))
+	}
+
+	configMaps := <-channels.ConfigMapList.List
+	if err := handle(<-channels.ConfigMapList.Error); err != nil {
+		return nil, nil, err
+	}
+	for _, item := range configMaps.Items {
+		add(api.ResourceKindConfigMap, item.ObjectMeta, nil, nil)
+	}
+
+	secrets := <-channels.SecretList.List
+	if err := handle(<-channels.SecretList.Error); err != nil {
+		return nil, nil, err
+	}
+	for _, item := range secrets.Items {
+		add(api.ResourceKindSecret, item.ObjectMeta, nil, nil)
+	}
+
+	persistentVolumeClaims := <-channels.PersistentVolumeClaimList.List
+	if err := handle(<-channels.PersistentVolumeClaimList.Error); err != nil {
+		return nil, nil, err
+	}
+	for _, item := range persistentVolumeClaims.Items {
+		add(api.ResourceKindPersistentVolumeClaim, item.ObjectMeta, nil, nil)
+	}
+
+	horizontalPodAutoscalers := <-channels.HorizontalPodAutoscalerList.List
+	if err := handle(<-channels.HorizontalPodAutoscalerList.Error); err != nil {
+		return nil, nil, err
+	}
+	for _, item := range horizontalPodAutoscalers.Items {
+		add(api.ResourceKindHorizontalPodAutoscaler, item.ObjectMeta, nil, nil)
+	}
+
+	limitRanges := <-channels.LimitRangeList.List
+	if err := handle(<-channels.LimitRangeList.Error); err != nil {
+		return nil, nil, err
+	}
+	for _, item := range limitRanges.Items {
+		add(api.ResourceKindLimitRange, item.ObjectMeta, nil, nil)
+	}
+
+	resourceQuotas := <-channels.ResourceQuotaList.List
+	if err := handle(<-channels.ResourceQuotaList.Error); err != nil {
+		return nil, nil, err
+	}
+	for _, item := range resourceQuotas.Items {
+		add(api.ResourceKindResourceQuota, item.ObjectMeta, nil, nil)
+	}
+
+	roles := <-channels.RoleList.List
+	if err := handle(<-channels.RoleList.Error); err != nil {
+		return nil, nil, err
+	}
+	for _, item := range roles.Items {
+		add(api.ResourceKindRole, item.ObjectMeta, nil, nil)
+	}
+
+	roleBindings := <-channels.RoleBindingList.List
+	if err := handle(<-channels.RoleBindingList.Error); err != nil {
+		return nil, nil, err
+	}
+	for _, item := range roleBindings.Items {
+		add(api.ResourceKindRoleBinding, item.ObjectMeta, nil, nil)
+	}
+
+	if channels.NodeList.List != nil {
+		nodes := <-channels.NodeList.List
+		if err := handle(<-channels.NodeList.Error); err != nil {
+			return nil, nil, err
+		}
+		for _, item := range nodes.Items {
+			add(api.ResourceKindNode, item.ObjectMeta, nil, getNodeIPs(&item))
+		}
+	}
+
+	if channels.NamespaceList.List != nil {
+		namespaces := <-channels.NamespaceList.List
+		if err := handle(<-channels.NamespaceList.Error); err != nil {
+			return nil, nil, err
+		}
+		for _, item := range namespaces.Items {
+			add(api.ResourceKindNamespace, item.ObjectMeta, nil, nil)
+		}
+	}
+
+	if channels.PersistentVolumeList.List != nil {
+		persistentVolumes := <-channels.PersistentVolumeList.List
+		if err := handle(<-channels.PersistentVolumeList.Error); err != nil {
+			return nil, nil, err
+		}
+		for _, item := range persistentVolumes.Items {
+			add(api.ResourceKindPersistentVolume, item.ObjectMeta, nil, nil)
+		}
+	}
+
+	if channels.StorageClassList.List != nil {
+		storageClasses := <-channels.StorageClassList.List
+		if err := handle(<-channels.StorageClassList.Error); err != nil {
+			return nil, nil, err
+		}
+		for _, item := range storageClasses.Items {
+			add(api.ResourceKindStorageClass, item.ObjectMeta, nil, nil)
+		}
+	}
+
+	if channels.ClusterRoleList.List != nil {
+		clusterRoles := <-channels.ClusterRoleList.List
+		if err := handle(<-channels.ClusterRoleList.Error); err != nil {
+			return nil, nil, err
+		}
+		for _, item := range clusterRoles.Items {
+			add(api.ResourceKindClusterRole, item.ObjectMeta, nil, nil)
+		}
+	}
+
+	if channels.ClusterRoleBindingList.List != nil {
+		clusterRoleBindings := <-channels.ClusterRoleBindingList.List
+		if err := handle(<-channels.ClusterRoleBindingList.Error); err != nil {
+			return nil, nil, err
+		}
+		for _, item := range clusterRoleBindings.Items {
+			add(api.ResourceKindClusterRoleBinding, item.ObjectMeta, nil, nil)
+		}
+	}
+
+	return objects, nonCriticalErrors, nil
+}
+
+// getPodSpecImages returns images of all containers and init containers from the given pod spec.
+func getPodSpecImages(spec *v1.PodSpec) []string {
+	return append(common.GetContainerImages(spec), common.GetInitContainerImages(spec)...)
+}
+
+// getPodIPs returns IPs of the pod. Host IP is not included, as it is shared by all pods of the node.
+func getPodIPs(pod *v1.Pod) []string {
+	ips := make([]string, 0)
+	for _, podIP := range pod.Status.PodIPs {
+		ips = append(ips, podIP.IP)
+	}
+	if len(ips) == 0 && len(pod.Status.PodIP) > 0 {
+		ips = append(ips, pod.Status.PodIP)
+	}
+	return ips
+}
+
+func getServiceIPs(service *v1.Service) []string {
+	ips := make([]string, 0)
+	if len(service.Spec.ClusterIP) > 0 && service.Spec.ClusterIP != v1.ClusterIPNone {
+		ips = append(ips, service.Spec.ClusterIP)
+	}
+	ips = append(ips, service.Spec.ExternalIPs...)
+	if len(service.Spec.LoadBalancerIP) > 0 {
+		ips = append(ips, service.Spec.LoadBalancerIP)
+	}
+	return append(ips, getLoadBalancerIPs(service.Status.LoadBalancer.Ingress)...)
+}
+
+func getIngressIPs(ingress *extensions.Ingress) []string {
+	return getLoadBalancerIPs(ingress.Status.LoadBalancer.Ingress)
+}
+
+func getLoadBalancerIPs(ingresses []v1.LoadBalancerIngress) []string {
+	ips := make([]string, 0)
+	for _, ingress := range ingresses {
+		if len(ingress.IP) > 0 {
+			ips = append(ips, ingress.IP)
+		}
+		if len(ingress.Hostname) > 0 {
+			ips = append(ips, ingress.Hostname)
+		}
+	}
+	return ips
+}
+
+func getNodeIPs(node *v1.Node) []string {
+	ips := make([]string, 0)
+	for _, address := range node.Status.Addresses {
+		ips = append(ips, address.Address)
+	}
+	return ips
+}
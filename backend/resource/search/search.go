@@ -0,0 +1,189 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
+)
+
+// SearchResult contains objects of all kinds that match the search query.
+type SearchResult struct {
+	ListMeta api.ListMeta `json:"listMeta"`
+
+	// Query is the normalized search query.
+	Query string `json:"query"`
+
+	// List of hits ordered by relevance, unless different order was requested.
+	Hits []SearchHit `json:"hits"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// SearchHit is a single object that matches the search query.
+type SearchHit struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// Score is the relevance of the hit. Higher is better.
+	Score int `json:"score"`
+
+	// Matches lists all fields of the object that matched the query.
+	Matches []SearchMatch `json:"matches"`
+
+	// Link is the API path of the object details.
+	Link string `json:"link"`
+}
+
+// Search looks for objects of all kinds that can be listed through common.ResourceChannels and for custom
+// resource objects, which name, labels, annotations, images or IPs match given query. Cluster scoped objects
+// are searched only when the namespace query is not limited to a single namespace.
+func Search(client kubernetes.Interface, extClient apiextensionsclientset.Interface, dynamicClient dynamic.Interface,
+	nsQuery *common.NamespaceQuery, query string, dsQuery *dataselect.DataSelectQuery) (*SearchResult, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	result := &SearchResult{Query: query, Hits: make([]SearchHit, 0), Errors: make([]error, 0)}
+	if len(query) == 0 {
+		return result, nil
+	}
+
+	log.Printf("Searching for %q in namespace %s", query, nsQuery.ToRequestParam())
+	clusterScoped := nsQuery.ToRequestParam() == v1.NamespaceAll
+
+	// Events and endpoints are left out on purpose. Events are not objects users look for and endpoints share
+	// the name and IPs with their services, so they would only duplicate service hits.
+	channels := &common.ResourceChannels{
+		PodList:                     common.GetPodListChannel(client, nsQuery, 1),
+		DeploymentList:              common.GetDeploymentListChannel(client, nsQuery, 1),
+		ReplicaSetList:              common.GetReplicaSetListChannel(client, nsQuery, 1),
+		ReplicationControllerList:   common.GetReplicationControllerListChannel(client, nsQuery, 1),
+		DaemonSetList:               common.GetDaemonSetListChannel(client, nsQuery, 1),
+		StatefulSetList:             common.GetStatefulSetListChannel(client, nsQuery, 1),
+		JobList:                     common.GetJobListChannel(client, nsQuery, 1),
+		CronJobList:                 common.GetCronJobListChannel(client, nsQuery, 1),
+		ServiceList:                 common.GetServiceListChannel(client, nsQuery, 1),
+		IngressList:                 common.GetIngressListChannel(client, nsQuery, 1),
+		ConfigMapList:               common.GetConfigMapListChannel(client, nsQuery, 1),
+		SecretList:                  common.GetSecretListChannel(client, nsQuery, 1),
+		PersistentVolumeClaimList:   common.GetPersistentVolumeClaimListChannel(client, nsQuery, 1),
+		HorizontalPodAutoscalerList: common.GetHorizontalPodAutoscalerListChannel(client, nsQuery, 1),
+		LimitRangeList:              common.GetLimitRangeListChannel(client, nsQuery, 1),
+		ResourceQuotaList:           common.GetResourceQuotaListChannel(client, nsQuery, 1),
+		RoleList:                    common.GetRoleListChannel(client, nsQuery, 1),
+		RoleBindingList:             common.GetRoleBindingListChannel(client, nsQuery, 1),
+	}
+	if clusterScoped {
+		channels.NodeList = common.GetNodeListChannel(client, 1)
+		channels.NamespaceList = common.GetNamespaceListChannel(client, 1)
+		channels.PersistentVolumeList = common.GetPersistentVolumeListChannel(client, 1)
+		channels.StorageClassList = common.GetStorageClassListChannel(client, 1)
+		channels.ClusterRoleList = common.GetClusterRoleListChannel(client, 1)
+		channels.ClusterRoleBindingList = common.GetClusterRoleBindingListChannel(client, 1)
+	}
+	crdChannel := getCustomResourceObjectChannel(extClient, dynamicClient, nsQuery, clusterScoped)
+
+	objects, nonCriticalErrors, criticalError := readSearchables(channels, nsQuery)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	crdObjects := <-crdChannel.List
+	crdErrors := <-crdChannel.Error
+	objects = append(objects, crdObjects...)
+	nonCriticalErrors = errors.MergeErrors(nonCriticalErrors, crdErrors)
+
+	hits := make([]SearchHit, 0)
+	for _, object := range objects {
+		score, matches := match(object, query)
+		if score == 0 {
+			continue
+		}
+		hits = append(hits, SearchHit{
+			ObjectMeta: api.NewObjectMeta(object.objectMeta),
+			TypeMeta:   api.NewTypeMeta(api.ResourceKind(object.kind)),
+			Score:      score,
+			Matches:    matches,
+			Link:       getLink(object),
+		})
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].TypeMeta.Kind != hits[j].TypeMeta.Kind {
+			return hits[i].TypeMeta.Kind < hits[j].TypeMeta.Kind
+		}
+		if hits[i].ObjectMeta.Namespace != hits[j].ObjectMeta.Namespace {
+			return hits[i].ObjectMeta.Namespace < hits[j].ObjectMeta.Namespace
+		}
+		return hits[i].ObjectMeta.Name < hits[j].ObjectMeta.Name
+	})
+
+	result.Hits, result.ListMeta.TotalItems = selectHits(hits, dsQuery)
+	result.Errors = nonCriticalErrors
+	return result, nil
+}
+
+// selectHits filters, sorts and paginates hits. Hits are already ordered by relevance, so they are sorted
+// only when sortBy was requested and with a stable sort, which keeps relevance order among equal values.
+func selectHits(hits []SearchHit, dsQuery *dataselect.DataSelectQuery) ([]SearchHit, int) {
+	selector := dataselect.DataSelector{GenericDataList: toCells(hits), DataSelectQuery: dsQuery}
+	selector.Filter()
+	filteredTotal := len(selector.GenericDataList)
+	if len(dsQuery.SortQuery.SortByList) > 0 {
+		sort.Stable(selector)
+	}
+	return fromCells(selector.Paginate().GenericDataList), filteredTotal
+}
+
+// getLink returns the API path of object details. Kinds without a dedicated detail route are linked to the
+// raw resource route.
+func getLink(object searchable) string {
+	namespace, name := object.objectMeta.Namespace, object.objectMeta.Name
+	switch object.kind {
+	case api.ResourceKindNode:
+		return fmt.Sprintf("/api/v1/node/%s/%s", common.AllNamespacesPlaceholder, name)
+	case api.ResourceKindNamespace, api.ResourceKindPersistentVolume, api.ResourceKindStorageClass,
+		api.ResourceKindClusterRole, api.ResourceKindClusterRoleBinding:
+		return fmt.Sprintf("/api/v1/%s/%s", object.kind, name)
+	case api.ResourceKindLimitRange, api.ResourceKindResourceQuota:
+		return fmt.Sprintf("/api/v1/_raw/%s/namespace/%s/name/%s", object.kind, namespace, name)
+	case api.ResourceKindPod, api.ResourceKindDeployment, api.ResourceKindReplicaSet,
+		api.ResourceKindReplicationController, api.ResourceKindDaemonSet, api.ResourceKindStatefulSet,
+		api.ResourceKindJob, api.ResourceKindCronJob, api.ResourceKindService, api.ResourceKindIngress,
+		api.ResourceKindConfigMap, api.ResourceKindSecret, api.ResourceKindPersistentVolumeClaim,
+		api.ResourceKindHorizontalPodAutoscaler, api.ResourceKindRole, api.ResourceKindRoleBinding:
+		return fmt.Sprintf("/api/v1/%s/%s/%s", object.kind, namespace, name)
+	}
+
+	// Anything else is a custom resource object which kind is the name of its definition.
+	if len(namespace) == 0 {
+		namespace = common.AllNamespacesPlaceholder
+	}
+	return fmt.Sprintf("/api/v1/crd/%s/%s/object/%s", object.kind, namespace, name)
+}
@@ -0,0 +1,214 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
+)
+
+func getSearchTestClient() *fake.Clientset {
+	podSpec := v1.PodSpec{Containers: []v1.Container{{Name: "app", Image: "registry/foo:1.2"}}}
+	return fake.NewSimpleClientset(
+		&v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web-1", Namespace: "default", Labels: map[string]string{"app": "web"}},
+			Spec:       podSpec,
+			Status:     v1.PodStatus{PodIP: "10.0.0.7", HostIP: "192.168.1.2"},
+		},
+		&apps.Deployment{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       apps.DeploymentSpec{Template: v1.PodTemplateSpec{Spec: podSpec}},
+		},
+		&v1.Service{
+			ObjectMeta: metaV1.ObjectMeta{Name: "frontend", Namespace: "default",
+				Annotations: map[string]string{"owner": "web-team"}},
+			Spec: v1.ServiceSpec{ClusterIP: "10.96.0.10"},
+		},
+		&v1.ConfigMap{ObjectMeta: metaV1.ObjectMeta{Name: "web-config", Namespace: "other"}},
+		&v1.Node{
+			ObjectMeta: metaV1.ObjectMeta{Name: "node-1"},
+			Status:     v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "192.168.1.2"}}},
+		},
+	)
+}
+
+func getHitLinks(result *SearchResult) []string {
+	links := make([]string, 0)
+	for _, hit := range result.Hits {
+		links = append(links, hit.Link)
+	}
+	return links
+}
+
+func TestSearch(t *testing.T) {
+	cases := []struct {
+		info     string
+		nsQuery  *common.NamespaceQuery
+		query    string
+		expected []string
+	}{
+		{
+			"name matches are ranked by relevance",
+			common.NewNamespaceQuery(nil),
+			"WEB",
+			[]string{
+				"/api/v1/deployment/default/web",
+				"/api/v1/configmap/other/web-config",
+				"/api/v1/pod/default/web-1",
+				"/api/v1/service/default/frontend",
+			},
+		},
+		{
+			"single namespace",
+			common.NewSameNamespaceQuery("default"),
+			"web",
+			[]string{
+				"/api/v1/deployment/default/web",
+				"/api/v1/pod/default/web-1",
+				"/api/v1/service/default/frontend",
+			},
+		},
+		{
+			"image",
+			common.NewNamespaceQuery(nil),
+			"foo:1.2",
+			[]string{"/api/v1/deployment/default/web", "/api/v1/pod/default/web-1"},
+		},
+		{
+			"IP",
+			common.NewNamespaceQuery(nil),
+			"10.0.0.7",
+			[]string{"/api/v1/pod/default/web-1"},
+		},
+		{
+			"host IP matches only the node",
+			common.NewNamespaceQuery(nil),
+			"192.168.1.2",
+			[]string{"/api/v1/node/_all/node-1"},
+		},
+		{
+			"cluster scoped objects are skipped for single namespace",
+			common.NewSameNamespaceQuery("default"),
+			"192.168.1.2",
+			[]string{},
+		},
+		{
+			"empty query",
+			common.NewNamespaceQuery(nil),
+			"  ",
+			[]string{},
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := Search(getSearchTestClient(), nil, nil, c.nsQuery, c.query, dataselect.NoDataSelect)
+		if err != nil {
+			t.Errorf("Search: %s. Unexpected error: %s", c.info, err)
+			continue
+		}
+
+		if links := getHitLinks(actual); !reflect.DeepEqual(links, c.expected) {
+			t.Errorf("Search: %s. Got %v, expected %v.", c.info, links, c.expected)
+		}
+	}
+}
+
+func TestSearchDataSelect(t *testing.T) {
+	dsQuery := dataselect.NewDataSelectQuery(dataselect.NewPaginationQuery(1, 0),
+		dataselect.NewSortQuery([]string{"a", "name"}), dataselect.NewFilterQuery([]string{"namespace", "default"}),
+		dataselect.NoMetrics)
+	actual, err := Search(getSearchTestClient(), nil, nil, common.NewNamespaceQuery(nil), "web", dsQuery)
+	if err != nil {
+		t.Fatalf("Search() returned error: %s", err)
+	}
+
+	expected := []string{"/api/v1/service/default/frontend"}
+	if links := getHitLinks(actual); actual.ListMeta.TotalItems != 3 || !reflect.DeepEqual(links, expected) {
+		t.Errorf("Search() returned %v of %d hits, expected %v of 3", links, actual.ListMeta.TotalItems, expected)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	object := searchable{
+		objectMeta: metaV1.ObjectMeta{
+			Name:   "nginx",
+			Labels: map[string]string{"app": "nginx-proxy"},
+			Annotations: map[string]string{
+				lastAppliedConfigAnnotation: `{"metadata":{"name":"nginx"}}`,
+			},
+		},
+		images: []string{"nginx:1.19"},
+	}
+
+	score, matches := match(object, "nginx")
+	expected := []SearchMatch{
+		{Field: MatchFieldName, Value: "nginx"},
+		{Field: MatchFieldImage, Value: "nginx:1.19"},
+		{Field: MatchFieldLabel, Value: "app=nginx-proxy"},
+	}
+	if score != scoreNameExact || !reflect.DeepEqual(matches, expected) {
+		t.Errorf("match() == %d, %v, expected %d, %v", score, matches, scoreNameExact, expected)
+	}
+
+	if score, _ := match(object, "apache"); score != 0 {
+		t.Errorf("match() == %d, expected 0", score)
+	}
+}
+
+func TestServedVersion(t *testing.T) {
+	v1Versions := []apiextensionsv1.CustomResourceDefinitionVersion{
+		{Name: "v1alpha1", Served: false, Storage: false},
+		{Name: "v1beta1", Served: true, Storage: false},
+		{Name: "v1", Served: true, Storage: true},
+	}
+	cases := []struct {
+		versions []apiextensionsv1.CustomResourceDefinitionVersion
+		expected string
+	}{
+		{v1Versions, "v1"},
+		{v1Versions[:2], "v1beta1"},
+		{v1Versions[:1], ""},
+	}
+	for _, c := range cases {
+		crd := &apiextensionsv1.CustomResourceDefinition{
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{Versions: c.versions}}
+		if actual := servedVersionV1(crd); actual != c.expected {
+			t.Errorf("servedVersionV1(%v) == %q, expected %q", c.versions, actual, c.expected)
+		}
+	}
+
+	crd := &apiextensionsv1beta1.CustomResourceDefinition{
+		Spec: apiextensionsv1beta1.CustomResourceDefinitionSpec{Version: "v1"}}
+	if actual := servedVersionV1beta1(crd); actual != "v1" {
+		t.Errorf("servedVersionV1beta1() without versions == %q, expected %q", actual, "v1")
+	}
+	crd.Spec.Versions = []apiextensionsv1beta1.CustomResourceDefinitionVersion{
+		{Name: "v1", Served: true, Storage: false},
+		{Name: "v2", Served: true, Storage: true},
+	}
+	if actual := servedVersionV1beta1(crd); actual != "v2" {
+		t.Errorf("servedVersionV1beta1() == %q, expected %q", actual, "v2")
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// expiringSoonPeriod is the time before the end of validity from which a certificate is considered as expiring soon.
+const expiringSoonPeriod = 30 * 24 * time.Hour
+
+// Certificate is a parsed certificate of a TLS secret.
+type Certificate struct {
+	Subject      string      `json:"subject"`
+	Issuer       string      `json:"issuer"`
+	SerialNumber string      `json:"serialNumber"`
+	DNSNames     []string    `json:"dnsNames"`
+	IPAddresses  []string    `json:"ipAddresses"`
+	IsCA         bool        `json:"isCA"`
+	NotBefore    metaV1.Time `json:"notBefore"`
+	NotAfter     metaV1.Time `json:"notAfter"`
+
+	// True if the certificate is no longer valid.
+	Expired bool `json:"expired"`
+
+	// True if the certificate is still valid but expires within 30 days.
+	ExpiringSoon bool `json:"expiringSoon"`
+}
+
+// ParseCertificates returns all certificates of a PEM encoded chain. Blocks that are not certificates are skipped.
+func ParseCertificates(data []byte, now time.Time) ([]Certificate, error) {
+	certificates := make([]Certificate, 0)
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, toCertificate(certificate, now))
+	}
+	return certificates, nil
+}
+
+func toCertificate(certificate *x509.Certificate, now time.Time) Certificate {
+	ipAddresses := make([]string, 0, len(certificate.IPAddresses))
+	for _, ip := range certificate.IPAddresses {
+		ipAddresses = append(ipAddresses, ip.String())
+	}
+
+	dnsNames := certificate.DNSNames
+	if dnsNames == nil {
+		dnsNames = make([]string, 0)
+	}
+
+	return Certificate{
+		Subject:      certificate.Subject.String(),
+		Issuer:       certificate.Issuer.String(),
+		SerialNumber: certificate.SerialNumber.String(),
+		DNSNames:     dnsNames,
+		IPAddresses:  ipAddresses,
+		IsCA:         certificate.IsCA,
+		NotBefore:    metaV1.NewTime(certificate.NotBefore),
+		NotAfter:     metaV1.NewTime(certificate.NotAfter),
+		Expired:      now.After(certificate.NotAfter),
+		ExpiringSoon: !now.After(certificate.NotAfter) && now.Add(expiringSoonPeriod).After(certificate.NotAfter),
+	}
+}
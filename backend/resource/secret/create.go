@@ -0,0 +1,252 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+)
+
+// OpaqueSecretSpec is a specification of a secret with arbitrary keys, implements SecretSpec.
+type OpaqueSecretSpec struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+
+	// Data of the secret. Values must be Base64 encoded.
+	Data map[string][]byte `json:"data"`
+}
+
+// GetName returns the name of the secret
+func (spec *OpaqueSecretSpec) GetName() string {
+	return spec.Name
+}
+
+// GetType returns the type of the secret, which is always v1.SecretTypeOpaque
+func (spec *OpaqueSecretSpec) GetType() v1.SecretType {
+	return v1.SecretTypeOpaque
+}
+
+// GetNamespace returns the namespace of the secret
+func (spec *OpaqueSecretSpec) GetNamespace() string {
+	return spec.Namespace
+}
+
+// GetData returns the data the secret carries
+func (spec *OpaqueSecretSpec) GetData() map[string][]byte {
+	return spec.Data
+}
+
+// Validate checks that the secret has at least one key and that all keys are valid.
+func (spec *OpaqueSecretSpec) Validate() error {
+	if len(spec.Data) == 0 {
+		return errors.NewInvalid("Secret must contain at least one key")
+	}
+	for key := range spec.Data {
+		if err := validateKey(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TLSSecretSpec is a specification of a TLS secret, implements SecretSpec.
+type TLSSecretSpec struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+
+	// PEM encoded certificate chain, starting with the leaf certificate.
+	Certificate string `json:"certificate"`
+
+	// PEM encoded private key of the leaf certificate.
+	PrivateKey string `json:"privateKey"`
+}
+
+// GetName returns the name of the secret
+func (spec *TLSSecretSpec) GetName() string {
+	return spec.Name
+}
+
+// GetType returns the type of the secret, which is always v1.SecretTypeTLS
+func (spec *TLSSecretSpec) GetType() v1.SecretType {
+	return v1.SecretTypeTLS
+}
+
+// GetNamespace returns the namespace of the secret
+func (spec *TLSSecretSpec) GetNamespace() string {
+	return spec.Namespace
+}
+
+// GetData returns the certificate and the private key under the keys used by TLS secrets
+func (spec *TLSSecretSpec) GetData() map[string][]byte {
+	return map[string][]byte{
+		v1.TLSCertKey:       []byte(spec.Certificate),
+		v1.TLSPrivateKeyKey: []byte(spec.PrivateKey),
+	}
+}
+
+// Validate checks that the certificate can be parsed and matches the private key.
+func (spec *TLSSecretSpec) Validate() error {
+	if _, err := tls.X509KeyPair([]byte(spec.Certificate), []byte(spec.PrivateKey)); err != nil {
+		return errors.NewInvalid(fmt.Sprintf("Invalid certificate or private key: %s", err.Error()))
+	}
+	return nil
+}
+
+// BasicAuthSecretSpec is a specification of a basic authentication secret, implements SecretSpec.
+type BasicAuthSecretSpec struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+}
+
+// GetName returns the name of the secret
+func (spec *BasicAuthSecretSpec) GetName() string {
+	return spec.Name
+}
+
+// GetType returns the type of the secret, which is always v1.SecretTypeBasicAuth
+func (spec *BasicAuthSecretSpec) GetType() v1.SecretType {
+	return v1.SecretTypeBasicAuth
+}
+
+// GetNamespace returns the namespace of the secret
+func (spec *BasicAuthSecretSpec) GetNamespace() string {
+	return spec.Namespace
+}
+
+// GetData returns the username and the password. Empty values are left out.
+func (spec *BasicAuthSecretSpec) GetData() map[string][]byte {
+	data := make(map[string][]byte)
+	if len(spec.Username) > 0 {
+		data[v1.BasicAuthUsernameKey] = []byte(spec.Username)
+	}
+	if len(spec.Password) > 0 {
+		data[v1.BasicAuthPasswordKey] = []byte(spec.Password)
+	}
+	return data
+}
+
+// Validate checks that a username or a password is set, as required by the API server.
+func (spec *BasicAuthSecretSpec) Validate() error {
+	if len(spec.Username) == 0 && len(spec.Password) == 0 {
+		return errors.NewInvalid("Basic auth secret must contain a username or a password")
+	}
+	return nil
+}
+
+// SSHAuthSecretSpec is a specification of an SSH authentication secret, implements SecretSpec.
+type SSHAuthSecretSpec struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+
+	// PEM encoded private key.
+	PrivateKey string `json:"privateKey"`
+}
+
+// GetName returns the name of the secret
+func (spec *SSHAuthSecretSpec) GetName() string {
+	return spec.Name
+}
+
+// GetType returns the type of the secret, which is always v1.SecretTypeSSHAuth
+func (spec *SSHAuthSecretSpec) GetType() v1.SecretType {
+	return v1.SecretTypeSSHAuth
+}
+
+// GetNamespace returns the namespace of the secret
+func (spec *SSHAuthSecretSpec) GetNamespace() string {
+	return spec.Namespace
+}
+
+// GetData returns the private key
+func (spec *SSHAuthSecretSpec) GetData() map[string][]byte {
+	return map[string][]byte{v1.SSHAuthPrivateKey: []byte(spec.PrivateKey)}
+}
+
+// Validate checks that the private key is PEM encoded.
+func (spec *SSHAuthSecretSpec) Validate() error {
+	if block, _ := pem.Decode([]byte(spec.PrivateKey)); block == nil {
+		return errors.NewInvalid("SSH private key must be PEM encoded")
+	}
+	return nil
+}
+
+// DockerConfigJSONSecretSpec is a specification of an image pull secret for a single registry, implements
+// SecretSpec. The .dockerconfigjson key is generated from the credentials.
+type DockerConfigJSONSecretSpec struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Server    string `json:"server"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+	Email     string `json:"email"`
+}
+
+// dockerConfigEntry is a single registry entry of the .dockerconfigjson key.
+type dockerConfigEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Email    string `json:"email,omitempty"`
+	Auth     string `json:"auth"`
+}
+
+// GetName returns the name of the secret
+func (spec *DockerConfigJSONSecretSpec) GetName() string {
+	return spec.Name
+}
+
+// GetType returns the type of the secret, which is always v1.SecretTypeDockerConfigJson
+func (spec *DockerConfigJSONSecretSpec) GetType() v1.SecretType {
+	return v1.SecretTypeDockerConfigJson
+}
+
+// GetNamespace returns the namespace of the secret
+func (spec *DockerConfigJSONSecretSpec) GetNamespace() string {
+	return spec.Namespace
+}
+
+// GetData returns the generated .dockerconfigjson key
+func (spec *DockerConfigJSONSecretSpec) GetData() map[string][]byte {
+	config := map[string]map[string]dockerConfigEntry{
+		"auths": {
+			spec.Server: {
+				Username: spec.Username,
+				Password: spec.Password,
+				Email:    spec.Email,
+				Auth:     base64.StdEncoding.EncodeToString([]byte(spec.Username + ":" + spec.Password)),
+			},
+		},
+	}
+
+	// Marshalling of maps and strings cannot fail.
+	data, _ := json.Marshal(config)
+	return map[string][]byte{v1.DockerConfigJsonKey: data}
+}
+
+// Validate checks that the registry and the credentials are set.
+func (spec *DockerConfigJSONSecretSpec) Validate() error {
+	if len(spec.Server) == 0 || len(spec.Username) == 0 || len(spec.Password) == 0 {
+		return errors.NewInvalid("Registry server, username and password are required")
+	}
+	return nil
+}
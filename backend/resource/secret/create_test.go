@@ -0,0 +1,155 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newCertificate returns a PEM encoded self-signed certificate and its private key.
+func newCertificate(t *testing.T, notAfter time.Time) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+}
+
+func TestValidateSecretSpec(t *testing.T) {
+	certificate, key := newCertificate(t, time.Now().Add(time.Hour))
+	_, otherKey := newCertificate(t, time.Now().Add(time.Hour))
+
+	cases := []struct {
+		spec  SecretSpec
+		valid bool
+	}{
+		{&OpaqueSecretSpec{Data: map[string][]byte{"config.yaml": []byte("a")}}, true},
+		{&OpaqueSecretSpec{Data: map[string][]byte{"a/b": []byte("a")}}, false},
+		{&OpaqueSecretSpec{}, false},
+		{&TLSSecretSpec{Certificate: certificate, PrivateKey: key}, true},
+		{&TLSSecretSpec{Certificate: certificate, PrivateKey: otherKey}, false},
+		{&TLSSecretSpec{Certificate: "invalid", PrivateKey: key}, false},
+		{&BasicAuthSecretSpec{Username: "admin"}, true},
+		{&BasicAuthSecretSpec{}, false},
+		{&SSHAuthSecretSpec{PrivateKey: key}, true},
+		{&SSHAuthSecretSpec{PrivateKey: "ssh-rsa AAAA"}, false},
+		{&DockerConfigJSONSecretSpec{Server: "registry.example.com", Username: "user", Password: "pass"}, true},
+		{&DockerConfigJSONSecretSpec{Server: "registry.example.com", Username: "user"}, false},
+		{&ImagePullSecretSpec{Data: []byte("{}")}, true},
+		{&ImagePullSecretSpec{}, false},
+	}
+
+	for _, c := range cases {
+		err := c.spec.Validate()
+		if (err == nil) != c.valid {
+			t.Errorf("Validate() of %#v == %v, expected valid: %v", c.spec, err, c.valid)
+		}
+	}
+}
+
+func TestCreateSecret(t *testing.T) {
+	spec := &DockerConfigJSONSecretSpec{
+		Name:      "registry",
+		Namespace: "default",
+		Server:    "registry.example.com",
+		Username:  "user",
+		Password:  "pass",
+	}
+	client := fake.NewSimpleClientset()
+
+	if _, err := CreateSecret(client, spec); err != nil {
+		t.Fatalf("CreateSecret() unexpected error: %v", err)
+	}
+
+	actual, err := client.CoreV1().Secrets("default").Get(context.TODO(), "registry", metaV1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]byte{
+		v1.DockerConfigJsonKey: []byte(`{"auths":{"registry.example.com":` +
+			`{"username":"user","password":"pass","auth":"dXNlcjpwYXNz"}}}`),
+	}
+	if actual.Type != v1.SecretTypeDockerConfigJson || !reflect.DeepEqual(actual.Data, expected) {
+		t.Errorf("CreateSecret() created %s secret with data %s, expected %s", actual.Type, actual.Data, expected)
+	}
+
+	if _, err := CreateSecret(client, &OpaqueSecretSpec{Name: "empty", Namespace: "default"}); err == nil {
+		t.Error("CreateSecret() expected an error for an invalid spec")
+	}
+}
+
+func TestGetSecretDetailCertificates(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		notAfter     time.Time
+		expired      bool
+		expiringSoon bool
+	}{
+		{now.Add(365 * 24 * time.Hour), false, false},
+		{now.Add(24 * time.Hour), false, true},
+		{now.Add(-time.Hour), true, false},
+	}
+
+	for _, c := range cases {
+		certificate, key := newCertificate(t, c.notAfter)
+		secret := &v1.Secret{
+			Type: v1.SecretTypeTLS,
+			Data: map[string][]byte{v1.TLSCertKey: []byte(certificate), v1.TLSPrivateKeyKey: []byte(key)},
+		}
+
+		actual := getSecretDetail(secret, now)
+		if len(actual.Certificates) != 1 || len(actual.Errors) > 0 {
+			t.Fatalf("getSecretDetail() == %#v, expected a single certificate", actual)
+		}
+		if cert := actual.Certificates[0]; cert.Subject != "CN=example.com" || cert.Expired != c.expired ||
+			cert.ExpiringSoon != c.expiringSoon {
+			t.Errorf("getSecretDetail() certificate == %#v, expected expired: %v, expiring soon: %v", cert,
+				c.expired, c.expiringSoon)
+		}
+	}
+}
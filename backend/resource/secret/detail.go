@@ -16,24 +16,41 @@ package secret
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"sort"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
 )
 
 // SecretDetail API resource provides mechanisms to inject containers with configuration data while keeping
-// containers agnostic of Kubernetes
+// containers agnostic of Kubernetes. Values are redacted, they can only be read one key at a time with
+// RevealSecretKey.
 type SecretDetail struct {
 	// Extends list item structure.
 	Secret `json:",inline"`
 
-	// Data contains the secret data.  Each key must be a valid DNS_SUBDOMAIN
-	// or leading dot followed by valid DNS_SUBDOMAIN.
-	// The serialized form of the secret data is a base64 encoded string,
-	// representing the arbitrary (possibly non-string) data value here.
-	Data map[string][]byte `json:"data"`
+	// Keys of the secret data, without their values.
+	Keys []SecretKey `json:"keys"`
+
+	// Parsed certificate chain of TLS secrets.
+	Certificates []Certificate `json:"certificates,omitempty"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// SecretKey is a single key of the secret data.
+type SecretKey struct {
+	Name string `json:"name"`
+
+	// Size of the value in bytes.
+	Size int `json:"size"`
 }
 
 // GetSecretDetail returns detailed information about a secret
@@ -45,12 +62,29 @@ func GetSecretDetail(client kubernetes.Interface, namespace, name string) (*Secr
 		return nil, err
 	}
 
-	return getSecretDetail(rawSecret), nil
+	return getSecretDetail(rawSecret, time.Now()), nil
 }
 
-func getSecretDetail(rawSecret *v1.Secret) *SecretDetail {
-	return &SecretDetail{
+func getSecretDetail(rawSecret *v1.Secret, now time.Time) *SecretDetail {
+	detail := &SecretDetail{
 		Secret: toSecret(rawSecret),
-		Data:   rawSecret.Data,
+		Keys:   make([]SecretKey, 0, len(rawSecret.Data)),
+		Errors: make([]error, 0),
 	}
+
+	for key, value := range rawSecret.Data {
+		detail.Keys = append(detail.Keys, SecretKey{Name: key, Size: len(value)})
+	}
+	sort.Slice(detail.Keys, func(i, j int) bool { return detail.Keys[i].Name < detail.Keys[j].Name })
+
+	if rawSecret.Type == v1.SecretTypeTLS {
+		certificates, err := ParseCertificates(rawSecret.Data[v1.TLSCertKey], now)
+		if err != nil {
+			detail.Errors = append(detail.Errors,
+				errors.NewInvalid(fmt.Sprintf("Could not parse certificate: %s", err.Error())))
+		}
+		detail.Certificates = certificates
+	}
+
+	return detail
 }
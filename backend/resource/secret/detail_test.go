@@ -17,6 +17,7 @@ package secret
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/ycyxuehan/dashboard-gin/backend/api"
 
@@ -45,12 +46,13 @@ func TestGetSecretDetail(t *testing.T) {
 						Name: "foo",
 					},
 				},
-				Data: map[string][]byte{"app": {0, 1, 2, 3}},
+				Keys:   []SecretKey{{Name: "app", Size: 4}},
+				Errors: []error{},
 			},
 		},
 	}
 	for _, c := range cases {
-		actual := getSecretDetail(c.secrets)
+		actual := getSecretDetail(c.secrets, time.Now())
 		if !reflect.DeepEqual(actual, c.expected) {
 			t.Errorf("getSecretDetail(%#v) == \n%#v\nexpected \n%#v\n", c.secrets, actual, c.expected)
 		}
@@ -0,0 +1,262 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/deployment"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/pod"
+)
+
+const (
+	// revealSubresource is the subresource of secrets checked before a value is revealed. It does not exist in the
+	// API, but can be granted with RBAC like any other subresource, e.g. "secrets/reveal" with the "get" verb, so
+	// that reading values in the dashboard is allowed separately from reading secrets.
+	revealSubresource = "reveal"
+
+	// revealEventReason is the reason of the event recorded for every revealed value.
+	revealEventReason = "KeyRevealed"
+
+	// eventSourceComponent is the component recorded as the source of events created by the dashboard.
+	eventSourceComponent = "kubernetes-dashboard"
+)
+
+// SecretKeyValue is a single revealed value of a secret.
+type SecretKeyValue struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Key       string `json:"key"`
+
+	// Value of the key. It is Base64 encoded.
+	Value []byte `json:"value"`
+}
+
+// SecretKeyChange is the result of a change of a single key. It lists the consumers of the key, so that the impact
+// of the change is visible.
+type SecretKeyChange struct {
+	Secret    *SecretDetail  `json:"secret"`
+	Consumers *pod.Consumers `json:"consumers"`
+
+	// Deployments restarted after the change.
+	Rolled []pod.Consumer `json:"rolled"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// SecretKeySpec is a specification of a new value of a single key.
+type SecretKeySpec struct {
+	// Value of the key. It must be Base64 encoded.
+	Value []byte `json:"value"`
+}
+
+// RevealSecretKey returns the value of a single key. The caller needs the "get" permission on the "secrets/reveal"
+// subresource of the secret. Every revealed value is recorded as an event of the secret, that names the user, so it
+// shows up in the secret events and in the API server audit log under the name of the caller. The value is not
+// returned if the event cannot be recorded. Empty user stands for requests that use privileges of the dashboard.
+func RevealSecretKey(client kubernetes.Interface, user, namespace, name, key string) (*SecretKeyValue, error) {
+	log.Printf("Revealing key %s of %s secret in %s namespace for %s", key, name, namespace, revealedBy(user))
+
+	allowed, err := canReveal(client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, k8serrors.NewForbidden(v1.Resource("secrets/"+revealSubresource), name,
+			fmt.Errorf("revealing secret values requires get permission on secrets/%s", revealSubresource))
+	}
+
+	secret, err := client.CoreV1().Secrets(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := secret.Data[key]
+	if !ok {
+		return nil, errors.NewNotFound(fmt.Sprintf("Key %s not found in %s secret", key, name))
+	}
+
+	if err := recordReveal(client, secret, key, user); err != nil {
+		return nil, err
+	}
+	return &SecretKeyValue{Namespace: namespace, Name: name, Key: key, Value: value}, nil
+}
+
+// UpdateSecretKey sets the value of a single key. The key is added if it does not exist yet. If roll is set, the
+// consuming deployments are restarted afterwards.
+func UpdateSecretKey(client kubernetes.Interface, namespace, name, key string, spec *SecretKeySpec,
+	roll bool) (*SecretKeyChange, error) {
+	log.Printf("Updating key %s of %s secret in %s namespace", key, name, namespace)
+
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	return updateSecretData(client, namespace, name, key, roll, func(data map[string][]byte) error {
+		data[key] = spec.Value
+		return nil
+	})
+}
+
+// DeleteSecretKey removes a single key. If roll is set, the consuming deployments are restarted afterwards.
+func DeleteSecretKey(client kubernetes.Interface, namespace, name, key string, roll bool) (*SecretKeyChange,
+	error) {
+	log.Printf("Deleting key %s of %s secret in %s namespace", key, name, namespace)
+
+	return updateSecretData(client, namespace, name, key, roll, func(data map[string][]byte) error {
+		if _, ok := data[key]; !ok {
+			return errors.NewNotFound(fmt.Sprintf("Key %s not found in %s secret", key, name))
+		}
+		delete(data, key)
+		return nil
+	})
+}
+
+// updateSecretData applies the change to the data of the secret and updates it. The update fails with a conflict if
+// the secret was changed in the meantime. TLS secrets must still contain a matching certificate and key afterwards.
+func updateSecretData(client kubernetes.Interface, namespace, name, key string, roll bool,
+	change func(data map[string][]byte) error) (*SecretKeyChange, error) {
+	secret, err := client.CoreV1().Secrets(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	if err := change(secret.Data); err != nil {
+		return nil, err
+	}
+
+	if secret.Type == v1.SecretTypeTLS {
+		if _, err := tls.X509KeyPair(secret.Data[v1.TLSCertKey], secret.Data[v1.TLSPrivateKeyKey]); err != nil {
+			return nil, errors.NewInvalid(fmt.Sprintf("Invalid certificate or private key: %s", err.Error()))
+		}
+	}
+
+	updated, err := client.CoreV1().Secrets(namespace).Update(context.TODO(), secret, metaV1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SecretKeyChange{
+		Secret: getSecretDetail(updated, time.Now()),
+		Rolled: make([]pod.Consumer, 0),
+		Errors: make([]error, 0),
+	}
+
+	// The secret is changed already, so failures from here on are only reported.
+	result.Consumers, err = pod.GetConsumers(client, pod.ConfigSource{
+		Kind:      api.ResourceKindSecret,
+		Namespace: namespace,
+		Name:      name,
+		Key:       key,
+	})
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return result, nil
+	}
+
+	if roll {
+		result.Rolled, result.Errors = deployment.RollDeployments(client, namespace, result.Consumers)
+	}
+	return result, nil
+}
+
+func canReveal(client kubernetes.Interface, namespace, name string) (bool, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   namespace,
+				Verb:        "get",
+				Resource:    "secrets",
+				Subresource: revealSubresource,
+				Name:        name,
+			},
+		},
+	}
+
+	result, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(context.TODO(), review,
+		metaV1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return result.Status.Allowed, nil
+}
+
+// recordReveal creates an event for the revealed key. The event is created with the credentials of the caller.
+func recordReveal(client kubernetes.Interface, secret *v1.Secret, key, user string) error {
+	log.Printf("Key %s of %s secret in %s namespace revealed to %s", key, secret.Name, secret.Namespace,
+		revealedBy(user))
+
+	now := metaV1.Now()
+	event := &v1.Event{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", secret.Name, now.UnixNano()),
+			Namespace: secret.Namespace,
+		},
+		InvolvedObject: v1.ObjectReference{
+			Kind:            "Secret",
+			APIVersion:      "v1",
+			Namespace:       secret.Namespace,
+			Name:            secret.Name,
+			UID:             secret.UID,
+			ResourceVersion: secret.ResourceVersion,
+		},
+		Reason:         revealEventReason,
+		Message:        fmt.Sprintf("Value of key %s was revealed in the dashboard by %s", key, revealedBy(user)),
+		Source:         v1.EventSource{Component: eventSourceComponent},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+		Type:           v1.EventTypeNormal,
+	}
+
+	if _, err := client.CoreV1().Events(secret.Namespace).Create(context.TODO(), event,
+		metaV1.CreateOptions{}); err != nil {
+		return errors.NewInternal(fmt.Sprintf("Could not record reveal of key %s of %s secret: %s", key,
+			secret.Name, err.Error()))
+	}
+	return nil
+}
+
+func revealedBy(user string) string {
+	if len(user) == 0 {
+		return "anonymous user with dashboard privileges"
+	}
+	return "user " + user
+}
+
+func validateKey(key string) error {
+	if messages := validation.IsConfigMapKey(key); len(messages) > 0 {
+		return errors.NewInvalid(fmt.Sprintf("Invalid key %s: %s", key, strings.Join(messages, ", ")))
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func newKeysClient(allowReveal bool) *fake.Clientset {
+	client := fake.NewSimpleClientset(&v1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Type:       v1.SecretTypeOpaque,
+		Data:       map[string][]byte{"user": []byte("admin"), "password": []byte("secret")},
+	})
+	client.PrependReactor("create", "selfsubjectaccessreviews",
+		func(action clienttesting.Action) (bool, runtime.Object, error) {
+			review := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+			attributes := review.Spec.ResourceAttributes
+			review.Status.Allowed = allowReveal && attributes.Subresource == revealSubresource &&
+				attributes.Name == "foo"
+			return true, review, nil
+		})
+	return client
+}
+
+func TestRevealSecretKey(t *testing.T) {
+	cases := []struct {
+		allowReveal bool
+		key         string
+		expected    *SecretKeyValue
+		forbidden   bool
+		notFound    bool
+	}{
+		{true, "password", &SecretKeyValue{Namespace: "default", Name: "foo", Key: "password",
+			Value: []byte("secret")}, false, false},
+		{false, "password", nil, true, false},
+		{true, "missing", nil, false, true},
+	}
+
+	for _, c := range cases {
+		client := newKeysClient(c.allowReveal)
+		actual, err := RevealSecretKey(client, "alice", "default", "foo", c.key)
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("RevealSecretKey() == %#v, expected %#v", actual, c.expected)
+		}
+		if k8serrors.IsForbidden(err) != c.forbidden || k8serrors.IsNotFound(err) != c.notFound {
+			t.Errorf("RevealSecretKey() unexpected error: %v", err)
+		}
+
+		events, _ := client.CoreV1().Events("default").List(context.TODO(), metaV1.ListOptions{})
+		if revealed := len(events.Items) > 0; revealed != (c.expected != nil) {
+			t.Errorf("expected an event to be recorded: %v, got %d events", c.expected != nil, len(events.Items))
+		}
+		for _, event := range events.Items {
+			if event.Reason != revealEventReason || event.InvolvedObject.Name != "foo" ||
+				!strings.Contains(event.Message, "alice") {
+				t.Errorf("unexpected event %#v", event)
+			}
+		}
+	}
+}
+
+func TestRevealSecretKeyWithoutEvent(t *testing.T) {
+	client := newKeysClient(true)
+	client.PrependReactor("create", "events", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8serrors.NewForbidden(v1.Resource("events"), "", fmt.Errorf("not allowed"))
+	})
+
+	actual, err := RevealSecretKey(client, "alice", "default", "foo", "password")
+	if actual != nil || err == nil {
+		t.Errorf("RevealSecretKey() == %#v, %v, expected an error when the reveal cannot be recorded", actual, err)
+	}
+}
+
+func TestUpdateAndDeleteSecretKey(t *testing.T) {
+	client := newKeysClient(false)
+
+	change, err := UpdateSecretKey(client, "default", "foo", "token", &SecretKeySpec{Value: []byte("abc")}, false)
+	if err != nil {
+		t.Fatalf("UpdateSecretKey() unexpected error: %v", err)
+	}
+	expected := []SecretKey{{Name: "password", Size: 6}, {Name: "token", Size: 3}, {Name: "user", Size: 5}}
+	if !reflect.DeepEqual(change.Secret.Keys, expected) {
+		t.Errorf("UpdateSecretKey() keys == %#v, expected %#v", change.Secret.Keys, expected)
+	}
+
+	change, err = DeleteSecretKey(client, "default", "foo", "user", false)
+	if err != nil {
+		t.Fatalf("DeleteSecretKey() unexpected error: %v", err)
+	}
+	expected = []SecretKey{{Name: "password", Size: 6}, {Name: "token", Size: 3}}
+	if !reflect.DeepEqual(change.Secret.Keys, expected) {
+		t.Errorf("DeleteSecretKey() keys == %#v, expected %#v", change.Secret.Keys, expected)
+	}
+
+	if _, err := DeleteSecretKey(client, "default", "foo", "user", false); !k8serrors.IsNotFound(err) {
+		t.Errorf("DeleteSecretKey() of a missing key == %v, expected not found", err)
+	}
+	if _, err := UpdateSecretKey(client, "default", "foo", "a/b", &SecretKeySpec{}, false); err == nil {
+		t.Error("UpdateSecretKey() expected an error for an invalid key")
+	}
+}
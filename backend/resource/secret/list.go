@@ -33,6 +33,7 @@ type SecretSpec interface {
 	GetType() v1.SecretType
 	GetNamespace() string
 	GetData() map[string][]byte
+	Validate() error
 }
 
 // ImagePullSecretSpec is a specification of an image pull secret implements SecretSpec
@@ -64,6 +65,14 @@ func (spec *ImagePullSecretSpec) GetData() map[string][]byte {
 	return map[string][]byte{v1.DockerConfigKey: spec.Data}
 }
 
+// Validate checks that the .dockercfg property is set
+func (spec *ImagePullSecretSpec) Validate() error {
+	if len(spec.Data) == 0 {
+		return errors.NewInvalid("Image pull secret data is required")
+	}
+	return nil
+}
+
 // Secret is a single secret returned to the frontend.
 type Secret struct {
 	ObjectMeta api.ObjectMeta `json:"objectMeta"`
@@ -96,8 +105,25 @@ func GetSecretList(client kubernetes.Interface, namespace *common.NamespaceQuery
 	return ToSecretList(secretList.Items, nonCriticalErrors, dsQuery), nil
 }
 
+// GetSecretListFromChannels returns a list of all secrets in the cluster reading required resource list once
+// from the channels.
+func GetSecretListFromChannels(channels *common.ResourceChannels, dsQuery *dataselect.DataSelectQuery) (*SecretList, error) {
+	secrets := <-channels.SecretList.List
+	err := <-channels.SecretList.Error
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	return ToSecretList(secrets.Items, nonCriticalErrors, dsQuery), nil
+}
+
 // CreateSecret creates a single secret using the cluster API client
 func CreateSecret(client kubernetes.Interface, spec SecretSpec) (*Secret, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
 	namespace := spec.GetNamespace()
 	secret := &v1.Secret{
 		ObjectMeta: metaV1.ObjectMeta{
@@ -33,6 +33,8 @@ func (self ServiceCell) GetProperty(name dataselect.PropertyName) dataselect.Com
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
 	case dataselect.TypeProperty:
 		return dataselect.StdComparableString(self.Spec.Type)
+	case dataselect.LabelProperty:
+		return dataselect.StdComparableLabels(self.ObjectMeta.Labels)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
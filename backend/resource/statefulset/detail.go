@@ -21,6 +21,7 @@ import (
 	"github.com/ycyxuehan/dashboard-gin/backend/errors"
 	metricapi "github.com/ycyxuehan/dashboard-gin/backend/integration/metric/api"
 	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/poddisruptionbudget"
 	apps "k8s.io/api/apps/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -31,6 +32,9 @@ type StatefulSetDetail struct {
 	// Extends list item structure.
 	StatefulSet `json:",inline"`
 
+	// Pod disruption budgets that match the pods of the stateful set and limit their voluntary evictions.
+	ProtectingBudgets []poddisruptionbudget.PodDisruptionBudget `json:"protectingBudgets"`
+
 	// List of non-critical errors, that occurred during resource retrieval.
 	Errors []error `json:"errors"`
 }
@@ -51,13 +55,21 @@ func GetStatefulSetDetail(client kubernetes.Interface, metricClient metricapi.Me
 		return nil, criticalError
 	}
 
-	ssDetail := getStatefulSetDetail(ss, podInfo, nonCriticalErrors)
+	budgets, err := poddisruptionbudget.GetProtectingBudgets(client, namespace, ss.Spec.Template.Labels)
+	nonCriticalErrors, criticalError = errors.AppendError(err, nonCriticalErrors)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	ssDetail := getStatefulSetDetail(ss, podInfo, budgets, nonCriticalErrors)
 	return &ssDetail, nil
 }
 
-func getStatefulSetDetail(statefulSet *apps.StatefulSet, podInfo *common.PodInfo, nonCriticalErrors []error) StatefulSetDetail {
+func getStatefulSetDetail(statefulSet *apps.StatefulSet, podInfo *common.PodInfo,
+	budgets []poddisruptionbudget.PodDisruptionBudget, nonCriticalErrors []error) StatefulSetDetail {
 	return StatefulSetDetail{
-		StatefulSet: toStatefulSet(statefulSet, podInfo),
-		Errors:      nonCriticalErrors,
+		StatefulSet:       toStatefulSet(statefulSet, podInfo),
+		ProtectingBudgets: budgets,
+		Errors:            nonCriticalErrors,
 	}
 }
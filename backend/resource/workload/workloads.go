@@ -0,0 +1,179 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"log"
+
+	client "k8s.io/client-go/kubernetes"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/errors"
+	metricapi "github.com/ycyxuehan/dashboard-gin/backend/integration/metric/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/cronjob"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/daemonset"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/deployment"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/job"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/pod"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/replicaset"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/replicationcontroller"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/statefulset"
+)
+
+// Workloads structure contains all resource lists grouped into the workloads category.
+type Workloads struct {
+	DeploymentList deployment.DeploymentList `json:"deploymentList"`
+
+	ReplicaSetList replicaset.ReplicaSetList `json:"replicaSetList"`
+
+	JobList job.JobList `json:"jobList"`
+
+	CronJobList cronjob.CronJobList `json:"cronJobList"`
+
+	ReplicationControllerList replicationcontroller.ReplicationControllerList `json:"replicationControllerList"`
+
+	PodList pod.PodList `json:"podList"`
+
+	DaemonSetList daemonset.DaemonSetList `json:"daemonSetList"`
+
+	StatefulSetList statefulset.StatefulSetList `json:"statefulSetList"`
+
+	// Status holds number of resources in each state per resource kind.
+	Status map[api.ResourceKind]common.ResourceStatus `json:"status"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// GetWorkloads returns a list of all workloads in the cluster.
+func GetWorkloads(client client.Interface, metricClient metricapi.MetricClient, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*Workloads, error) {
+	log.Printf("Getting workloads in namespace %s", nsQuery.ToRequestParam())
+
+	// Pods and events are read by every list of pod controllers and by the pod list itself.
+	channels := &common.ResourceChannels{
+		ReplicationControllerList: common.GetReplicationControllerListChannel(client, nsQuery, 1),
+		ReplicaSetList:            common.GetReplicaSetListChannel(client, nsQuery, 2),
+		JobList:                   common.GetJobListChannel(client, nsQuery, 1),
+		CronJobList:               common.GetCronJobListChannel(client, nsQuery, 1),
+		DaemonSetList:             common.GetDaemonSetListChannel(client, nsQuery, 1),
+		DeploymentList:            common.GetDeploymentListChannel(client, nsQuery, 1),
+		StatefulSetList:           common.GetStatefulSetListChannel(client, nsQuery, 1),
+		PodList:                   common.GetPodListChannel(client, nsQuery, 7),
+		EventList:                 common.GetEventListChannel(client, nsQuery, 7),
+	}
+
+	return GetWorkloadsFromChannels(channels, metricClient, dsQuery)
+}
+
+// GetWorkloadsFromChannels returns a list of all workloads in the cluster, from the channel sources.
+func GetWorkloadsFromChannels(channels *common.ResourceChannels, metricClient metricapi.MetricClient,
+	dsQuery *dataselect.DataSelectQuery) (*Workloads, error) {
+	numErrs := 8
+	errChan := make(chan error, numErrs)
+	rsChan := make(chan *replicaset.ReplicaSetList, 1)
+	jobChan := make(chan *job.JobList, 1)
+	cronJobChan := make(chan *cronjob.CronJobList, 1)
+	deploymentChan := make(chan *deployment.DeploymentList, 1)
+	rcChan := make(chan *replicationcontroller.ReplicationControllerList, 1)
+	podChan := make(chan *pod.PodList, 1)
+	dsChan := make(chan *daemonset.DaemonSetList, 1)
+	ssChan := make(chan *statefulset.StatefulSetList, 1)
+
+	go func() {
+		rcList, err := replicationcontroller.GetReplicationControllerListFromChannels(channels, dsQuery, metricClient)
+		errChan <- err
+		rcChan <- rcList
+	}()
+
+	go func() {
+		rsList, err := replicaset.GetReplicaSetListFromChannels(channels, dsQuery, metricClient)
+		errChan <- err
+		rsChan <- rsList
+	}()
+
+	go func() {
+		jobList, err := job.GetJobListFromChannels(channels, dsQuery, metricClient)
+		errChan <- err
+		jobChan <- jobList
+	}()
+
+	go func() {
+		cronJobList, err := cronjob.GetCronJobListFromChannels(channels, dsQuery, metricClient)
+		errChan <- err
+		cronJobChan <- cronJobList
+	}()
+
+	go func() {
+		deploymentList, err := deployment.GetDeploymentListFromChannels(channels, dsQuery, metricClient)
+		errChan <- err
+		deploymentChan <- deploymentList
+	}()
+
+	go func() {
+		podList, err := pod.GetPodListFromChannels(channels, dsQuery, metricClient)
+		errChan <- err
+		podChan <- podList
+	}()
+
+	go func() {
+		dsList, err := daemonset.GetDaemonSetListFromChannels(channels, dsQuery, metricClient)
+		errChan <- err
+		dsChan <- dsList
+	}()
+
+	go func() {
+		ssList, err := statefulset.GetStatefulSetListFromChannels(channels, dsQuery, metricClient)
+		errChan <- err
+		ssChan <- ssList
+	}()
+
+	for i := 0; i < numErrs; i++ {
+		err := <-errChan
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	workloads := &Workloads{
+		ReplicaSetList:            *(<-rsChan),
+		JobList:                   *(<-jobChan),
+		CronJobList:               *(<-cronJobChan),
+		ReplicationControllerList: *(<-rcChan),
+		DeploymentList:            *(<-deploymentChan),
+		PodList:                   *(<-podChan),
+		DaemonSetList:             *(<-dsChan),
+		StatefulSetList:           *(<-ssChan),
+	}
+
+	workloads.Status = map[api.ResourceKind]common.ResourceStatus{
+		api.ResourceKindDeployment:            workloads.DeploymentList.Status,
+		api.ResourceKindReplicaSet:            workloads.ReplicaSetList.Status,
+		api.ResourceKindJob:                   workloads.JobList.Status,
+		api.ResourceKindCronJob:               workloads.CronJobList.Status,
+		api.ResourceKindReplicationController: workloads.ReplicationControllerList.Status,
+		api.ResourceKindPod:                   workloads.PodList.Status,
+		api.ResourceKindDaemonSet:             workloads.DaemonSetList.Status,
+		api.ResourceKindStatefulSet:           workloads.StatefulSetList.Status,
+	}
+
+	workloads.Errors = errors.MergeErrors(workloads.ReplicaSetList.Errors, workloads.JobList.Errors,
+		workloads.CronJobList.Errors, workloads.ReplicationControllerList.Errors, workloads.DeploymentList.Errors,
+		workloads.PodList.Errors, workloads.DaemonSetList.Errors, workloads.StatefulSetList.Errors)
+
+	return workloads, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2017 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"reflect"
+	"testing"
+
+	batch "k8s.io/api/batch/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ycyxuehan/dashboard-gin/backend/api"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/common"
+	"github.com/ycyxuehan/dashboard-gin/backend/resource/dataselect"
+)
+
+func TestGetWorkloads(t *testing.T) {
+	suspend := false
+	client := fake.NewSimpleClientset(
+		&v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "pod-1", Namespace: "default"},
+			Status:     v1.PodStatus{Phase: v1.PodSucceeded},
+		},
+		&v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "pod-2", Namespace: "default"},
+			Status:     v1.PodStatus{Phase: v1.PodFailed},
+		},
+		&v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "pod-3", Namespace: "other"},
+			Status:     v1.PodStatus{Phase: v1.PodRunning},
+		},
+		&batch.CronJob{
+			ObjectMeta: metaV1.ObjectMeta{Name: "cron", Namespace: "default"},
+			Spec:       batch.CronJobSpec{Suspend: &suspend},
+		},
+	)
+
+	workloads, err := GetWorkloads(client, nil, common.NewSameNamespaceQuery("default"), dataselect.NoDataSelect)
+	if err != nil {
+		t.Fatalf("GetWorkloads() returned error: %s", err)
+	}
+
+	if workloads.PodList.ListMeta.TotalItems != 2 || workloads.CronJobList.ListMeta.TotalItems != 1 {
+		t.Errorf("GetWorkloads() returned %d pods and %d cron jobs, expected 2 and 1",
+			workloads.PodList.ListMeta.TotalItems, workloads.CronJobList.ListMeta.TotalItems)
+	}
+
+	expected := map[api.ResourceKind]common.ResourceStatus{
+		api.ResourceKindPod:     {Succeeded: 1, Failed: 1},
+		api.ResourceKindCronJob: {Running: 1},
+	}
+	for kind, status := range expected {
+		if !reflect.DeepEqual(workloads.Status[kind], status) {
+			t.Errorf("GetWorkloads() status of %s == %+v, expected %+v", kind, workloads.Status[kind], status)
+		}
+	}
+
+	if len(workloads.Status) != 8 {
+		t.Errorf("GetWorkloads() should return status of all 8 workload kinds, got %d", len(workloads.Status))
+	}
+}
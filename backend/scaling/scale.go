@@ -17,6 +17,7 @@ package scaling
 import (
 	"context"
 	"strconv"
+	"strings"
 
 	apps "k8s.io/api/apps/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -88,6 +89,28 @@ func ScaleResource(cfg *rest.Config, kind, namespace, name, count string) (*Repl
 	}, nil
 }
 
+// CheckScaleTarget checks that the resource referenced by an autoscaler exists and supports the scale
+// subresource. Kind and API version are taken from the scale target reference, e.g. "Deployment" and "apps/v1".
+func CheckScaleTarget(cfg *rest.Config, apiVersion, kind, namespace, name string) error {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return err
+	}
+
+	sc, err := getScaleGetter(cfg)
+	if err != nil {
+		return err
+	}
+
+	gr := schema.GroupResource{Resource: strings.ToLower(kind)}
+	if len(gv.Group) > 0 {
+		gr = getGroupResource(gr.Resource + "." + gv.Group)
+	}
+
+	_, err = sc.Scales(namespace).Get(context.TODO(), gr, name, metaV1.GetOptions{})
+	return err
+}
+
 func getScaleGetter(cfg *rest.Config) (scale.ScalesGetter, error) {
 	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
 	if err != nil {
@@ -17,6 +17,7 @@ package validation
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/ycyxuehan/dashboard-gin/backend/args"
+	authApi "github.com/ycyxuehan/dashboard-gin/backend/auth/api"
 	"github.com/ycyxuehan/dashboard-gin/backend/client"
 )
 
@@ -30,6 +31,8 @@ type LoginStatus struct {
 	// True if dashboard is configured to use HTTPS connection. It is required for secure
 	// data exchange during login operation.
 	HTTPSMode bool `json:"httpsMode"`
+	// True when client certificate verified by the TLS listener is found in request.
+	ClientCertificatePresent bool `json:"clientCertificatePresent"`
 	// True if impersonation is enabled
 	ImpersonationPresent bool `json:"impersonationPresent"`
 
@@ -48,11 +51,15 @@ func ValidateLoginStatus(c *gin.Context) *LoginStatus {
 		httpsMode = true
 	}
 
+	certificatePresent := c.Request.TLS != nil && len(c.Request.TLS.VerifiedChains) > 0 &&
+		authApi.ToAuthenticationModes(args.Holder.GetAuthenticationMode()).IsEnabled(authApi.ClientCertificate)
+
 	loginStatus := &LoginStatus{
-		TokenPresent:         len(tokenHeader) > 0,
-		HeaderPresent:        len(authHeader) > 0,
-		ImpersonationPresent: len(impersonationHeader) > 0,
-		HTTPSMode:            httpsMode,
+		TokenPresent:             len(tokenHeader) > 0,
+		HeaderPresent:            len(authHeader) > 0,
+		ImpersonationPresent:     len(impersonationHeader) > 0,
+		HTTPSMode:                httpsMode,
+		ClientCertificatePresent: certificatePresent,
 	}
 
 	if loginStatus.ImpersonationPresent {